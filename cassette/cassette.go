@@ -0,0 +1,273 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cassette records the Redfish requests sent to a BMC and their responses to a file, and
+// serves them back in place of the BMC. A cassette recorded on a misbehaving server reproduces its
+// behavior locally, and the cassettes of real servers are fixtures for regression tests.
+//
+// Credentials are redacted before anything is written: the authentication headers, the password
+// like properties and attributes of the JSON and XML bodies, including the server configuration
+// profiles they embed, and the content of the uploaded files. The host of each request is recorded,
+// so that a cassette holds the traffic of several BMCs; the requests recorded without a host, e.g.
+// once it has been removed from a cassette to share it, replay against any endpoint.
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// version of the cassette file format
+	version = 1
+	// redacted replaces the secrets in the cassettes
+	redacted = "REDACTED"
+)
+
+// redactedHeaders are the headers whose values are never recorded
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"X-Auth-Token":        true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// redactedProperties are the fragments of the JSON property names whose values are never recorded,
+// compared in lower case. SSLCertificateFile holds the private key of custom certificates.
+var redactedProperties = []string{"password", "passphrase", "secret", "token", "privatekey", "sslcertificatefile"}
+
+// xmlElement matches the XML elements holding text, as the attributes of the server configuration
+// profiles: <Attribute Name="Users.2#Password">calvin</Attribute>. The names of the opening and
+// closing tags are compared by redactXML, as regexp has no back references.
+var xmlElement = regexp.MustCompile(`<([A-Za-z_][\w.:-]*)(\s[^<>]*)?>([^<]*)</([A-Za-z_][\w.:-]*)>`)
+
+// xmlName matches the Name attribute of an XML element
+var xmlName = regexp.MustCompile(`\bName\s*=\s*"([^"]*)"`)
+
+// Cassette is a sequence of recorded requests and responses
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response of the BMC
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Host is the host and port of the request URL, and URI its path
+// and query.
+type Request struct {
+	Method  string            `json:"method"`
+	Host    string            `json:"host,omitempty"`
+	URI     string            `json:"uri"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    Body              `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    Body              `json:"body,omitempty"`
+}
+
+// Body is the content of a request or a response. It is written as text, or base64 encoded with a
+// "base64:" prefix when it is not valid UTF-8.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal("base64:" + base64.StdEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if encoded, isEncoded := strings.CutPrefix(text, "base64:"); isEncoded {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("invalid base64 body: %w", err)
+		}
+		*b = decoded
+		return nil
+	}
+	*b = []byte(text)
+	return nil
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if c.Version != version {
+		return nil, fmt.Errorf("unsupported version %d of the cassette %s", c.Version, path)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the file, replacing it atomically so that an interrupted run
+// leaves a readable cassette
+func (c *Cassette) Save(path string) error {
+	c.Version = version
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // #nosec G104
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		tmp.Close() // #nosec G104
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// redactHeader returns the value of the header as recorded
+func redactHeader(name, value string) string {
+	if redactedHeaders[name] {
+		return redacted
+	}
+	return value
+}
+
+// redactBody returns the body as recorded. The secrets of JSON and XML bodies are replaced, and
+// the files of multipart bodies are dropped.
+func redactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(contentType, "multipart/") {
+		return []byte(fmt.Sprintf("<multipart body of %d bytes omitted>", len(body)))
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		if text, found := redactXML(string(body)); found {
+			return []byte(text)
+		}
+		return body
+	}
+	if !redactValue(value) {
+		return body
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return content
+}
+
+// redactValue replaces the secrets found in the JSON value, and tells whether there were any.
+// Only the strings are secrets, so that PasswordChangeRequired keeps its type. The XML strings,
+// as the ImportBuffer of a server configuration profile, are redacted as XML bodies, and the
+// Value of the attributes of the JSON profiles is a secret when their Name is.
+func redactValue(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if name, isString := v["Name"].(string); isString && isSecret(name) {
+			if text, isString := v["Value"].(string); isString && len(text) > 0 {
+				v["Value"] = redacted
+				found = true
+			}
+		}
+		for name, property := range v {
+			text, isString := property.(string)
+			if isString && len(text) > 0 && isSecret(name) {
+				v[name] = redacted
+				found = true
+				continue
+			}
+			if isString {
+				if xml, redactedXML := redactXML(text); redactedXML {
+					v[name] = xml
+					found = true
+				}
+				continue
+			}
+			found = redactValue(property) || found
+		}
+	case []interface{}:
+		for _, item := range v {
+			found = redactValue(item) || found
+		}
+	}
+	return found
+}
+
+// redactXML replaces the text of the XML elements holding a secret, and tells whether there were
+// any. An element holds a secret when its tag or its Name attribute is the name of a secret.
+func redactXML(text string) (string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(text), "<") {
+		return text, false
+	}
+	found := false
+	redactedText := xmlElement.ReplaceAllStringFunc(text, func(element string) string {
+		parts := xmlElement.FindStringSubmatch(element)
+		tag, attributes, content, closingTag := parts[1], parts[2], parts[3], parts[4]
+		if tag != closingTag || len(strings.TrimSpace(content)) == 0 {
+			return element
+		}
+		secret := isSecret(tag)
+		if name := xmlName.FindStringSubmatch(attributes); name != nil {
+			secret = secret || isSecret(name[1])
+		}
+		if !secret {
+			return element
+		}
+		found = true
+		return "<" + tag + attributes + ">" + redacted + "</" + closingTag + ">"
+	})
+	return redactedText, found
+}
+
+// isSecret tells whether the JSON property or the XML element holds a secret
+func isSecret(name string) bool {
+	lower := strings.ToLower(name)
+	for _, fragment := range redactedProperties {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newBMC returns a server answering the account and the job requests like an iDRAC
+func newBMC(t *testing.T) *httptest.Server {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json;odata.metadata=minimal;charset=utf-8")
+		w.Header().Set("X-Auth-Token", "0123456789abcdef")
+		switch {
+		case r.Method == http.MethodPatch:
+			body, _ := io.ReadAll(r.Body) // #nosec G104
+			w.Write(body)                 // #nosec G104
+		case r.URL.Path == "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_1":
+			polls++
+			state := "Running"
+			if polls > 1 {
+				state = "Completed"
+			}
+			w.Write([]byte(`{"Id":"JID_1","JobState":"` + state + `"}`)) // #nosec G104
+		case r.URL.Path == "/redfish/v1/SSE":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("id: 1\ndata: {\"Events\":[]}\n\n")) // #nosec G104
+		case r.URL.Path == "/redfish/v1/firmware":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0xff, 0xfe, 0x00}) // #nosec G104
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func send(t *testing.T, client *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("invalid request: %s", err)
+	}
+	req.SetBasicAuth("root", "calvin")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, url, err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read the response: %s", err)
+	}
+	return resp.StatusCode, string(content)
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	bmc := newBMC(t)
+	path := filepath.Join(t.TempDir(), "idrac.json")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	recording := &http.Client{Transport: recorder.Transport(http.DefaultTransport)}
+	account := `{"UserName":"admin","Password":"secret123","Oem":{"Dell":{"SSLCertificateFile":"-----BEGIN"}}}`
+	send(t, recording, http.MethodPatch, bmc.URL+"/redfish/v1/AccountService/Accounts/3", account)
+	send(t, recording, http.MethodGet, bmc.URL+"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_1", "")
+	send(t, recording, http.MethodGet, bmc.URL+"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_1", "")
+	send(t, recording, http.MethodGet, bmc.URL+"/redfish/v1/firmware?version=1", "")
+	// the event streams are not recorded
+	if _, body := send(t, recording, http.MethodGet, bmc.URL+"/redfish/v1/SSE", ""); !strings.Contains(body, "data:") {
+		t.Errorf("unexpected event stream %q", body)
+	}
+
+	t.Run("test secrets are redacted", func(t *testing.T) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unable to read the cassette: %s", err)
+		}
+		for _, secret := range []string{"secret123", "BEGIN", "0123456789abcdef", "cm9vdDpjYWx2aW4"} {
+			if bytes.Contains(content, []byte(secret)) {
+				t.Errorf("the cassette contains %q", secret)
+			}
+		}
+		c, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load failed: %s", err)
+		}
+		if len(c.Interactions) != 4 {
+			t.Fatalf("expected 4 interactions, got %d", len(c.Interactions))
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(c.Interactions[0].Request.Body, &body); err != nil {
+			t.Fatalf("invalid request body: %s", err)
+		}
+		if body["UserName"] != "admin" || body["Password"] != redacted {
+			t.Errorf("unexpected request body %v", body)
+		}
+		if c.Interactions[0].Request.Headers["Authorization"] != redacted {
+			t.Errorf("unexpected headers %v", c.Interactions[0].Request.Headers)
+		}
+		if c.Interactions[0].Request.Host != bmc.Listener.Addr().String() {
+			t.Errorf("expected the host of the BMC, got %q", c.Interactions[0].Request.Host)
+		}
+	})
+
+	t.Run("test replay", func(t *testing.T) {
+		replayer, err := NewReplayer(path)
+		if err != nil {
+			t.Fatalf("NewReplayer failed: %s", err)
+		}
+		replaying := &http.Client{Transport: replayer}
+		jobURL := bmc.URL + "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_1"
+		for _, state := range []string{"Running", "Completed", "Completed"} {
+			status, body := send(t, replaying, http.MethodGet, jobURL, "")
+			if status != http.StatusOK || !strings.Contains(body, state) {
+				t.Errorf("expected the job to be %s, got %d %s", state, status, body)
+			}
+		}
+		status, body := send(t, replaying, http.MethodGet, bmc.URL+"/redfish/v1/firmware?version=1", "")
+		if status != http.StatusOK || body != string([]byte{0xff, 0xfe, 0x00}) {
+			t.Errorf("unexpected binary response %d %q", status, body)
+		}
+		if _, err := replaying.Get(bmc.URL + "/redfish/v1/Systems"); err == nil {
+			t.Errorf("expected an error for a request missing from the cassette")
+		}
+		// the responses of a server are not served to another one
+		if _, err := replaying.Get("https://idrac.example.com/redfish/v1/firmware?version=1"); err == nil {
+			t.Errorf("expected an error for a request to another host")
+		}
+	})
+}
+
+func TestCassetteReplayHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.json")
+	c := Cassette{Interactions: []Interaction{
+		{Request{Method: http.MethodGet, Host: "idrac1:443", URI: "/redfish/v1"}, Response{Status: http.StatusOK, Body: Body("idrac1")}},
+		{Request{Method: http.MethodGet, Host: "idrac2:443", URI: "/redfish/v1"}, Response{Status: http.StatusOK, Body: Body("idrac2")}},
+		{Request{Method: http.MethodGet, URI: "/redfish/v1/Systems"}, Response{Status: http.StatusOK, Body: Body("any")}},
+	}}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %s", err)
+	}
+	replaying := &http.Client{Transport: replayer}
+	for url, want := range map[string]string{
+		"https://idrac1:443/redfish/v1":         "idrac1",
+		"https://idrac2:443/redfish/v1":         "idrac2",
+		"https://idrac1:443/redfish/v1/Systems": "any",
+		"https://idrac3:443/redfish/v1/Systems": "any",
+	} {
+		if status, body := send(t, replaying, http.MethodGet, url, ""); status != http.StatusOK || body != want {
+			t.Errorf("expected %s for %s, got %d %s", want, url, status, body)
+		}
+	}
+	if _, err := replaying.Get("https://idrac3:443/redfish/v1"); err == nil {
+		t.Errorf("expected an error for a host missing from the cassette")
+	}
+}
+
+func TestCassetteRedactBody(t *testing.T) {
+	body := redactBody("application/json", []byte(`{"Members":[{"Token":"abc","Name":"x"}],"PasswordChangeRequired":false}`))
+	var value map[string]interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		t.Fatalf("invalid body: %s", err)
+	}
+	member := value["Members"].([]interface{})[0].(map[string]interface{})
+	if member["Token"] != redacted || member["Name"] != "x" {
+		t.Errorf("unexpected member %v", member)
+	}
+	if value["PasswordChangeRequired"] != false {
+		t.Errorf("unexpected body %v", value)
+	}
+	if multipart := redactBody("multipart/form-data; boundary=x", []byte("firmware")); bytes.Contains(multipart, []byte("firmware")) {
+		t.Errorf("multipart body was recorded: %s", multipart)
+	}
+	if text := redactBody("text/plain", []byte("Password=x")); string(text) != "Password=x" {
+		t.Errorf("unexpected text body %s", text)
+	}
+}
+
+func TestCassetteRedactProfiles(t *testing.T) {
+	profile := `<SystemConfiguration><Component FQDD="iDRAC.Embedded.1">` +
+		`<Attribute Name="Users.2#UserName">root</Attribute>` +
+		`<Attribute Name="Users.2#Password">calvin</Attribute>` +
+		`<Attribute Name="SNMP.1#AgentCommunity">public</Attribute>` +
+		`</Component><Password>hunter2</Password></SystemConfiguration>`
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"xml body", "application/xml", profile},
+		{"import buffer", "application/json", `{"ImportBuffer":` + jsonString(profile) + `,"ShareParameters":{"Target":["ALL"]}}`},
+		{"json profile", "application/json", `{"SystemConfiguration":{"Components":[{"FQDD":"iDRAC.Embedded.1","Attributes":[` +
+			`{"Name":"Users.2#UserName","Value":"root"},{"Name":"Users.2#Password","Value":"calvin"}]}]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := string(redactBody(tt.contentType, []byte(tt.body)))
+			for _, secret := range []string{"calvin", "hunter2"} {
+				if strings.Contains(body, secret) {
+					t.Errorf("the body contains %q: %s", secret, body)
+				}
+			}
+			for _, kept := range []string{"Users.2#UserName", "root", redacted} {
+				if !strings.Contains(body, kept) {
+					t.Errorf("the body is missing %q: %s", kept, body)
+				}
+			}
+		})
+	}
+	if body := string(redactBody("application/xml", []byte(profile))); !strings.Contains(body, "public") {
+		t.Errorf("unexpected redaction of an attribute which is not a secret: %s", body)
+	}
+}
+
+func jsonString(text string) string {
+	quoted, _ := json.Marshal(text)
+	return string(quoted)
+}
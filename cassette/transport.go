@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// eventStreamType is the content type of the Server-Sent Events streams
+const eventStreamType = "text/event-stream"
+
+// recordedResponseHeaders are the response headers kept in the cassettes, the other ones
+// describing the connection rather than the Redfish service
+var recordedResponseHeaders = []string{
+	"Content-Type", "Location", "ETag", "Retry-After", "OData-Version", "X-Auth-Token", "Allow", "Link",
+}
+
+// Recorder writes a cassette. It is safe for concurrent use, so that the clients of several
+// servers record to the same cassette.
+type Recorder struct {
+	path string
+
+	lock     sync.Mutex
+	cassette Cassette
+}
+
+// recordingTransport is an http.RoundTripper which sends the requests with its base transport
+// and records them with their responses
+type recordingTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+// NewRecorder returns a recorder writing the cassette at the path, which is created or replaced
+func NewRecorder(path string) (*Recorder, error) {
+	r := &Recorder{path: path}
+	if err := r.cassette.Save(path); err != nil {
+		return nil, fmt.Errorf("unable to create the cassette %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Transport returns an http.RoundTripper sending the requests with the base transport and
+// recording them
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{recorder: r, base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if requestBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close() // #nosec G104
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), eventStreamType) {
+		// a stream of Server-Sent Events never ends, it is not recorded and replays as missing
+		return resp, nil
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close() // #nosec G104
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			Host:    req.URL.Host,
+			URI:     req.URL.RequestURI(),
+			Headers: make(map[string]string),
+			Body:    redactBody(req.Header.Get("Content-Type"), requestBody),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: make(map[string]string),
+			Body:    redactBody(resp.Header.Get("Content-Type"), responseBody),
+		},
+	}
+	for name := range req.Header {
+		interaction.Request.Headers[name] = redactHeader(name, req.Header.Get(name))
+	}
+	for _, name := range recordedResponseHeaders {
+		if value := resp.Header.Get(name); len(value) > 0 {
+			interaction.Response.Headers[name] = redactHeader(name, value)
+		}
+	}
+
+	if err := t.recorder.add(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// add records the interaction. The cassette is saved after each interaction, so that it holds
+// the requests sent before the provider was stopped.
+func (r *Recorder) add(interaction Interaction) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		return fmt.Errorf("unable to save the cassette %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper which answers the requests with the responses of a cassette,
+// without sending them. It is safe for concurrent use.
+//
+// The requests are matched by host, method and URI, the requests recorded without a host matching
+// those of any host. The responses to the same request are served in the order they were
+// recorded, the last one being served again once they are all used, so that polling a job
+// replays its progress and then its final state.
+type Replayer struct {
+	path string
+
+	lock      sync.Mutex
+	responses map[string][]Response
+	served    map[string]int
+}
+
+// NewReplayer returns a replayer of the cassette at the path
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{path: path, responses: make(map[string][]Response), served: make(map[string]int)}
+	for _, interaction := range c.Interactions {
+		key := requestKey(interaction.Request.Host, interaction.Request.Method, interaction.Request.URI)
+		r.responses[key] = append(r.responses[key], interaction.Response)
+	}
+	return r, nil
+}
+
+// requestKey identifies the responses to a request. An empty host gives the key of the requests
+// recorded without a host.
+func requestKey(host, method, uri string) string {
+	return strings.TrimSpace(host + " " + method + " " + uri)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close() // #nosec G104
+	}
+	key := requestKey(req.URL.Host, req.Method, req.URL.RequestURI())
+
+	r.lock.Lock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		key = requestKey("", req.Method, req.URL.RequestURI())
+		responses = r.responses[key]
+	}
+	if len(responses) == 0 {
+		r.lock.Unlock()
+		return nil, fmt.Errorf("the cassette %s has no response to %s %s%s", r.path, req.Method, req.URL.Host, req.URL.RequestURI())
+	}
+	i := r.served[key]
+	if i < len(responses)-1 {
+		r.served[key]++
+	}
+	recorded := responses[i]
+	r.lock.Unlock()
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+	for name, value := range recorded.Headers {
+		resp.Header.Set(name, value)
+	}
+	return resp, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+)
+
+// CollectionParallelism is the number of members of a collection read at the same time, when the service
+// does not expand them, so as not to overwhelm the BMC. The clients must allow as many concurrent requests.
+const CollectionParallelism = 3
+
+// clientResource is a pointer to a gofish resource
+type clientResource[T any] interface {
+	*T
+	SetClient(redfishcommon.Client)
+}
+
+// ExpandQuery returns the $expand query expanding the resources one level below a resource, such as the
+// members of a collection, or an empty string when the service root does not advertise it in
+// ProtocolFeaturesSupported
+func ExpandQuery(service *gofish.Service) string {
+	expand := service.ProtocolFeaturesSupported.ExpandQuery
+	switch {
+	case expand.NoLinks && expand.Levels:
+		return "$expand=.($levels=1)"
+	case expand.NoLinks:
+		return "$expand=."
+	default:
+		return ""
+	}
+}
+
+// GetCollection returns the members of the collection at the URI. The members are read along with the
+// collection through $expand when the service supports it, so that a collection of any size takes a single
+// request, and otherwise a few at a time.
+func GetCollection[T any, PT clientResource[T]](service *gofish.Service, uri string) ([]*T, error) {
+	members, err := GetCollectionMembers(service, uri)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResources[T, PT](service, members)
+}
+
+// GetLinkedCollection returns the members of the collection a property of the resource at the URI links to,
+// such as the VirtualMedia of a manager. The collection is read along with the resource through $expand when
+// the service supports it, with its members when the service expands two levels, and otherwise as in
+// GetCollection.
+func GetLinkedCollection[T any, PT clientResource[T]](service *gofish.Service, uri, property string) ([]*T, error) {
+	document, err := getDocument(service, uri, linkedExpandQuery(service))
+	if err != nil {
+		return nil, err
+	}
+	var collection map[string]json.RawMessage
+	var link string
+	if err := json.Unmarshal(document[property], &collection); err == nil {
+		json.Unmarshal(collection["@odata.id"], &link) // #nosec G104
+	}
+	if len(link) == 0 {
+		return nil, fmt.Errorf("the resource %s has no %s collection", uri, property)
+	}
+	if _, expanded := collection["Members"]; !expanded {
+		return GetCollection[T, PT](service, link)
+	}
+	members, err := collectionMembers(service, link, collection)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResources[T, PT](service, members)
+}
+
+// linkedExpandQuery returns the $expand query expanding the collections a resource links to along with their
+// members when the service allows two levels, and otherwise the ExpandQuery
+func linkedExpandQuery(service *gofish.Service) string {
+	expand := service.ProtocolFeaturesSupported.ExpandQuery
+	if expand.NoLinks && expand.Levels && expand.MaxLevels >= 2 {
+		return "$expand=.($levels=2)"
+	}
+	return ExpandQuery(service)
+}
+
+// GetReferenced returns the resources referenced by an array property of the resource at the URI, such as
+// the Drives of a storage. The resources are read along with the resource through $expand when the service
+// supports it, and otherwise a few at a time.
+func GetReferenced[T any, PT clientResource[T]](service *gofish.Service, uri, property string) ([]*T, error) {
+	document, err := getDocument(service, uri, ExpandQuery(service))
+	if err != nil {
+		return nil, err
+	}
+	var entries []json.RawMessage
+	if value, ok := document[property]; ok {
+		if err := json.Unmarshal(value, &entries); err != nil {
+			return nil, fmt.Errorf("the property %s of %s is not an array: %w", property, uri, err)
+		}
+	}
+	resources, err := resolveReferences(service, entries)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResources[T, PT](service, resources)
+}
+
+// GetCollectionMembers returns the JSON documents of the members of the collection at the URI, following
+// the next links of the collections split in pages, see GetCollection
+func GetCollectionMembers(service *gofish.Service, uri string) ([]json.RawMessage, error) {
+	document, err := getDocument(service, uri, ExpandQuery(service))
+	if err != nil {
+		return nil, err
+	}
+	return collectionMembers(service, uri, document)
+}
+
+// collectionMembers returns the JSON documents of the members of a collection read from the URI, reading its
+// next pages
+func collectionMembers(service *gofish.Service, uri string, document map[string]json.RawMessage) ([]json.RawMessage, error) {
+	query := ExpandQuery(service)
+	var members []json.RawMessage
+	for {
+		var page []json.RawMessage
+		if err := json.Unmarshal(document["Members"], &page); err != nil {
+			return nil, fmt.Errorf("the resource %s is not a collection", uri)
+		}
+		members = append(members, page...)
+		var next string
+		if nextLink, ok := document["Members@odata.nextLink"]; ok {
+			json.Unmarshal(nextLink, &next) // #nosec G104
+		}
+		if len(next) == 0 {
+			return resolveReferences(service, members)
+		}
+		var err error
+		if document, err = getDocument(service, next, query); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getDocument returns the properties of the resource at the URI, read with the query if any
+func getDocument(service *gofish.Service, uri, query string) (map[string]json.RawMessage, error) {
+	target := uri
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(uri, "?") {
+			separator = "&"
+		}
+		target += separator + query
+	}
+	resp, err := service.GetClient().Get(target)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var document map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", uri, err)
+	}
+	return document, nil
+}
+
+// resolveReferences replaces the references, objects with only an @odata.id, with the resources they designate.
+// The service may have expanded all, some or none of them.
+func resolveReferences(service *gofish.Service, entries []json.RawMessage) ([]json.RawMessage, error) {
+	resources := make([]json.RawMessage, len(entries))
+	uris := make([]string, len(entries))
+	// the entries are all checked before any request, not to leave requests running on an invalid entry
+	for i, entry := range entries {
+		var reference map[string]json.RawMessage
+		if err := json.Unmarshal(entry, &reference); err != nil {
+			return nil, fmt.Errorf("invalid reference %s: %w", entry, err)
+		}
+		if err := json.Unmarshal(reference["@odata.id"], &uris[i]); err != nil || len(reference) != 1 {
+			uris[i] = ""
+			resources[i] = entry
+		}
+	}
+
+	errs := make([]error, len(entries))
+	limiter := make(chan struct{}, CollectionParallelism)
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		if len(uri) == 0 {
+			continue
+		}
+		wg.Add(1)
+		limiter <- struct{}{}
+		go func(i int, uri string) {
+			defer wg.Done()
+			defer func() { <-limiter }()
+			resp, err := service.GetClient().Get(uri)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer resp.Body.Close()
+			errs[i] = json.NewDecoder(resp.Body).Decode(&resources[i])
+		}(i, uri)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+// decodeResources returns the gofish resources of JSON documents
+func decodeResources[T any, PT clientResource[T]](service *gofish.Service, documents []json.RawMessage) ([]*T, error) {
+	result := make([]*T, 0, len(documents))
+	for _, document := range documents {
+		resource := PT(new(T))
+		if err := json.Unmarshal(document, resource); err != nil {
+			return nil, err
+		}
+		resource.SetClient(service.GetClient())
+		result = append(result, (*T)(resource))
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"terraform-provider-redfish/emulator"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	firmwareInventoryURI = "/redfish/v1/UpdateService/FirmwareInventory"
+	raidStorageURI       = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1"
+)
+
+// disableExpand makes the service look as if its root did not advertise $expand
+func disableExpand(service *gofish.Service) {
+	service.ProtocolFeaturesSupported.ExpandQuery.NoLinks = false
+	service.ProtocolFeaturesSupported.ExpandQuery.Levels = false
+}
+
+func TestExpandQuery(t *testing.T) {
+	_, service := emulator.Connect(t)
+	if query := ExpandQuery(service); query != "$expand=.($levels=1)" {
+		t.Errorf("unexpected query %q", query)
+	}
+	service.ProtocolFeaturesSupported.ExpandQuery.Levels = false
+	if query := ExpandQuery(service); query != "$expand=." {
+		t.Errorf("unexpected query %q", query)
+	}
+	disableExpand(service)
+	if query := ExpandQuery(service); query != "" {
+		t.Errorf("unexpected query %q", query)
+	}
+}
+
+func TestGetCollection(t *testing.T) {
+	for _, expand := range []bool{true, false} {
+		s, service := emulator.Connect(t)
+		expected := 1
+		if !expand {
+			disableExpand(service)
+			// the collection, then each of its members
+			expected = 5
+		}
+		start := s.Requests()
+		inventories, err := GetCollection[redfish.SoftwareInventory](service, firmwareInventoryURI)
+		if err != nil {
+			t.Fatalf("unable to read the firmware inventory: %s", err)
+		}
+		if len(inventories) != 4 || len(inventories[0].Version) == 0 {
+			t.Fatalf("unexpected firmware inventory %v", inventories)
+		}
+		if requests := s.Requests() - start; requests != expected {
+			t.Errorf("expand %t: expected %d requests, got %d", expand, expected, requests)
+		}
+	}
+}
+
+func TestGetCollectionPages(t *testing.T) {
+	s, service := emulator.Connect(t)
+	collection, _ := s.Resource(firmwareInventoryURI)
+	members := collection["Members"].([]interface{})
+	collection["Members"] = members[:2]
+	collection["Members@odata.nextLink"] = firmwareInventoryURI + "Page2"
+	s.SetResource(firmwareInventoryURI, collection)
+	s.SetResource(firmwareInventoryURI+"Page2", map[string]interface{}{
+		"@odata.id": firmwareInventoryURI + "Page2",
+		"Members":   members[2:],
+	})
+
+	inventories, err := GetCollection[redfish.SoftwareInventory](service, firmwareInventoryURI)
+	if err != nil {
+		t.Fatalf("unable to read the firmware inventory: %s", err)
+	}
+	if len(inventories) != 4 {
+		t.Errorf("expected the members of both pages, got %d", len(inventories))
+	}
+}
+
+func TestGetReferenced(t *testing.T) {
+	for _, expand := range []bool{true, false} {
+		s, service := emulator.Connect(t)
+		expected := 1
+		if !expand {
+			disableExpand(service)
+			expected = 5
+		}
+		start := s.Requests()
+		drives, err := GetReferenced[redfish.Drive](service, raidStorageURI, "Drives")
+		if err != nil {
+			t.Fatalf("unable to read the drives: %s", err)
+		}
+		if len(drives) != 4 {
+			t.Fatalf("expected 4 drives, got %d", len(drives))
+		}
+		// the order of the references is kept
+		if drives[0].ID != "Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1" {
+			t.Errorf("unexpected first drive %s", drives[0].ID)
+		}
+		if requests := s.Requests() - start; requests != expected {
+			t.Errorf("expand %t: expected %d requests, got %d", expand, expected, requests)
+		}
+	}
+}
+
+func TestResolveReferencesInvalid(t *testing.T) {
+	s, service := emulator.Connect(t)
+	start := s.Requests()
+	entries := []json.RawMessage{
+		json.RawMessage(`{"@odata.id": "` + raidStorageURI + `"}`),
+		json.RawMessage(`"` + raidStorageURI + `"`),
+	}
+	if _, err := resolveReferences(service, entries); err == nil {
+		t.Fatalf("expected an error for an invalid reference")
+	}
+	// no request is left running on the valid reference
+	if requests := s.Requests() - start; requests != 0 {
+		t.Errorf("expected no request, got %d", requests)
+	}
+}
+
+func TestGetLinkedCollection(t *testing.T) {
+	for _, expand := range []bool{true, false} {
+		s, service := emulator.Connect(t)
+		// the system along with its storage collection, then each of its members
+		expected := 3
+		if !expand {
+			disableExpand(service)
+			expected = 4
+		}
+		start := s.Requests()
+		storage, err := GetLinkedCollection[redfish.Storage](service, "/redfish/v1/Systems/System.Embedded.1", "Storage")
+		if err != nil {
+			t.Fatalf("unable to read the storage: %s", err)
+		}
+		if len(storage) != 2 {
+			t.Fatalf("expected 2 storage, got %d", len(storage))
+		}
+		if requests := s.Requests() - start; requests != expected {
+			t.Errorf("expand %t: expected %d requests, got %d", expand, expected, requests)
+		}
+		// the resources can read their own links
+		drives, err := storage[0].Drives()
+		if err != nil || len(drives) == 0 {
+			t.Errorf("unable to read the drives of %s: %v", storage[0].ID, err)
+		}
+
+		if _, err := GetLinkedCollection[redfish.Storage](service, "/redfish/v1/Systems/System.Embedded.1", "Unknown"); err == nil {
+			t.Errorf("expected an error for an unknown collection")
+		}
+	}
+}
+
+func TestLinkedExpandQuery(t *testing.T) {
+	_, service := emulator.Connect(t)
+	if query := linkedExpandQuery(service); query != "$expand=.($levels=1)" {
+		t.Errorf("unexpected query %q", query)
+	}
+	service.ProtocolFeaturesSupported.ExpandQuery.MaxLevels = 2
+	if query := linkedExpandQuery(service); query != "$expand=.($levels=2)" {
+		t.Errorf("unexpected query %q", query)
+	}
+	disableExpand(service)
+	if query := linkedExpandQuery(service); query != "" {
+		t.Errorf("unexpected query %q", query)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/stmcginnis/gofish"
+)
+
+const (
+	// SSECheckInterval is the time between two checks of a job followed with Server-Sent Events (variable is in
+	// seconds). The job is still read from time to time, in case an event is missed.
+	SSECheckInterval int = 120
+	// maxEventSize is the largest event read from the stream
+	maxEventSize = 1 << 20
+)
+
+// jobNotification is sent by a jobEventStream
+type jobNotification int
+
+const (
+	// streamOpened tells that the stream is open, so that the events are received from now on
+	streamOpened jobNotification = iota
+	// jobEvent tells that an event about the job was received
+	jobEvent
+)
+
+// jobEventStream follows the Server-Sent Events stream of a service, the ServerSentEventUri of its EventService,
+// and notifies the events about a job
+type jobEventStream struct {
+	// notifications is closed when the stream is over
+	notifications chan jobNotification
+	done          chan struct{}
+
+	mu     sync.Mutex
+	body   io.Closer
+	closed bool
+	// jobIDs are the IDs of the job, the last part of its URIs
+	jobIDs map[string]bool
+}
+
+// streamedEvent holds the properties of the events needed to tell which job they are about
+type streamedEvent struct {
+	Events []struct {
+		OriginOfCondition struct {
+			ODataID string `json:"@odata.id"`
+		}
+	}
+}
+
+// openJobEvents opens the Server-Sent Events stream of the service to follow the job, or returns nil when the
+// service has none. The stream is opened in the background, and must be closed.
+func openJobEvents(service *gofish.Service, jobURI string) *jobEventStream {
+	eventService, err := service.EventService()
+	if err != nil || len(eventService.ServerSentEventURI) == 0 {
+		return nil
+	}
+	s := &jobEventStream{
+		notifications: make(chan jobNotification),
+		done:          make(chan struct{}),
+		jobIDs:        make(map[string]bool),
+	}
+	s.watch(jobURI)
+	go s.run(service, eventService.ServerSentEventURI)
+	return s
+}
+
+// Notifications returns the channel of the notifications, nil for a nil stream
+func (s *jobEventStream) Notifications() <-chan jobNotification {
+	if s == nil {
+		return nil
+	}
+	return s.notifications
+}
+
+// Close ends the stream
+func (s *jobEventStream) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+	if s.body != nil {
+		s.body.Close() // #nosec G104
+	}
+}
+
+// run reads the stream until it is over or closed
+func (s *jobEventStream) run(service *gofish.Service, uri string) {
+	defer close(s.notifications)
+	resp, err := service.GetClient().GetWithHeaders(uri, map[string]string{"Accept": "text/event-stream"})
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.body = resp.Body
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		resp.Body.Close() // #nosec G104
+		return
+	}
+	if !s.notify(streamOpened) {
+		return
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxEventSize)
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) > 0 {
+			// the data of an event may span several lines, the other fields are not needed
+			if value, isData := strings.CutPrefix(line, "data:"); isData {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+		// an empty line ends the event
+		if len(data) > 0 && s.aboutJob(strings.Join(data, "\n")) && !s.notify(jobEvent) {
+			return
+		}
+		data = nil
+	}
+}
+
+// notify sends the notification, unless the stream is closed
+func (s *jobEventStream) notify(n jobNotification) bool {
+	select {
+	case s.notifications <- n:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// watch adds a URI of the job, such as the URI of its task, to the ones the events are compared with
+func (s *jobEventStream) watch(uri string) {
+	id := path.Base(strings.TrimSuffix(uri, "/"))
+	if len(id) == 0 || id == "." || id == "/" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobIDs[id] = true
+}
+
+// follow adds the URIs of the task or the job read by the waiter, and tells whether the job is identified, so
+// that its events are recognized. A task monitor is only identified once it returns its task.
+func (s *jobEventStream) follow(result *JobResult) bool {
+	switch {
+	case result.Task != nil && len(result.Task.ODataID) > 0:
+		s.watch(result.Task.ODataID)
+	case result.Job != nil && len(result.Job.ODataID) > 0:
+		s.watch(result.Job.ODataID)
+	default:
+		return false
+	}
+	return true
+}
+
+// aboutJob tells whether an event of the stream is about the job. The jobs are compared by their ID, as an
+// event may refer to the job of the job queue of a task, such as /redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_123
+// for /redfish/v1/TaskService/Tasks/JID_123.
+func (s *jobEventStream) aboutJob(data string) bool {
+	var event streamedEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range event.Events {
+		if s.jobIDs[path.Base(strings.TrimSuffix(record.OriginOfCondition.ODataID, "/"))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"terraform-provider-redfish/emulator"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	eventServiceURI = "/redfish/v1/EventService"
+	testSystemURI   = "/redfish/v1/Systems/System.Embedded.1"
+	testStorageURI  = testSystemURI + "/Storage/RAID.Integrated.1-1"
+)
+
+// startJob creates a volume on the emulator and returns the URI of its job
+func startJob(t *testing.T, service *gofish.Service, applyTime string) string {
+	t.Helper()
+	resp, err := service.GetClient().Post(testStorageURI+"/Volumes", map[string]interface{}{
+		"Name":     "TerraformVol1",
+		"RAIDType": "RAID0",
+		"Drives": []interface{}{map[string]interface{}{
+			"@odata.id": testStorageURI + "/Drives/Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1",
+		}},
+		"@Redfish.OperationApplyTime": applyTime,
+	})
+	if err != nil {
+		t.Fatalf("unable to create the volume: %s", err)
+	}
+	resp.Body.Close() // #nosec G104
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.Header.Get("Location")
+}
+
+// disableEvents makes the service look as if its EventService had no Server-Sent Events stream
+func disableEvents(t *testing.T, s *emulator.Server) {
+	t.Helper()
+	eventService, _ := s.Resource(eventServiceURI)
+	delete(eventService, "ServerSentEventUri")
+	s.SetResource(eventServiceURI, eventService)
+}
+
+func TestWaitForJobEvents(t *testing.T) {
+	_, service := emulator.Connect(t)
+	jobURI := startJob(t, service, "Immediate")
+
+	// the job is followed with the events, long before it would be polled
+	start := time.Now()
+	result, err := WaitForJob(context.Background(), service, jobURI, JobWaitOptions{Interval: 60, Timeout: 30})
+	if err != nil {
+		t.Fatalf("the job failed: %s", err)
+	}
+	if result.State != string(redfish.CompletedJobState) {
+		t.Errorf("unexpected state %s", result.State)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("the job took %s", elapsed)
+	}
+}
+
+func TestWaitForJobWithoutEvents(t *testing.T) {
+	s, service := emulator.Connect(t)
+	disableEvents(t, s)
+	jobURI := startJob(t, service, "Immediate")
+
+	result, err := WaitForJob(context.Background(), service, jobURI, JobWaitOptions{Interval: 1, Timeout: 30})
+	if err != nil {
+		t.Fatalf("the job failed: %s", err)
+	}
+	if result.State != string(redfish.CompletedJobState) {
+		t.Errorf("unexpected state %s", result.State)
+	}
+}
+
+func TestWaitForJobStreamDrop(t *testing.T) {
+	s, service := emulator.Connect(t)
+	jobURI := startJob(t, service, "OnReset")
+
+	type outcome struct {
+		result *JobResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := WaitForJob(context.Background(), service, jobURI, JobWaitOptions{Interval: 1, Timeout: 20})
+		done <- outcome{result, err}
+	}()
+
+	// once the stream is gone, the job released by the restart of the server only runs when it is polled
+	time.Sleep(2 * time.Second)
+	s.CloseStreams()
+	system, err := redfish.GetComputerSystem(service.GetClient(), testSystemURI)
+	if err != nil {
+		t.Fatalf("unable to read the system: %s", err)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("unable to restart the system: %s", err)
+	}
+	// the system goes through its power states as it is read
+	for i := 0; i < 5; i++ {
+		if _, err = redfish.GetComputerSystem(service.GetClient(), testSystemURI); err != nil {
+			t.Fatalf("unable to read the system: %s", err)
+		}
+	}
+
+	o := <-done
+	if o.err != nil {
+		t.Fatalf("the job failed: %s", o.err)
+	}
+	if o.result.State != string(redfish.CompletedJobState) {
+		t.Errorf("unexpected state %s", o.result.State)
+	}
+}
+
+func TestJobEventStreamAboutJob(t *testing.T) {
+	s := &jobEventStream{jobIDs: make(map[string]bool)}
+	s.watch("/redfish/v1/TaskService/Tasks/JID_123/")
+	if s.follow(&JobResult{Task: &redfish.Task{}}) {
+		t.Errorf("a task without URI is identified")
+	}
+	tests := []struct {
+		data  string
+		about bool
+	}{
+		{`{"Events":[{"OriginOfCondition":{"@odata.id":"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_123"}}]}`, true},
+		{`{"Events":[{"OriginOfCondition":{"@odata.id":"/redfish/v1/Systems/System.Embedded.1"}},` +
+			`{"OriginOfCondition":{"@odata.id":"/redfish/v1/TaskService/Tasks/JID_123"}}]}`, true},
+		{`{"Events":[{"OriginOfCondition":{"@odata.id":"/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_124"}}]}`, false},
+		{`{"Events":[{"MessageId":"IDRAC.2.8.PR19"}]}`, false},
+		{`not an event`, false},
+	}
+	for _, test := range tests {
+		if about := s.aboutJob(test.data); about != test.about {
+			t.Errorf("aboutJob(%s) = %t, expected %t", test.data, about, test.about)
+		}
+	}
+}
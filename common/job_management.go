@@ -18,13 +18,19 @@ limitations under the License.
 package common
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"net/http"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
 	"github.com/stmcginnis/gofish/redfish"
 )
 
@@ -39,165 +45,331 @@ const (
 	JobErrorWithState = "the job has finished unsucessfully with a %s state"
 	// Percentage to track completion of job
 	Percentage int = 100
+	// dellFailedJobState is the state of failed Dell OEM jobs
+	dellFailedJobState = "Failed"
+	// dellCompletedWithErrorsJobState is the state of Dell OEM jobs completed with errors
+	dellCompletedWithErrorsJobState = "CompletedWithErrors"
+	// dellJobIDPrefix is the prefix of Dell job IDs
+	dellJobIDPrefix = "JID_"
 )
 
-// WaitForTaskToFinish waits for a redfish job to finish.
-// Parameters:
-//   - jobURI -> URI for the job to check.
-//   - timeBetweenAttempts -> time to wait between attempts. I.e. 30 means 30 seconds.
-//   - timeout -> maximun time to wait until job is considered failed.
-func WaitForTaskToFinish(service *gofish.Service, jobURI string, timeBetweenAttempts int64, timeout int64) error {
-	// Create tickers
-	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
-	for {
-		select {
-		case <-attemptTick.C:
-			// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
-			// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
-			job, err := redfish.GetTask(service.GetClient(), jobURI)
-			if err == nil {
-				log.Printf("[DEBUG] - Attempting one more time... Job state is %s\n", job.TaskState)
-				// Check if job has finished
-				switch status := job.TaskState; status {
-				case redfish.CompletedTaskState:
-					return nil
-				case redfish.KilledTaskState:
-					return fmt.Errorf(JobErrorWithState, job.TaskState)
-				case redfish.ExceptionTaskState:
-					return fmt.Errorf(JobErrorWithState, job.TaskState)
-				}
-			}
-		case <-timeoutTick.C:
-			log.Printf("[DEBUG] - Error. Timeout reached\n")
-			return fmt.Errorf("timeout waiting for the job to finish")
+// JobWaitOptions configures how WaitForJob waits for a job
+type JobWaitOptions struct {
+	// Interval is the time between two checks of the job, in seconds
+	Interval int64
+	// Timeout is the maximum time to wait for the job to finish, in seconds
+	Timeout int64
+	// CancelOnInterrupt deletes the job from the BMC when the context is cancelled,
+	// for instance when Terraform is interrupted
+	CancelOnInterrupt bool
+	// PendingOnTimeout returns the job without error if it is still pending or running at timeout.
+	// It is meant for jobs which are only run at the next reboot of the server.
+	PendingOnTimeout bool
+}
+
+// JobResult holds the last known details of a job
+type JobResult struct {
+	// URI is the job, task or task monitor URI
+	URI string
+	// State is the TaskState or JobState of the job, or the Failed or CompletedWithErrors state of a Dell job.
+	// A task monitor which returned the result of its operation is in the Completed state.
+	State string
+	// Status is the TaskStatus or JobStatus of the job
+	Status string
+	// PercentComplete is the progress of the job
+	PercentComplete int
+	// Messages holds the messages of the job, including the Dell OEM job message
+	Messages []redfishcommon.Message
+	// Task is set when the URI points to a task
+	Task *redfish.Task
+	// Job is set when the URI points to a job
+	Job *redfish.Job
+	// Body is the last response body. Once a task monitor is completed, it is the result of the operation.
+	Body []byte
+}
+
+// JobError is returned when a job finishes unsuccessfully
+type JobError struct {
+	URI      string
+	State    string
+	Messages []redfishcommon.Message
+}
+
+// Error implements the error interface
+func (e *JobError) Error() string {
+	msg := fmt.Sprintf(JobErrorWithState, e.State)
+	var details []string
+	for _, m := range e.Messages {
+		switch {
+		case len(m.Message) > 0 && len(m.Resolution) > 0:
+			details = append(details, fmt.Sprintf("%s Resolution: %s", m.Message, m.Resolution))
+		case len(m.Message) > 0:
+			details = append(details, m.Message)
 		}
 	}
+	if len(details) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(details, "; "))
+	}
+	return msg
 }
 
-// WaitForJobToFinish waits for a redfish job to finish.
-// Parameters:
-//   - jobURI -> URI for the job to check.
-//   - timeBetweenAttempts -> time to wait between attempts. I.e. 30 means 30 seconds.
-//   - timeout -> maximun time to wait until job is considered failed.
-func WaitForJobToFinish(service *gofish.Service, jobURI string, timeBetweenAttempts int64, timeout int64) error {
-	// Create tickers
-	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
-	for {
-		select {
-		case <-attemptTick.C:
-			// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
-			// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
-			job, err := redfish.GetJob(service.GetClient(), jobURI)
-			if err == nil {
-				log.Printf("[DEBUG] - Attempting one more time... Job state is %s\n", job.JobState)
-				// Check if job has finished
-				switch status := job.JobState; status {
-				case redfish.CompletedJobState:
-					return nil
-				case redfish.ExceptionJobState:
-					return fmt.Errorf(JobErrorWithState, job.JobState)
-				}
-			}
-		case <-timeoutTick.C:
-			log.Printf("[DEBUG] - Error. Timeout reached\n")
-			return fmt.Errorf("timeout waiting for the job to finish")
-		}
+// jobDocument holds the properties of tasks, jobs and Dell jobs needed to follow them
+type jobDocument struct {
+	TaskState       string
+	TaskStatus      string
+	JobState        string
+	JobStatus       string
+	PercentComplete *int
+	Messages        []redfishcommon.Message
+	// Message, MessageArgs and MessageId are set on Dell jobs of the manager job queue
+	Message     string
+	MessageArgs []string
+	MessageID   string `json:"MessageId"`
+	Oem         DellJob
+}
+
+// WaitForJobResponse waits for the operation started by a request to finish. Redfish services answer
+// asynchronous requests with 202 Accepted and the task monitor in the Location header, which is followed
+// until the operation is over. Any other response is considered completed.
+func WaitForJobResponse(ctx context.Context, service *gofish.Service, resp *http.Response, opts JobWaitOptions) (*JobResult, error) {
+	if resp.StatusCode != http.StatusAccepted {
+		return &JobResult{State: string(redfish.CompletedTaskState)}, nil
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return nil, fmt.Errorf("the request was accepted without task monitor: %w", err)
 	}
+	return WaitForJob(ctx, service, location.EscapedPath(), opts)
 }
 
-// GetJobDetailsOnFinish waits for a redfish job to finish and returns the job details.
-// Parameters:
-//   - jobURI -> URI for the job to check.
-//   - timeBetweenAttempts -> time to wait between attempts. I.e. 30 means 30 seconds.
-//   - timeout -> maximum time to wait until job is considered failed.
-func GetJobDetailsOnFinish(service *gofish.Service, jobURI string, timeBetweenAttempts int64, timeout int64) (*redfish.Job, error) {
-	// Create tickers
-	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
+// WaitForJob waits for a task, a job or a task monitor to finish and returns its last known details.
+// The wait stops when the job finishes, the timeout is reached or the context is cancelled.
+// The returned error is a *JobError when the job finished unsuccessfully, including the Dell jobs completed with
+// errors.
+//
+// When the service has a Server-Sent Events stream, the job is read as soon as an event about it is received,
+// and only polled every SSECheckInterval in case an event is missed. The job is polled every interval again
+// when the stream is not available or drops.
+func WaitForJob(ctx context.Context, service *gofish.Service, jobURI string, opts JobWaitOptions) (*JobResult, error) {
+	interval, timeout := opts.Interval, opts.Timeout
+	if interval <= 0 {
+		interval = int64(TimeBetweenAttempts)
+	}
+	if timeout <= 0 {
+		timeout = int64(Timeout)
+	}
+	pollInterval := time.Duration(interval) * time.Second
+	attemptTick := time.NewTicker(pollInterval)
+	timeoutTick := time.NewTimer(time.Duration(timeout) * time.Second)
 	defer attemptTick.Stop()
 	defer timeoutTick.Stop()
 
-	var job *redfish.Job
-	var err error
+	stream := openJobEvents(service, jobURI)
+	defer stream.Close()
+	notifications := stream.Notifications()
+	// streaming is set once the stream is open, and following once the events of the job are recognized
+	streaming, following := false, false
+
+	result := &JobResult{URI: jobURI}
+	var lastErr error
+	check := func() (bool, error) {
+		// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
+		// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
+		doc, err := getJob(service, result)
+		if err != nil {
+			lastErr = err
+			tflog.Debug(ctx, "Unable to get the job, attempting one more time", map[string]interface{}{
+				"uri":   jobURI,
+				"error": err.Error(),
+			})
+			return false, nil
+		}
+		tflog.Debug(ctx, "Attempting one more time... ", map[string]interface{}{
+			"uri":              jobURI,
+			"state":            result.State,
+			"percent_complete": result.PercentComplete,
+		})
+		if done, err := finished(doc, result); done {
+			return true, err
+		}
+		if streaming && !following && stream.follow(result) {
+			following = true
+			tflog.Debug(ctx, "Following the job with the events of the service", map[string]interface{}{"uri": jobURI})
+			attemptTick.Reset(max(pollInterval, time.Duration(SSECheckInterval)*time.Second))
+		}
+		return false, nil
+	}
 
 	for {
 		select {
-		case <-attemptTick.C:
-			// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
-			// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
-			job, err = redfish.GetJob(service.GetClient(), jobURI)
-			if err == nil {
-				log.Printf("[DEBUG] - Attempting one more time... Job state is %s\n", job.JobState)
-				// Check if job has finished or has some error and return the details
-				if job.JobState == redfish.CompletedJobState && job.PercentComplete == Percentage {
-					return job, nil
-				}
-				if job.JobState == redfish.ExceptionJobState {
-					return job, fmt.Errorf(JobErrorWithState, job.JobState)
-				}
+		case <-ctx.Done():
+			tflog.Warn(ctx, "Interrupted while waiting for the job to finish", map[string]interface{}{"uri": jobURI})
+			if opts.CancelOnInterrupt {
+				cancelJob(ctx, service, jobURI)
 			}
+			return result, fmt.Errorf("interrupted while waiting for the job to finish: %w", ctx.Err())
 		case <-timeoutTick.C:
-			if job == nil {
-				return nil, fmt.Errorf("Job details not available. Possible timeout")
+			return jobTimeout(ctx, result, lastErr, opts, timeout)
+		case notification, open := <-notifications:
+			switch {
+			case !open:
+				tflog.Debug(ctx, "The event stream of the service is not available, polling the job", map[string]interface{}{"uri": jobURI})
+				notifications, streaming = nil, false
+				if following {
+					following = false
+					attemptTick.Reset(pollInterval)
+				}
+			case notification == streamOpened:
+				streaming = true
 			}
-			if job.JobState == redfish.StartingJobState ||
-				job.JobState == redfish.RunningJobState ||
-				job.JobState == redfish.PendingJobState ||
-				job.JobState == redfish.NewJobState {
-				return job, nil
+			// The job may have finished before the stream was opened, or while it was down
+			if done, err := check(); done {
+				return result, err
+			}
+		case <-attemptTick.C:
+			if done, err := check(); done {
+				return result, err
 			}
-			log.Printf("[DEBUG] - Error. Timeout reached\n")
-			return nil, fmt.Errorf("Job wait timed out after %d minutes", timeout/60)
 		}
 	}
 }
 
-// GetJobAttachment waits for a redfish job to finish and returns the job attachment.
-func GetJobAttachment(service *gofish.Service, jobURI string, timeBetweenAttempts int64, timeout int64) ([]byte, error) {
-	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
-	defer attemptTick.Stop()
-	defer timeoutTick.Stop()
-	for {
-		select {
-		case <-attemptTick.C:
-			// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
-			// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
-			resp, err := service.GetClient().Get(jobURI)
-			if err != nil {
-				return nil, fmt.Errorf("error making request: %w", err)
-			}
+// getJob refreshes the job details and returns the job document, which is nil when the task monitor answered
+// with the result of its operation
+func getJob(service *gofish.Service, result *JobResult) (*jobDocument, error) {
+	resp, err := service.GetClient().Get(result.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+	result.Body = body
 
-			if resp.StatusCode == StatusCodeSuccess {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("error reading body: %w", err)
-				}
-				if resp.Body != nil {
-					err = resp.Body.Close()
-					if err != nil {
-						return nil, fmt.Errorf("error closing body: %w", err)
-					}
-				}
-				return body, nil
-			}
+	var doc jobDocument
+	if err := json.Unmarshal(body, &doc); err != nil || (doc.TaskState == "" && doc.JobState == "") {
+		// Not a job: the task monitor answers 202 Accepted as long as the operation is running,
+		// and then returns the result of the operation
+		if resp.StatusCode != http.StatusAccepted {
+			result.State, result.Status = string(redfish.CompletedTaskState), ""
+			result.PercentComplete = Percentage
+			result.Task, result.Job = nil, nil
+		}
+		return nil, nil
+	}
 
-		case <-timeoutTick.C:
-			return nil, fmt.Errorf("job wait timed out after %d minutes", timeout/60)
+	result.State, result.Status = doc.TaskState, doc.TaskStatus
+	result.Task, result.Job = nil, nil
+	if doc.TaskState != "" {
+		result.Task = &redfish.Task{}
+		err = json.Unmarshal(body, result.Task)
+	} else {
+		result.State, result.Status = doc.JobState, doc.JobStatus
+		result.Job = &redfish.Job{}
+		err = json.Unmarshal(body, result.Job)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.PercentComplete = 0
+	if doc.PercentComplete != nil {
+		result.PercentComplete = *doc.PercentComplete
+	}
+	result.Messages = doc.Messages
+	if len(doc.Message) > 0 {
+		result.Messages = appendMessage(result.Messages, redfishcommon.Message{
+			Message: doc.Message, MessageArgs: doc.MessageArgs, MessageID: doc.MessageID,
+		})
+	}
+	if len(doc.Oem.Dell.Message) > 0 {
+		result.Messages = appendMessage(result.Messages, redfishcommon.Message{
+			Message: doc.Oem.Dell.Message, MessageArgs: doc.Oem.Dell.MessageArgs, MessageID: doc.Oem.Dell.MessageId,
+		})
+	}
+	// the tasks of Dell jobs are Completed or in Exception, their Oem tells how the job ended
+	switch doc.Oem.Dell.JobState {
+	case dellFailedJobState, dellCompletedWithErrorsJobState:
+		result.State = doc.Oem.Dell.JobState
+	}
+	return &doc, nil
+}
+
+// appendMessage appends a message of a Dell job, unless the job already has it, as the Dell jobs repeat their
+// message in their Messages and Oem
+func appendMessage(messages []redfishcommon.Message, message redfishcommon.Message) []redfishcommon.Message {
+	for _, m := range messages {
+		if m.Message == message.Message {
+			return messages
+		}
+	}
+	return append(messages, message)
+}
+
+// finished tells whether the job is over, and returns a *JobError if it was not successful. The job document
+// is nil once the task monitor returned the result of the operation.
+func finished(doc *jobDocument, result *JobResult) (bool, error) {
+	switch result.State {
+	case string(redfish.CompletedTaskState):
+		// jobs reporting their progress are only over once it reaches 100 percent
+		if doc != nil && doc.JobState != "" && doc.PercentComplete != nil && *doc.PercentComplete < Percentage {
+			return false, nil
+		}
+		return true, nil
+	case string(redfish.KilledTaskState), string(redfish.ExceptionTaskState), string(redfish.CancelledTaskState),
+		string(redfish.InterruptedTaskState), dellFailedJobState, dellCompletedWithErrorsJobState:
+		return true, &JobError{URI: result.URI, State: result.State, Messages: result.Messages}
+	}
+	return false, nil
+}
+
+// jobTimeout returns the result of a wait which reached its timeout
+func jobTimeout(ctx context.Context, result *JobResult, lastErr error, opts JobWaitOptions, timeout int64) (*JobResult, error) {
+	tflog.Debug(ctx, "Error. Timeout reached", map[string]interface{}{"uri": result.URI})
+	if result.State == "" {
+		if lastErr != nil {
+			return result, fmt.Errorf("job details not available after %d seconds: %w", timeout, lastErr)
 		}
+		return result, fmt.Errorf("job details not available after %d seconds", timeout)
+	}
+	if opts.PendingOnTimeout {
+		return result, nil
+	}
+	return result, fmt.Errorf("timeout waiting for the job to finish after %d seconds, job state is %s", timeout, result.State)
+}
+
+// cancelJob deletes the job from the BMC. Services which do not support DELETE on tasks
+// and task monitors get the job deleted from the Dell job queue instead.
+func cancelJob(ctx context.Context, service *gofish.Service, jobURI string) {
+	resp, err := service.GetClient().Delete(jobURI)
+	if err == nil {
+		resp.Body.Close() // #nosec G104
+		tflog.Info(ctx, "The job has been cancelled", map[string]interface{}{"uri": jobURI})
+		return
 	}
+	if id := path.Base(jobURI); strings.HasPrefix(id, dellJobIDPrefix) {
+		err = DeleteDellJob(service, id)
+	}
+	if err != nil {
+		tflog.Warn(ctx, "Unable to cancel the job", map[string]interface{}{
+			"uri":   jobURI,
+			"error": err.Error(),
+		})
+		return
+	}
+	tflog.Info(ctx, "The job has been deleted", map[string]interface{}{"uri": jobURI})
 }
 
 // OEMJob contains the job details
 type OEMJob struct {
-	JobState       string `json:"JobState"`
-	JobType        string `json:"JobType"`
-	Message        string `json:"Message"`
-	MessageId      string `json:"MessageId"`
-	Name           string `json:"Name"`
-	CompletionTime string `json:"CompletionTime"`
+	JobState       string   `json:"JobState"`
+	JobType        string   `json:"JobType"`
+	Message        string   `json:"Message"`
+	MessageArgs    []string `json:"MessageArgs"`
+	MessageId      string   `json:"MessageId"`
+	Name           string   `json:"Name"`
+	CompletionTime string   `json:"CompletionTime"`
 }
 
 // Dell contains the job details
@@ -210,58 +382,32 @@ type DellJob struct {
 	Dell Dell
 }
 
-// WaitForDellJobToFinish waits for a redfish job to finish and returns the job details.
-func WaitForDellJobToFinish(service *gofish.Service, jobURI string, timeBetweenAttempts int64, timeout int64) error {
-	var oemJob DellJob
-	// Create tickers
-	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
-	for {
-		select {
-		case <-attemptTick.C:
-			// For some reason iDRAC 4.40.00.0 from time to time gives the following error:
-			// iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
-			job, err := redfish.GetTask(service.GetClient(), jobURI)
-			if err == nil {
-				// Check if job has finished
-				switch status := job.TaskState; status {
-				case redfish.CompletedTaskState:
-					if job.Oem != nil {
-						err = json.Unmarshal(job.Oem, &oemJob)
-						if err != nil {
-							return err
-						}
-						if oemJob.Dell.JobState == "Failed" {
-							return fmt.Errorf("job failed with message: %s", oemJob.Dell.Message)
-						}
-					}
-					return nil
-				case redfish.KilledTaskState:
-					return fmt.Errorf(JobErrorWithState, job.TaskState)
-				case redfish.ExceptionTaskState:
-					return fmt.Errorf(JobErrorWithState, job.TaskState)
-				}
-			}
-		case <-timeoutTick.C:
-			return fmt.Errorf("timeout waiting for the job to finish")
-		}
-	}
-}
-
 // DeleteDellJob is intended to delete a task schedules in a Dell system.
-// This function is only a workaround until HTTP DELETE is supported under each task o taskmonitor
+// This function is only a workaround until HTTP DELETE is supported under each task o taskmonitor.
+// The job is looked for in the job queue of each manager of the service, as the task does not
+// tell which manager holds it.
 //
 //	Parameters:
 //	- taskID: Id of the tasks to delete
 func DeleteDellJob(service *gofish.Service, taskID string) error {
-	url := "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/"
-	resp, err := service.GetClient().Delete(fmt.Sprintf("%s%s", url, taskID))
+	managers, err := service.Managers()
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != StatusCodeSuccess {
-		return fmt.Errorf(" error when deleting the task, Delete status code was %d", resp.StatusCode)
+	for _, manager := range managers {
+		resp, err := service.GetClient().Delete(fmt.Sprintf("%s/Jobs/%s", strings.TrimSuffix(manager.ODataID, "/"), taskID))
+		var redfishErr *redfishcommon.Error
+		if errors.As(err, &redfishErr) && redfishErr.HTTPReturnedStatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		resp.Body.Close() // #nosec G104
+		if resp.StatusCode != StatusCodeSuccess {
+			return fmt.Errorf(" error when deleting the task, Delete status code was %d", resp.StatusCode)
+		}
+		return nil
 	}
-	return nil
+	return fmt.Errorf("job %s not found in the job queues of the managers", taskID)
 }
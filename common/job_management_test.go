@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2020-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stmcginnis/gofish"
+)
+
+const monitorURI = "/redfish/v1/TaskService/TaskMonitors/JID_123"
+
+// monitorResponse is an answer of the task monitor
+type monitorResponse struct {
+	status int
+	body   string
+}
+
+// connectMonitor starts a service whose task monitor gives the responses in turn, the last one being repeated
+func connectMonitor(t *testing.T, responses ...monitorResponse) *gofish.Service {
+	t.Helper()
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != monitorURI {
+			w.Write([]byte(`{"@odata.id": "/redfish/v1/", "Id": "RootService"}`)) // #nosec G104
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		response := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body)) // #nosec G104
+	}))
+	t.Cleanup(server.Close)
+	client, err := gofish.Connect(gofish.ClientConfig{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	return client.Service
+}
+
+func TestWaitForJobTaskMonitor(t *testing.T) {
+	running := monitorResponse{http.StatusAccepted, `{"TaskState": "Running", "TaskStatus": "OK", "PercentComplete": 50}`}
+	tests := []struct {
+		name      string
+		responses []monitorResponse
+		state     string
+		body      string
+		// err is the state of the *JobError, if the job is not successful
+		err string
+	}{
+		{
+			name:      "result",
+			responses: []monitorResponse{running, {http.StatusOK, `<SystemConfiguration Model="PowerEdge R640"/>`}},
+			state:     "Completed",
+			body:      `<SystemConfiguration Model="PowerEdge R640"/>`,
+		},
+		{
+			name:      "JSON result",
+			responses: []monitorResponse{running, {http.StatusCreated, `{"@odata.id": "/redfish/v1/AccountService/Accounts/3"}`}},
+			state:     "Completed",
+			body:      `{"@odata.id": "/redfish/v1/AccountService/Accounts/3"}`,
+		},
+		{
+			name:      "accepted without task",
+			responses: []monitorResponse{{http.StatusAccepted, ""}, {http.StatusOK, "{}"}},
+			state:     "Completed",
+			body:      "{}",
+		},
+		{
+			name:      "completed task",
+			responses: []monitorResponse{running, {http.StatusOK, `{"TaskState": "Completed", "TaskStatus": "OK"}`}},
+			state:     "Completed",
+		},
+		{
+			name: "completed job at 100 percent",
+			responses: []monitorResponse{
+				{http.StatusOK, `{"JobState": "Completed", "PercentComplete": 90}`},
+				{http.StatusOK, `{"JobState": "Completed", "PercentComplete": 100}`},
+			},
+			state: "Completed",
+			body:  `{"JobState": "Completed", "PercentComplete": 100}`,
+		},
+		{
+			name: "completed with errors",
+			responses: []monitorResponse{running, {http.StatusOK, `{"TaskState": "Completed", "TaskStatus": "Warning",
+				"Oem": {"Dell": {"JobState": "CompletedWithErrors", "Message": "Unable to apply some settings."}}}`}},
+			state: "CompletedWithErrors",
+			err:   "CompletedWithErrors",
+		},
+		{
+			name:      "Dell job completed with errors",
+			responses: []monitorResponse{{http.StatusOK, `{"JobState": "CompletedWithErrors", "PercentComplete": 100}`}},
+			state:     "CompletedWithErrors",
+			err:       "CompletedWithErrors",
+		},
+		{
+			name:      "interrupted",
+			responses: []monitorResponse{running, {http.StatusOK, `{"TaskState": "Interrupted", "TaskStatus": "Warning"}`}},
+			state:     "Interrupted",
+			err:       "Interrupted",
+		},
+		{
+			name:      "exception",
+			responses: []monitorResponse{running, {http.StatusOK, `{"TaskState": "Exception", "TaskStatus": "Critical"}`}},
+			state:     "Exception",
+			err:       "Exception",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			service := connectMonitor(t, test.responses...)
+			result, err := WaitForJob(context.Background(), service, monitorURI, JobWaitOptions{Interval: 1, Timeout: 10})
+			if len(test.err) > 0 {
+				var jobErr *JobError
+				if !errors.As(err, &jobErr) || jobErr.State != test.err {
+					t.Fatalf("expected a job error with the state %s, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("the job failed: %s", err)
+			}
+			if result.State != test.state {
+				t.Errorf("expected the state %s, got %s", test.state, result.State)
+			}
+			if len(test.body) > 0 && string(result.Body) != test.body {
+				t.Errorf("expected the body %s, got %s", test.body, result.Body)
+			}
+		})
+	}
+}
+
+func TestWaitForJobRunningTimeout(t *testing.T) {
+	service := connectMonitor(t, monitorResponse{http.StatusOK, `{"JobState": "Completed", "PercentComplete": 50}`})
+	result, err := WaitForJob(context.Background(), service, monitorURI, JobWaitOptions{Interval: 1, Timeout: 2})
+	if err == nil {
+		t.Fatalf("expected a timeout for a job which does not reach 100 percent")
+	}
+	if result.PercentComplete != 50 {
+		t.Errorf("unexpected progress %d", result.PercentComplete)
+	}
+
+	service = connectMonitor(t, monitorResponse{http.StatusOK, `{"JobState": "Running", "PercentComplete": 50}`})
+	result, err = WaitForJob(context.Background(), service, monitorURI, JobWaitOptions{Interval: 1, Timeout: 2, PendingOnTimeout: true})
+	if err != nil || result.State != "Running" {
+		t.Errorf("expected the running job to be returned, got %s %v", result.State, err)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// accountSlots is the number of accounts of the iDRAC. The first one is reserved.
+const accountSlots = 16
+
+// accountRoles are the roles of the accounts
+var accountRoles = []interface{}{"Administrator", "Operator", "ReadOnly", "None"}
+
+// initAccounts creates the slots of the accounts, the second one being the administrator
+func (s *Server) initAccounts(username, password string) {
+	for i := 1; i <= accountSlots; i++ {
+		account := map[string]interface{}{
+			"@odata.type":            "#ManagerAccount.v1_8_0.ManagerAccount",
+			"Id":                     strconv.Itoa(i),
+			"Name":                   "User Account",
+			"Description":            "User Account",
+			"UserName":               "",
+			"Password":               nil,
+			"RoleId":                 "None",
+			"Enabled":                false,
+			"Locked":                 false,
+			"PasswordChangeRequired": false,
+			"AccountTypes":           []interface{}{"Redfish", "SNMP", "OEM", "HostConsole", "ManagerConsole", "IPMI", "KVMIP", "VirtualConsole", "VirtualMedia", "WebUI"},
+			"Links":                  map[string]interface{}{"Role": link(serviceRootURI + "/AccountService/Roles/None")},
+		}
+		if i == 2 {
+			account["UserName"], account["RoleId"], account["Enabled"] = username, "Administrator", true
+			account["Links"] = map[string]interface{}{"Role": link(serviceRootURI + "/AccountService/Roles/Administrator")}
+			s.passwords[account["Id"].(string)] = password
+		}
+		s.addMember(accountsURI, account)
+	}
+}
+
+// checkCredentials tells whether the credentials are the ones of an enabled account
+func (s *Server) checkCredentials(username, password string) bool {
+	for id, p := range s.passwords {
+		account := s.resources[accountsURI+"/"+id]
+		if account["UserName"] == username && account["Enabled"] == true && p == password && len(username) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// patchAccount implements the changes of an account. The user name must be unique, and the password is never returned.
+func (s *Server) patchAccount(r *request) (*response, error) {
+	account, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if account["Id"] == "1" {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyNotWritable", []string{"UserName"}, "#/UserName")
+	}
+	password, hasPassword := body["Password"]
+	delete(body, "Password")
+	if err := checkPatch(account, body, ""); err != nil {
+		return nil, err
+	}
+	if err := s.checkAccount(account, body, password, hasPassword); err != nil {
+		return nil, err
+	}
+
+	id := account["Id"].(string)
+	if hasPassword {
+		s.passwords[id] = toString(password)
+	}
+	merge(account, body)
+	if role, isSet := body["RoleId"]; isSet {
+		account["Links"] = map[string]interface{}{"Role": link(serviceRootURI + "/AccountService/Roles/" + toString(role))}
+	}
+	if account["UserName"] == "" {
+		delete(s.passwords, id)
+	}
+	return ok(successBody()), nil
+}
+
+// createAccount adds an account to the collection of accounts, once UseAccountCollection has been called.
+// The password is never returned.
+func (s *Server) createAccount(body map[string]interface{}) (*response, error) {
+	password, hasPassword := body["Password"]
+	delete(body, "Password")
+	delete(body, "Id")
+	if err := s.checkAccount(map[string]interface{}{}, body, password, hasPassword); err != nil {
+		return nil, err
+	}
+	account := map[string]interface{}{
+		"@odata.type": "#ManagerAccount.v1_8_0.ManagerAccount",
+		"Name":        "User Account",
+		"RoleId":      "None",
+		"Enabled":     false,
+		"Locked":      false,
+	}
+	merge(account, body)
+	account["Links"] = map[string]interface{}{"Role": link(serviceRootURI + "/AccountService/Roles/" + toString(account["RoleId"]))}
+	account = s.addMember(accountsURI, account)
+	if hasPassword {
+		s.passwords[account["Id"].(string)] = toString(password)
+	}
+	return &response{
+		status:  http.StatusCreated,
+		headers: map[string]string{"Location": account["@odata.id"].(string)},
+		body:    account,
+	}, nil
+}
+
+// checkAccount validates the changes of an account
+func (s *Server) checkAccount(account, body map[string]interface{}, password interface{}, hasPassword bool) error {
+	if name, isSet := body["UserName"]; isSet {
+		username, isString := name.(string)
+		if !isString || len(username) > 16 {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueError", []string{"UserName"}, "#/UserName")
+		}
+		for _, uri := range linkURIs(s.resources[accountsURI]["Members"].([]interface{})) {
+			other := s.resources[toString(uri)]
+			if len(username) > 0 && other["UserName"] == username && other["Id"] != account["Id"] {
+				return newError(http.StatusBadRequest, "Base.1.12.ResourceAlreadyExists",
+					[]string{"ManagerAccount", "UserName", username}, "#/UserName")
+			}
+		}
+	}
+	if role, isSet := body["RoleId"]; isSet && !contains(accountRoles, role) {
+		return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(role), "RoleId"}, "#/RoleId")
+	}
+	if value, isSet := body["Enabled"]; isSet {
+		if _, isBool := value.(bool); !isBool {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueTypeError", []string{toString(value), "Enabled"}, "#/Enabled")
+		}
+	}
+	if hasPassword {
+		text, isString := password.(string)
+		if !isString || len(text) < 4 || len(text) > 40 {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueError", []string{"Password"}, "#/Password")
+		}
+	}
+	return nil
+}
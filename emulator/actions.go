@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type actionHandler func(s *Server, r *request, body map[string]interface{}) (*response, error)
+
+// actions are the handlers of the actions by name. An action is only run on the resources declaring it.
+var actions = map[string]actionHandler{
+	"ComputerSystem.Reset":                                   (*Server).resetSystem,
+	"Manager.Reset":                                          (*Server).resetManager,
+	"VirtualMedia.InsertMedia":                               (*Server).insertMedia,
+	"VirtualMedia.EjectMedia":                                (*Server).ejectMedia,
+	"DelliDRACCardService.ImportSSLCertificate":              (*Server).importSSLCertificate,
+	"DelliDRACCardService.SSLResetCfg":                       (*Server).resetSSLConfig,
+	"DellJobService.DeleteJobQueue":                          (*Server).deleteJobQueue,
+	"EID_674_Manager.ExportSystemConfiguration":              (*Server).exportSystemConfiguration,
+	"EID_674_Manager.ImportSystemConfiguration":              (*Server).importSystemConfiguration,
+	"UpdateService.SimpleUpdate":                             (*Server).simpleUpdate,
+	"DellSoftwareInstallationService.InstallFromRepository":  (*Server).installFromRepository,
+	"DellSoftwareInstallationService.GetRepoBasedUpdateList": (*Server).getRepoBasedUpdateList,
+	"EventService.SubmitTestEvent":                           (*Server).submitTestEvent,
+}
+
+// postAction runs the action at the URI of the request
+func (s *Server) postAction(r *request) (*response, error) {
+	handle, exists := actions[actionName(r)]
+	res, found := s.resources[actionResource(r.uri)]
+	if !exists || !found || !declaresTarget(res["Actions"], r.uri) {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionNotSupported", []string{actionName(r)})
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return handle(s, r, body)
+}
+
+// actionName returns the name of the action at the URI of the request
+func actionName(r *request) string {
+	if len(r.params) == 0 {
+		return r.uri
+	}
+	return r.params[0]
+}
+
+// actionResource returns the URI of the resource of an action target
+func actionResource(target string) string {
+	return target[:strings.LastIndex(target, "/Actions/")]
+}
+
+// declaresTarget tells whether the Actions property holds the target
+func declaresTarget(actions interface{}, target string) bool {
+	switch value := actions.(type) {
+	case map[string]interface{}:
+		if value["target"] == target {
+			return true
+		}
+		for _, v := range value {
+			if declaresTarget(v, target) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range value {
+			if declaresTarget(v, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// actionParameter returns a required string parameter of the action, which must be one of its allowable values if the
+// action declares them
+func actionParameter(res map[string]interface{}, r *request, body map[string]interface{}, name string) (string, error) {
+	value, isString := body[name].(string)
+	if !isString || len(value) == 0 {
+		return "", newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{actionName(r), name})
+	}
+	allowed, declared := allowableValues(res["Actions"], r.uri, name)
+	if declared && !contains(allowed, value) {
+		return "", newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			[]string{value, name, actionName(r)}, "#/"+name)
+	}
+	return value, nil
+}
+
+// allowableValues returns the allowable values of the parameter declared by the action of the target
+func allowableValues(actions interface{}, target, name string) ([]interface{}, bool) {
+	value, isMap := actions.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+	if value["target"] == target {
+		allowed, declared := value[name+"@Redfish.AllowableValues"].([]interface{})
+		return allowed, declared
+	}
+	for _, v := range value {
+		if allowed, declared := allowableValues(v, target, name); declared {
+			return allowed, true
+		}
+	}
+	return nil, false
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
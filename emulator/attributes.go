@@ -0,0 +1,266 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+)
+
+const (
+	biosRegistryURI    = serviceRootURI + "/Registries/BiosAttributeRegistry/BiosAttributeRegistry.json"
+	managerRegistryURI = serviceRootURI + "/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json"
+)
+
+// attributeSet is a set of attributes checked against an attribute registry
+type attributeSet struct {
+	registry map[string]interface{}
+	// values are the current values of the attributes
+	values map[string]interface{}
+	// secretKey prefixes the keys of the Password values in Server.secrets
+	secretKey string
+}
+
+// registryEntries returns the attributes of the registry by name, and its dependencies
+func (a attributeSet) registryEntries() (map[string]map[string]interface{}, []interface{}) {
+	entries, _ := a.registry["RegistryEntries"].(map[string]interface{})
+	list, _ := entries["Attributes"].([]interface{})
+	attributes := make(map[string]map[string]interface{}, len(list))
+	for _, e := range list {
+		if entry, isMap := e.(map[string]interface{}); isMap {
+			attributes[fmt.Sprint(entry["AttributeName"])] = entry
+		}
+	}
+	dependencies, _ := entries["Dependencies"].([]interface{})
+	return attributes, dependencies
+}
+
+// check validates the values to set, and returns the messages of the invalid ones
+func (a attributeSet) check(values map[string]interface{}) error {
+	attributes, dependencies := a.registryEntries()
+	// The dependencies are evaluated with the values after the change
+	next := make(map[string]interface{}, len(a.values))
+	for k, v := range a.values {
+		next[k] = v
+	}
+	for k, v := range values {
+		next[k] = v
+	}
+
+	var messages []map[string]interface{}
+	for _, name := range sortedKeys(values) {
+		pointer := "#/Attributes/" + name
+		entry, exists := attributes[name]
+		if _, present := a.values[name]; !exists || !present {
+			messages = append(messages, newMessage("Base.1.12.PropertyUnknown", []string{name}, pointer))
+			continue
+		}
+		if readOnly(name, entry, dependencies, next) {
+			messages = append(messages, newMessage("Base.1.12.PropertyNotWritable", []string{name}, pointer))
+			continue
+		}
+		if message := checkValue(name, entry, values[name]); message != nil {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) > 0 {
+		return &redfishError{status: http.StatusBadRequest, messages: messages}
+	}
+	return nil
+}
+
+// setAttributes stores the values, keeping the Password ones as secrets
+func (s *Server) setAttributes(a attributeSet, values map[string]interface{}) {
+	attributes, _ := a.registryEntries()
+	for name, value := range values {
+		if attributes[name]["Type"] == "Password" {
+			s.secrets[a.secretKey+name] = value
+			continue
+		}
+		a.values[name] = value
+	}
+}
+
+// checkValue checks the value against the type and the bounds of the registry entry
+func checkValue(name string, entry map[string]interface{}, value interface{}) map[string]interface{} {
+	pointer := "#/Attributes/" + name
+	text := fmt.Sprint(value)
+	typeError := newMessage("Base.1.12.PropertyValueTypeError", []string{text, name}, pointer)
+	switch entry["Type"] {
+	case "Enumeration":
+		str, isString := value.(string)
+		if !isString {
+			return typeError
+		}
+		values, _ := entry["Value"].([]interface{})
+		for _, v := range values {
+			allowed, _ := v.(map[string]interface{})
+			if allowed["ValueName"] == str || allowed["ValueDisplayName"] == str {
+				return nil
+			}
+		}
+		return newMessage("Base.1.12.PropertyValueNotInList", []string{text, name}, pointer)
+	case "Integer":
+		number, isNumber := value.(float64)
+		if !isNumber || number != math.Trunc(number) {
+			return typeError
+		}
+		lower, hasLower := entry["LowerBound"].(float64)
+		upper, hasUpper := entry["UpperBound"].(float64)
+		if (hasLower && number < lower) || (hasUpper && number > upper) {
+			return newMessage("Base.1.12.PropertyValueError", []string{name}, pointer)
+		}
+	case "String", "Password":
+		str, isString := value.(string)
+		if !isString {
+			return typeError
+		}
+		if !checkLength(entry, str) || !checkExpression(entry, str) {
+			return newMessage("Base.1.12.PropertyValueError", []string{name}, pointer)
+		}
+	case "Boolean":
+		if _, isBool := value.(bool); !isBool {
+			return typeError
+		}
+	}
+	return nil
+}
+
+func checkLength(entry map[string]interface{}, value string) bool {
+	minLength, hasMin := entry["MinLength"].(float64)
+	maxLength, hasMax := entry["MaxLength"].(float64)
+	return (!hasMin || len(value) >= int(minLength)) && (!hasMax || len(value) <= int(maxLength))
+}
+
+// checkExpression matches the value with the regular expression of the entry, named ValueExpression
+// in the BIOS registry and Regex in the iDRAC one
+func checkExpression(entry map[string]interface{}, value string) bool {
+	for _, key := range []string{"ValueExpression", "Regex"} {
+		if expression, hasExpression := entry[key].(string); hasExpression && len(expression) > 0 {
+			re, err := regexp.Compile(expression)
+			if err == nil && !re.MatchString(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readOnly tells whether the attribute can be written, once the Map dependencies setting its ReadOnly
+// property are evaluated with the values
+func readOnly(name string, entry map[string]interface{}, dependencies []interface{}, values map[string]interface{}) bool {
+	result := entry["ReadOnly"] == true || entry["Readonly"] == true
+	for _, d := range dependencies {
+		dependency, _ := d.(map[string]interface{})
+		if dependency["DependencyFor"] != name || dependency["Type"] != "Map" {
+			continue
+		}
+		mapping, _ := dependency["Dependency"].(map[string]interface{})
+		if mapping["MapToProperty"] != "ReadOnly" {
+			continue
+		}
+		terms, _ := mapping["MapFrom"].([]interface{})
+		if matches(terms, values) {
+			result = mapping["MapToValue"] == true
+		}
+	}
+	return result
+}
+
+// matches evaluates the MapFrom terms of a dependency, combined by their MapTerms from left to right
+func matches(terms []interface{}, values map[string]interface{}) bool {
+	result := false
+	for i, t := range terms {
+		term, _ := t.(map[string]interface{})
+		current := condition(term, values[fmt.Sprint(term["MapFromAttribute"])])
+		switch {
+		case i == 0:
+			result = current
+		case term["MapTerms"] == "OR":
+			result = result || current
+		default:
+			result = result && current
+		}
+	}
+	return result
+}
+
+// condition evaluates the MapFromCondition of a term on the current value of its attribute
+func condition(term map[string]interface{}, value interface{}) bool {
+	expected := term["MapFromValue"]
+	number, isNumber := value.(float64)
+	bound, isBound := expected.(float64)
+	switch term["MapFromCondition"] {
+	case "EQU":
+		return fmt.Sprint(value) == fmt.Sprint(expected)
+	case "NEQ":
+		return fmt.Sprint(value) != fmt.Sprint(expected)
+	case "GTR":
+		return isNumber && isBound && number > bound
+	case "GEQ":
+		return isNumber && isBound && number >= bound
+	case "LSS":
+		return isNumber && isBound && number < bound
+	case "LEQ":
+		return isNumber && isBound && number <= bound
+	}
+	return false
+}
+
+// patchDellAttributes implements the PATCH of the iDRAC, System and LifecycleController attributes,
+// which are applied immediately
+func (s *Server) patchDellAttributes(r *request) (*response, error) {
+	res, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	values, err := attributesOf(body)
+	if err != nil {
+		return nil, err
+	}
+	set := attributeSet{
+		registry:  s.resources[managerRegistryURI],
+		values:    res["Attributes"].(map[string]interface{}),
+		secretKey: r.uri + "#",
+	}
+	if err := set.check(values); err != nil {
+		return nil, err
+	}
+	s.setAttributes(set, values)
+	return ok(successBody()), nil
+}
+
+// attributesOf returns the Attributes of a settings PATCH, which must not have other properties
+func attributesOf(body map[string]interface{}) (map[string]interface{}, error) {
+	for name := range body {
+		if name != "Attributes" && name != "@Redfish.SettingsApplyTime" && name != "@Redfish.OperationApplyTime" {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyUnknown", []string{name}, "#/"+name)
+		}
+	}
+	values, isMap := body["Attributes"].(map[string]interface{})
+	if !isMap {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyMissing", []string{"Attributes"}, "#/Attributes")
+	}
+	return values, nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish"
+)
+
+// Connect starts an emulator for a test, with the root/calvin administrator account, and returns it
+// with a service connected to it. The setups change the emulator before the connection, e.g. to
+// emulate another vendor from the service root. The emulator is closed at the end of the test.
+func Connect(t testing.TB, setups ...func(s *Server)) (*Server, *gofish.Service) {
+	t.Helper()
+	s, err := New("root", "calvin")
+	if err != nil {
+		t.Fatalf("unable to start the emulator: %s", err)
+	}
+	t.Cleanup(s.Close)
+	for _, setup := range setups {
+		setup(s)
+	}
+	client, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:  s.URL(),
+		Username:  "root",
+		Password:  "calvin",
+		Insecure:  true,
+		BasicAuth: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	return s, client.Service
+}
@@ -0,0 +1,422 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package emulator serves a stateful Redfish service modelled on a Dell PowerEdge server managed by
+// an iDRAC 6.x, so that the acceptance tests of the provider can run without hardware.
+//
+// The emulator keeps the resources in memory and changes them the way the iDRAC does: settings
+// PATCHes and storage operations create jobs which go from Scheduled to Running to Completed
+// as they are polled, or by themselves while a Server-Sent Events stream is open, jobs applied on
+// reset wait for the server to be restarted, and power actions go through the transitional power
+// states.
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	serviceRootURI    = "/redfish/v1"
+	systemURI         = serviceRootURI + "/Systems/System.Embedded.1"
+	managerURI        = serviceRootURI + "/Managers/iDRAC.Embedded.1"
+	sessionsURI       = serviceRootURI + "/SessionService/Sessions"
+	accountsURI       = serviceRootURI + "/AccountService/Accounts"
+	firmwareURI       = serviceRootURI + "/UpdateService/FirmwareInventory"
+	dellJobsURI       = managerURI + "/Jobs"
+	jobServiceJobsURI = serviceRootURI + "/JobService/Jobs"
+	tasksURI          = serviceRootURI + "/TaskService/Tasks"
+)
+
+// Server is a Redfish service emulating a Dell server. It is safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	lock      sync.Mutex
+	resources map[string]map[string]interface{}
+	// passwords of the accounts, which are never returned by GET
+	passwords map[string]string
+	// secrets holds the values of the Password attributes, which are read as null
+	secrets map[string]interface{}
+	// collections are the collections whose members are created by POST and removed by DELETE
+	collections map[string]bool
+	sessions    map[string]string
+	jobs        map[string]*job
+	jobCount    int
+	power       powerSequence
+	// subscriptionHeaders holds the HTTP headers of the event subscriptions, which are never returned by GET
+	subscriptionHeaders map[string]map[string]interface{}
+	eventCount          int
+	// deliveries are the events being posted to the subscriptions
+	deliveries sync.WaitGroup
+	// streams are the open Server-Sent Events streams, which end when closing is closed
+	streams map[chan map[string]interface{}]bool
+	closing chan struct{}
+	// shares holds the Server Configuration Profiles exported to network shares
+	shares map[string][]byte
+	// repository holds the packages found by the last update from a repository
+	repository []catalogPackage
+	// requests is the number of requests served, to check how many a client makes
+	requests int
+	now      func() time.Time
+}
+
+// New starts an emulator on a random local port, with an administrator account of the
+// given credentials. The emulator serves HTTPS with a self signed certificate.
+func New(username, password string) (*Server, error) {
+	s := &Server{
+		resources: make(map[string]map[string]interface{}),
+		passwords: make(map[string]string),
+		secrets:   make(map[string]interface{}),
+		collections: map[string]bool{
+			subscriptionsURI: true,
+		},
+		sessions: make(map[string]string),
+		jobs:     make(map[string]*job),
+		shares:   make(map[string][]byte),
+		now:      time.Now,
+
+		subscriptionHeaders: make(map[string]map[string]interface{}),
+		streams:             make(map[chan map[string]interface{}]bool),
+		closing:             make(chan struct{}),
+	}
+	if err := s.loadFixtures(); err != nil {
+		return nil, err
+	}
+	s.loadMessageRegistries()
+	s.initAccounts(username, password)
+	s.server = httptest.NewTLSServer(s)
+	return s, nil
+}
+
+// URL returns the base URL of the emulator, such as https://127.0.0.1:41055
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Endpoint returns the host:port of the emulator, the form expected by the TF_TESTING_ENDPOINT variables
+func (s *Server) Endpoint() string {
+	u, err := url.Parse(s.server.URL)
+	if err != nil {
+		return s.server.URL
+	}
+	return u.Host
+}
+
+// Close shuts the emulator down
+func (s *Server) Close() {
+	// the streams would keep the server from closing
+	s.CloseStreams()
+	s.server.Close()
+	s.deliveries.Wait()
+}
+
+// Resource returns a copy of the resource at the URI, as it would be returned by GET
+func (s *Server) Resource(uri string) (map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	res, ok := s.resources[normalizeURI(uri)]
+	if !ok {
+		return nil, false
+	}
+	return deepCopy(res).(map[string]interface{}), true
+}
+
+// Requests returns the number of requests served by the emulator
+func (s *Server) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+// SetResource replaces the resource at the URI, so that tests can set up states the emulator does not reach by itself
+func (s *Server) SetResource(uri string, resource map[string]interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.resources[normalizeURI(uri)] = deepCopy(resource).(map[string]interface{})
+}
+
+// UseAccountCollection makes the accounts members of a collection, created by POST and removed by DELETE
+// as on most Redfish services, instead of the fixed slots of the iDRAC
+func (s *Server) UseAccountCollection() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.collections[accountsURI] = true
+}
+
+// request is a request routed to a handler
+type request struct {
+	*http.Request
+	uri string
+	// params are the submatches of the route pattern
+	params []string
+}
+
+// response is the answer of a handler
+type response struct {
+	status  int
+	headers map[string]string
+	body    interface{}
+	// raw is returned as is instead of body when it is set
+	raw []byte
+}
+
+type handler func(s *Server, r *request) (*response, error)
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  handler
+}
+
+func newRoute(method, pattern string, handle handler) route {
+	return route{method: method, pattern: regexp.MustCompile("^" + pattern + "$"), handle: handle}
+}
+
+var routes = []route{
+	newRoute(http.MethodGet, "/redfish", (*Server).getVersions),
+	newRoute(http.MethodGet, `/redfish/v1/(?:Managers/iDRAC\.Embedded\.1|JobService)/Jobs`, (*Server).getJobs),
+	newRoute(http.MethodGet, serviceRootURI+"/TaskService/Tasks", (*Server).getJobs),
+	newRoute(http.MethodGet, `/redfish/v1/(?:Managers/iDRAC\.Embedded\.1|JobService)/Jobs/([^/]+)`, (*Server).getJob),
+	newRoute(http.MethodGet, serviceRootURI+"/TaskService/Tasks/([^/]+)", (*Server).getJob),
+	newRoute(http.MethodGet, ".*", (*Server).getResource),
+
+	newRoute(http.MethodPatch, systemURI, (*Server).patchSystem),
+	newRoute(http.MethodPatch, systemURI+"/Bios/Settings", (*Server).patchBiosSettings),
+	newRoute(http.MethodPatch, systemURI+"/BootOptions/([^/]+)", (*Server).patchBootOption),
+	newRoute(http.MethodPatch, systemURI+"/Storage/([^/]+)/Volumes/([^/]+)/Settings", (*Server).patchVolume),
+	newRoute(http.MethodPatch, accountsURI+"/([^/]+)", (*Server).patchAccount),
+	newRoute(http.MethodPatch, managerURI+"/Oem/Dell/DellAttributes/([^/]+)", (*Server).patchDellAttributes),
+	newRoute(http.MethodPatch, subscriptionsURI+"/([^/]+)", (*Server).patchSubscription),
+	newRoute(http.MethodPatch, ".*", (*Server).patchResource),
+
+	newRoute(http.MethodPost, sessionsURI, (*Server).createSession),
+	newRoute(http.MethodPost, firmwareURI, (*Server).uploadFirmware),
+	newRoute(http.MethodPost, systemURI+"/Storage/([^/]+)/Volumes", (*Server).createVolume),
+	newRoute(http.MethodPost, ".*/Actions/(?:Oem/)?([^/]+)", (*Server).postAction),
+	newRoute(http.MethodPost, subscriptionsURI, (*Server).createSubscription),
+	newRoute(http.MethodPost, ".*", (*Server).createMember),
+
+	newRoute(http.MethodDelete, sessionsURI+"/([^/]+)", (*Server).deleteSession),
+	newRoute(http.MethodDelete, `/redfish/v1/(?:Managers/iDRAC\.Embedded\.1|JobService)/Jobs/([^/]+)`, (*Server).deleteJob),
+	newRoute(http.MethodDelete, serviceRootURI+"/TaskService/Tasks/([^/]+)", (*Server).deleteJob),
+	newRoute(http.MethodDelete, systemURI+"/Storage/([^/]+)/Volumes/([^/]+)", (*Server).deleteVolume),
+	newRoute(http.MethodDelete, subscriptionsURI+"/([^/]+)", (*Server).deleteSubscription),
+	newRoute(http.MethodDelete, ".*", (*Server).deleteMember),
+}
+
+// publicURIs can be read without credentials, as on the iDRAC
+var publicURIs = map[string]bool{
+	"/redfish":                true,
+	serviceRootURI:            true,
+	serviceRootURI + "/odata": true,
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && normalizeURI(r.URL.Path) == sseURI {
+		// the stream is served without holding the lock
+		s.serveEvents(w, r)
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests++
+
+	req := &request{Request: r, uri: normalizeURI(r.URL.Path)}
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	public := (method == http.MethodGet && publicURIs[req.uri]) || (method == http.MethodPost && req.uri == sessionsURI)
+	if !public && !s.authenticated(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="RedfishService"`)
+		s.write(w, r, errorResponse(newError(http.StatusUnauthorized, "Base.1.12.NoValidSession", nil)))
+		return
+	}
+
+	for _, rt := range routes {
+		if rt.method != method {
+			continue
+		}
+		if match := rt.pattern.FindStringSubmatch(req.uri); match != nil {
+			req.params = match[1:]
+			resp, err := rt.handle(s, req)
+			if err == nil && method == http.MethodGet {
+				resp, err = s.queryResponse(req, resp)
+			}
+			if err != nil {
+				resp = errorResponse(err)
+			}
+			s.write(w, r, resp)
+			return
+		}
+	}
+	s.write(w, r, errorResponse(newError(http.StatusMethodNotAllowed, "Base.1.12.OperationNotAllowed", nil)))
+}
+
+// errorResponse returns the response of a handler error
+func errorResponse(err error) *response {
+	var redfishErr *redfishError
+	if !errors.As(err, &redfishErr) {
+		redfishErr = newError(http.StatusInternalServerError, "Base.1.12.GeneralError", nil)
+		redfishErr.messages[0]["Message"] = err.Error()
+	}
+	return &response{status: redfishErr.status, body: redfishErr.body()}
+}
+
+func (s *Server) write(w http.ResponseWriter, r *http.Request, resp *response) {
+	content := resp.raw
+	if content == nil && resp.body != nil {
+		var err error
+		if content, err = json.Marshal(resp.body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	header := w.Header()
+	header.Set("OData-Version", "4.0")
+	if resp.raw == nil {
+		header.Set("Content-Type", "application/json;odata.metadata=minimal;charset=utf-8")
+	}
+	if r.Method == http.MethodGet && resp.status == http.StatusOK {
+		header.Set("ETag", etag(content))
+	}
+	for k, v := range resp.headers {
+		header.Set(k, v)
+	}
+	w.WriteHeader(resp.status)
+	if r.Method != http.MethodHead && len(content) > 0 {
+		w.Write(content) // #nosec G104
+	}
+}
+
+// authenticated checks the session token or the basic credentials of the request against the enabled accounts
+func (s *Server) authenticated(r *http.Request) bool {
+	if token := r.Header.Get("X-Auth-Token"); len(token) > 0 {
+		_, ok := s.sessions[token]
+		return ok
+	}
+	username, password, ok := r.BasicAuth()
+	return ok && s.checkCredentials(username, password)
+}
+
+func (*Server) getVersions(_ *request) (*response, error) {
+	return ok(map[string]interface{}{"v1": serviceRootURI + "/"}), nil
+}
+
+// createSession implements the login of the session authentication
+func (s *Server) createSession(r *request) (*response, error) {
+	var body struct {
+		UserName string
+		Password string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.MalformedJSON", nil)
+	}
+	if !s.checkCredentials(body.UserName, body.Password) {
+		return nil, newError(http.StatusUnauthorized, "Base.1.12.NoValidSession", nil)
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	session := s.addMember(sessionsURI, map[string]interface{}{
+		"@odata.type": "#Session.v1_6_0.Session",
+		"Name":        "User Session",
+		"Description": "User Session",
+		"UserName":    body.UserName,
+		"Password":    nil,
+	})
+	s.sessions[hex.EncodeToString(token)] = session["@odata.id"].(string)
+	return &response{
+		status: http.StatusCreated,
+		headers: map[string]string{
+			"X-Auth-Token": hex.EncodeToString(token),
+			"Location":     session["@odata.id"].(string),
+		},
+		body: session,
+	}, nil
+}
+
+// deleteSession implements the logout of the session authentication
+func (s *Server) deleteSession(r *request) (*response, error) {
+	if _, exists := s.resources[r.uri]; !exists {
+		return nil, newError(http.StatusNotFound, "Base.1.12.ResourceMissingAtURI", []string{r.uri})
+	}
+	for token, uri := range s.sessions {
+		if uri == r.uri {
+			delete(s.sessions, token)
+		}
+	}
+	s.removeMember(sessionsURI, r.uri)
+	return ok(successBody()), nil
+}
+
+// ok returns a 200 OK response
+func ok(body interface{}) *response {
+	return &response{status: http.StatusOK, body: body}
+}
+
+// noContent returns a 204 No Content response
+func noContent() *response {
+	return &response{status: http.StatusNoContent}
+}
+
+// accepted returns the 202 Accepted response of an operation run by the job
+func accepted(location string) *response {
+	return &response{
+		status:  http.StatusAccepted,
+		headers: map[string]string{"Location": location},
+		body:    map[string]interface{}{"@Message.ExtendedInfo": []interface{}{newMessage("IDRAC.2.8.JCP001", nil)}},
+	}
+}
+
+// successBody returns the body of a successful operation
+func successBody() map[string]interface{} {
+	return map[string]interface{}{"@Message.ExtendedInfo": []interface{}{newMessage("Base.1.12.Success", nil)}}
+}
+
+// normalizeURI removes the trailing slash and the query of a URI
+func normalizeURI(uri string) string {
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		uri = uri[:i]
+	}
+	if len(uri) > 1 {
+		uri = strings.TrimSuffix(uri, "/")
+	}
+	return uri
+}
+
+// etag returns a weak entity tag of the content
+func etag(content []byte) string {
+	var sum uint32 = 2166136261
+	for _, b := range content {
+		sum = (sum ^ uint32(b)) * 16777619
+	}
+	return fmt.Sprintf(`W/"%08x"`, sum)
+}
@@ -0,0 +1,473 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	testUsername = "root"
+	testPassword = "calvin"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	s, err := New(testUsername, testPassword)
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// call sends a request to the emulator and decodes the JSON body of the response, if any
+func call(t *testing.T, s *Server, method, uri string, body interface{}) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	var content []byte
+	if body != nil {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			t.Fatalf("invalid body: %s", err)
+		}
+	}
+	req, err := http.NewRequest(method, s.URL()+uri, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("invalid request: %s", err)
+	}
+	req.SetBasicAuth(testUsername, testPassword)
+	resp, err := s.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, uri, err)
+	}
+	defer resp.Body.Close()
+	var decoded map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&decoded) // #nosec G104
+	return resp, decoded
+}
+
+// expectStatus checks the status of the response
+func expectStatus(t *testing.T, resp *http.Response, status int) {
+	t.Helper()
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s: expected status %d, got %d", resp.Request.Method, resp.Request.URL.Path, status, resp.StatusCode)
+	}
+}
+
+// pollJob reads the job until it is over and returns its final state
+func pollJob(t *testing.T, s *Server, location string) map[string]interface{} {
+	t.Helper()
+	for i := 0; i < 10; i++ {
+		resp, job := call(t, s, http.MethodGet, location, nil)
+		expectStatus(t, resp, http.StatusOK)
+		if state := job["JobState"]; state != jobScheduled && state != jobRunning && state != "Pending" {
+			return job
+		}
+	}
+	t.Fatalf("job %s did not complete", location)
+	return nil
+}
+
+// powerCycle restarts the system and reads it until it is back on
+func powerCycle(t *testing.T, s *Server) {
+	t.Helper()
+	resp, _ := call(t, s, http.MethodPost, systemURI+"/Actions/ComputerSystem.Reset", map[string]interface{}{"ResetType": "ForceRestart"})
+	expectStatus(t, resp, http.StatusNoContent)
+	for i := 0; i < 10; i++ {
+		if _, system := call(t, s, http.MethodGet, systemURI, nil); system["PowerState"] == powerOn {
+			return
+		}
+	}
+	t.Fatal("the system did not power on")
+}
+
+func TestEmulatorGofish(t *testing.T) {
+	s := newTestServer(t)
+	client, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:  s.URL(),
+		Username:  testUsername,
+		Password:  testPassword,
+		Insecure:  true,
+		BasicAuth: true,
+	})
+	if err != nil {
+		t.Fatalf("Connect failed: %s", err)
+	}
+	defer client.Logout()
+
+	if client.Service.Vendor != "Dell" {
+		t.Errorf("unexpected vendor %s", client.Service.Vendor)
+	}
+	systems, err := client.Service.Systems()
+	if err != nil || len(systems) != 1 {
+		t.Fatalf("unexpected systems %v: %v", systems, err)
+	}
+	bios, err := systems[0].Bios()
+	if err != nil {
+		t.Fatalf("Bios failed: %s", err)
+	}
+	if bios.Attributes.String("NumLock") != "On" {
+		t.Errorf("unexpected NumLock %s", bios.Attributes.String("NumLock"))
+	}
+	storage, err := systems[0].Storage()
+	if err != nil || len(storage) != 2 {
+		t.Fatalf("unexpected storage %v: %v", storage, err)
+	}
+	registries, err := client.Service.Registries()
+	if err != nil || len(registries) == 0 {
+		t.Fatalf("unexpected registries %v: %v", registries, err)
+	}
+
+	if _, err := gofish.Connect(gofish.ClientConfig{Endpoint: s.URL(), Username: testUsername, Password: "wrong",
+		Insecure: true}); err == nil {
+		t.Error("expected an error for invalid credentials")
+	}
+}
+
+func TestEmulatorBiosJob(t *testing.T) {
+	s := newTestServer(t)
+	settings := map[string]interface{}{
+		"Attributes":                 map[string]interface{}{"NumLock": "Off", "AcPwrRcvryUserDelay": 70},
+		"@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "OnReset"},
+	}
+
+	t.Run("test invalid attributes are rejected", func(t *testing.T) {
+		resp, body := call(t, s, http.MethodPatch, biosURI+"/Settings", map[string]interface{}{
+			"Attributes": map[string]interface{}{"NumLock": "Maybe", "SystemModelName": "x", "Unknown": 1},
+		})
+		expectStatus(t, resp, http.StatusBadRequest)
+		messages := body["error"].(map[string]interface{})["@Message.ExtendedInfo"].([]interface{})
+		if len(messages) != 3 {
+			t.Errorf("expected a message per attribute, got %v", messages)
+		}
+	})
+
+	t.Run("test the job waits for the reset", func(t *testing.T) {
+		resp, _ := call(t, s, http.MethodPatch, biosURI+"/Settings", settings)
+		expectStatus(t, resp, http.StatusAccepted)
+		location := resp.Header.Get("Location")
+		for i := 0; i < 3; i++ {
+			if _, job := call(t, s, http.MethodGet, location, nil); job["JobState"] != jobScheduled {
+				t.Fatalf("expected a scheduled job, got %v", job["JobState"])
+			}
+		}
+		resp, _ = call(t, s, http.MethodPatch, biosURI+"/Settings", settings)
+		expectStatus(t, resp, http.StatusBadRequest)
+
+		powerCycle(t, s)
+		if job := pollJob(t, s, location); job["JobState"] != jobCompleted {
+			t.Fatalf("unexpected job %v", job)
+		}
+		_, bios := call(t, s, http.MethodGet, biosURI, nil)
+		attributes := bios["Attributes"].(map[string]interface{})
+		if attributes["NumLock"] != "Off" || attributes["AcPwrRcvryUserDelay"] != float64(70) {
+			t.Errorf("the settings were not applied: %v", attributes)
+		}
+	})
+
+	t.Run("test dependencies make attributes read only", func(t *testing.T) {
+		resp, _ := call(t, s, http.MethodPatch, biosURI+"/Settings", map[string]interface{}{
+			"Attributes": map[string]interface{}{"AcPwrRcvryDelay": "Immediate", "AcPwrRcvryUserDelay": 90},
+		})
+		expectStatus(t, resp, http.StatusBadRequest)
+	})
+}
+
+func TestEmulatorPower(t *testing.T) {
+	s := newTestServer(t)
+	reset := func(resetType string) {
+		resp, _ := call(t, s, http.MethodPost, systemURI+"/Actions/ComputerSystem.Reset", map[string]interface{}{"ResetType": resetType})
+		expectStatus(t, resp, http.StatusNoContent)
+	}
+	expectPower := func(states ...string) {
+		t.Helper()
+		for _, state := range states {
+			if _, system := call(t, s, http.MethodGet, systemURI, nil); system["PowerState"] != state {
+				t.Fatalf("expected the power state %s, got %v", state, system["PowerState"])
+			}
+		}
+	}
+
+	reset("ForceOff")
+	expectPower(powerOffGoing, powerOff, powerOff)
+	reset("On")
+	expectPower(powerOnGoing, powerOn)
+	reset("GracefulRestart")
+	expectPower(powerOffGoing, powerOnGoing, powerOn)
+
+	resp, _ := call(t, s, http.MethodPost, systemURI+"/Actions/ComputerSystem.Reset", map[string]interface{}{"ResetType": "Sleep"})
+	expectStatus(t, resp, http.StatusBadRequest)
+}
+
+func TestEmulatorDellAttributes(t *testing.T) {
+	s := newTestServer(t)
+	uri := dellAttributesURI + "iDRAC.Embedded.1"
+
+	resp, _ := call(t, s, http.MethodPatch, uri, map[string]interface{}{
+		"Attributes": map[string]interface{}{"Users.3.UserName": "mike", "Users.3.Password": "test1234", "Users.3.Privilege": 511},
+	})
+	expectStatus(t, resp, http.StatusOK)
+	_, res := call(t, s, http.MethodGet, uri, nil)
+	attributes := res["Attributes"].(map[string]interface{})
+	if attributes["Users.3.UserName"] != "mike" || attributes["Users.3.Password"] != nil {
+		t.Errorf("unexpected attributes %v", attributes)
+	}
+
+	for _, invalid := range []map[string]interface{}{
+		{"Users.3.Privilege": 512},
+		{"SysLog.1.PowerLogInterval": "5"},
+		{"LCAttributes.1.IgnoreCertWarning": "On"},
+		{"Info.1.Version": "7.00.00.00"},
+	} {
+		resp, _ := call(t, s, http.MethodPatch, uri, map[string]interface{}{"Attributes": invalid})
+		expectStatus(t, resp, http.StatusBadRequest)
+	}
+}
+
+func TestEmulatorVolume(t *testing.T) {
+	s := newTestServer(t)
+	storageURI := systemURI + "/Storage/RAID.Integrated.1-1"
+	drive := map[string]interface{}{"@odata.id": storageURI + "/Drives/Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"}
+
+	resp, _ := call(t, s, http.MethodPost, storageURI+"/Volumes", map[string]interface{}{
+		"Name": "TerraformVol1", "RAIDType": "RAID1", "Drives": []interface{}{drive},
+	})
+	expectStatus(t, resp, http.StatusBadRequest)
+
+	resp, _ = call(t, s, http.MethodPost, storageURI+"/Volumes", map[string]interface{}{
+		"Name": "TerraformVol1", "RAIDType": "RAID0", "Drives": []interface{}{drive}, "ReadCachePolicy": "",
+		"@Redfish.OperationApplyTime": "Immediate",
+	})
+	expectStatus(t, resp, http.StatusAccepted)
+	if job := pollJob(t, s, resp.Header.Get("Location")); job["JobState"] != jobCompleted {
+		t.Fatalf("unexpected job %v", job)
+	}
+	volumeURI := storageURI + "/Volumes/Disk.Virtual.1:RAID.Integrated.1-1"
+	_, volume := call(t, s, http.MethodGet, volumeURI, nil)
+	if volume["Name"] != "TerraformVol1" || volume["VolumeType"] != string(redfish.NonRedundantVolumeType) ||
+		volume["ReadCachePolicy"] != "Off" {
+		t.Fatalf("unexpected volume %v", volume)
+	}
+
+	resp, _ = call(t, s, http.MethodDelete, volumeURI, nil)
+	expectStatus(t, resp, http.StatusAccepted)
+	pollJob(t, s, resp.Header.Get("Location"))
+	resp, _ = call(t, s, http.MethodGet, volumeURI, nil)
+	expectStatus(t, resp, http.StatusNotFound)
+}
+
+func TestEmulatorRepositoryUpdate(t *testing.T) {
+	s := newTestServer(t)
+	service := systemURI + "/Oem/Dell/DellSoftwareInstallationService/Actions/DellSoftwareInstallationService."
+
+	resp, _ := call(t, s, http.MethodPost, service+"GetRepoBasedUpdateList", map[string]interface{}{})
+	expectStatus(t, resp, http.StatusBadRequest)
+
+	resp, _ = call(t, s, http.MethodPost, service+"InstallFromRepository", map[string]interface{}{
+		"IPAddress": "downloads.dell.com", "ShareType": "HTTPS", "ApplyUpdate": "False",
+	})
+	expectStatus(t, resp, http.StatusAccepted)
+	if job := pollJob(t, s, resp.Header.Get("Location")); job["JobState"] != "Completed" {
+		t.Fatalf("unexpected job %v", job)
+	}
+
+	resp, body := call(t, s, http.MethodPost, service+"GetRepoBasedUpdateList", map[string]interface{}{})
+	expectStatus(t, resp, http.StatusOK)
+	var list struct {
+		Instances []struct {
+			ClassName  string `xml:"CLASSNAME,attr"`
+			Properties []struct {
+				Name  string `xml:"NAME,attr"`
+				Value string `xml:"VALUE"`
+			} `xml:"PROPERTY"`
+		} `xml:"MESSAGE>SIMPLEREQ>VALUE.NAMEDINSTANCE>INSTANCENAME"`
+	}
+	if err := xml.Unmarshal([]byte(body["PackageList"].(string)), &list); err != nil {
+		t.Fatalf("invalid package list: %s", err)
+	}
+	if len(list.Instances) != len(firmwareComponents) || list.Instances[0].ClassName != "DCIM_RepoUpdateSWID" {
+		t.Errorf("unexpected package list %+v", list)
+	}
+}
+
+func TestEmulatorQuery(t *testing.T) {
+	s := newTestServer(t)
+	storageURI := systemURI + "/Storage/RAID.Integrated.1-1"
+
+	_, storage := call(t, s, http.MethodGet, storageURI+"?$expand=.($levels=1)", nil)
+	drives, _ := storage["Drives"].([]interface{})
+	if len(drives) == 0 {
+		t.Fatalf("the storage has no drive")
+	}
+	for _, drive := range drives {
+		if _, expanded := drive.(map[string]interface{})["CapacityBytes"]; !expanded {
+			t.Errorf("the drive %v was not expanded", drive)
+		}
+	}
+	// the references of Links are not expanded by "."
+	_, system := call(t, s, http.MethodGet, systemURI+"?$expand=.($levels=1)", nil)
+	if bios, _ := system["Bios"].(map[string]interface{}); bios["Attributes"] == nil {
+		t.Errorf("the BIOS %v was not expanded", system["Bios"])
+	}
+	links, _ := system["Links"].(map[string]interface{})
+	for _, chassis := range links["Chassis"].([]interface{}) {
+		if len(chassis.(map[string]interface{})) != 1 {
+			t.Errorf("the link %v was expanded", chassis)
+		}
+	}
+
+	_, system = call(t, s, http.MethodGet, systemURI+"?$select=PowerState,Status/Health", nil)
+	if len(system) != 4 || system["PowerState"] != powerOn || system["@odata.id"] != systemURI || system["Status"] == nil {
+		t.Errorf("unexpected selection %v", system)
+	}
+
+	resp, _ := call(t, s, http.MethodGet, systemURI+"?$expand=Storage", nil)
+	expectStatus(t, resp, http.StatusBadRequest)
+}
+
+func TestEmulatorSubscription(t *testing.T) {
+	s := newTestServer(t)
+	resp, _ := call(t, s, http.MethodPost, subscriptionsURI, map[string]interface{}{"Protocol": "Redfish"})
+	expectStatus(t, resp, http.StatusBadRequest)
+	resp, _ = call(t, s, http.MethodPost, subscriptionsURI, map[string]interface{}{
+		"Destination": "https://collector.example.com/events", "SubscriptionType": "Syslog",
+	})
+	expectStatus(t, resp, http.StatusBadRequest)
+
+	resp, _ = call(t, s, http.MethodPost, subscriptionsURI, map[string]interface{}{
+		"Destination":      "https://collector.example.com/events",
+		"RegistryPrefixes": []interface{}{"IDRAC"},
+		"HttpHeaders":      map[string]interface{}{"Authorization": "Bearer token"},
+	})
+	expectStatus(t, resp, http.StatusCreated)
+	uri := resp.Header.Get("Location")
+	_, subscription := call(t, s, http.MethodGet, uri, nil)
+	if subscription["Protocol"] != "Redfish" || subscription["SubscriptionType"] != "RedfishEvent" ||
+		len(subscription["HttpHeaders"].([]interface{})) != 0 || len(subscription["RegistryPrefixes"].([]interface{})) != 1 {
+		t.Fatalf("unexpected subscription %v", subscription)
+	}
+
+	resp, _ = call(t, s, http.MethodPatch, uri, map[string]interface{}{"Destination": "https://other.example.com"})
+	expectStatus(t, resp, http.StatusBadRequest)
+	resp, _ = call(t, s, http.MethodPatch, uri, map[string]interface{}{"Context": "collector"})
+	expectStatus(t, resp, http.StatusOK)
+	if _, subscription = call(t, s, http.MethodGet, uri, nil); subscription["Context"] != "collector" {
+		t.Fatalf("the context was not changed: %v", subscription)
+	}
+
+	resp, _ = call(t, s, http.MethodDelete, uri, nil)
+	expectStatus(t, resp, http.StatusOK)
+	resp, _ = call(t, s, http.MethodGet, uri, nil)
+	expectStatus(t, resp, http.StatusNotFound)
+	if _, collection := call(t, s, http.MethodGet, subscriptionsURI, nil); len(collection["Members"].([]interface{})) != 0 {
+		t.Fatalf("the subscription is still a member: %v", collection)
+	}
+}
+
+func TestEmulatorEventStream(t *testing.T) {
+	s := newTestServer(t)
+	s.lock.Lock()
+	j := s.newJob("Configure: RAID.Integrated.1-1", "RAIDConfiguration", false)
+	s.lock.Unlock()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL()+sseURI, nil)
+	req.SetBasicAuth(testUsername, testPassword)
+	resp, err := s.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("unable to open the stream: %s", err)
+	}
+	defer resp.Body.Close()
+	expectStatus(t, resp, http.StatusOK)
+
+	// the job runs while the stream is open, and its events are streamed until it is over
+	var messages []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(messages) < 2 && scanner.Scan() {
+		data, isData := strings.CutPrefix(scanner.Text(), "data: ")
+		if !isData {
+			continue
+		}
+		var event struct {
+			Events []struct {
+				MessageID         string `json:"MessageId"`
+				OriginOfCondition map[string]string
+			}
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil || len(event.Events) != 1 {
+			t.Fatalf("invalid event %s", data)
+		}
+		if origin := event.Events[0].OriginOfCondition["@odata.id"]; origin != dellJobsURI+"/"+j.id {
+			t.Fatalf("unexpected origin %s", origin)
+		}
+		messages = append(messages, event.Events[0].MessageID)
+	}
+	if strings.Join(messages, " ") != "IDRAC.2.8.PR20 IDRAC.2.8.PR19" {
+		t.Errorf("unexpected events %v", messages)
+	}
+
+	// the stream ends once closed
+	s.CloseStreams()
+	io.Copy(io.Discard, resp.Body) // #nosec G104
+	if _, job := call(t, s, http.MethodGet, dellJobsURI+"/"+j.id, nil); job["JobState"] != jobCompleted {
+		t.Errorf("unexpected job %v", job)
+	}
+}
+
+func TestEmulatorLocalExport(t *testing.T) {
+	s := newTestServer(t)
+	resp, _ := call(t, s, http.MethodPost, managerURI+"/Actions/Oem/EID_674_Manager.ExportSystemConfiguration",
+		map[string]interface{}{"ExportFormat": "XML", "ShareParameters": map[string]interface{}{"Target": []string{"BIOS"}}})
+	expectStatus(t, resp, http.StatusAccepted)
+	location := resp.Header.Get("Location")
+
+	// the task monitor answers with the profile once the job is over
+	for i := 0; i < 5; i++ {
+		req, err := http.NewRequest(http.MethodGet, s.URL()+location, nil)
+		if err != nil {
+			t.Fatalf("invalid request: %s", err)
+		}
+		req.SetBasicAuth(testUsername, testPassword)
+		resp, err := s.server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s failed: %s", location, err)
+		}
+		content, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusAccepted {
+			continue
+		}
+		expectStatus(t, resp, http.StatusOK)
+		var profile scpProfile
+		if err := xml.Unmarshal(content, &profile); err != nil || len(profile.Components) != 1 ||
+			profile.Components[0].FQDD != "BIOS.Setup.1-1" {
+			t.Fatalf("expected the BIOS profile, got %s %v", content, err)
+		}
+		return
+	}
+	t.Fatalf("the export did not complete")
+}
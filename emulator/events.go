@@ -0,0 +1,350 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	subscriptionsURI = serviceRootURI + "/EventService/Subscriptions"
+	// sseURI is the ServerSentEventUri of the EventService
+	sseURI = serviceRootURI + "/SSE"
+	// eventTimeout is the time the emulator waits for a destination to accept an event
+	eventTimeout = 10 * time.Second
+	// streamBuffer is the number of events kept for a stream whose client is slow, the next ones are dropped
+	streamBuffer = 64
+	// streamJobStep is the time between two steps of the jobs while a stream is open
+	streamJobStep = 100 * time.Millisecond
+)
+
+var (
+	subscriptionProtocols  = []interface{}{"Redfish", "SNMPv2c", "SNMPv3"}
+	subscriptionTypes      = []interface{}{"RedfishEvent", "SSE", "SNMPTrap", "SNMPInform"}
+	subscriptionFormats    = []interface{}{"Event", "MetricReport"}
+	subscriptionFilters    = []string{"RegistryPrefixes", "MessageIds", "ResourceTypes"}
+	subscriptionProperties = map[string]bool{
+		"Destination": true, "Protocol": true, "SubscriptionType": true, "EventFormatType": true, "Context": true,
+		"HttpHeaders": true, "RegistryPrefixes": true, "MessageIds": true, "ResourceTypes": true,
+	}
+)
+
+// createSubscription adds an event subscription. The HTTP headers of the subscription are kept aside, as
+// they are read as an empty array.
+func (s *Server) createSubscription(r *request) (*response, error) {
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubscription(body); err != nil {
+		return nil, err
+	}
+	subscription := map[string]interface{}{
+		"@odata.type":      "#EventDestination.v1_13_0.EventDestination",
+		"Name":             "EventSubscription",
+		"Description":      "Event Subscription Details",
+		"Destination":      body["Destination"],
+		"Protocol":         "Redfish",
+		"SubscriptionType": "RedfishEvent",
+		"EventFormatType":  "Event",
+		"Context":          "",
+		"HttpHeaders":      []interface{}{},
+		"Status":           map[string]interface{}{"Health": "OK", "HealthRollup": "OK", "State": "Enabled"},
+	}
+	for _, filter := range subscriptionFilters {
+		subscription[filter] = []interface{}{}
+	}
+	headers, _ := body["HttpHeaders"].(map[string]interface{})
+	delete(body, "HttpHeaders")
+	merge(subscription, body)
+
+	subscription = s.addMember(subscriptionsURI, subscription)
+	uri := subscription["@odata.id"].(string)
+	s.subscriptionHeaders[uri] = headers
+	return &response{
+		status:  http.StatusCreated,
+		headers: map[string]string{"Location": uri},
+		body:    subscription,
+	}, nil
+}
+
+// patchSubscription changes the context or the HTTP headers of an event subscription, the other properties
+// being only set at its creation
+func (s *Server) patchSubscription(r *request) (*response, error) {
+	subscription, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(body) {
+		if name != "Context" && name != "HttpHeaders" {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyNotWritable", []string{name}, "#/"+name)
+		}
+	}
+	if headers, isSet := body["HttpHeaders"]; isSet {
+		object, isObject := headers.(map[string]interface{})
+		if !isObject {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyValueTypeError",
+				[]string{toString(headers), "HttpHeaders"}, "#/HttpHeaders")
+		}
+		s.subscriptionHeaders[r.uri] = object
+	}
+	if context, isSet := body["Context"]; isSet {
+		subscription["Context"] = context
+	}
+	return ok(successBody()), nil
+}
+
+// deleteSubscription removes an event subscription along with its HTTP headers
+func (s *Server) deleteSubscription(r *request) (*response, error) {
+	if _, err := s.resource(r.uri); err != nil {
+		return nil, err
+	}
+	s.removeMember(subscriptionsURI, r.uri)
+	delete(s.subscriptionHeaders, r.uri)
+	return ok(successBody()), nil
+}
+
+// checkSubscription validates the properties of a new event subscription
+func checkSubscription(body map[string]interface{}) error {
+	for _, name := range sortedKeys(body) {
+		if !subscriptionProperties[name] {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyUnknown", []string{name}, "#/"+name)
+		}
+	}
+	if destination, _ := body["Destination"].(string); len(destination) == 0 {
+		return newError(http.StatusBadRequest, "Base.1.12.PropertyMissing", []string{"Destination"}, "#/Destination")
+	}
+	for name, allowed := range map[string][]interface{}{
+		"Protocol":         subscriptionProtocols,
+		"SubscriptionType": subscriptionTypes,
+		"EventFormatType":  subscriptionFormats,
+	} {
+		if value, isSet := body[name]; isSet && !contains(allowed, value) {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(value), name}, "#/"+name)
+		}
+	}
+	for _, filter := range subscriptionFilters {
+		if value, isSet := body[filter]; isSet {
+			if _, isArray := value.([]interface{}); !isArray {
+				return newError(http.StatusBadRequest, "Base.1.12.PropertyValueTypeError", []string{toString(value), filter}, "#/"+filter)
+			}
+		}
+	}
+	if headers, isSet := body["HttpHeaders"]; isSet {
+		if _, isObject := headers.(map[string]interface{}); !isObject {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueTypeError", []string{toString(headers), "HttpHeaders"}, "#/HttpHeaders")
+		}
+	}
+	return nil
+}
+
+// submitTestEvent sends an event built from the parameters of the action to the subscriptions whose filters
+// match it, as the EventService.SubmitTestEvent action of the iDRAC
+func (s *Server) submitTestEvent(r *request, body map[string]interface{}) (*response, error) {
+	messageID, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "MessageId")
+	if err != nil {
+		return nil, err
+	}
+	args, _ := body["MessageArgs"].([]interface{})
+	origin, _ := body["OriginOfCondition"].(string)
+	record := s.newEventRecord(messageID, args, origin)
+	if message, isString := body["Message"].(string); isString {
+		record["Message"] = message
+	}
+	if severity, isString := body["Severity"].(string); isString {
+		record["MessageSeverity"] = severity
+	}
+	s.publishEvent(record)
+	return noContent(), nil
+}
+
+// newEventRecord returns an event record of the message, whose text and severity are the ones of the message
+// templates
+func (s *Server) newEventRecord(messageID string, args []interface{}, origin string) map[string]interface{} {
+	s.eventCount++
+	if args == nil {
+		args = []interface{}{}
+	}
+	record := map[string]interface{}{
+		"EventId":         strconv.Itoa(s.eventCount),
+		"EventTimestamp":  s.now().Format(time.RFC3339),
+		"MemberId":        "0",
+		"MessageId":       messageID,
+		"MessageArgs":     args,
+		"MessageSeverity": "OK",
+	}
+	if template, exists := messageTemplates[messageID]; exists {
+		record["Message"], record["MessageSeverity"] = template.message, template.severity
+	}
+	if len(origin) > 0 {
+		record["OriginOfCondition"] = link(origin)
+	}
+	return record
+}
+
+// publishEvent sends the event record to the subscriptions whose filters match it, and to the open
+// Server-Sent Events streams
+func (s *Server) publishEvent(record map[string]interface{}) {
+	messageID := toString(record["MessageId"])
+	for _, uri := range linkURIs(s.resources[subscriptionsURI]["Members"].([]interface{})) {
+		subscription := s.resources[toString(uri)]
+		if subscription["SubscriptionType"] != "RedfishEvent" || !subscriptionMatches(subscription, messageID) {
+			continue
+		}
+		s.deliverEvent(toString(subscription["Destination"]), s.subscriptionHeaders[toString(uri)],
+			eventPayload(record, subscription["Context"]))
+	}
+	s.streamEvent(record)
+}
+
+// eventPayload returns the Event holding the record, as posted to the subscriptions
+func eventPayload(record map[string]interface{}, context interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.type": "#Event.v1_7_0.Event",
+		"Id":          record["EventId"],
+		"Name":        "Event Array",
+		"Context":     context,
+		"Events":      []interface{}{record},
+	}
+}
+
+// subscriptionMatches tells whether the registry prefix and MessageId filters of the subscription let the
+// message through
+func subscriptionMatches(subscription map[string]interface{}, messageID string) bool {
+	prefix, _, _ := strings.Cut(messageID, ".")
+	if prefixes, _ := subscription["RegistryPrefixes"].([]interface{}); len(prefixes) > 0 && !contains(prefixes, prefix) {
+		return false
+	}
+	if ids, _ := subscription["MessageIds"].([]interface{}); len(ids) > 0 && !contains(ids, messageID) {
+		return false
+	}
+	return true
+}
+
+// deliverEvent posts the event to the destination in the background, as the BMC does not wait for its
+// subscribers. The events which cannot be delivered are dropped.
+func (s *Server) deliverEvent(destination string, headers map[string]interface{}, event map[string]interface{}) {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	s.deliveries.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		req, err := http.NewRequest(http.MethodPost, destination, bytes.NewReader(content))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, toString(value))
+		}
+		client := &http.Client{
+			Timeout: eventTimeout,
+			// the destinations usually have self signed certificates
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, // #nosec G402
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close() // #nosec G104
+		}
+	}()
+}
+
+// serveEvents streams the events of the service as Server-Sent Events, until the client leaves or the
+// streams are closed. The jobs run by themselves while a stream is open, as a client following the events
+// does not poll them.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.requests++
+	if !s.authenticated(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="RedfishService"`)
+		s.write(w, r, errorResponse(newError(http.StatusUnauthorized, "Base.1.12.NoValidSession", nil)))
+		s.lock.Unlock()
+		return
+	}
+	stream := make(chan map[string]interface{}, streamBuffer)
+	s.streams[stream] = true
+	closing := s.closing
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.streams, stream)
+		s.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	step := time.NewTicker(streamJobStep)
+	defer step.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-closing:
+			return
+		case <-step.C:
+			s.lock.Lock()
+			s.runJobs()
+			s.lock.Unlock()
+		case record := <-stream:
+			content, err := json.Marshal(eventPayload(record, ""))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", toString(record["EventId"]), content) // #nosec G104
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// streamEvent sends the event record to the open streams
+func (s *Server) streamEvent(record map[string]interface{}) {
+	for stream := range s.streams {
+		select {
+		case stream <- record:
+		default:
+		}
+	}
+}
+
+// CloseStreams ends the open Server-Sent Events streams, as a reset of the iDRAC does
+func (s *Server) CloseStreams() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	close(s.closing)
+	s.closing = make(chan struct{})
+}
@@ -0,0 +1,323 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dell job states
+const (
+	jobScheduled           = "Scheduled"
+	jobRunning             = "Running"
+	jobCompleted           = "Completed"
+	jobCompletedWithErrors = "CompletedWithErrors"
+	jobFailed              = "Failed"
+)
+
+// jobStates maps the Dell job states to the states of the Job and Task resources, and to their status
+var jobStates = map[string]struct{ state, status string }{
+	jobScheduled:           {"Pending", "OK"},
+	jobRunning:             {"Running", "OK"},
+	jobCompleted:           {"Completed", "OK"},
+	jobCompletedWithErrors: {"Completed", "Warning"},
+	jobFailed:              {"Exception", "Critical"},
+}
+
+// outcome is the end of a job
+type outcome struct {
+	state   string
+	message map[string]interface{}
+	// result is returned by the task monitor of the job once it is over, instead of the task
+	result []byte
+}
+
+// success returns the outcome of a job which completed
+func success() outcome {
+	return outcome{state: jobCompleted, message: newMessage("IDRAC.2.8.PR19", nil)}
+}
+
+// failure returns the outcome of a job which failed with the message
+func failure(id string, args ...string) outcome {
+	return outcome{state: jobFailed, message: newMessage(id, args)}
+}
+
+// job is an operation run by the server. It moves one step each time it is polled: from Scheduled to
+// Running, then to its outcome. A job applied on reset stays Scheduled until the server is restarted.
+type job struct {
+	id      string
+	name    string
+	jobType string
+	state   string
+	onReset bool
+	percent int
+	message map[string]interface{}
+	start   time.Time
+	end     time.Time
+	// target is the FQDD of the component configured by the job
+	target string
+	// run decides the outcome of the job, which is successful if run is nil
+	run func() outcome
+	// apply holds the changes made by the job when it is successful
+	apply  []func()
+	result []byte
+}
+
+// newJob schedules a job. Its effects are set by the caller in run and apply.
+func (s *Server) newJob(name, jobType string, onReset bool) *job {
+	s.jobCount++
+	j := &job{
+		id:      fmt.Sprintf("JID_%012d", 812345678900+s.jobCount),
+		name:    name,
+		jobType: jobType,
+		state:   jobScheduled,
+		onReset: onReset,
+		message: newMessage("IDRAC.2.8.JCP001", nil),
+		start:   s.now(),
+	}
+	s.jobs[j.id] = j
+	return j
+}
+
+// advance moves the job one step further, and sends an event when its state changes
+func (s *Server) advance(j *job) {
+	previous := j.state
+	defer func() {
+		if j.state != previous {
+			s.publishJobEvent(j)
+		}
+	}()
+	switch j.state {
+	case jobScheduled:
+		if !j.onReset {
+			j.state, j.percent, j.message = jobRunning, 50, newMessage("IDRAC.2.8.PR20", nil)
+		}
+	case jobRunning:
+		result := success()
+		if j.run != nil {
+			result = j.run()
+		}
+		if result.state != jobFailed {
+			for _, apply := range j.apply {
+				apply()
+			}
+		}
+		j.state, j.percent, j.message = result.state, 100, result.message
+		// the result given when the job was created, such as an exported profile, is kept
+		if result.result != nil {
+			j.result = result.result
+		}
+		j.end = s.now()
+	}
+}
+
+// runJobs moves the jobs which are not over one step further
+func (s *Server) runJobs() {
+	ids := make([]string, 0, len(s.jobs))
+	for id, j := range s.jobs {
+		if !j.over() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s.advance(s.jobs[id])
+	}
+}
+
+// publishJobEvent sends the event of the current message of the job, about the job of the job queue
+func (s *Server) publishJobEvent(j *job) {
+	record := s.newEventRecord(toString(j.message["MessageId"]), nil, dellJobsURI+"/"+j.id)
+	record["Message"], record["MessageArgs"] = j.message["Message"], j.message["MessageArgs"]
+	record["MessageSeverity"] = j.message["Severity"]
+	s.publishEvent(record)
+}
+
+// over tells whether the job reached its outcome
+func (j *job) over() bool {
+	return j.state != jobScheduled && j.state != jobRunning
+}
+
+// pendingJob returns the configuration job of the component which has not started yet, if any
+func (s *Server) pendingJob(target string) *job {
+	for _, j := range s.jobs {
+		if j.target == target && j.state == jobScheduled {
+			return j
+		}
+	}
+	return nil
+}
+
+// releaseJobs starts the jobs waiting for the server to restart
+func (s *Server) releaseJobs() {
+	for _, j := range s.jobs {
+		j.onReset = false
+	}
+}
+
+// getJob returns a job as a DellJob, a Job or a Task depending on the URI. The task monitor answers
+// 202 Accepted while the job runs, and the result of the job if it has one when it is over.
+func (s *Server) getJob(r *request) (*response, error) {
+	j, exists := s.jobs[r.params[0]]
+	if !exists {
+		return nil, newError(http.StatusNotFound, "Base.1.12.ResourceMissingAtURI", []string{r.uri})
+	}
+	s.advance(j)
+	switch {
+	case strings.HasPrefix(r.uri, tasksURI):
+		if !j.over() {
+			return &response{status: http.StatusAccepted, body: j.task()}, nil
+		}
+		if j.result != nil {
+			return &response{status: http.StatusOK, raw: j.result}, nil
+		}
+		return ok(j.task()), nil
+	case strings.HasPrefix(r.uri, jobServiceJobsURI):
+		return ok(j.redfishJob()), nil
+	default:
+		return ok(j.dellJob()), nil
+	}
+}
+
+// getJobs returns the collection of the jobs, at the URI of the request
+func (s *Server) getJobs(r *request) (*response, error) {
+	ids := make([]string, 0, len(s.jobs))
+	for id := range s.jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, link(r.uri+"/"+id))
+	}
+	odataType, name := "#JobCollection.JobCollection", "JobQueue"
+	if r.uri == tasksURI {
+		odataType, name = "#TaskCollection.TaskCollection", "Task Collection"
+	}
+	return ok(map[string]interface{}{
+		"@odata.id":           r.uri,
+		"@odata.type":         odataType,
+		"Name":                name,
+		"Members":             members,
+		"Members@odata.count": len(members),
+	}), nil
+}
+
+// deleteJob removes the job from the job queue
+func (s *Server) deleteJob(r *request) (*response, error) {
+	if _, exists := s.jobs[r.params[0]]; !exists {
+		return nil, newError(http.StatusNotFound, "Base.1.12.ResourceMissingAtURI", []string{r.uri})
+	}
+	delete(s.jobs, r.params[0])
+	return ok(successBody()), nil
+}
+
+// deleteJobQueue implements the DellJobService.DeleteJobQueue action
+func (s *Server) deleteJobQueue(r *request, body map[string]interface{}) (*response, error) {
+	id, _ := body["JobID"].(string)
+	switch {
+	case id == "JID_CLEARALL":
+		s.jobs = make(map[string]*job)
+	case len(id) == 0:
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{actionName(r), "JobID"})
+	default:
+		if _, exists := s.jobs[id]; !exists {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+				[]string{id, "JobID", actionName(r)}, "#/JobID")
+		}
+		delete(s.jobs, id)
+	}
+	return ok(successBody()), nil
+}
+
+// dellJob returns the job as a DellJob, as found at /redfish/v1/Managers/iDRAC.Embedded.1/Jobs
+func (j *job) dellJob() map[string]interface{} {
+	res := map[string]interface{}{
+		"@odata.context":          "/redfish/v1/$metadata#DellJob.DellJob",
+		"@odata.id":               dellJobsURI + "/" + j.id,
+		"@odata.type":             "#DellJob.v1_5_0.DellJob",
+		"ActualRunningStartTime":  nil,
+		"ActualRunningStopTime":   nil,
+		"CompletionTime":          nil,
+		"Description":             "Job Instance",
+		"EndTime":                 "TIME_NA",
+		"Id":                      j.id,
+		"JobState":                j.state,
+		"JobType":                 j.jobType,
+		"Message":                 j.message["Message"],
+		"MessageArgs":             j.message["MessageArgs"],
+		"MessageArgs@odata.count": len(j.message["MessageArgs"].([]string)),
+		"MessageId":               j.message["MessageId"],
+		"Name":                    j.name,
+		"PercentComplete":         j.percent,
+		"StartTime":               "TIME_NOW",
+		"TargetSettingsURI":       nil,
+	}
+	if j.state != jobScheduled {
+		res["ActualRunningStartTime"] = j.start.Format(time.RFC3339)
+	}
+	if j.over() {
+		res["ActualRunningStopTime"] = j.end.Format(time.RFC3339)
+		res["CompletionTime"] = j.end.Format(time.RFC3339)
+	}
+	return res
+}
+
+// redfishJob returns the job as a Job, as found at /redfish/v1/JobService/Jobs
+func (j *job) redfishJob() map[string]interface{} {
+	res := j.common()
+	res["@odata.id"] = jobServiceJobsURI + "/" + j.id
+	res["@odata.type"] = "#Job.v1_5_1.Job"
+	res["JobState"] = jobStates[j.state].state
+	res["JobStatus"] = jobStates[j.state].status
+	return res
+}
+
+// task returns the job as a Task, as found at /redfish/v1/TaskService/Tasks
+func (j *job) task() map[string]interface{} {
+	res := j.common()
+	res["@odata.id"] = tasksURI + "/" + j.id
+	res["@odata.type"] = "#Task.v1_5_1.Task"
+	res["TaskState"] = jobStates[j.state].state
+	res["TaskStatus"] = jobStates[j.state].status
+	return res
+}
+
+// common returns the properties shared by the Job and Task views of the job
+func (j *job) common() map[string]interface{} {
+	dell := j.dellJob()
+	delete(dell, "@odata.id")
+	delete(dell, "@odata.context")
+	res := map[string]interface{}{
+		"Id":              j.id,
+		"Name":            j.name,
+		"Description":     "Job Instance",
+		"PercentComplete": j.percent,
+		"StartTime":       j.start.Format(time.RFC3339),
+		"Messages":        []interface{}{j.message},
+		"Oem":             map[string]interface{}{"Dell": dell},
+	}
+	if j.over() {
+		res["EndTime"] = j.end.Format(time.RFC3339)
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// mediaImageTypes are the extensions of the images the virtual media can mount
+var mediaImageTypes = map[string]bool{".iso": true, ".img": true}
+
+// resetManager implements the Manager.Reset action. The iDRAC is back as soon as the action returns.
+func (s *Server) resetManager(r *request, body map[string]interface{}) (*response, error) {
+	if _, err := actionParameter(s.resources[managerURI], r, body, "ResetType"); err != nil {
+		return nil, err
+	}
+	return noContent(), nil
+}
+
+// insertMedia implements the VirtualMedia.InsertMedia action
+func (s *Server) insertMedia(r *request, body map[string]interface{}) (*response, error) {
+	media := s.resources[actionResource(r.uri)]
+	if media["Inserted"] == true {
+		return nil, newError(http.StatusBadRequest, "IDRAC.2.8.VRM0012", nil)
+	}
+	image, _ := body["Image"].(string)
+	if len(image) == 0 {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{actionName(r), "Image"})
+	}
+	imageURL, err := url.Parse(image)
+	if err != nil || !mediaImageTypes[strings.ToLower(path.Ext(imageURL.Path))] {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+			[]string{image, "Image", actionName(r)}, "#/Image")
+	}
+	protocol := strings.ToUpper(imageURL.Scheme)
+	if value, isSet := body["TransferProtocolType"].(string); isSet && len(value) > 0 && value != protocol {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+			[]string{value, "TransferProtocolType", actionName(r)}, "#/TransferProtocolType")
+	}
+	if value, isSet := body["TransferMethod"].(string); isSet && len(value) > 0 && value != "Stream" {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			[]string{value, "TransferMethod", actionName(r)}, "#/TransferMethod")
+	}
+	writeProtected, isSet := body["WriteProtected"].(bool)
+	if !isSet {
+		writeProtected = true
+	}
+	merge(media, map[string]interface{}{
+		"Image":                image,
+		"ImageName":            path.Base(imageURL.Path),
+		"Inserted":             true,
+		"ConnectedVia":         "URI",
+		"TransferMethod":       "Stream",
+		"TransferProtocolType": protocol,
+		"WriteProtected":       writeProtected,
+	})
+	return noContent(), nil
+}
+
+// ejectMedia implements the VirtualMedia.EjectMedia action
+func (s *Server) ejectMedia(r *request, _ map[string]interface{}) (*response, error) {
+	media := s.resources[actionResource(r.uri)]
+	if media["Inserted"] != true {
+		return nil, newError(http.StatusBadRequest, "IDRAC.2.8.VRM0009", nil)
+	}
+	merge(media, map[string]interface{}{
+		"Image":                nil,
+		"ImageName":            nil,
+		"Inserted":             false,
+		"ConnectedVia":         "NotConnected",
+		"TransferProtocolType": nil,
+	})
+	return noContent(), nil
+}
+
+// importSSLCertificate implements the DelliDRACCardService.ImportSSLCertificate action. A custom certificate
+// is a base64 encoded PKCS#12 file, the other types are PEM encoded.
+func (s *Server) importSSLCertificate(r *request, body map[string]interface{}) (*response, error) {
+	certificateType, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "CertificateType")
+	if err != nil {
+		return nil, err
+	}
+	content, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "SSLCertificateFile")
+	if err != nil {
+		return nil, err
+	}
+	valid := strings.Contains(content, "-----BEGIN CERTIFICATE-----")
+	if certificateType == "CustomCertificate" {
+		der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(content), ""))
+		valid = err == nil && len(der) > 0 && der[0] == 0x30
+	}
+	if !valid {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+			[]string{"SSLCertificateFile", "SSLCertificateFile", actionName(r)}, "#/SSLCertificateFile")
+	}
+	return ok(successBody()), nil
+}
+
+// resetSSLConfig implements the DelliDRACCardService.SSLResetCfg action, which restores the default certificate
+func (*Server) resetSSLConfig(_ *request, _ map[string]interface{}) (*response, error) {
+	return ok(successBody()), nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// registriesURI is the collection of the registry files
+const registriesURI = serviceRootURI + "/Registries"
+
+// messageTemplate is an entry of a message registry, with %1, %2... placeholders for the arguments
+type messageTemplate struct {
+	message    string
+	severity   string
+	resolution string
+}
+
+// messageTemplates holds the messages of the Base and iDRAC registries returned by the emulator
+var messageTemplates = map[string]messageTemplate{
+	"Base.1.12.Success": {
+		"Successfully Completed Request", "OK", "None",
+	},
+	"Base.1.12.Created": {
+		"The resource has been created successfully.", "OK", "None",
+	},
+	"Base.1.12.GeneralError": {
+		"A general error has occurred. See Resolution for information on how to resolve the error.", "Critical",
+		"None",
+	},
+	"Base.1.12.NoValidSession": {
+		"There is no valid session established with the implementation.", "Critical",
+		"Establish a session before attempting any operations.",
+	},
+	"Base.1.12.ResourceMissingAtURI": {
+		"The resource at the URI %1 was not found.", "Critical",
+		"Place a valid resource at the URI or correct the URI and resubmit the request.",
+	},
+	"Base.1.12.OperationNotAllowed": {
+		"The HTTP method is not allowed on this resource.", "Critical",
+		"None",
+	},
+	"Base.1.12.MalformedJSON": {
+		"The request body submitted was malformed JSON and could not be parsed by the receiving service.", "Critical",
+		"Ensure that the request body is valid JSON and resubmit the request.",
+	},
+	"Base.1.12.PropertyUnknown": {
+		"The property %1 is not in the list of valid properties for the resource.", "Warning",
+		"Remove the unknown property from the request body and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PropertyNotWritable": {
+		"The property %1 is a read only property and cannot be assigned a value.", "Warning",
+		"Remove the property from the request body and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PropertyMissing": {
+		"The property %1 is a required property and must be included in the request.", "Warning",
+		"Ensure that the property is in the request body and has a valid value and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PropertyValueNotInList": {
+		"The value %1 for the property %2 is not in the list of acceptable values.", "Warning",
+		"Choose a value from the enumeration list that the implementation can support and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PropertyValueTypeError": {
+		"The value %1 for the property %2 is of a different type than the property can accept.", "Warning",
+		"Correct the value for the property in the request body and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PropertyValueError": {
+		"The value provided for the property %1 is not valid.", "Warning",
+		"Correct the value for the property in the request body and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.QueryParameterValueFormatError": {
+		"The value %1 for the parameter %2 is of a different format than the parameter can accept.", "Warning",
+		"Correct the value for the query parameter in the request and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.PreconditionFailed": {
+		"The ETag supplied did not match the ETag required to change this resource.", "Critical",
+		"Try the operation again using the appropriate ETag.",
+	},
+	"Base.1.12.ResourceAlreadyExists": {
+		"The requested resource of type %1 with the property %2 with the value %3 already exists.", "Critical",
+		"Do not repeat the create operation as the resource has already been created.",
+	},
+	"Base.1.12.ResourceInUse": {
+		"The change to the requested resource failed because the resource is in use or in transition.", "Warning",
+		"Remove the condition and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.ActionNotSupported": {
+		"The action %1 is not supported by the resource.", "Critical",
+		"The action supplied cannot be resubmitted to the implementation. Perhaps the action was invalid, the wrong resource " +
+			"was the target or the implementation documentation may be of assistance.",
+	},
+	"Base.1.12.ActionParameterMissing": {
+		"The action %1 requires the parameter %2 to be present in the request body.", "Critical",
+		"Supply the action with the required parameter in the request body when the request is resubmitted.",
+	},
+	"Base.1.12.ActionParameterValueNotInList": {
+		"The value %1 for the parameter %2 in the action %3 is not in the list of acceptable values.", "Warning",
+		"Choose a value from the enumeration list that the implementation can support and resubmit the request if the operation failed.",
+	},
+	"Base.1.12.ActionParameterValueFormatError": {
+		"The value %1 for the parameter %2 in the action %3 is of a different format than the parameter can accept.", "Warning",
+		"Correct the value for the parameter in the request body and resubmit the request if the operation failed.",
+	},
+	"IDRAC.2.8.JCP001": {
+		"Task successfully scheduled.", "Informational", "No response action is required.",
+	},
+	"IDRAC.2.8.PR20": {
+		"Job in progress.", "Informational", "No response action is required.",
+	},
+	"IDRAC.2.8.PR19": {
+		"Job completed successfully.", "Informational", "No response action is required.",
+	},
+	"IDRAC.2.8.SYS053": {
+		"Import of Server Configuration Profile operation completed with errors.", "Warning",
+		"Review the configuration results and retry the operation for the components which were not configured.",
+	},
+	"IDRAC.2.8.SYS044": {
+		"Unable to find the Server Configuration Profile file %1 on the network share.", "Critical",
+		"Make sure the file is available on the network share and retry the operation.",
+	},
+	"IDRAC.2.8.RED003": {
+		"Unable to transfer the image file %1.", "Critical",
+		"Make sure the image location and the transfer protocol are correct and retry the operation.",
+	},
+	"IDRAC.2.8.RED004": {
+		"The package %1 is not a valid Dell Update Package.", "Critical",
+		"Make sure the package is a Dell Update Package supported by the server and retry the operation.",
+	},
+	"IDRAC.2.8.SUP029": {
+		"Unable to retrieve the list of updates because no repository update was run.", "Warning",
+		"Run InstallFromRepository and retry the operation.",
+	},
+	"IDRAC.2.8.SYS011": {
+		"Pending configuration values are already committed, unable to perform another set operation.", "Warning",
+		"Wait for the scheduled job to complete or delete the configuration job before attempting more set attribute operations.",
+	},
+	"IDRAC.2.8.VRM0012": {
+		"The requested Virtual Media operation cannot be completed because the Virtual Media device is already connected.",
+		"Warning", "Eject the Virtual Media and retry the operation.",
+	},
+	"IDRAC.2.8.VRM0009": {
+		"Virtual Media is detached or Virtual Media devices are already in use.", "Warning",
+		"Make sure that Virtual Media is attached and the Virtual Media devices are available, and retry the operation.",
+	},
+}
+
+// newMessage returns the Redfish Message of the registry entry identified by id, with the arguments
+// substituted in its text. The optional related properties are JSON pointers in the request body.
+func newMessage(id string, args []string, related ...string) map[string]interface{} {
+	template, ok := messageTemplates[id]
+	if !ok {
+		panic(fmt.Sprintf("unknown message %s", id))
+	}
+	text := template.message
+	for i := len(args); i > 0; i-- {
+		text = strings.ReplaceAll(text, fmt.Sprintf("%%%d", i), args[i-1])
+	}
+	if args == nil {
+		args = []string{}
+	}
+	if related == nil {
+		related = []string{}
+	}
+	return map[string]interface{}{
+		"@odata.type":       "#Message.v1_1_1.Message",
+		"MessageId":         id,
+		"Message":           text,
+		"MessageArgs":       args,
+		"RelatedProperties": related,
+		"Severity":          template.severity,
+		"Resolution":        template.resolution,
+	}
+}
+
+// loadMessageRegistries adds the message registries of the message templates, such as Base.1.12, and their
+// registry files to the registries of the service
+func (s *Server) loadMessageRegistries() {
+	ids := make([]string, 0, len(messageTemplates))
+	for id := range messageTemplates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var names []string
+	registries := make(map[string]map[string]interface{})
+	for _, id := range ids {
+		separator := strings.LastIndex(id, ".")
+		registry, key := id[:separator], id[separator+1:]
+		if _, exists := registries[registry]; !exists {
+			registries[registry] = make(map[string]interface{})
+			names = append(names, registry)
+		}
+		template := messageTemplates[id]
+		registries[registry][key] = map[string]interface{}{
+			"Description":  template.message,
+			"Message":      template.message,
+			"Severity":     template.severity,
+			"NumberOfArgs": strings.Count(template.message, "%"),
+			"Resolution":   template.resolution,
+		}
+	}
+
+	collection := s.resources[registriesURI]
+	members, _ := collection["Members"].([]interface{})
+	for _, registry := range names {
+		prefix, version, _ := strings.Cut(registry, ".")
+		fileURI := registriesURI + "/" + registry
+		uri := fileURI + "/" + registry + ".json"
+		s.resources[fileURI] = map[string]interface{}{
+			"@odata.id":   fileURI,
+			"@odata.type": "#MessageRegistryFile.v1_1_3.MessageRegistryFile",
+			"Id":          registry,
+			"Name":        prefix + " Message Registry File",
+			"Description": "Registry Definition File for " + prefix,
+			"Languages":   []interface{}{"en"},
+			"Registry":    registry,
+			"Location":    []interface{}{map[string]interface{}{"Language": "en", "Uri": uri}},
+		}
+		s.resources[uri] = map[string]interface{}{
+			"@odata.id":       uri,
+			"@odata.type":     "#MessageRegistry.v1_4_1.MessageRegistry",
+			"Id":              registry + ".0",
+			"Name":            prefix + " Message Registry",
+			"Language":        "en",
+			"OwningEntity":    "Dell",
+			"RegistryPrefix":  prefix,
+			"RegistryVersion": version + ".0",
+			"Messages":        registries[registry],
+		}
+		members = append(members, map[string]interface{}{"@odata.id": fileURI})
+	}
+	collection["Members"] = members
+	collection["Members@odata.count"] = len(members)
+}
+
+// redfishError is an error answered to a request, with the messages of its @Message.ExtendedInfo
+type redfishError struct {
+	status   int
+	messages []map[string]interface{}
+}
+
+// Error implements error
+func (e *redfishError) Error() string {
+	texts := make([]string, 0, len(e.messages))
+	for _, m := range e.messages {
+		texts = append(texts, fmt.Sprint(m["Message"]))
+	}
+	return strings.Join(texts, " ")
+}
+
+// body returns the Redfish error response
+func (e *redfishError) body() map[string]interface{} {
+	return map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":                  "Base.1.12.GeneralError",
+			"message":               "A general error has occurred. See ExtendedInfo for more information",
+			"@Message.ExtendedInfo": e.messages,
+		},
+	}
+}
+
+// newError returns a redfishError with a single message
+func newError(status int, id string, args []string, related ...string) *redfishError {
+	return &redfishError{status: status, messages: []map[string]interface{}{newMessage(id, args, related...)}}
+}
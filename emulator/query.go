@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// odataProperties are the annotations of a resource kept by $select
+var odataProperties = map[string]bool{
+	"@odata.id":      true,
+	"@odata.type":    true,
+	"@odata.context": true,
+	"@odata.etag":    true,
+}
+
+// queryResponse applies the query parameters of the request to the resource of a GET response
+func (s *Server) queryResponse(r *request, resp *response) (*response, error) {
+	body, isObject := resp.body.(map[string]interface{})
+	if resp.status != http.StatusOK || !isObject {
+		return resp, nil
+	}
+	body, err := s.applyQuery(r, body)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: resp.status, headers: resp.headers, body: body}, nil
+}
+
+// applyQuery implements the $expand and $select query parameters the service root advertises in its
+// ProtocolFeaturesSupported. Expanded references are replaced with the resources they designate, up to the
+// single level of MaxLevels. $select keeps the given top level properties, and the annotations of the resource.
+func (s *Server) applyQuery(r *request, body map[string]interface{}) (map[string]interface{}, error) {
+	query := r.URL.Query()
+	expand, selected := query.Get("$expand"), query.Get("$select")
+	if len(expand) == 0 && len(selected) == 0 {
+		return body, nil
+	}
+	result := deepCopy(body).(map[string]interface{})
+	if len(expand) > 0 {
+		kind, err := parseExpand(expand)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range result {
+			result[name] = s.expand(r, value, kind, name == "Links")
+		}
+	}
+	if len(selected) > 0 {
+		keep := make(map[string]bool)
+		for _, name := range strings.Split(selected, ",") {
+			// nested properties, such as Status/Health, keep their top level property
+			name, _, _ = strings.Cut(strings.TrimSpace(name), "/")
+			keep[name] = true
+		}
+		for name := range result {
+			if !keep[name] && !odataProperties[name] {
+				delete(result, name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// parseExpand returns the kind of the references to expand, "*", "." or "~", of an $expand value such as
+// .($levels=1)
+func parseExpand(expand string) (string, error) {
+	kind, options, hasOptions := strings.Cut(expand, "(")
+	if kind != "*" && kind != "." && kind != "~" {
+		return "", newError(http.StatusBadRequest, "Base.1.12.QueryParameterValueFormatError", []string{expand, "$expand"})
+	}
+	if hasOptions {
+		levels, found := strings.CutPrefix(strings.TrimSuffix(options, ")"), "$levels=")
+		if value, err := strconv.Atoi(levels); !found || err != nil || value < 1 {
+			return "", newError(http.StatusBadRequest, "Base.1.12.QueryParameterValueFormatError", []string{expand, "$expand"})
+		}
+	}
+	return kind, nil
+}
+
+// expand replaces the references of a property with their resources, for the kind of expansion
+func (s *Server) expand(r *request, value interface{}, kind string, inLinks bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if uri := linkURI(v); len(uri) > 0 && len(v) == 1 {
+			if (kind == "." && inLinks) || (kind == "~" && !inLinks) {
+				return v
+			}
+			if res, ok := s.get(r, uri); ok {
+				return res
+			}
+			return v
+		}
+		for name, nested := range v {
+			v[name] = s.expand(r, nested, kind, inLinks || name == "Links")
+		}
+		return v
+	case []interface{}:
+		for i, element := range v {
+			v[i] = s.expand(r, element, kind, inLinks)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// get returns the resource at the URI as served by GET, for the expansion of a reference
+func (s *Server) get(r *request, uri string) (map[string]interface{}, bool) {
+	for _, rt := range routes {
+		if rt.method != http.MethodGet {
+			continue
+		}
+		match := rt.pattern.FindStringSubmatch(uri)
+		if match == nil {
+			continue
+		}
+		resp, err := rt.handle(s, &request{Request: r.Request, uri: uri, params: match[1:]})
+		if err != nil || resp.status != http.StatusOK {
+			return nil, false
+		}
+		body, isObject := resp.body.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+		return deepCopy(body).(map[string]interface{}), true
+	}
+	return nil, false
+}
@@ -0,0 +1,283 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fixtures hold the resources of the emulated server, as maps of @odata.id to resource
+//
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// readOnlyProperties cannot be changed by PATCH on any resource
+var readOnlyProperties = map[string]bool{
+	"Id":      true,
+	"Actions": true,
+	"Links":   true,
+	"Members": true,
+	"Status":  true,
+}
+
+func (s *Server) loadFixtures() error {
+	entries, err := fixtures.ReadDir("fixtures")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		content, err := fixtures.ReadFile("fixtures/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		var resources map[string]map[string]interface{}
+		if err := json.Unmarshal(content, &resources); err != nil {
+			return fmt.Errorf("unable to load the fixture %s: %w", entry.Name(), err)
+		}
+		for uri, res := range resources {
+			res["@odata.id"] = uri
+			s.resources[uri] = res
+		}
+	}
+	return nil
+}
+
+// getResource returns the resource at the URI of the request
+func (s *Server) getResource(r *request) (*response, error) {
+	if r.uri == systemURI {
+		s.advancePower()
+	}
+	res, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	return ok(res), nil
+}
+
+// resource returns the resource at the URI, or a 404 error
+func (s *Server) resource(uri string) (map[string]interface{}, error) {
+	res, exists := s.resources[uri]
+	if !exists {
+		return nil, newError(http.StatusNotFound, "Base.1.12.ResourceMissingAtURI", []string{uri})
+	}
+	return res, nil
+}
+
+// patchResource merges the body of the request in the resource. The properties of the body must exist in the resource.
+func (s *Server) patchResource(r *request) (*response, error) {
+	res, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	if _, isCollection := res["Members"]; isCollection {
+		return nil, newError(http.StatusMethodNotAllowed, "Base.1.12.OperationNotAllowed", nil)
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPatch(res, body, ""); err != nil {
+		return nil, err
+	}
+	merge(res, body)
+	return ok(successBody()), nil
+}
+
+// checkPatch checks that the properties of the body exist in the resource and can be written
+func checkPatch(res, body map[string]interface{}, pointer string) error {
+	var messages []map[string]interface{}
+	for _, name := range sortedKeys(body) {
+		if strings.HasPrefix(name, "@") {
+			continue
+		}
+		property := pointer + "/" + name
+		current, exists := res[name]
+		switch {
+		case !exists:
+			messages = append(messages, newMessage("Base.1.12.PropertyUnknown", []string{name}, "#"+property))
+		case readOnlyProperties[name] || strings.HasPrefix(name, "@odata"):
+			messages = append(messages, newMessage("Base.1.12.PropertyNotWritable", []string{name}, "#"+property))
+		default:
+			nested, isObject := body[name].(map[string]interface{})
+			currentNested, wasObject := current.(map[string]interface{})
+			if isObject && wasObject {
+				if err := checkPatch(currentNested, nested, property); err != nil {
+					messages = append(messages, err.(*redfishError).messages...)
+				}
+			}
+		}
+	}
+	if len(messages) > 0 {
+		return &redfishError{status: http.StatusBadRequest, messages: messages}
+	}
+	return nil
+}
+
+// createMember adds the body of the request as a new member of the collection
+func (s *Server) createMember(r *request) (*response, error) {
+	res, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	if _, isCollection := res["Members"]; !isCollection || !s.collections[r.uri] {
+		return nil, newError(http.StatusMethodNotAllowed, "Base.1.12.OperationNotAllowed", nil)
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.uri == accountsURI {
+		return s.createAccount(body)
+	}
+	member := s.addMember(r.uri, body)
+	return &response{
+		status:  http.StatusCreated,
+		headers: map[string]string{"Location": member["@odata.id"].(string)},
+		body:    member,
+	}, nil
+}
+
+// deleteMember removes the resource from its collection
+func (s *Server) deleteMember(r *request) (*response, error) {
+	if _, err := s.resource(r.uri); err != nil {
+		return nil, err
+	}
+	collection := path.Dir(r.uri)
+	if !s.collections[collection] {
+		return nil, newError(http.StatusMethodNotAllowed, "Base.1.12.OperationNotAllowed", nil)
+	}
+	s.removeMember(collection, r.uri)
+	if collection == accountsURI {
+		delete(s.passwords, path.Base(r.uri))
+	}
+	return ok(successBody()), nil
+}
+
+// addMember stores the resource as a new member of the collection, with the next free numeric Id
+// unless the resource has an Id
+func (s *Server) addMember(collection string, member map[string]interface{}) map[string]interface{} {
+	id, hasID := member["Id"].(string)
+	for n := 1; !hasID; n++ {
+		if _, exists := s.resources[collection+"/"+strconv.Itoa(n)]; !exists {
+			id, hasID = strconv.Itoa(n), true
+		}
+	}
+	uri := collection + "/" + id
+	member["@odata.id"] = uri
+	member["Id"] = id
+	s.resources[uri] = member
+
+	res := s.resources[collection]
+	members, _ := res["Members"].([]interface{})
+	res["Members"] = append(members, link(uri))
+	res["Members@odata.count"] = len(members) + 1
+	return member
+}
+
+// removeMember removes the resource and its link in the collection
+func (s *Server) removeMember(collection, uri string) {
+	delete(s.resources, uri)
+	res, exists := s.resources[collection]
+	if !exists {
+		return
+	}
+	members, _ := res["Members"].([]interface{})
+	kept := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		if linkURI(m) != uri {
+			kept = append(kept, m)
+		}
+	}
+	res["Members"] = kept
+	res["Members@odata.count"] = len(kept)
+}
+
+// decodeBody decodes the JSON object of the request body
+func decodeBody(r *request) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body == nil {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.MalformedJSON", nil)
+	}
+	return body, nil
+}
+
+// link returns a reference to the resource at the URI
+func link(uri string) map[string]interface{} {
+	return map[string]interface{}{"@odata.id": uri}
+}
+
+// linkURI returns the URI of a reference
+func linkURI(ref interface{}) string {
+	if m, isMap := ref.(map[string]interface{}); isMap {
+		uri, _ := m["@odata.id"].(string)
+		return uri
+	}
+	return ""
+}
+
+// merge copies the properties of patch in res, merging the nested objects
+func merge(res, patch map[string]interface{}) {
+	for k, v := range patch {
+		if strings.HasPrefix(k, "@Redfish.") {
+			continue
+		}
+		nested, isObject := v.(map[string]interface{})
+		current, wasObject := res[k].(map[string]interface{})
+		if isObject && wasObject {
+			merge(current, nested)
+			continue
+		}
+		res[k] = deepCopy(v)
+	}
+}
+
+// deepCopy copies a value decoded from JSON
+func deepCopy(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(value))
+		for i, e := range value {
+			c[i] = deepCopy(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,281 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const dellAttributesURI = managerURI + "/Oem/Dell/DellAttributes/"
+
+// scpComponent is a component of a Server Configuration Profile, whose attributes are found in an attribute set
+type scpComponent struct {
+	fqdd string
+	// uri is the resource holding the attributes of the component
+	uri string
+	// registry is the attribute registry of the component
+	registry string
+	// groups restrict the attributes of the component to some groups, for the targets covering part of it
+	groups []string
+}
+
+// scpTargets are the components exported and imported for the targets of the SCP actions
+var scpTargets = map[string][]scpComponent{
+	"IDRAC":               {{fqdd: "iDRAC.Embedded.1", uri: dellAttributesURI + "iDRAC.Embedded.1", registry: managerRegistryURI}},
+	"System":              {{fqdd: "System.Embedded.1", uri: dellAttributesURI + "System.Embedded.1", registry: managerRegistryURI}},
+	"LifecycleController": {{fqdd: "LifecycleController.Embedded.1", uri: dellAttributesURI + "LifecycleController.Embedded.1", registry: managerRegistryURI}},
+	"BIOS":                {{fqdd: biosFQDD, uri: biosURI, registry: biosRegistryURI}},
+	"EventFilters": {{
+		fqdd: "iDRAC.Embedded.1", uri: dellAttributesURI + "iDRAC.Embedded.1", registry: managerRegistryURI,
+		groups: []string{"SysLog", "SNMPAlert", "EmailAlert"},
+	}},
+}
+
+// scpAttribute is an attribute of a Server Configuration Profile
+type scpAttribute struct {
+	Name  string `xml:"Name,attr" json:"Name"`
+	Value string `xml:",chardata" json:"Value"`
+}
+
+// scpProfile is a Server Configuration Profile, in the layout of both its XML and JSON formats
+type scpProfile struct {
+	XMLName    xml.Name `xml:"SystemConfiguration" json:"-"`
+	Model      string   `xml:"Model,attr" json:"Model"`
+	ServiceTag string   `xml:"ServiceTag,attr" json:"ServiceTag"`
+	TimeStamp  string   `xml:"TimeStamp,attr" json:"TimeStamp"`
+	Components []struct {
+		FQDD       string         `xml:"FQDD,attr" json:"FQDD"`
+		Attributes []scpAttribute `xml:"Attribute" json:"Attributes"`
+	} `xml:"Component" json:"Components"`
+}
+
+// exportSystemConfiguration implements the export of a Server Configuration Profile. The profile is returned by
+// the task monitor of a local export, and kept by the emulator for the exports to a network share.
+func (s *Server) exportSystemConfiguration(r *request, body map[string]interface{}) (*response, error) {
+	share, _ := body["ShareParameters"].(map[string]interface{})
+	format, _ := body["ExportFormat"].(string)
+	if len(format) == 0 {
+		format = "XML"
+	}
+	if format != "XML" && format != "JSON" {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			[]string{format, "ExportFormat", actionName(r)}, "#/ExportFormat")
+	}
+	profile, err := s.exportProfile(targetsOf(share), format)
+	if err != nil {
+		return nil, err
+	}
+
+	j := s.newJob("Export: Server Configuration Profile", "ExportConfiguration", false)
+	shareType, _ := share["ShareType"].(string)
+	if len(shareType) == 0 || shareType == "Local" || shareType == "LOCAL" {
+		j.result = profile
+	} else {
+		j.apply = append(j.apply, func() { s.shares[shareKey(share)] = profile })
+	}
+	return accepted(tasksURI + "/" + j.id), nil
+}
+
+// exportProfile returns the Server Configuration Profile of the targets in the format
+func (s *Server) exportProfile(targets []string, format string) ([]byte, error) {
+	var profile scpProfile
+	profile.Model, profile.ServiceTag = "PowerEdge R650", "EMU0001"
+	profile.TimeStamp = s.now().Format("Mon Jan 2 15:04:05 2006")
+	for _, component := range scpComponents(targets) {
+		set := attributeSet{registry: s.resources[component.registry], values: s.attributes(component.uri)}
+		attributes, _ := set.registryEntries()
+		var exported []scpAttribute
+		for _, name := range sortedKeys(set.values) {
+			if attributes[name]["Type"] == "Password" || !inGroups(name, component.groups) {
+				continue
+			}
+			exported = append(exported, scpAttribute{Name: scpName(component, name), Value: toString(set.values[name])})
+		}
+		profile.Components = append(profile.Components, struct {
+			FQDD       string         `xml:"FQDD,attr" json:"FQDD"`
+			Attributes []scpAttribute `xml:"Attribute" json:"Attributes"`
+		}{component.fqdd, exported})
+	}
+	if format == "JSON" {
+		return json.MarshalIndent(map[string]interface{}{"SystemConfiguration": profile}, "", "  ")
+	}
+	content, err := xml.MarshalIndent(profile, "", "  ")
+	return append(content, '\n'), err
+}
+
+// importSystemConfiguration implements the import of a Server Configuration Profile, from the buffer of the
+// request or from a network share where it was exported. The attributes which cannot be set are reported by
+// a job completed with errors.
+func (s *Server) importSystemConfiguration(r *request, body map[string]interface{}) (*response, error) {
+	share, _ := body["ShareParameters"].(map[string]interface{})
+	content := []byte(toString(body["ImportBuffer"]))
+	if len(content) == 0 {
+		content = s.shares[shareKey(share)]
+	}
+	j := s.newJob("Import: Server Configuration Profile", "ImportConfiguration", false)
+	if len(content) == 0 {
+		j.run = func() outcome { return failure("IDRAC.2.8.SYS044", toString(share["FileName"])) }
+		return accepted(tasksURI + "/" + j.id), nil
+	}
+	profile, err := parseProfile(content)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+			[]string{"ImportBuffer", "ImportBuffer", actionName(r)}, "#/ImportBuffer")
+	}
+	targets := targetsOf(share)
+	j.run = func() outcome { return s.importProfile(profile, targets) }
+	return accepted(tasksURI + "/" + j.id), nil
+}
+
+// importProfile sets the attributes of the profile which belong to the targets
+func (s *Server) importProfile(profile *scpProfile, targets []string) outcome {
+	result := success()
+	for _, component := range scpComponents(targets) {
+		set := attributeSet{
+			registry:  s.resources[component.registry],
+			values:    s.attributes(component.uri),
+			secretKey: component.uri + "#",
+		}
+		for _, c := range profile.Components {
+			if c.FQDD != component.fqdd {
+				continue
+			}
+			for _, attribute := range c.Attributes {
+				name := attributeName(component, attribute.Name)
+				if !inGroups(name, component.groups) {
+					continue
+				}
+				value := typedValue(set, name, attribute.Value)
+				if err := set.check(map[string]interface{}{name: value}); err != nil {
+					result = outcome{state: jobCompletedWithErrors, message: newMessage("IDRAC.2.8.SYS053", nil)}
+					continue
+				}
+				s.setAttributes(set, map[string]interface{}{name: value})
+			}
+		}
+	}
+	return result
+}
+
+// parseProfile decodes a Server Configuration Profile in the XML or JSON format
+func parseProfile(content []byte) (*scpProfile, error) {
+	var profile scpProfile
+	trimmed := strings.TrimSpace(string(content))
+	if strings.HasPrefix(trimmed, "{") {
+		var doc struct{ SystemConfiguration *scpProfile }
+		if err := json.Unmarshal([]byte(trimmed), &doc); err != nil || doc.SystemConfiguration == nil {
+			return nil, fmt.Errorf("invalid profile: %w", err)
+		}
+		return doc.SystemConfiguration, nil
+	}
+	if err := xml.Unmarshal([]byte(trimmed), &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// attributes returns the attributes of the resource
+func (s *Server) attributes(uri string) map[string]interface{} {
+	values, _ := s.resources[uri]["Attributes"].(map[string]interface{})
+	return values
+}
+
+// typedValue converts the text of a profile attribute to the type of the attribute in the registry
+func typedValue(set attributeSet, name, text string) interface{} {
+	attributes, _ := set.registryEntries()
+	switch attributes[name]["Type"] {
+	case "Integer":
+		var number float64
+		if _, err := fmt.Sscan(text, &number); err == nil {
+			return number
+		}
+	case "Boolean":
+		return text == "true" || text == "True"
+	}
+	return text
+}
+
+// targetsOf returns the targets of the share parameters of an SCP action
+func targetsOf(share map[string]interface{}) []string {
+	var targets []string
+	switch value := share["Target"].(type) {
+	case []interface{}:
+		for _, t := range value {
+			targets = append(targets, toString(t))
+		}
+	case string:
+		targets = strings.Split(value, ",")
+	}
+	if len(targets) == 0 {
+		targets = []string{"ALL"}
+	}
+	return targets
+}
+
+// scpComponents returns the components of the targets
+func scpComponents(targets []string) []scpComponent {
+	var components []scpComponent
+	for _, target := range targets {
+		if target == "ALL" {
+			return []scpComponent{scpTargets["IDRAC"][0], scpTargets["System"][0], scpTargets["LifecycleController"][0],
+				scpTargets["BIOS"][0]}
+		}
+		components = append(components, scpTargets[strings.TrimSpace(target)]...)
+	}
+	return components
+}
+
+// scpName returns the name of an attribute in a profile. The iDRAC attributes are named Group.Index#Name there.
+func scpName(component scpComponent, name string) string {
+	if component.registry != managerRegistryURI {
+		return name
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i] + "#" + name[i+1:]
+	}
+	return name
+}
+
+// attributeName returns the name of the attribute of a profile
+func attributeName(component scpComponent, name string) string {
+	if component.registry != managerRegistryURI {
+		return name
+	}
+	return strings.Replace(name, "#", ".", 1)
+}
+
+// inGroups tells whether the attribute belongs to one of the groups, an empty list meaning all of them
+func inGroups(name string, groups []string) bool {
+	for _, group := range groups {
+		if strings.HasPrefix(name, group+".") {
+			return true
+		}
+	}
+	return len(groups) == 0
+}
+
+// shareKey identifies a file on a network share
+func shareKey(share map[string]interface{}) string {
+	return strings.Join([]string{
+		toString(share["ShareType"]), toString(share["IPAddress"]), toString(share["ShareName"]), toString(share["FileName"]),
+	}, "|")
+}
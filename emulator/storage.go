@@ -0,0 +1,308 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+)
+
+// raidDrives are the minimum and maximum numbers of drives of the RAID types, 0 meaning no maximum
+var raidDrives = map[string][2]int{
+	"RAID0":  {1, 0},
+	"RAID1":  {2, 2},
+	"RAID5":  {3, 0},
+	"RAID6":  {4, 0},
+	"RAID10": {4, 0},
+}
+
+// volumeTypes are the volume types of the RAID types
+var volumeTypes = map[string]string{
+	"RAID0":  "NonRedundant",
+	"RAID1":  "Mirrored",
+	"RAID5":  "StripedWithParity",
+	"RAID6":  "StripedWithParity",
+	"RAID10": "SpannedMirrors",
+}
+
+// volumePolicies are the values accepted for the cache policies of the volumes
+var volumePolicies = map[string][]interface{}{
+	"ReadCachePolicy":  {"Off", "ReadAhead", "AdaptiveReadAhead"},
+	"WriteCachePolicy": {"ProtectedWriteBack", "UnprotectedWriteBack", "WriteThrough"},
+	"DiskCachePolicy":  {"Enabled", "Disabled"},
+}
+
+// volumeSettings are the properties of a volume which can be changed
+var volumeSettings = map[string]bool{
+	"Name":             true,
+	"DisplayName":      true,
+	"ReadCachePolicy":  true,
+	"WriteCachePolicy": true,
+	"Encrypted":        true,
+	"EncryptionTypes":  true,
+	"Oem":              true,
+}
+
+// createVolume implements the creation of a virtual disk, run by a RAID configuration job
+func (s *Server) createVolume(r *request) (*response, error) {
+	storageURI := path.Dir(r.uri)
+	if _, err := s.resource(storageURI); err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	applyTime, err := checkOperationApplyTime(s.resources[r.uri], body)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := body["Name"].(string)
+	if len(name) == 0 {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyMissing", []string{"Name"}, "#/Name")
+	}
+	drives, err := s.volumeDrives(storageURI, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPolicies(body); err != nil {
+		return nil, err
+	}
+
+	dropEmpty(body)
+	volume := s.newVolume(r.uri, name, body, drives)
+	j := s.newJob("Configure: "+path.Base(storageURI), "RAIDConfiguration", applyTime == "OnReset")
+	j.apply = append(j.apply, func() {
+		s.addMember(r.uri, volume)
+		settings := make(map[string]interface{}, len(volumeSettings))
+		for name := range volumeSettings {
+			settings[name] = deepCopy(volume[name])
+		}
+		s.resources[volume["@odata.id"].(string)+"/Settings"] = settings
+	})
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
+
+// volumeDrives returns the drives of a new volume, whose number must suit its RAID type
+func (s *Server) volumeDrives(storageURI string, body map[string]interface{}) ([]interface{}, error) {
+	raidType, _ := body["RAIDType"].(string)
+	limits, known := raidDrives[raidType]
+	if !known {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{raidType, "RAIDType"}, "#/RAIDType")
+	}
+	drives, _ := body["Drives"].([]interface{})
+	storageDrives, _ := s.resources[storageURI]["Drives"].([]interface{})
+	for _, d := range drives {
+		if !contains(linkURIs(storageDrives), linkURI(d)) {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyValueError", []string{"Drives"}, "#/Drives")
+		}
+	}
+	if len(drives) < limits[0] || (limits[1] > 0 && len(drives) > limits[1]) {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyValueError", []string{"Drives"}, "#/Drives")
+	}
+	return drives, nil
+}
+
+// newVolume returns the volume created by the body of the request
+func (s *Server) newVolume(volumesURI, name string, body map[string]interface{}, drives []interface{}) map[string]interface{} {
+	var id string
+	for n := 0; len(id) == 0; n++ {
+		candidate := fmt.Sprintf("Disk.Virtual.%d:%s", n, path.Base(path.Dir(volumesURI)))
+		if _, exists := s.resources[volumesURI+"/"+candidate]; !exists {
+			id = candidate
+		}
+	}
+	var driveSize float64
+	for _, d := range drives {
+		size, _ := s.resources[linkURI(d)]["CapacityBytes"].(float64)
+		if driveSize == 0 || size < driveSize {
+			driveSize = size
+		}
+	}
+	capacity, _ := body["CapacityBytes"].(float64)
+	if capacity == 0 {
+		capacity = volumeCapacity(body["RAIDType"].(string), len(drives), driveSize)
+	}
+	volume := map[string]interface{}{
+		"@odata.type":        "#Volume.v1_8_0.Volume",
+		"Id":                 id,
+		"Name":               name,
+		"DisplayName":        name,
+		"Description":        name,
+		"RAIDType":           body["RAIDType"],
+		"VolumeType":         volumeTypes[body["RAIDType"].(string)],
+		"CapacityBytes":      capacity,
+		"OptimumIOSizeBytes": 65536,
+		"ReadCachePolicy":    "Off",
+		"WriteCachePolicy":   "WriteThrough",
+		"Encrypted":          false,
+		"EncryptionTypes":    []interface{}{"NativeDriveEncryption"},
+		"Links":              map[string]interface{}{"Drives": deepCopy(drives), "Drives@odata.count": len(drives)},
+		"Oem": map[string]interface{}{
+			"Dell": map[string]interface{}{
+				"DellVolume": map[string]interface{}{"DiskCachePolicy": "Disabled"},
+			},
+		},
+		"Status": map[string]interface{}{"Health": "OK", "State": "Enabled"},
+		"@Redfish.Settings": map[string]interface{}{
+			"SettingsObject":      link(volumesURI + "/" + id + "/Settings"),
+			"SupportedApplyTimes": []interface{}{"Immediate", "OnReset"},
+		},
+	}
+	if size, isSet := body["OptimumIOSizeBytes"].(float64); isSet && size > 0 {
+		volume["OptimumIOSizeBytes"] = size
+	}
+	for name := range volumeSettings {
+		if value, isSet := body[name]; isSet {
+			merge(volume, map[string]interface{}{name: value})
+		}
+	}
+	return volume
+}
+
+// volumeCapacity returns the largest capacity of a volume on the drives
+func volumeCapacity(raidType string, drives int, driveSize float64) float64 {
+	switch raidType {
+	case "RAID1", "RAID10":
+		return driveSize * float64(drives) / 2
+	case "RAID5":
+		return driveSize * float64(drives-1)
+	case "RAID6":
+		return driveSize * float64(drives-2)
+	default:
+		return driveSize * float64(drives)
+	}
+}
+
+// patchVolume implements the changes of a volume, run by a RAID configuration job
+func (s *Server) patchVolume(r *request) (*response, error) {
+	volumeURI := path.Dir(r.uri)
+	volume, err := s.resource(volumeURI)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkApplyTime(volume, body); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(body) {
+		if name[0] != '@' && !volumeSettings[name] {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyNotWritable", []string{name}, "#/"+name)
+		}
+	}
+	if err := checkPolicies(body); err != nil {
+		return nil, err
+	}
+
+	dropEmpty(body)
+	applyTime, _ := body["@Redfish.SettingsApplyTime"].(map[string]interface{})
+	j := s.newJob("Configure: "+params(r, 0), "RAIDConfiguration", applyTime["ApplyTime"] == "OnReset")
+	j.apply = append(j.apply, func() { merge(volume, body) })
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
+
+// deleteVolume implements the deletion of a volume, run by a RAID configuration job
+func (s *Server) deleteVolume(r *request) (*response, error) {
+	if _, err := s.resource(r.uri); err != nil {
+		return nil, err
+	}
+	j := s.newJob("Configure: "+params(r, 0), "RAIDConfiguration", false)
+	j.apply = append(j.apply, func() {
+		s.removeMember(path.Dir(r.uri), r.uri)
+		delete(s.resources, r.uri+"/Settings")
+	})
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
+
+// checkOperationApplyTime checks the @Redfish.OperationApplyTime of the body against the ones supported by the collection
+func checkOperationApplyTime(collection, body map[string]interface{}) (string, error) {
+	applyTime, _ := body["@Redfish.OperationApplyTime"].(string)
+	if len(applyTime) == 0 {
+		return "Immediate", nil
+	}
+	support, _ := collection["@Redfish.OperationApplyTimeSupport"].(map[string]interface{})
+	supported, _ := support["SupportedValues"].([]interface{})
+	if !contains(supported, applyTime) {
+		return "", newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{applyTime, "@Redfish.OperationApplyTime"},
+			"#/@Redfish.OperationApplyTime")
+	}
+	return applyTime, nil
+}
+
+// checkPolicies checks the cache policies of a volume. Empty values are ignored.
+func checkPolicies(body map[string]interface{}) error {
+	values := map[string]interface{}{
+		"ReadCachePolicy":  body["ReadCachePolicy"],
+		"WriteCachePolicy": body["WriteCachePolicy"],
+		"DiskCachePolicy":  diskCachePolicy(body),
+	}
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		if value == nil || value == "" {
+			continue
+		}
+		if !contains(volumePolicies[name], value) {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(value), name}, "#/"+name)
+		}
+	}
+	return nil
+}
+
+// diskCachePolicy returns the Dell disk cache policy of the body
+func diskCachePolicy(body map[string]interface{}) string {
+	oem, _ := body["Oem"].(map[string]interface{})
+	dell, _ := oem["Dell"].(map[string]interface{})
+	volume, _ := dell["DellVolume"].(map[string]interface{})
+	policy, _ := volume["DiskCachePolicy"].(string)
+	return policy
+}
+
+// dropEmpty removes the empty strings of the body, which the provider sends for the properties not configured
+func dropEmpty(body map[string]interface{}) {
+	for k, v := range body {
+		switch value := v.(type) {
+		case string:
+			if len(value) == 0 {
+				delete(body, k)
+			}
+		case map[string]interface{}:
+			dropEmpty(value)
+		}
+	}
+}
+
+// linkURIs returns the URIs of the references
+func linkURIs(refs []interface{}) []interface{} {
+	uris := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		uris = append(uris, linkURI(ref))
+	}
+	return uris
+}
+
+// params returns the submatch of the route pattern at the index, or an empty string
+func params(r *request, i int) string {
+	if i < len(r.params) {
+		return r.params[i]
+	}
+	return ""
+}
@@ -0,0 +1,276 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"net/http"
+)
+
+const (
+	biosURI        = systemURI + "/Bios"
+	biosFQDD       = "BIOS.Setup.1-1"
+	powerOn        = "On"
+	powerOff       = "Off"
+	powerOnGoing   = "PoweringOn"
+	powerOffGoing  = "PoweringOff"
+	bootOptionsURI = systemURI + "/BootOptions"
+)
+
+// bootOverrideValues are the values accepted for the boot source override properties, besides the target
+var bootOverrideValues = map[string][]string{
+	"BootSourceOverrideEnabled": {"Once", "Continuous", "Disabled"},
+	"BootSourceOverrideMode":    {"UEFI", "Legacy"},
+}
+
+// powerSequence holds the power states the system goes through after a reset
+type powerSequence struct {
+	// states are taken one by one, each time the system is read
+	states []string
+	// boot tells whether the sequence starts the system, which runs the jobs applied on reset
+	boot bool
+}
+
+// advancePower moves the system to the next state of the power sequence
+func (s *Server) advancePower() {
+	if len(s.power.states) == 0 {
+		return
+	}
+	s.resources[systemURI]["PowerState"] = s.power.states[0]
+	s.power.states = s.power.states[1:]
+	if len(s.power.states) == 0 && s.power.boot {
+		s.power.boot = false
+		s.releaseJobs()
+	}
+}
+
+// resetSystem implements the ComputerSystem.Reset action
+func (s *Server) resetSystem(r *request, body map[string]interface{}) (*response, error) {
+	resetType, err := actionParameter(s.resources[systemURI], r, body, "ResetType")
+	if err != nil {
+		return nil, err
+	}
+	// A reset interrupts the transitions of the previous one
+	for len(s.power.states) > 0 {
+		s.advancePower()
+	}
+
+	on := s.resources[systemURI]["PowerState"] == powerOn
+	switch resetType {
+	case "On", "ForceOn":
+		if !on {
+			s.power = powerSequence{states: []string{powerOnGoing, powerOn}, boot: true}
+		}
+	case "ForceOff", "GracefulShutdown":
+		if on {
+			s.power = powerSequence{states: []string{powerOffGoing, powerOff}}
+		}
+	case "ForceRestart", "GracefulRestart", "PowerCycle":
+		s.power = powerSequence{states: []string{powerOnGoing, powerOn}, boot: true}
+		if on {
+			s.power.states = append([]string{powerOffGoing}, s.power.states...)
+		}
+	case "PushPowerButton":
+		s.power = powerSequence{states: []string{powerOnGoing, powerOn}, boot: true}
+		if on {
+			s.power = powerSequence{states: []string{powerOffGoing, powerOff}}
+		}
+	}
+	return noContent(), nil
+}
+
+// biosJob returns the pending BIOS configuration job, creating it if there is none. The changes
+// of the BIOS settings and of the boot configuration are applied by this job on the next reset.
+func (s *Server) biosJob() *job {
+	if j := s.pendingJob(biosFQDD); j != nil {
+		return j
+	}
+	j := s.newJob("Configure: "+biosFQDD, "BIOSConfiguration", true)
+	j.target = biosFQDD
+	return j
+}
+
+// patchBiosSettings stores the BIOS attributes in the settings object, to be applied by a BIOS configuration job
+func (s *Server) patchBiosSettings(r *request) (*response, error) {
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	values, err := attributesOf(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkApplyTime(s.resources[biosURI], body); err != nil {
+		return nil, err
+	}
+	if s.pendingJob(biosFQDD) != nil {
+		if settings := s.resources[biosURI+"/Settings"]["Attributes"].(map[string]interface{}); len(settings) > 0 {
+			return nil, newError(http.StatusBadRequest, "IDRAC.2.8.SYS011", nil)
+		}
+	}
+	set := attributeSet{
+		registry:  s.resources[biosRegistryURI],
+		values:    s.resources[biosURI]["Attributes"].(map[string]interface{}),
+		secretKey: biosURI + "#",
+	}
+	if err := set.check(values); err != nil {
+		return nil, err
+	}
+
+	s.resources[biosURI+"/Settings"]["Attributes"] = deepCopy(values)
+	j := s.biosJob()
+	j.apply = append(j.apply, func() {
+		s.setAttributes(set, values)
+		s.resources[biosURI+"/Settings"]["Attributes"] = map[string]interface{}{}
+	})
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
+
+// checkApplyTime checks the @Redfish.SettingsApplyTime of the body against the ones supported by the resource
+func checkApplyTime(res, body map[string]interface{}) error {
+	applyTime, _ := body["@Redfish.SettingsApplyTime"].(map[string]interface{})
+	value, isSet := applyTime["ApplyTime"]
+	if !isSet {
+		return nil
+	}
+	settings, _ := res["@Redfish.Settings"].(map[string]interface{})
+	supported, _ := settings["SupportedApplyTimes"].([]interface{})
+	if !contains(supported, value) {
+		return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(value), "ApplyTime"},
+			"#/@Redfish.SettingsApplyTime/ApplyTime")
+	}
+	return nil
+}
+
+// patchSystem implements the changes of the boot configuration. The boot order and the boot mode of the
+// override are BIOS settings applied by the BIOS configuration job, the other properties are set immediately.
+func (s *Server) patchSystem(r *request) (*response, error) {
+	system := s.resources[systemURI]
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	boot, hasBoot := body["Boot"].(map[string]interface{})
+	delete(body, "Boot")
+	if err := checkPatch(system, body, ""); err != nil {
+		return nil, err
+	}
+	if err := s.checkBoot(boot); err != nil {
+		return nil, err
+	}
+	merge(system, body)
+	if !hasBoot {
+		return ok(successBody()), nil
+	}
+
+	deferred := map[string]interface{}{}
+	for _, name := range []string{"BootOrder", "BootSourceOverrideMode"} {
+		if value, isSet := boot[name]; isSet {
+			deferred[name] = value
+			delete(boot, name)
+		}
+	}
+	merge(system["Boot"].(map[string]interface{}), boot)
+	if len(deferred) == 0 {
+		return ok(successBody()), nil
+	}
+	j := s.biosJob()
+	j.apply = append(j.apply, func() {
+		merge(system["Boot"].(map[string]interface{}), deferred)
+		if mode, isSet := deferred["BootSourceOverrideMode"]; isSet {
+			bootMode := map[string]string{"UEFI": "Uefi", "Legacy": "Bios"}[toString(mode)]
+			s.resources[biosURI]["Attributes"].(map[string]interface{})["BootMode"] = bootMode
+		}
+	})
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
+
+// checkBoot validates the changes of the Boot property of the system. Empty override values are ignored.
+func (s *Server) checkBoot(boot map[string]interface{}) error {
+	current := s.resources[systemURI]["Boot"].(map[string]interface{})
+	for _, name := range sortedKeys(boot) {
+		value := boot[name]
+		pointer := "#/Boot/" + name
+		if value == "" {
+			delete(boot, name)
+			continue
+		}
+		var allowed []interface{}
+		switch name {
+		case "BootOrder":
+			return checkBootOrder(current, value)
+		case "BootSourceOverrideTarget":
+			allowed, _ = current["BootSourceOverrideTarget@Redfish.AllowableValues"].([]interface{})
+		case "BootSourceOverrideEnabled", "BootSourceOverrideMode":
+			for _, v := range bootOverrideValues[name] {
+				allowed = append(allowed, v)
+			}
+		case "UefiTargetBootSourceOverride":
+			continue
+		default:
+			if _, exists := current[name]; !exists {
+				return newError(http.StatusBadRequest, "Base.1.12.PropertyUnknown", []string{name}, pointer)
+			}
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyNotWritable", []string{name}, pointer)
+		}
+		if !contains(allowed, value) {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(value), name}, pointer)
+		}
+	}
+	return nil
+}
+
+// checkBootOrder checks that the boot order is made of all the boot options of the system
+func checkBootOrder(boot map[string]interface{}, value interface{}) error {
+	order, _ := value.([]interface{})
+	current, _ := boot["BootOrder"].([]interface{})
+	seen := map[string]bool{}
+	for _, ref := range order {
+		if !contains(current, ref) || seen[toString(ref)] {
+			return newError(http.StatusBadRequest, "Base.1.12.PropertyValueNotInList", []string{toString(ref), "BootOrder"},
+				"#/Boot/BootOrder")
+		}
+		seen[toString(ref)] = true
+	}
+	if len(order) != len(current) {
+		return newError(http.StatusBadRequest, "Base.1.12.PropertyValueError", []string{"BootOrder"}, "#/Boot/BootOrder")
+	}
+	return nil
+}
+
+// patchBootOption enables or disables a boot option, with the BIOS configuration job
+func (s *Server) patchBootOption(r *request) (*response, error) {
+	option, err := s.resource(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPatch(option, body, ""); err != nil {
+		return nil, err
+	}
+	for name, value := range body {
+		if _, isBool := value.(bool); name != "BootOptionEnabled" || !isBool {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.PropertyNotWritable", []string{name}, "#/"+name)
+		}
+	}
+	j := s.biosJob()
+	j.apply = append(j.apply, func() { merge(option, body) })
+	return accepted(dellJobsURI + "/" + j.id), nil
+}
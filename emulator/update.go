@@ -0,0 +1,282 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// firmwareComponent is a component of the server which can be updated
+type firmwareComponent struct {
+	softwareID string
+	name       string
+	// onReset tells whether the update is applied when the server restarts
+	onReset bool
+	// catalogVersion is the version found in the repositories
+	catalogVersion string
+}
+
+// firmwareComponents are the components by prefix of their Dell Update Package names
+var firmwareComponents = map[string]firmwareComponent{
+	"BIOS":  {softwareID: "159", name: "BIOS", onReset: true, catalogVersion: "2.22.0"},
+	"iDRAC": {softwareID: "25227", name: "Integrated Dell Remote Access Controller", catalogVersion: "7.00.00.00"},
+	"SAS-RAID": {
+		softwareID: "109447", name: "PERC H755 Front", onReset: true, catalogVersion: "52.26.0-5179",
+	},
+}
+
+// packageVersion finds the version in the name of a Dell Update Package, such as BIOS_8MRPC_WN64_2.19.1.EXE
+var packageVersion = regexp.MustCompile(`(\d+(?:[.-]\d+)+)(?:_A\d+)?\.[A-Za-z0-9]+$`)
+
+// packageTypes are the extensions of the Dell Update Packages
+var packageTypes = map[string]bool{".exe": true, ".bin": true, ".d9": true, ".pm": true}
+
+// catalogPackage is an update found in a repository
+type catalogPackage struct {
+	component firmwareComponent
+	name      string
+	installed string
+	jobID     string
+}
+
+// dellPackage returns the component and the version of a Dell Update Package from its file name
+func dellPackage(image string) (firmwareComponent, string, bool) {
+	name := path.Base(image)
+	match := packageVersion.FindStringSubmatch(name)
+	if match == nil || !packageTypes[strings.ToLower(path.Ext(name))] {
+		return firmwareComponent{}, "", false
+	}
+	for prefix, component := range firmwareComponents {
+		if strings.HasPrefix(name, prefix) {
+			return component, match[1], true
+		}
+	}
+	return firmwareComponent{}, "", false
+}
+
+// uploadFirmware implements the upload of a Dell Update Package to the firmware inventory, as an Available entry
+func (s *Server) uploadFirmware(r *request) (*response, error) {
+	if match := r.Header.Get("If-Match"); len(match) > 0 {
+		content, err := json.Marshal(s.resources[firmwareURI])
+		if err != nil {
+			return nil, err
+		}
+		if match != etag(content) {
+			return nil, newError(http.StatusPreconditionFailed, "Base.1.12.PreconditionFailed", nil)
+		}
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{"Upload", "file"})
+	}
+	defer file.Close()
+	if _, err := io.Copy(io.Discard, file); err != nil {
+		return nil, err
+	}
+	component, version, valid := dellPackage(header.Filename)
+	if !valid {
+		return nil, newError(http.StatusBadRequest, "IDRAC.2.8.RED004", []string{header.Filename})
+	}
+	entry := s.firmwareEntry("Available", component, version)
+	return &response{
+		status:  http.StatusCreated,
+		headers: map[string]string{"Location": entry["@odata.id"].(string)},
+		body:    entry,
+	}, nil
+}
+
+// firmwareEntry adds an entry of the firmware inventory
+func (s *Server) firmwareEntry(state string, component firmwareComponent, version string) map[string]interface{} {
+	return s.addMember(firmwareURI, map[string]interface{}{
+		"@odata.type": "#SoftwareInventory.v1_5_0.SoftwareInventory",
+		"Id":          fmt.Sprintf("%s-%s-%s", state, component.softwareID, version),
+		"Name":        component.name,
+		"SoftwareId":  component.softwareID,
+		"Version":     version,
+		"Updateable":  true,
+		"Status":      map[string]interface{}{"Health": "OK", "State": "Enabled"},
+	})
+}
+
+// installFirmware replaces the installed firmware of the component, which becomes the previous one
+func (s *Server) installFirmware(component firmwareComponent, version string) map[string]interface{} {
+	for _, uri := range linkURIs(s.resources[firmwareURI]["Members"].([]interface{})) {
+		entry := s.resources[toString(uri)]
+		if entry["SoftwareId"] != component.softwareID {
+			continue
+		}
+		id := toString(entry["Id"])
+		switch {
+		case strings.HasPrefix(id, "Previous-"), strings.HasPrefix(id, "Available-") && entry["Version"] == version:
+			s.removeMember(firmwareURI, toString(uri))
+		case strings.HasPrefix(id, "Installed-"):
+			s.removeMember(firmwareURI, toString(uri))
+			s.firmwareEntry("Previous", component, toString(entry["Version"]))
+		}
+	}
+	return s.firmwareEntry("Installed", component, version)
+}
+
+// installedVersion returns the installed version of the component
+func (s *Server) installedVersion(component firmwareComponent) string {
+	for _, uri := range linkURIs(s.resources[firmwareURI]["Members"].([]interface{})) {
+		entry := s.resources[toString(uri)]
+		if entry["SoftwareId"] == component.softwareID && strings.HasPrefix(toString(entry["Id"]), "Installed-") {
+			return toString(entry["Version"])
+		}
+	}
+	return ""
+}
+
+// simpleUpdate implements the UpdateService.SimpleUpdate action, with an image uploaded to the firmware inventory or
+// pulled from a remote location. The task monitor returns the installed firmware once the update is completed.
+func (s *Server) simpleUpdate(r *request, body map[string]interface{}) (*response, error) {
+	image, _ := body["ImageURI"].(string)
+	if len(image) == 0 {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{actionName(r), "ImageURI"})
+	}
+	if protocol, isSet := body["TransferProtocol"].(string); isSet {
+		if _, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "TransferProtocol"); err != nil {
+			return nil, err
+		}
+		if strings.Contains(image, "://") && !strings.HasPrefix(strings.ToUpper(image), protocol+"://") {
+			return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+				[]string{image, "ImageURI", actionName(r)}, "#/ImageURI")
+		}
+	}
+
+	component, version, valid := dellPackage(image)
+	if uploaded, exists := s.resources[image]; exists && strings.HasPrefix(image, firmwareURI+"/") {
+		version, valid = toString(uploaded["Version"]), false
+		for _, c := range firmwareComponents {
+			if c.softwareID == uploaded["SoftwareId"] {
+				component, valid = c, true
+			}
+		}
+	}
+	j := s.newJob("Firmware Update: "+component.name, "FirmwareUpdate", valid && component.onReset)
+	j.run = func() outcome {
+		if !valid {
+			return failure("IDRAC.2.8.RED003", image)
+		}
+		result := success()
+		result.result, _ = json.Marshal(s.installFirmware(component, version)) // #nosec G104
+		return result
+	}
+	return accepted(tasksURI + "/" + j.id), nil
+}
+
+// installFromRepository implements the DellSoftwareInstallationService.InstallFromRepository action. The
+// repository holds an update for each component, which is installed if ApplyUpdate is True.
+func (s *Server) installFromRepository(r *request, body map[string]interface{}) (*response, error) {
+	shareType, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "ShareType")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := actionParameter(s.resources[actionResource(r.uri)], r, body, "IPAddress"); err != nil {
+		return nil, err
+	}
+	if (shareType == "NFS" || shareType == "CIFS") && len(toString(body["ShareName"])) == 0 {
+		return nil, newError(http.StatusBadRequest, "Base.1.12.ActionParameterMissing", []string{actionName(r), "ShareName"})
+	}
+	apply := strings.EqualFold(toString(body["ApplyUpdate"]), "True")
+	reboot := body["RebootNeeded"] == true
+
+	j := s.newJob("Repository Update", "RepositoryUpdate", false)
+	j.run = func() outcome {
+		s.repository = nil
+		for _, component := range firmwareComponents {
+			installed := s.installedVersion(component)
+			if len(installed) == 0 || installed == component.catalogVersion {
+				continue
+			}
+			pkg := catalogPackage{
+				component: component,
+				name:      fmt.Sprintf("%s_%s.EXE", strings.ReplaceAll(component.name, " ", "-"), component.catalogVersion),
+				installed: installed,
+			}
+			if apply {
+				update := s.newJob("Firmware Update: "+component.name, "FirmwareUpdate", component.onReset && !reboot)
+				c := component
+				update.apply = append(update.apply, func() { s.installFirmware(c, c.catalogVersion) })
+				pkg.jobID = update.id
+			}
+			s.repository = append(s.repository, pkg)
+		}
+		if apply && reboot {
+			s.releaseJobs()
+		}
+		return success()
+	}
+	return accepted(jobServiceJobsURI + "/" + j.id), nil
+}
+
+// cimProperty is a property of a CIM instance
+type cimProperty struct {
+	Name  string `xml:"NAME,attr"`
+	Type  string `xml:"TYPE,attr"`
+	Value string `xml:"VALUE"`
+}
+
+// getRepoBasedUpdateList implements the DellSoftwareInstallationService.GetRepoBasedUpdateList action, which
+// returns the packages of the last repository update as CIM instances
+func (s *Server) getRepoBasedUpdateList(_ *request, _ map[string]interface{}) (*response, error) {
+	if s.repository == nil {
+		return nil, newError(http.StatusBadRequest, "IDRAC.2.8.SUP029", nil)
+	}
+	type instance struct {
+		ClassName  string        `xml:"CLASSNAME,attr"`
+		Properties []cimProperty `xml:"PROPERTY"`
+	}
+	var list struct {
+		XMLName   xml.Name   `xml:"CIM"`
+		Version   string     `xml:"CIMVERSION,attr"`
+		Instances []instance `xml:"MESSAGE>SIMPLEREQ>VALUE.NAMEDINSTANCE>INSTANCENAME"`
+	}
+	list.Version = "2.0"
+	for _, pkg := range s.repository {
+		rebootType := "NONE"
+		if pkg.component.onReset {
+			rebootType = "HOST"
+		}
+		list.Instances = append(list.Instances, instance{
+			ClassName: "DCIM_RepoUpdateSWID",
+			Properties: []cimProperty{
+				{"DisplayName", "string", pkg.component.name},
+				{"PackageName", "string", pkg.name},
+				{"PackageVersion", "string", pkg.component.catalogVersion},
+				{"ComponentInstalledVersion", "string", pkg.installed},
+				{"Criticality", "string", "2"},
+				{"RebootType", "string", rebootType},
+				{"JobID", "string", pkg.jobID},
+			},
+		})
+	}
+	content, err := xml.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return ok(map[string]interface{}{"PackageList": xml.Header + string(content)}), nil
+}
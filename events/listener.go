@@ -0,0 +1,385 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events receives the events a Redfish service posts to the destination of its subscriptions
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// eventsPath is the path of the destination the events are posted to
+	eventsPath = "/redfish/events"
+	// maxEventSize is the largest event payload read
+	maxEventSize = 1 << 20
+	// certificateValidity is the validity of the self signed certificate of the listener
+	certificateValidity = 24 * time.Hour
+	readHeaderTimeout   = 10 * time.Second
+	// maxRecords is the number of records a listener keeps, the oldest ones being dropped
+	maxRecords = 1000
+)
+
+// Record is an event record of the Events array of a Redfish Event
+type Record struct {
+	EventID           string `json:"EventId"`
+	EventTimestamp    string
+	Message           string
+	MessageArgs       []string
+	MessageID         string `json:"MessageId"`
+	MessageSeverity   string
+	OriginOfCondition string
+	// Context is the context of the subscription the event was sent to
+	Context string
+}
+
+// UnmarshalJSON reads the @odata.id of the OriginOfCondition, given as a link
+func (r *Record) UnmarshalJSON(b []byte) error {
+	type temp Record
+	var t struct {
+		temp
+		OriginOfCondition json.RawMessage
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	*r = Record(t.temp)
+	var origin struct {
+		ODataID string `json:"@odata.id"`
+	}
+	if json.Unmarshal(t.OriginOfCondition, &origin) == nil {
+		r.OriginOfCondition = origin.ODataID
+	}
+	return nil
+}
+
+// event is the payload posted by the service
+type event struct {
+	Context string
+	Events  []Record
+}
+
+// Filter selects the records waited for. The empty fields match any record.
+type Filter struct {
+	// MessageIDs are the MessageIds of the records, such as IDRAC.2.8.SYS1003. The versions of the registries
+	// are ignored, and may be left out, such as IDRAC.SYS1003.
+	MessageIDs []string
+	// OriginOfCondition is the @odata.id of the resource the records are about
+	OriginOfCondition string
+	// Context is the context of the subscription the records were sent to
+	Context string
+}
+
+// Match tells whether the record is selected by the filter
+func (f Filter) Match(r Record) bool {
+	if len(f.Context) > 0 && r.Context != f.Context {
+		return false
+	}
+	if len(f.OriginOfCondition) > 0 && strings.TrimSuffix(r.OriginOfCondition, "/") != strings.TrimSuffix(f.OriginOfCondition, "/") {
+		return false
+	}
+	if len(f.MessageIDs) == 0 {
+		return true
+	}
+	for _, id := range f.MessageIDs {
+		if sameMessage(id, r.MessageID) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameMessage compares the registry prefixes and the keys of two MessageIds
+func sameMessage(a, b string) bool {
+	if a == b {
+		return true
+	}
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	if len(aParts) < 2 || len(bParts) < 2 {
+		return false
+	}
+	return aParts[0] == bParts[0] && aParts[len(aParts)-1] == bParts[len(bParts)-1]
+}
+
+// Listener is an HTTPS server receiving the events posted to it. The last records are kept, so that the events
+// sent between the subscription and the call to Wait are not missed.
+type Listener struct {
+	server      *http.Server
+	listener    net.Listener
+	url         string
+	certificate *tls.Certificate
+
+	// pool shares the listener, which is closed with its last user
+	pool    *Pool
+	address string
+	users   int
+
+	mu      sync.Mutex
+	records []Record
+	// dropped is the number of records dropped, the position of the first record kept
+	dropped int64
+	// subscribed holds the position of the next record when the subscriptions of the contexts were created
+	subscribed map[string]int64
+	// received is closed when a record is received, and replaced
+	received chan struct{}
+}
+
+// Listen starts a listener on the address, such as 10.0.0.5:8443. The listener serves the certificate, or else a
+// self signed certificate for the host of the address. An address without host, or with an unspecified one, such
+// as :8443 or 0.0.0.0:8443, listens on all the addresses of the host; the listener then has no URL, and the
+// subscriptions must give a destination the service reaches it at.
+func Listen(address string, certificate *tls.Certificate) (*Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		listener.Close() // #nosec G104
+		return nil, err
+	}
+	listenerHost, port, _ := net.SplitHostPort(listener.Addr().String())
+	var url string
+	if UnspecifiedHost(address) {
+		host = listenerHost
+	} else {
+		url = "https://" + net.JoinHostPort(host, port) + eventsPath
+	}
+	if certificate == nil {
+		if certificate, err = selfSignedCertificate(host); err != nil {
+			listener.Close() // #nosec G104
+			return nil, err
+		}
+	}
+
+	l := &Listener{
+		listener:    listener,
+		url:         url,
+		certificate: certificate,
+		subscribed:  make(map[string]int64),
+		received:    make(chan struct{}),
+	}
+	l.server = &http.Server{
+		Handler:           http.HandlerFunc(l.receive),
+		ReadHeaderTimeout: readHeaderTimeout,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{*certificate},
+			MinVersion:   tls.VersionTLS12,
+		},
+	}
+	go l.server.ServeTLS(listener, "", "") // #nosec G104
+	return l, nil
+}
+
+// UnspecifiedHost tells whether the address has no host the service can send the events to, such as :8443 or
+// 0.0.0.0:8443, which listen on all the addresses of the host
+func UnspecifiedHost(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	return err == nil && (len(host) == 0 || net.ParseIP(host).IsUnspecified())
+}
+
+// URL returns the URL of the listener, the destination of the subscriptions. The host of the URL is the one
+// of the address of the listener, which must be reachable by the service. The URL is empty when the listener
+// listens on all the addresses of the host.
+func (l *Listener) URL() string {
+	return l.url
+}
+
+// Position returns the position of the next record received, from which Wait can look for a record
+func (l *Listener) Position() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped + int64(len(l.records))
+}
+
+// MarkSubscription keeps the position of the next record received as the one of the subscription of the context,
+// which must be marked before the subscription is created
+func (l *Listener) MarkSubscription(context string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribed[context] = l.dropped + int64(len(l.records))
+}
+
+// SubscriptionPosition returns the position marked for the subscription of the context, or the position of the
+// next record when the subscription was not marked
+func (l *Listener) SubscriptionPosition(context string) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if position, ok := l.subscribed[context]; ok {
+		return position
+	}
+	return l.dropped + int64(len(l.records))
+}
+
+// Close stops the listener, once all its users closed it when it is shared by a pool
+func (l *Listener) Close() error {
+	if l.pool != nil && !l.pool.release(l) {
+		return nil
+	}
+	return l.server.Close()
+}
+
+// Pool shares a listener between the waits on the same address, such as the resources of an apply listening on the
+// same port. The waits tell their events apart by the context of their subscription and the origin of the events.
+type Pool struct {
+	mu        sync.Mutex
+	listeners map[string]*Listener
+}
+
+// NewPool returns an empty pool of listeners
+func NewPool() *Pool {
+	return &Pool{listeners: make(map[string]*Listener)}
+}
+
+// Listen returns the listener of the address, started on the first call. The listener serves the certificate
+// of the first call, the next calls must give the same certificate or none. Each call must be followed by a Close.
+func (p *Pool) Listen(address string, certificate *tls.Certificate) (*Listener, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l, ok := p.listeners[address]; ok {
+		if certificate != nil && !sameCertificate(l.certificate, certificate) {
+			return nil, fmt.Errorf("the listener on %s already serves another certificate", address)
+		}
+		l.users++
+		return l, nil
+	}
+	l, err := Listen(address, certificate)
+	if err != nil {
+		return nil, err
+	}
+	l.pool, l.address, l.users = p, address, 1
+	p.listeners[address] = l
+	return l, nil
+}
+
+// release tells whether the listener has no user left, removing it from the pool
+func (p *Pool) release(l *Listener) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l.users--
+	if l.users > 0 {
+		return false
+	}
+	delete(p.listeners, l.address)
+	return true
+}
+
+// sameCertificate compares the leaf certificates of two certificate chains
+func sameCertificate(a, b *tls.Certificate) bool {
+	if a == nil || b == nil || len(a.Certificate) == 0 || len(b.Certificate) == 0 {
+		return false
+	}
+	return bytes.Equal(a.Certificate[0], b.Certificate[0])
+}
+
+// Wait returns the first record received from the position matching the filter, or an error when the context is
+// done first. The records dropped since the position are not looked at.
+func (l *Listener) Wait(ctx context.Context, position int64, filter Filter) (*Record, error) {
+	for next := position; ; {
+		l.mu.Lock()
+		records, received := l.records[max(next-l.dropped, 0):], l.received
+		next = l.dropped + int64(len(l.records))
+		l.mu.Unlock()
+		for _, record := range records {
+			if filter.Match(record) {
+				return &record, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("no matching event was received in time")
+			}
+			return nil, ctx.Err()
+		case <-received:
+		}
+	}
+}
+
+// receive reads the records of an event
+func (l *Listener) receive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var e event
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEventSize)).Decode(&e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, record := range e.Events {
+		record.Context = e.Context
+		l.records = append(l.records, record)
+	}
+	if drop := len(l.records) - maxRecords; drop > 0 {
+		l.records = append([]Record(nil), l.records[drop:]...)
+		l.dropped += int64(drop)
+	}
+	close(l.received)
+	l.received = make(chan struct{})
+}
+
+// selfSignedCertificate returns a certificate of the host, either an IP address or a name
+func selfSignedCertificate(host string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
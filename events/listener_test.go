@@ -0,0 +1,282 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"terraform-provider-redfish/emulator"
+
+	"github.com/stmcginnis/gofish"
+)
+
+const (
+	systemURI           = "/redfish/v1/Systems/System.Embedded.1"
+	subscriptionsURI    = "/redfish/v1/EventService/Subscriptions"
+	submitTestEventURI  = "/redfish/v1/EventService/Actions/EventService.SubmitTestEvent"
+	subscriptionContext = "listener-test"
+)
+
+// listen starts a listener on a free local port
+func listen(t *testing.T) *Listener {
+	t.Helper()
+	listener, err := Listen("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatalf("unable to start the listener: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() }) // #nosec G104
+	return listener
+}
+
+// post sends a request to the service and checks it succeeded
+func post(t *testing.T, service *gofish.Service, uri string, body interface{}) {
+	t.Helper()
+	resp, err := service.GetClient().Post(uri, body)
+	if err != nil {
+		t.Fatalf("POST %s failed: %s", uri, err)
+	}
+	resp.Body.Close() // #nosec G104
+}
+
+func TestListenerWait(t *testing.T) {
+	_, service := emulator.Connect(t)
+	listener := listen(t)
+	post(t, service, subscriptionsURI, map[string]interface{}{
+		"Destination": listener.URL(),
+		"Protocol":    "Redfish",
+		"Context":     subscriptionContext,
+	})
+
+	// the events sent before Wait are kept
+	post(t, service, submitTestEventURI, map[string]interface{}{"MessageId": "IDRAC.2.8.SYS053", "OriginOfCondition": systemURI})
+	post(t, service, submitTestEventURI, map[string]interface{}{
+		"MessageId": "IDRAC.2.8.PR19", "OriginOfCondition": systemURI, "MessageArgs": []string{"JID_001"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	record, err := listener.Wait(ctx, 0, Filter{
+		MessageIDs:        []string{"IDRAC.PR19"},
+		OriginOfCondition: systemURI,
+		Context:           subscriptionContext,
+	})
+	if err != nil {
+		t.Fatalf("the event was not received: %s", err)
+	}
+	if record.MessageID != "IDRAC.2.8.PR19" || record.OriginOfCondition != systemURI || len(record.Message) == 0 ||
+		len(record.MessageArgs) != 1 {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestListenerTimeout(t *testing.T) {
+	_, service := emulator.Connect(t)
+	listener := listen(t)
+	post(t, service, subscriptionsURI, map[string]interface{}{
+		"Destination":      listener.URL(),
+		"Protocol":         "Redfish",
+		"Context":          subscriptionContext,
+		"RegistryPrefixes": []string{"Base"},
+	})
+	// the subscription does not let the iDRAC messages through
+	post(t, service, submitTestEventURI, map[string]interface{}{"MessageId": "IDRAC.2.8.PR19"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if record, err := listener.Wait(ctx, listener.Position(), Filter{MessageIDs: []string{"IDRAC.2.8.PR19"}}); err == nil {
+		t.Errorf("expected a timeout, got %+v", record)
+	}
+}
+
+func TestPoolListen(t *testing.T) {
+	_, service := emulator.Connect(t)
+	pool := NewPool()
+	first, err := pool.Listen("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatalf("unable to start the listener: %s", err)
+	}
+	second, err := pool.Listen("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatalf("unable to share the listener: %s", err)
+	}
+	if first != second {
+		t.Fatalf("expected the listener to be shared")
+	}
+	other, _ := selfSignedCertificate("127.0.0.1") // #nosec G104
+	if _, err := pool.Listen("127.0.0.1:0", other); err == nil {
+		t.Errorf("expected an error for another certificate")
+	}
+
+	// the listener keeps serving its other user
+	first.Close() // #nosec G104
+	for _, subscription := range []string{"first", "second"} {
+		post(t, service, subscriptionsURI, map[string]interface{}{
+			"Destination": second.URL(),
+			"Protocol":    "Redfish",
+			"Context":     subscription,
+		})
+	}
+	post(t, service, submitTestEventURI, map[string]interface{}{"MessageId": "IDRAC.2.8.SYS1003"})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	record, err := second.Wait(ctx, 0, Filter{MessageIDs: []string{"IDRAC.SYS1003"}, Context: "second"})
+	if err != nil {
+		t.Fatalf("the event was not received: %s", err)
+	}
+	// each subscription gets its own record
+	if record.Context != "second" {
+		t.Errorf("unexpected record %+v", record)
+	}
+
+	second.Close() // #nosec G104
+	third, err := pool.Listen("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatalf("unable to start the listener: %s", err)
+	}
+	defer third.Close() // #nosec G104
+	if third == second {
+		t.Errorf("expected a new listener once the last user closed it")
+	}
+}
+
+func TestListenerPosition(t *testing.T) {
+	_, service := emulator.Connect(t)
+	listener := listen(t)
+	post(t, service, subscriptionsURI, map[string]interface{}{
+		"Destination": listener.URL(),
+		"Protocol":    "Redfish",
+		"Context":     subscriptionContext,
+	})
+	post(t, service, submitTestEventURI, map[string]interface{}{"MessageId": "IDRAC.2.8.SYS1003", "MessageArgs": []string{"first"}})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := listener.Wait(ctx, 0, Filter{MessageIDs: []string{"IDRAC.SYS1003"}}); err != nil {
+		t.Fatalf("the event was not received: %s", err)
+	}
+
+	// the records received before the mark of a subscription are not looked at by its waits
+	listener.MarkSubscription("second")
+	if position := listener.SubscriptionPosition("second"); position != 1 {
+		t.Errorf("expected the subscription to be marked after the first record, got %d", position)
+	}
+	post(t, service, submitTestEventURI, map[string]interface{}{"MessageId": "IDRAC.2.8.SYS1003", "MessageArgs": []string{"second"}})
+	record, err := listener.Wait(ctx, listener.SubscriptionPosition("second"), Filter{MessageIDs: []string{"IDRAC.SYS1003"}})
+	if err != nil {
+		t.Fatalf("the event was not received: %s", err)
+	}
+	if len(record.MessageArgs) != 1 || record.MessageArgs[0] != "second" {
+		t.Errorf("expected the record sent after the mark, got %+v", record)
+	}
+	if position := listener.SubscriptionPosition("unknown"); position != listener.Position() {
+		t.Errorf("expected the next position for a subscription without mark, got %d", position)
+	}
+}
+
+// postEvent posts an event to the listener and returns the status of the response
+func postEvent(t *testing.T, listener *Listener, body []byte) int {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}} // #nosec G402
+	resp, err := client.Post(listener.URL(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("unable to post: %s", err)
+	}
+	resp.Body.Close() // #nosec G104
+	return resp.StatusCode
+}
+
+func TestListenerDropsRecords(t *testing.T) {
+	listener := listen(t)
+	records := make([]Record, maxRecords+10)
+	for i := range records {
+		records[i] = Record{EventID: strconv.Itoa(i), MessageID: "IDRAC.2.8.SYS1003"}
+	}
+	body, _ := json.Marshal(event{Context: subscriptionContext, Events: records}) // #nosec G104
+	if status := postEvent(t, listener, body); status != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if position := listener.Position(); position != maxRecords+10 {
+		t.Errorf("expected the position after all the records, got %d", position)
+	}
+	// the oldest records are dropped
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	record, err := listener.Wait(ctx, 0, Filter{MessageIDs: []string{"IDRAC.SYS1003"}})
+	if err != nil {
+		t.Fatalf("the event was not received: %s", err)
+	}
+	if record.EventID != "10" {
+		t.Errorf("expected the first record kept, got %+v", record)
+	}
+}
+
+func TestListenUnspecifiedHost(t *testing.T) {
+	for _, address := range []string{":0", "0.0.0.0:0", "[::]:0"} {
+		if !UnspecifiedHost(address) {
+			t.Errorf("expected %s to have no host", address)
+		}
+	}
+	if UnspecifiedHost("127.0.0.1:0") || UnspecifiedHost("listener.example.com:8443") {
+		t.Errorf("expected the hosts to be specified")
+	}
+	listener, err := Listen(":0", nil)
+	if err != nil {
+		t.Fatalf("unable to start the listener: %s", err)
+	}
+	defer listener.Close() // #nosec G104
+	if url := listener.URL(); len(url) > 0 {
+		t.Errorf("expected no URL for a listener on all the addresses, got %s", url)
+	}
+}
+
+func TestListenerInvalidEvent(t *testing.T) {
+	listener := listen(t)
+	if status := postEvent(t, listener, []byte("not an event")); status != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got %d", status)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	record := Record{MessageID: "IDRAC.2.8.SYS1003", OriginOfCondition: systemURI, Context: "wait"}
+	tests := []struct {
+		filter Filter
+		match  bool
+	}{
+		{Filter{}, true},
+		{Filter{MessageIDs: []string{"IDRAC.2.8.SYS1003"}}, true},
+		{Filter{MessageIDs: []string{"IDRAC.2.9.SYS1003"}}, true},
+		{Filter{MessageIDs: []string{"IDRAC.SYS1003"}}, true},
+		{Filter{MessageIDs: []string{"EEMI.SYS1003"}}, false},
+		{Filter{MessageIDs: []string{"SYS1003"}}, false},
+		{Filter{MessageIDs: []string{"IDRAC.2.8.SYS1000", "IDRAC.2.8.SYS1003"}}, true},
+		{Filter{OriginOfCondition: systemURI + "/"}, true},
+		{Filter{OriginOfCondition: "/redfish/v1/Managers/iDRAC.Embedded.1"}, false},
+		{Filter{Context: "other"}, false},
+	}
+	for _, test := range tests {
+		if match := test.filter.Match(record); match != test.match {
+			t.Errorf("%+v.Match() = %t, expected %t", test.filter, match, test.match)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Properties of a resource no other datasource exposes
+data "redfish_generic" "chassis" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  odata_id = "/redfish/v1/Chassis/System.Embedded.1"
+  select   = ["AssetTag", "SerialNumber"]
+}
+
+// Members of a collection, read in a single request when the service supports $expand
+data "redfish_generic" "nics" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  odata_id       = "/redfish/v1/Systems/System.Embedded.1/EthernetInterfaces"
+  expand_members = true
+  select         = ["Id", "MACAddress"]
+}
+
+output "serial_numbers" {
+  value = { for name, chassis in data.redfish_generic.chassis : name => chassis.flattened["SerialNumber"] }
+}
+
+output "mac_addresses" {
+  value = { for name, nics in data.redfish_generic.nics : name => [for nic in jsondecode(nics.body).Members : nic.MACAddress] }
+}
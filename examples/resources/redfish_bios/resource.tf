@@ -27,7 +27,7 @@ resource "redfish_bios" "bios" {
 
   // Bios attributes to be altered
   attributes = {
-    "NumLock" = "On"
+    "NumLock" = { string = "On" }
   }
 
   /* Reset parameters to be applied after bios settings are applied
@@ -27,13 +27,13 @@ resource "redfish_dell_idrac_attributes" "idrac" {
 
   // iDRAC attributes to be modified
   attributes = {
-    "Users.3.Enable"                         = "Disabled"
-    "Users.3.UserName"                       = "mike"
-    "Users.3.Password"                       = "test1234"
-    "Users.3.Privilege"                      = 511
-    "Redfish.1.NumericDynamicSegmentsEnable" = "Disabled"
-    "SysLog.1.PowerLogInterval"              = "5"
-    "Time.1.Timezone"                        = "CST6CDT"
+    "Users.3.Enable"                         = { string = "Disabled" }
+    "Users.3.UserName"                       = { string = "mike" }
+    "Users.3.Password"                       = { string = "test1234" }
+    "Users.3.Privilege"                      = { int = 511 }
+    "Redfish.1.NumericDynamicSegmentsEnable" = { string = "Disabled" }
+    "SysLog.1.PowerLogInterval"              = { int = 5 }
+    "Time.1.Timezone"                        = { string = "CST6CDT" }
   }
 }
 
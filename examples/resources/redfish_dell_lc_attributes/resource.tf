@@ -27,7 +27,7 @@ resource "redfish_dell_lc_attributes" "lc" {
 
   // LC attributes to be modified
   attributes = {
-    "LCAttributes.1.IgnoreCertWarning"               = "On"
-    "LCAttributes.1.CollectSystemInventoryOnRestart" = "Disabled"
+    "LCAttributes.1.IgnoreCertWarning"               = { string = "On" }
+    "LCAttributes.1.CollectSystemInventoryOnRestart" = { string = "Disabled" }
   }
 }
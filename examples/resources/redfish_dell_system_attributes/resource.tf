@@ -27,7 +27,7 @@ resource "redfish_dell_system_attributes" "system" {
 
   // System attributes to be modified
   attributes = {
-    "ServerPwr.1.PSPFCEnabled" = "Disabled"
-    "SupportInfo.1.Outsourced" = "Yes"
+    "ServerPwr.1.PSPFCEnabled" = { string = "Disabled" }
+    "SupportInfo.1.Outsourced" = { string = "Yes" }
   }
 }
@@ -0,0 +1,44 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+resource "redfish_event_subscription" "collector" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // URI the events are posted to
+  destination = "https://collector.myawesomecompany.org/redfish/events"
+
+  /* Optional filters of the events, all the events are sent when none is set.
+     Changing them creates a new subscription.
+  */
+  registry_prefixes = ["IDRAC"]
+  resource_types    = ["ComputerSystem", "Job"]
+
+  // Sent back in the Context property of each event
+  context = "monitoring-collector"
+
+  // Sent with each event, never returned by the server
+  http_headers = {
+    Authorization = "Bearer <token>"
+  }
+}
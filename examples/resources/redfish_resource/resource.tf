@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Property of a resource set immediately
+resource "redfish_resource" "asset_tag" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  odata_id = "/redfish/v1/Chassis/System.Embedded.1"
+  // Only the properties of the body are compared with the resource to detect drift
+  body = jsonencode({
+    AssetTag = "rack1-${each.key}"
+  })
+}
+
+// Properties PATCHed to the settings object of the @Redfish.Settings of a resource
+resource "redfish_resource" "bios" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  odata_id = "/redfish/v1/Systems/System.Embedded.1/Bios"
+  body = jsonencode({
+    Attributes = {
+      NumLock = "On"
+    }
+  })
+
+  /* list of possible apply times:
+      [ Immediate, OnReset, AtMaintenanceWindowStart, InMaintenanceWindowOnReset ]
+  */
+  settings_apply_time = "OnReset"
+  /* Reset parameters to be applied after the properties are PATCHed
+     list of possible value:
+      [ ForceRestart, GracefulRestart, PowerCycle]
+  */
+  reset_type    = "GracefulRestart"
+  reset_timeout = "120"
+  // The maximum amount of time to wait for the job to be completed
+  job_timeout = "1200"
+}
@@ -0,0 +1,48 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+resource "redfish_wait_for_event" "reset" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // MessageIds of the events waited for, the version of the registry may be left out
+  message_ids = ["IDRAC.SYS1003"]
+
+  // Only the events about this resource are waited for
+  origin_of_condition = "/redfish/v1/Systems/System.Embedded.1"
+
+  // Address the provider listens on, which must be reachable by the server BMC
+  listener_address = "10.0.0.5:8443"
+
+  // The maximum amount of time to wait for the event in seconds
+  timeout = 900
+
+  // Changing them waits for the event again
+  triggers = {
+    maintenance_window = "2024-06-01"
+  }
+}
+
+output "reset_event" {
+  value = redfish_wait_for_event.reset
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dell
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"terraform-provider-redfish/cassette"
+
+	"github.com/stmcginnis/gofish"
+)
+
+// The cassettes of testdata are replayed through gofish, as the provider reads them.
+// emulator.cassette.json is recorded from the emulator of the emulator package, and the
+// cassettes captured on real iDRACs can be added to cassettes with their expected values.
+var cassettes = []struct {
+	file             string
+	firmwareVersion  string
+	dellAttributes   int
+	updateTarget     string
+	raidControllerID string
+	raidFirmware     string
+}{
+	{
+		file:             "emulator.cassette.json",
+		firmwareVersion:  "6.10.80.00",
+		dellAttributes:   3,
+		updateTarget:     "/redfish/v1/UpdateService/Actions/Oem/DellUpdateService.Install",
+		raidControllerID: "RAID.Integrated.1-1",
+		raidFirmware:     "52.16.1-4405",
+	},
+}
+
+func replayCassette(t *testing.T, file string) *gofish.APIClient {
+	t.Helper()
+	replayer, err := cassette.NewReplayer(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatalf("unable to load the cassette: %s", err)
+	}
+	client, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:   "https://idrac.example.com",
+		Username:   "root",
+		Password:   "calvin",
+		BasicAuth:  true,
+		HTTPClient: &http.Client{Transport: replayer},
+	})
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	return client
+}
+
+func TestCassettes(t *testing.T) {
+	for _, c := range cassettes {
+		t.Run(c.file, func(t *testing.T) {
+			client := replayCassette(t, c.file)
+
+			managers, err := client.Service.Managers()
+			if err != nil || len(managers) == 0 {
+				t.Fatalf("unable to read the managers: %v", err)
+			}
+			manager, err := Manager(managers[0])
+			if err != nil {
+				t.Fatalf("unable to extend the manager: %s", err)
+			}
+			assertField(t, manager.FirmwareVersion, c.firmwareVersion)
+			attributes, err := manager.DellAttributes()
+			if err != nil {
+				t.Fatalf("unable to read the Dell attributes: %s", err)
+			}
+			assertInt(t, len(attributes), c.dellAttributes)
+
+			updateService, err := client.Service.UpdateService()
+			if err != nil {
+				t.Fatalf("unable to read the update service: %s", err)
+			}
+			dellUpdateService, err := UpdateService(updateService)
+			if err != nil {
+				t.Fatalf("unable to extend the update service: %s", err)
+			}
+			assertField(t, dellUpdateService.Actions.DellUpdateServiceTarget, c.updateTarget)
+
+			systems, err := client.Service.Systems()
+			if err != nil || len(systems) == 0 {
+				t.Fatalf("unable to read the systems: %v", err)
+			}
+			storage, err := systems[0].Storage()
+			if err != nil {
+				t.Fatalf("unable to read the storage: %s", err)
+			}
+			found := false
+			for _, s := range storage {
+				if s.ID != c.raidControllerID {
+					continue
+				}
+				found = true
+				dellStorage, err := Storage(s)
+				if err != nil {
+					t.Fatalf("unable to extend the storage: %s", err)
+				}
+				assertField(t, dellStorage.OemData.DellController.ControllerFirmwareVersion, c.raidFirmware)
+			}
+			assertBool(t, found, true)
+		})
+	}
+}
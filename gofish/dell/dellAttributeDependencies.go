@@ -0,0 +1,312 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dell
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// mapDependency is the type of the dependencies setting a property of an attribute
+	mapDependency = "Map"
+	// instanceTemplate stands for the group instance of an attribute in the names of the dependencies
+	instanceTemplate = "n"
+)
+
+// EffectiveAttribute returns the attribute of the given name once the Map dependencies of the registry are
+// evaluated with the values of the attributes, such as their current values updated with the planned ones.
+// The dependencies may set its Readonly, Hidden and WriteOnly flags, its bounds, its lengths and its value.
+func (m *ManagerAttributeRegistry) EffectiveAttribute(name string, values map[string]interface{}) (*ManagerAttribute, error) {
+	attr, err := m.getAttribute(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependency := range m.matchingDependencies(name, values) {
+		setAttributeProperty(attr, dependency.Dependency.MapToProperty, dependency.Dependency.MapToValue)
+	}
+	return attr, nil
+}
+
+// CheckDependencies checks that the dependencies of the registry allow the changes of the attributes. They are
+// evaluated with the current values of the attributes updated with the changes.
+func (m *ManagerAttributeRegistry) CheckDependencies(current, changes map[string]interface{}) error {
+	values := make(map[string]interface{}, len(current)+len(changes))
+	for k, v := range current {
+		values[k] = v
+	}
+	for k, v := range changes {
+		values[k] = v
+	}
+
+	var err error
+	for _, name := range sortedNames(changes) {
+		attr, attrErr := m.EffectiveAttribute(name, values)
+		if attrErr != nil {
+			err = errors.Join(err, attrErr)
+			continue
+		}
+		err = errors.Join(err, m.checkEffectiveAttribute(attr, changes[name], values))
+	}
+	return err
+}
+
+func (m *ManagerAttributeRegistry) checkEffectiveAttribute(attr *ManagerAttribute, value interface{}, values map[string]interface{}) error {
+	if attr.Readonly {
+		causes := m.readonlyCauses(attr.AttributeName, values)
+		if len(causes) == 0 {
+			return fmt.Errorf("attribute %s cannot be written as it is read only", attr.AttributeName)
+		}
+		return fmt.Errorf("attribute %s cannot be written as it is read only with the values of %s",
+			attr.AttributeName, strings.Join(causes, ", "))
+	}
+	if attr.DependencyValue != nil && attributeString(attr.DependencyValue) != attributeString(value) {
+		return fmt.Errorf("attribute %s is set to %s by its dependencies", attr.AttributeName, attributeString(attr.DependencyValue))
+	}
+	if number, ok := attributeNumber(value); ok && attr.Type == "Integer" &&
+		(number < float64(attr.LowerBound) || number > float64(attr.UpperBound)) {
+		return fmt.Errorf("value %s of attribute %s is out of bounds. Lower bound is %d, upper bound is %d",
+			attributeString(value), attr.AttributeName, attr.LowerBound, attr.UpperBound)
+	}
+	if str, ok := value.(string); ok && (attr.Type == "String" || attr.Type == "Password") &&
+		(len(str) < attr.MinLength || len(str) > attr.MaxLength) {
+		return fmt.Errorf("length %d of attribute %s is not compliant. Min length %d, max length %d",
+			len(str), attr.AttributeName, attr.MinLength, attr.MaxLength)
+	}
+	return nil
+}
+
+// readonlyCauses returns the attributes of the dependencies making an attribute read only
+func (m *ManagerAttributeRegistry) readonlyCauses(name string, values map[string]interface{}) []string {
+	var causes []string
+	for _, dependency := range m.matchingDependencies(name, values) {
+		if !strings.EqualFold(dependency.Dependency.MapToProperty, "Readonly") {
+			continue
+		}
+		instances, _ := matchTemplate(dependency.DependencyFor, name)
+		for _, term := range dependency.Dependency.MapFrom {
+			causes = append(causes, resolveTemplate(term.MapFromAttribute, instances))
+		}
+	}
+	return causes
+}
+
+// PatchOrder splits the changes of attributes into the successive PATCHes applying them, so that the attributes
+// the other changes depend on are applied before their dependents.
+func (m *ManagerAttributeRegistry) PatchOrder(changes map[string]interface{}) []map[string]interface{} {
+	levels := make(map[string]int, len(changes))
+	visiting := make(map[string]bool, len(changes))
+	var level func(name string) int
+	level = func(name string) int {
+		if l, done := levels[name]; done {
+			return l
+		}
+		// the attributes depending on each other are applied together
+		if visiting[name] {
+			return 0
+		}
+		visiting[name] = true
+		result := 0
+		for _, dependency := range m.dependenciesFor(name) {
+			instances, _ := matchTemplate(dependency.DependencyFor, name)
+			for _, term := range dependency.Dependency.MapFrom {
+				from := resolveTemplate(term.MapFromAttribute, instances)
+				if _, changed := changes[from]; changed && from != name {
+					result = max(result, level(from)+1)
+				}
+			}
+		}
+		levels[name] = result
+		return result
+	}
+
+	var batches []map[string]interface{}
+	for _, name := range sortedNames(changes) {
+		l := level(name)
+		for len(batches) <= l {
+			batches = append(batches, make(map[string]interface{}))
+		}
+		batches[l][name] = changes[name]
+	}
+	return batches
+}
+
+// dependenciesFor returns the Map dependencies of the attribute
+func (m *ManagerAttributeRegistry) dependenciesFor(name string) []AttributeDependency {
+	var result []AttributeDependency
+	for _, dependency := range m.Dependencies {
+		if dependency.Type != mapDependency {
+			continue
+		}
+		if _, ok := matchTemplate(dependency.DependencyFor, name); ok {
+			result = append(result, dependency)
+		}
+	}
+	return result
+}
+
+// matchingDependencies returns the Map dependencies of the attribute whose terms are met by the values
+func (m *ManagerAttributeRegistry) matchingDependencies(name string, values map[string]interface{}) []AttributeDependency {
+	var result []AttributeDependency
+	for _, dependency := range m.dependenciesFor(name) {
+		instances, _ := matchTemplate(dependency.DependencyFor, name)
+		if termsMet(dependency.Dependency.MapFrom, instances, values) {
+			result = append(result, dependency)
+		}
+	}
+	return result
+}
+
+// termsMet evaluates the MapFrom terms of a dependency, combined by their MapTerms from left to right
+func termsMet(terms []DependencyMapFrom, instances []string, values map[string]interface{}) bool {
+	result := false
+	for i, term := range terms {
+		current := termMet(term, values[resolveTemplate(term.MapFromAttribute, instances)])
+		switch {
+		case i == 0:
+			result = current
+		case term.MapTerms == "OR":
+			result = result || current
+		default:
+			result = result && current
+		}
+	}
+	return result
+}
+
+// termMet evaluates the MapFromCondition of a term on the value of its attribute. Only the conditions on
+// the CurrentValue of the attributes are evaluated.
+func termMet(term DependencyMapFrom, value interface{}) bool {
+	if value == nil || (len(term.MapFromProperty) > 0 && term.MapFromProperty != "CurrentValue") {
+		return false
+	}
+	number, isNumber := attributeNumber(value)
+	bound, isBound := attributeNumber(term.MapFromValue)
+	switch term.MapFromCondition {
+	case "EQU":
+		return attributeString(value) == attributeString(term.MapFromValue)
+	case "NEQ":
+		return attributeString(value) != attributeString(term.MapFromValue)
+	case "GTR":
+		return isNumber && isBound && number > bound
+	case "GEQ":
+		return isNumber && isBound && number >= bound
+	case "LSS":
+		return isNumber && isBound && number < bound
+	case "LEQ":
+		return isNumber && isBound && number <= bound
+	}
+	return false
+}
+
+// setAttributeProperty sets the property of the attribute a dependency maps to
+func setAttributeProperty(attr *ManagerAttribute, property string, value interface{}) {
+	number, _ := attributeNumber(value)
+	switch strings.ToLower(property) {
+	case "readonly":
+		attr.Readonly = attributeString(value) == "true"
+	case "hidden":
+		attr.Hidden = attributeString(value) == "true"
+	case "writeonly":
+		attr.WriteOnly = attributeString(value) == "true"
+	case "lowerbound":
+		attr.LowerBound = int(number)
+	case "upperbound":
+		attr.UpperBound = int(number)
+	case "minlength":
+		attr.MinLength = int(number)
+	case "maxlength":
+		attr.MaxLength = int(number)
+	case "currentvalue":
+		attr.DependencyValue = value
+	}
+}
+
+// matchTemplate tells whether the attribute name of a dependency, such as QuickSync.n.InactivityTimeout,
+// stands for the given attribute, and returns the group instances of the attribute standing for its "n".
+// The FQDD prefix of the name of the dependency, such as System.Embedded.n#, is ignored.
+func matchTemplate(template, name string) ([]string, bool) {
+	templateParts := strings.Split(template[strings.LastIndex(template, "#")+1:], ".")
+	nameParts := strings.Split(name, ".")
+	if len(templateParts) != len(nameParts) {
+		return nil, false
+	}
+	var instances []string
+	for i, part := range templateParts {
+		if part == instanceTemplate {
+			if _, err := strconv.Atoi(nameParts[i]); err == nil {
+				instances = append(instances, nameParts[i])
+				continue
+			}
+		}
+		if part != nameParts[i] {
+			return nil, false
+		}
+	}
+	return instances, true
+}
+
+// resolveTemplate returns the name of the attribute of a dependency, once its "n" are replaced by the
+// instances of the dependent attribute
+func resolveTemplate(template string, instances []string) string {
+	parts := strings.Split(template[strings.LastIndex(template, "#")+1:], ".")
+	i := 0
+	for j, part := range parts {
+		if part == instanceTemplate && i < len(instances) {
+			parts[j] = instances[i]
+			i++
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// attributeString returns the value of an attribute as a string, the numbers decoded from JSON included
+func attributeString(value interface{}) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// attributeNumber returns the value of an integer attribute
+func attributeNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func sortedNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
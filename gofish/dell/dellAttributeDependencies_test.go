@@ -0,0 +1,238 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dell
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var sampleJSONDependencies = `
+{
+    "Id": "ManagerAttributeRegistry.v1_0_0",
+    "RegistryEntries": {
+        "Attributes": [
+            {
+                "AttributeName": "QuickSync.1.InactivityTimerEnable",
+                "Type": "Enumeration",
+                "Value": [{"ValueDisplayName": "Disabled", "ValueName": "0"}, {"ValueDisplayName": "Enabled", "ValueName": "1"}]
+            },
+            {
+                "AttributeName": "QuickSync.1.InactivityTimeout",
+                "Type": "Integer",
+                "LowerBound": 120,
+                "UpperBound": 3600
+            },
+            {
+                "AttributeName": "ThermalSettings.1.AirExhaustTempSupport",
+                "Type": "Enumeration",
+                "Readonly": true
+            },
+            {
+                "AttributeName": "ThermalSettings.1.AirExhaustTemp",
+                "Type": "Integer",
+                "LowerBound": 0,
+                "UpperBound": 255
+            },
+            {
+                "AttributeName": "ThermalSettings.1.FanSpeedOffset",
+                "Type": "String",
+                "MinLength": 0,
+                "MaxLength": 16
+            }
+        ],
+        "Dependencies": [
+            {
+                "Dependency": {
+                    "MapFrom": [
+                        {
+                            "MapFromAttribute": "QuickSync.n.InactivityTimerEnable",
+                            "MapFromCondition": "EQU",
+                            "MapFromProperty": "CurrentValue",
+                            "MapFromValue": "Disabled"
+                        }
+                    ],
+                    "MapToAttribute": "QuickSync.n.InactivityTimeout",
+                    "MapToProperty": "Readonly",
+                    "MapToValue": true
+                },
+                "DependencyFor": "QuickSync.n.InactivityTimeout",
+                "Type": "Map"
+            },
+            {
+                "Dependency": {
+                    "MapFrom": [
+                        {
+                            "MapFromAttribute": "System.Embedded.n#ThermalSettings.n.AirExhaustTempSupport",
+                            "MapFromCondition": "NEQ",
+                            "MapFromProperty": "CurrentValue",
+                            "MapFromValue": "Supported"
+                        },
+                        {
+                            "MapFromAttribute": "ThermalSettings.n.FanSpeedOffset",
+                            "MapFromCondition": "EQU",
+                            "MapFromProperty": "CurrentValue",
+                            "MapFromValue": "Off",
+                            "MapTerms": "OR"
+                        }
+                    ],
+                    "MapToAttribute": "ThermalSettings.n.AirExhaustTemp",
+                    "MapToProperty": "CurrentValue",
+                    "MapToValue": 255
+                },
+                "DependencyFor": "ThermalSettings.n.AirExhaustTemp",
+                "Type": "Map"
+            },
+            {
+                "Dependency": {
+                    "MapFrom": [
+                        {
+                            "MapFromAttribute": "QuickSync.n.InactivityTimerEnable",
+                            "MapFromCondition": "EQU",
+                            "MapFromProperty": "CurrentValue",
+                            "MapFromValue": "Enabled"
+                        }
+                    ],
+                    "MapToAttribute": "QuickSync.n.InactivityTimeout",
+                    "MapToProperty": "UpperBound",
+                    "MapToValue": 1800
+                },
+                "DependencyFor": "QuickSync.n.InactivityTimeout",
+                "Type": "Map"
+            }
+        ]
+    }
+}
+`
+
+func TestDellAttributeDependencies(t *testing.T) {
+	var registry ManagerAttributeRegistry
+	err := json.NewDecoder(strings.NewReader(sampleJSONDependencies)).Decode(&registry)
+	if err != nil {
+		t.Fatal("could not decode ManagerAttributeRegistry JSON")
+	}
+	current := map[string]interface{}{
+		"QuickSync.1.InactivityTimerEnable":       "Disabled",
+		"QuickSync.1.InactivityTimeout":           float64(900),
+		"ThermalSettings.1.AirExhaustTempSupport": "Supported",
+		"ThermalSettings.1.AirExhaustTemp":        float64(70),
+		"ThermalSettings.1.FanSpeedOffset":        "Low",
+	}
+
+	t.Run("Test EffectiveAttribute method", func(t *testing.T) {
+		attr, err := registry.EffectiveAttribute("QuickSync.1.InactivityTimeout", current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBool(t, attr.Readonly, true)
+		assertInt(t, attr.UpperBound, 3600)
+
+		attr, err = registry.EffectiveAttribute("QuickSync.1.InactivityTimeout", map[string]interface{}{
+			"QuickSync.1.InactivityTimerEnable": "Enabled",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBool(t, attr.Readonly, false)
+		assertInt(t, attr.UpperBound, 1800)
+
+		attr, err = registry.EffectiveAttribute("ThermalSettings.1.AirExhaustTemp", current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertMapKeyValue(t, attr.DependencyValue, nil)
+
+		attr, err = registry.EffectiveAttribute("ThermalSettings.1.AirExhaustTemp", map[string]interface{}{
+			"ThermalSettings.1.AirExhaustTempSupport": "Supported",
+			"ThermalSettings.1.FanSpeedOffset":        "Off",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertMapKeyValue(t, attr.DependencyValue, float64(255))
+
+		_, err = registry.EffectiveAttribute("non.existent.property", current)
+		assertCheckAttribute(t, true, err)
+	})
+
+	t.Run("Test CheckDependencies method", func(t *testing.T) {
+		// Read only while the timer is disabled, must fail
+		err := registry.CheckDependencies(current, map[string]interface{}{"QuickSync.1.InactivityTimeout": 600})
+		assertCheckAttribute(t, true, err)
+		if err != nil && !strings.Contains(err.Error(), "QuickSync.1.InactivityTimerEnable") {
+			t.Errorf("error %s does not name the attribute making it read only", err)
+		}
+		// Enabled by the same change, must pass
+		assertCheckAttribute(t, false, registry.CheckDependencies(current, map[string]interface{}{
+			"QuickSync.1.InactivityTimerEnable": "Enabled",
+			"QuickSync.1.InactivityTimeout":     600,
+		}))
+		// Out of the bound set by the dependencies, must fail
+		assertCheckAttribute(t, true, registry.CheckDependencies(current, map[string]interface{}{
+			"QuickSync.1.InactivityTimerEnable": "Enabled",
+			"QuickSync.1.InactivityTimeout":     2400,
+		}))
+		// Value set by the dependencies, must fail unless it is the same
+		assertCheckAttribute(t, true, registry.CheckDependencies(current, map[string]interface{}{
+			"ThermalSettings.1.FanSpeedOffset": "Off",
+			"ThermalSettings.1.AirExhaustTemp": 80,
+		}))
+		assertCheckAttribute(t, false, registry.CheckDependencies(current, map[string]interface{}{
+			"ThermalSettings.1.FanSpeedOffset": "Off",
+			"ThermalSettings.1.AirExhaustTemp": 255,
+		}))
+		// Read only in the registry, must fail
+		assertCheckAttribute(t, true, registry.CheckDependencies(current, map[string]interface{}{
+			"ThermalSettings.1.AirExhaustTempSupport": "Unsupported",
+		}))
+	})
+
+	t.Run("Test PatchOrder method", func(t *testing.T) {
+		batches := registry.PatchOrder(map[string]interface{}{
+			"QuickSync.1.InactivityTimeout":     600,
+			"QuickSync.1.InactivityTimerEnable": "Enabled",
+			"ThermalSettings.1.AirExhaustTemp":  80,
+		})
+		want := []map[string]interface{}{
+			{"QuickSync.1.InactivityTimerEnable": "Enabled", "ThermalSettings.1.AirExhaustTemp": 80},
+			{"QuickSync.1.InactivityTimeout": 600},
+		}
+		if !reflect.DeepEqual(batches, want) {
+			t.Errorf("got %v, want %v", batches, want)
+		}
+
+		batches = registry.PatchOrder(map[string]interface{}{"QuickSync.1.InactivityTimeout": 600})
+		assertInt(t, len(batches), 1)
+		assertInt(t, len(registry.PatchOrder(map[string]interface{}{})), 0)
+	})
+}
+
+func TestMatchTemplate(t *testing.T) {
+	instances, ok := matchTemplate("System.Embedded.n#ThermalSettings.n.AirExhaustTemp", "ThermalSettings.1.AirExhaustTemp")
+	assertBool(t, ok, true)
+	assertArray(t, instances, []string{"1"})
+	assertField(t, resolveTemplate("System.Embedded.n#ThermalSettings.n.AirExhaustTempSupport", instances),
+		"ThermalSettings.1.AirExhaustTempSupport")
+
+	_, ok = matchTemplate("QuickSync.n.InactivityTimeout", "QuickSync.1.InactivityTimerEnable")
+	assertBool(t, ok, false)
+	_, ok = matchTemplate("QuickSync.n.InactivityTimeout", "QuickSync.n.InactivityTimeout.1")
+	assertBool(t, ok, false)
+}
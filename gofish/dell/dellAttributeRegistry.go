@@ -50,6 +50,50 @@ type ManagerAttribute struct {
 	Regex         string
 	Type          string
 	Value         []AttributeEnumValue // To be used with Enums
+	// DependencyValue is the value the dependencies of the registry set to the attribute, if any
+	DependencyValue interface{} `json:"-"`
+}
+
+// DependencyMapFrom is a term of the condition of a dependency, on the value of another attribute
+type DependencyMapFrom struct {
+	// MapFromAttribute is the attribute the condition is about. Its group instance may be "n", such as
+	// QuickSync.n.InactivityTimerEnable, standing for the instance of the dependent attribute.
+	MapFromAttribute string
+	// MapFromCondition is the comparison of the value of the attribute, such as EQU, NEQ, GTR, GEQ, LSS or LEQ
+	MapFromCondition string
+	// MapFromProperty is the property of the attribute compared, such as CurrentValue
+	MapFromProperty string
+	MapFromValue    interface{}
+	// MapTerms combines the term with the previous ones, either AND or OR
+	MapTerms string
+}
+
+// DependencyExpression sets a property of an attribute when its MapFrom terms are met
+type DependencyExpression struct {
+	MapFrom        []DependencyMapFrom
+	MapToAttribute string
+	// MapToProperty is the property of the attribute set, such as Readonly, Hidden or CurrentValue
+	MapToProperty string
+	MapToValue    interface{}
+}
+
+// AttributeDependency is a dependency of an attribute on the values of other attributes
+type AttributeDependency struct {
+	Dependency DependencyExpression
+	// DependencyFor is the attribute depending on the other ones
+	DependencyFor string
+	// Type is the type of the dependency. Only Map dependencies are defined.
+	Type string
+}
+
+// AttributeMenu is a menu grouping attributes in the registry
+type AttributeMenu struct {
+	DisplayName  string
+	DisplayOrder int
+	Hidden       bool
+	MenuName     string
+	MenuPath     string
+	Readonly     bool
 }
 
 // SupportedSystem struct represents details of the supported systems
@@ -62,11 +106,11 @@ type SupportedSystem struct {
 // ManagerAttributeRegistry contains attriutes for manager attribute registry
 type ManagerAttributeRegistry struct {
 	*common.Resource
-	Language     string
-	OwningEntity string
-	Attributes   []ManagerAttribute
-	// Dependencies
-	// Menus
+	Language         string
+	OwningEntity     string
+	Attributes       []ManagerAttribute
+	Dependencies     []AttributeDependency
+	Menus            []AttributeMenu
 	RegistryPrefix   string
 	RegistryVersion  string
 	SupportedSystems []SupportedSystem
@@ -94,7 +138,9 @@ func GetDellManagerAttributeRegistry(c common.Client, uri string) (*ManagerAttri
 func (m *ManagerAttributeRegistry) UnmarshalJSON(data []byte) error {
 	type temp ManagerAttributeRegistry
 	type RegistryEntries struct {
-		Attributes []ManagerAttribute
+		Attributes   []ManagerAttribute
+		Dependencies []AttributeDependency
+		Menus        []AttributeMenu
 	}
 	var t struct {
 		temp
@@ -108,6 +154,8 @@ func (m *ManagerAttributeRegistry) UnmarshalJSON(data []byte) error {
 
 	*m = ManagerAttributeRegistry(t.temp)
 	m.Attributes = t.RegistryEntries.Attributes
+	m.Dependencies = t.RegistryEntries.Dependencies
+	m.Menus = t.RegistryEntries.Menus
 
 	return nil
 }
@@ -237,6 +237,20 @@ func TestDellAttributesRegistry(t *testing.T) {
 		assertBool(t, registry.Attributes[3].WriteOnly, false)
 		assertInt(t, registry.Attributes[3].LowerBound, -1)
 		assertInt(t, registry.Attributes[3].UpperBound, 2592000)
+
+		// Check Dependencies and Menus
+		assertInt(t, len(registry.Dependencies), 2)
+		assertField(t, registry.Dependencies[0].DependencyFor, "QuickSync.n.InactivityTimeout")
+		assertField(t, registry.Dependencies[0].Type, "Map")
+		assertField(t, registry.Dependencies[0].Dependency.MapFrom[0].MapFromAttribute, "QuickSync.n.InactivityTimerEnable")
+		assertField(t, registry.Dependencies[0].Dependency.MapFrom[0].MapFromCondition, "EQU")
+		assertMapKeyValue(t, registry.Dependencies[0].Dependency.MapFrom[0].MapFromValue, "Enabled")
+		assertField(t, registry.Dependencies[0].Dependency.MapToProperty, "Readonly")
+		assertMapKeyValue(t, registry.Dependencies[0].Dependency.MapToValue, true)
+		assertField(t, registry.Dependencies[1].Dependency.MapFrom[0].MapFromAttribute, "System.Embedded.n#ThermalSettings.n.AirExhaustTempSupport")
+		assertInt(t, len(registry.Menus), 2)
+		assertField(t, registry.Menus[1].MenuName, "LCAttributes")
+		assertField(t, registry.Menus[1].MenuPath, "./LifecycleController.Embedded.1/LCAttributes")
 	})
 
 	t.Run("Test CheckAttribute method", func(t *testing.T) {
@@ -64,6 +64,19 @@ func (a AttributesMap) Bool(name string) bool {
 		maybeBool == "enabled")
 }
 
+// Changes returns the values differing from the current values of the attributes. The write only
+// attributes, such as passwords, are always returned, as their current value is not shown.
+func (a AttributesMap) Changes(values map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for name, value := range values {
+		current, ok := a[name]
+		if !ok || current == nil || attributeString(current) != attributeString(value) {
+			changes[name] = value
+		}
+	}
+	return changes
+}
+
 // Attributes is used to represent Dell attributes
 type Attributes struct {
 	common.Entity
@@ -73,4 +73,13 @@ func TestDellAttributes(t *testing.T) {
 	assertField(t, dellAttributes.Description, "This schema provides the oem attributes")
 	assertField(t, string(dellAttributes.settingsObject), "/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1/Settings")
 	assertMapKeyValue(t, dellAttributes.Attributes.Int("CurrentNIC.1.SharedNICScanTime"), 30)
+
+	changes := dellAttributes.Attributes.Changes(map[string]interface{}{
+		"CurrentNIC.1.MTU":         1500,
+		"CurrentNIC.1.VLanID":      2,
+		"CurrentNIC.1.Unavailable": "value",
+	})
+	assertInt(t, len(changes), 2)
+	assertMapKeyValue(t, changes["CurrentNIC.1.VLanID"], 2)
+	assertMapKeyValue(t, changes["CurrentNIC.1.Unavailable"], "value")
 }
@@ -0,0 +1,90 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"encoding/json"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Privileges are the iLO privileges of an account
+type Privileges struct {
+	HostBIOSConfigPriv       bool
+	HostNICConfigPriv        bool
+	HostStorageConfigPriv    bool
+	ILOConfigPriv            bool `json:"iLOConfigPriv"`
+	LoginPriv                bool
+	RemoteConsolePriv        bool
+	SystemRecoveryConfigPriv bool
+	UserConfigPriv           bool
+	VirtualMediaPriv         bool
+	VirtualPowerAndResetPriv bool
+}
+
+// None tells whether the account has none of the privileges
+func (p Privileges) None() bool {
+	return p == Privileges{}
+}
+
+// ManagerAccountOEM holds OEM information regarding HPE ManagerAccount
+type ManagerAccountOEM struct {
+	LoginName      string
+	Privileges     Privileges
+	ServiceAccount bool
+}
+
+// UnmarshalJSON unmarshals ManagerAccount OEM object from the raw JSON
+func (m *ManagerAccountOEM) UnmarshalJSON(data []byte) error {
+	type temp ManagerAccountOEM
+	type Hpe struct {
+		temp
+	}
+	var tempOEM struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempOEM)
+	if err != nil {
+		return err
+	}
+
+	*m = ManagerAccountOEM(tempOEM.Hpe.temp)
+	return nil
+}
+
+// ManagerAccountExtended contains gofish ManagerAccount data, as well as HPE OEM data
+type ManagerAccountExtended struct {
+	*redfish.ManagerAccount
+	// OemData will hold all ManagerAccount HPE OEM data
+	OemData ManagerAccountOEM
+}
+
+// ManagerAccount returns a hpe.ManagerAccount pointer given a redfish.ManagerAccount pointer from Gofish
+func ManagerAccount(account *redfish.ManagerAccount) (*ManagerAccountExtended, error) {
+	hpeAccount := &ManagerAccountExtended{ManagerAccount: account}
+	var oemData ManagerAccountOEM
+
+	err := json.Unmarshal(hpeAccount.Oem, &oemData)
+	if err != nil {
+		return nil, err
+	}
+	hpeAccount.OemData = oemData
+
+	return hpeAccount, nil
+}
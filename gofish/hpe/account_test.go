@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestHpeManagerAccount(t *testing.T) {
+	client := newFixtureClient()
+	account, err := redfish.GetManagerAccount(client, "/redfish/v1/AccountService/Accounts/3/")
+	if err != nil {
+		t.Fatalf("unable to read the account: %s", err)
+	}
+	hpeAccount, err := ManagerAccount(account)
+	if err != nil {
+		t.Fatalf("error when getting the HPE account: %s", err)
+	}
+
+	data := hpeAccount.OemData
+	assertField(t, hpeAccount.UserName, "ops")
+	assertField(t, data.LoginName, "Operations")
+	assertBool(t, data.ServiceAccount, false)
+	assertBool(t, data.Privileges.LoginPriv, true)
+	assertBool(t, data.Privileges.RemoteConsolePriv, true)
+	assertBool(t, data.Privileges.ILOConfigPriv, false)
+	assertBool(t, data.Privileges.None(), false)
+	assertBool(t, Privileges{}.None(), true)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// BiosLinks are the OEM links of the HPE Bios
+type BiosLinks struct {
+	BaseConfigs common.Link
+	Boot        common.Link
+	ISCSI       common.Link `json:"iScsi"`
+	Mappings    common.Link
+	TLSConfig   common.Link
+}
+
+// BiosOEM holds OEM information regarding HPE Bios
+type BiosOEM struct {
+	Links BiosLinks
+}
+
+// UnmarshalJSON unmarshals Bios OEM object from the raw JSON
+func (b *BiosOEM) UnmarshalJSON(data []byte) error {
+	type temp BiosOEM
+	type Hpe struct {
+		temp
+	}
+	var tempOEM struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempOEM)
+	if err != nil {
+		return err
+	}
+
+	*b = BiosOEM(tempOEM.Hpe.temp)
+	return nil
+}
+
+// BiosExtended contains gofish Bios data, as well as the HPE pending settings and OEM data
+type BiosExtended struct {
+	*redfish.Bios
+	// SettingsObject is the URI of the pending settings, such as /redfish/v1/systems/1/bios/settings/
+	SettingsObject string
+	// SettingsMessages are the messages of the last application of the pending settings
+	SettingsMessages []common.Message
+	// OemData will hold all Bios HPE OEM data
+	OemData BiosOEM
+}
+
+// Bios returns a hpe.Bios pointer given a redfish.Bios pointer from Gofish.
+// gofish keeps neither the Oem nor the settings of the Bios, which are read again from the service.
+func Bios(bios *redfish.Bios) (*BiosExtended, error) {
+	var t struct {
+		Settings common.Settings `json:"@Redfish.Settings"`
+		Oem      json.RawMessage
+	}
+	err := getObject(bios.GetClient(), bios.ODataID, &t)
+	if err != nil {
+		return nil, err
+	}
+	return biosExtended(bios, t.Settings, t.Oem)
+}
+
+// biosExtended parses the HPE pending settings and OEM data of a Bios
+func biosExtended(bios *redfish.Bios, settings common.Settings, oem json.RawMessage) (*BiosExtended, error) {
+	hpeBios := &BiosExtended{
+		Bios:             bios,
+		SettingsObject:   string(settings.SettingsObject),
+		SettingsMessages: settings.Messages,
+	}
+	var oemData BiosOEM
+
+	err := json.Unmarshal(oem, &oemData)
+	if err != nil {
+		return nil, err
+	}
+	hpeBios.OemData = oemData
+
+	return hpeBios, nil
+}
+
+// BootSettings returns the boot settings of the Bios, where the iLO keeps the boot order
+func (b *BiosExtended) BootSettings() (*BootSettings, error) {
+	if len(b.OemData.Links.Boot) == 0 {
+		return nil, fmt.Errorf("the bios %s has no boot settings", b.ODataID)
+	}
+	return GetBootSettings(b.GetClient(), string(b.OemData.Links.Boot))
+}
+
+// BootSource is a boot source of the boot settings
+type BootSource struct {
+	BootString           string
+	CorrelatableID       string
+	StructuredBootString string
+	UEFIDevicePath       string
+}
+
+// BootSettings are the HPE boot settings of the Bios
+type BootSettings struct {
+	Entity
+	BootSources      []BootSource
+	DefaultBootOrder []string
+	// PersistentBootConfigOrder is the boot order, as StructuredBootString of the boot sources
+	PersistentBootConfigOrder []string
+	// SettingsObject is the URI of the pending boot settings
+	SettingsObject string
+	// SettingsMessages are the messages of the last application of the pending boot settings
+	SettingsMessages []common.Message
+	client           common.Client
+}
+
+// UnmarshalJSON unmarshals BootSettings object from the raw JSON
+func (b *BootSettings) UnmarshalJSON(data []byte) error {
+	type temp BootSettings
+	var t struct {
+		temp
+		Settings common.Settings `json:"@Redfish.Settings"`
+	}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+
+	*b = BootSettings(t.temp)
+	b.SettingsObject = string(t.Settings.SettingsObject)
+	b.SettingsMessages = t.Settings.Messages
+	return nil
+}
+
+// GetBootSettings returns a BootSettings pointer given a client and a uri to query
+func GetBootSettings(c common.Client, uri string) (*BootSettings, error) {
+	var settings BootSettings
+	err := getObject(c, uri, &settings)
+	if err != nil {
+		return nil, err
+	}
+	settings.client = c
+	return &settings, nil
+}
+
+// StructuredBootString returns the StructuredBootString of the boot source with the given UEFI device path
+func (b *BootSettings) StructuredBootString(uefiDevicePath string) (string, bool) {
+	for _, source := range b.BootSources {
+		if source.UEFIDevicePath == uefiDevicePath {
+			return source.StructuredBootString, true
+		}
+	}
+	return "", false
+}
+
+// SetPersistentBootConfigOrder puts the boot order into the pending boot settings
+func (b *BootSettings) SetPersistentBootConfigOrder(order []string) error {
+	if len(b.SettingsObject) == 0 {
+		return fmt.Errorf("the boot settings %s have no pending settings", b.ODataID)
+	}
+	resp, err := b.client.Patch(b.SettingsObject, map[string]interface{}{"PersistentBootConfigOrder": order})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
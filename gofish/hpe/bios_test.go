@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestHpeBios(t *testing.T) {
+	client := newFixtureClient()
+	bios, err := redfish.GetBios(client, "/redfish/v1/systems/1/bios/")
+	if err != nil {
+		t.Fatalf("unable to read the bios: %s", err)
+	}
+	hpeBios, err := Bios(bios)
+	if err != nil {
+		t.Fatalf("error when getting the HPE bios: %s", err)
+	}
+
+	t.Run("Test_HPE_pending_settings", func(t *testing.T) {
+		assertField(t, hpeBios.SettingsObject, "/redfish/v1/systems/1/bios/settings/")
+		assertInt(t, len(hpeBios.SettingsMessages), 2)
+		failures := SettingsFailures(hpeBios.SettingsMessages)
+		assertInt(t, len(failures), 1)
+		assertField(t, failures[0].MessageID, "Base.1.0.PropertyValueNotInList")
+		assertArray(t, failures[0].MessageArgs, []string{"ProcHyperthreading"})
+	})
+
+	t.Run("Test_HPE_OEM_links", func(t *testing.T) {
+		links := hpeBios.OemData.Links
+		assertLink(t, links.Boot, "/redfish/v1/systems/1/bios/boot/")
+		assertLink(t, links.BaseConfigs, "/redfish/v1/systems/1/bios/baseconfigs/")
+		assertLink(t, links.ISCSI, "/redfish/v1/systems/1/bios/iscsi/")
+	})
+
+	t.Run("Test_HPE_boot_settings", func(t *testing.T) {
+		boot, err := hpeBios.BootSettings()
+		if err != nil {
+			t.Fatalf("BootSettings failed: %s", err)
+		}
+		assertField(t, boot.SettingsObject, "/redfish/v1/systems/1/bios/boot/settings/")
+		assertArray(t, boot.PersistentBootConfigOrder, []string{"HD.EmbRAID.1.8", "NIC.LOM.1.1.IPv4", "Generic.USB.1.1"})
+		assertInt(t, len(boot.BootSources), 3)
+		structured, ok := boot.StructuredBootString("UsbClass(0xFFFF,0xFFFF,0xFF,0xFF,0xFF)")
+		assertBool(t, ok, true)
+		assertField(t, structured, "Generic.USB.1.1")
+		_, ok = boot.StructuredBootString("PciRoot(0x9)")
+		assertBool(t, ok, false)
+
+		err = boot.SetPersistentBootConfigOrder([]string{"Generic.USB.1.1", "HD.EmbRAID.1.8", "NIC.LOM.1.1.IPv4"})
+		if err != nil {
+			t.Fatalf("SetPersistentBootConfigOrder failed: %s", err)
+		}
+		assertCall(t, client, "PATCH", "/redfish/v1/systems/1/bios/boot/settings/",
+			"map[PersistentBootConfigOrder:[Generic.USB.1.1 HD.EmbRAID.1.8 NIC.LOM.1.1.IPv4]]")
+	})
+}
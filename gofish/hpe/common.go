@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hpe extends the gofish objects with the OEM actions, links and data of the HPE iLO
+package hpe
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/stmcginnis/gofish/common"
+)
+
+// Entity provides the common basis for hpe and gofish objects
+type Entity struct {
+	ODataContext string `json:"@odata.context"`
+	ODataID      string `json:"@odata.id"`
+	ODataType    string `json:"@odata.type"`
+	ID           string `json:"Id"`
+	Name         string
+	Description  string
+}
+
+// getObject reads the object of an URI into v
+func getObject(c common.Client, uri string, v interface{}) error {
+	resp, err := c.Get(uri)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getOem reads the Oem of a resource, for the gofish objects which do not keep it
+func getOem(c common.Client, uri string) (json.RawMessage, error) {
+	var t struct {
+		Oem json.RawMessage
+	}
+	err := getObject(c, uri, &t)
+	if err != nil {
+		return nil, err
+	}
+	return t.Oem, nil
+}
+
+// listReferences returns the members of a collection
+func listReferences(c common.Client, uri string) ([]string, error) {
+	var collection struct {
+		Members common.Links
+	}
+	err := getObject(c, uri, &collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection.Members.ToStrings(), nil
+}
+
+// GetSettings returns the @Redfish.Settings of a resource. The iLO reports in its Messages the
+// outcome of the last application of the pending settings.
+func GetSettings(c common.Client, uri string) (*common.Settings, error) {
+	var t struct {
+		Settings common.Settings `json:"@Redfish.Settings"`
+	}
+	err := getObject(c, uri, &t)
+	if err != nil {
+		return nil, err
+	}
+	return &t.Settings, nil
+}
+
+// SettingsFailures returns the messages of settings reporting a failure. The iLO reports the
+// settings which were applied with a Success message, and the pending ones with SystemResetRequired.
+func SettingsFailures(messages []common.Message) []common.Message {
+	var failures []common.Message
+	for _, message := range messages {
+		if strings.HasSuffix(message.MessageID, ".Success") || strings.HasSuffix(message.MessageID, ".SystemResetRequired") {
+			continue
+		}
+		failures = append(failures, message)
+	}
+	return failures
+}
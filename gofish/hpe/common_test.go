@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stmcginnis/gofish/common"
+)
+
+// This file contains useful functions for testing purposes
+
+// fixtures are the files of testdata, by the URI of the iLO they were read from
+var fixtures = map[string]string{
+	"/redfish/v1/Managers/1/":                                                           "manager.json",
+	"/redfish/v1/Systems/1/":                                                            "system.json",
+	"/redfish/v1/systems/1/bios/":                                                       "bios.json",
+	"/redfish/v1/systems/1/bios/boot/":                                                  "boot.json",
+	"/redfish/v1/systems/1/smartstorageconfig/":                                         "smartstorageconfig.json",
+	"/redfish/v1/Systems/1/SmartStorage/":                                               "smartstorage.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/":                              "arraycontrollers.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/":                            "arraycontroller.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/LogicalDrives/":              "logicaldrives.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/LogicalDrives/1/":            "logicaldrive.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/LogicalDrives/1/DataDrives/": "datadrives.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/DiskDrives/":                 "physicaldrives.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/DiskDrives/0/":               "physicaldrive-0.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/DiskDrives/1/":               "physicaldrive-1.json",
+	"/redfish/v1/Systems/1/SmartStorage/ArrayControllers/0/DiskDrives/2/":               "physicaldrive-2.json",
+	"/redfish/v1/AccountService/Accounts/3/":                                            "account.json",
+}
+
+// fixtureClient answers the GET requests with the fixtures, and records the other requests
+type fixtureClient struct {
+	*common.TestClient
+}
+
+func newFixtureClient() *fixtureClient {
+	return &fixtureClient{TestClient: &common.TestClient{}}
+}
+
+func (c *fixtureClient) Get(uri string) (*http.Response, error) {
+	file, ok := fixtures[uri]
+	if !ok {
+		return nil, common.ConstructError(http.StatusNotFound, []byte("{}"))
+	}
+	content, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(content))}, nil
+}
+
+func assertField(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func assertBool(t testing.TB, got, want bool) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %t, want %t", got, want)
+	}
+}
+
+func assertInt(t testing.TB, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func assertArray(t testing.TB, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func assertLink(t testing.TB, got common.Link, want string) {
+	t.Helper()
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// assertCall checks the last request recorded by the client
+func assertCall(t testing.TB, c *fixtureClient, action, uri, payload string) {
+	t.Helper()
+	calls := c.CapturedCalls()
+	if len(calls) == 0 {
+		t.Fatalf("got no request, want %s %s", action, uri)
+	}
+	call := calls[len(calls)-1]
+	assertField(t, call.Action, action)
+	assertField(t, call.URL, uri)
+	assertField(t, call.Payload, payload)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// PostState is the progress of the power-on self test (POST) of the system
+type PostState string
+
+const (
+	// PowerOffPostState means the system is off
+	PowerOffPostState PostState = "PowerOff"
+	// InPostPostState means the system runs its POST, where the pending settings are applied
+	InPostPostState PostState = "InPost"
+	// InPostDiscoveryCompletePostState means the POST has discovered the devices of the system
+	InPostDiscoveryCompletePostState PostState = "InPostDiscoveryComplete"
+	// FinishedPostPostState means the POST is over, and the system boots
+	FinishedPostPostState PostState = "FinishedPost"
+)
+
+// ColdBootSystemReset is the SystemReset type which removes the power of the system, as PowerCycle does
+const ColdBootSystemReset = "ColdBoot"
+
+// SystemActions stores the OEM ComputerSystem actions from HPE
+type SystemActions struct {
+	// PowerButton
+	PowerButtonTarget   string
+	PowerButtonPushType []string
+
+	// SystemReset
+	SystemResetTarget    string
+	SystemResetResetType []string
+}
+
+// UnmarshalJSON unmarshals ComputerSystem Actions object from the raw JSON
+func (s *SystemActions) UnmarshalJSON(data []byte) error {
+	type PowerButton struct {
+		Target   string
+		PushType []string `json:"PushType@Redfish.AllowableValues"`
+	}
+
+	type SystemReset struct {
+		Target    string
+		ResetType []string `json:"ResetType@Redfish.AllowableValues"`
+	}
+
+	type Actions struct {
+		PowerButton PowerButton `json:"#HpeComputerSystemExt.PowerButton"`
+		SystemReset SystemReset `json:"#HpeComputerSystemExt.SystemReset"`
+	}
+	type Hpe struct {
+		Actions Actions
+	}
+	var tempActions struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempActions)
+	if err != nil {
+		return err
+	}
+
+	// Fill actions
+	s.PowerButtonTarget = tempActions.Hpe.Actions.PowerButton.Target
+	s.PowerButtonPushType = tempActions.Hpe.Actions.PowerButton.PushType
+	s.SystemResetTarget = tempActions.Hpe.Actions.SystemReset.Target
+	s.SystemResetResetType = tempActions.Hpe.Actions.SystemReset.ResetType
+
+	return nil
+}
+
+type systemLinks struct {
+	EthernetInterfaces common.Link
+	NetworkAdapters    common.Link
+	PCIDevices         common.Link
+	PCISlots           common.Link
+	SmartStorage       common.Link
+	USBPorts           common.Link
+}
+
+// UnmarshalJSON unmarshals ComputerSystem Links object from the raw JSON
+func (s *systemLinks) UnmarshalJSON(data []byte) error {
+	type temp systemLinks
+	type Hpe struct {
+		Links temp
+	}
+	var tempLink struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempLink)
+	if err != nil {
+		return err
+	}
+
+	*s = systemLinks(tempLink.Hpe.Links)
+
+	return nil
+}
+
+// SystemOEM holds OEM information regarding HPE ComputerSystem
+type SystemOEM struct {
+	CurrentPowerOnTimeSeconds      int
+	IntelligentProvisioningVersion string
+	PostMode                       string
+	PostState                      PostState
+	PowerAllocationLimit           int
+	PowerOnDelay                   string
+	SmartStorageConfig             common.Links
+}
+
+// UnmarshalJSON unmarshals ComputerSystem OEM object from the raw JSON
+func (s *SystemOEM) UnmarshalJSON(data []byte) error {
+	type temp SystemOEM
+	type Hpe struct {
+		temp
+	}
+	var tempOEM struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempOEM)
+	if err != nil {
+		return err
+	}
+
+	*s = SystemOEM(tempOEM.Hpe.temp)
+	return nil
+}
+
+// ComputerSystemExtended contains gofish ComputerSystem data, as well as HPE OEM actions, links and data
+type ComputerSystemExtended struct {
+	*redfish.ComputerSystem
+	// Actions will hold all ComputerSystem HPE OEM actions
+	Actions SystemActions
+	links   systemLinks
+	// OemData will hold all ComputerSystem HPE OEM data
+	OemData SystemOEM
+}
+
+// ComputerSystem returns a hpe.ComputerSystem pointer given a redfish.ComputerSystem pointer from Gofish.
+// gofish does not keep the Oem of the systems, which is read again from the service.
+func ComputerSystem(system *redfish.ComputerSystem) (*ComputerSystemExtended, error) {
+	oem, err := getOem(system.GetClient(), system.ODataID)
+	if err != nil {
+		return nil, err
+	}
+	return computerSystem(system, oem)
+}
+
+// computerSystem parses the HPE OEM actions, links and data of a system
+func computerSystem(system *redfish.ComputerSystem, oem json.RawMessage) (*ComputerSystemExtended, error) {
+	hpeSystem := &ComputerSystemExtended{ComputerSystem: system}
+	var actions SystemActions
+	var links systemLinks
+	var oemData SystemOEM
+
+	err := json.Unmarshal(oem, &actions)
+	if err != nil {
+		return nil, err
+	}
+	hpeSystem.Actions = actions
+
+	err = json.Unmarshal(oem, &links)
+	if err != nil {
+		return nil, err
+	}
+	hpeSystem.links = links
+
+	err = json.Unmarshal(oem, &oemData)
+	if err != nil {
+		return nil, err
+	}
+	hpeSystem.OemData = oemData
+
+	return hpeSystem, nil
+}
+
+// SystemReset resets the system with one of the SystemReset types of the iLO, such as ColdBoot
+func (s *ComputerSystemExtended) SystemReset(resetType string) error {
+	if len(s.Actions.SystemResetTarget) == 0 {
+		return fmt.Errorf("the system %s has no SystemReset action", s.ID)
+	}
+	resp, err := s.GetClient().Post(s.Actions.SystemResetTarget, map[string]interface{}{"ResetType": resetType})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+// PushPowerButton pushes the power button of the system, with the Press or PressAndHold push type
+func (s *ComputerSystemExtended) PushPowerButton(pushType string) error {
+	if len(s.Actions.PowerButtonTarget) == 0 {
+		return fmt.Errorf("the system %s has no PowerButton action", s.ID)
+	}
+	resp, err := s.GetClient().Post(s.Actions.PowerButtonTarget, map[string]interface{}{"PushType": pushType})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+// ArrayControllers returns the Smart Array controllers of the system
+func (s *ComputerSystemExtended) ArrayControllers() ([]*ArrayController, error) {
+	if len(s.links.SmartStorage) == 0 {
+		return nil, nil
+	}
+	smartStorage, err := GetSmartStorage(s.GetClient(), string(s.links.SmartStorage))
+	if err != nil {
+		return nil, err
+	}
+	return smartStorage.ArrayControllers()
+}
+
+// SmartStorageConfigs returns the configurations of the Smart Array controllers of the system
+func (s *ComputerSystemExtended) SmartStorageConfigs() ([]*SmartStorageConfig, error) {
+	var result []*SmartStorageConfig
+	for _, link := range s.OemData.SmartStorageConfig {
+		config, err := GetSmartStorageConfig(s.GetClient(), string(link))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, config)
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestHpeComputerSystem(t *testing.T) {
+	client := newFixtureClient()
+	system, err := redfish.GetComputerSystem(client, "/redfish/v1/Systems/1/")
+	if err != nil {
+		t.Fatalf("unable to read the system: %s", err)
+	}
+	hpeSystem, err := ComputerSystem(system)
+	if err != nil {
+		t.Fatalf("error when getting the HPE system: %s", err)
+	}
+
+	t.Run("Test_HPE_OEM_actions", func(t *testing.T) {
+		actions := hpeSystem.Actions
+		assertField(t, actions.PowerButtonTarget, "/redfish/v1/Systems/1/Actions/Oem/Hpe/HpeComputerSystemExt.PowerButton/")
+		assertArray(t, actions.PowerButtonPushType, []string{"Press", "PressAndHold"})
+		assertField(t, actions.SystemResetTarget, "/redfish/v1/Systems/1/Actions/Oem/Hpe/HpeComputerSystemExt.SystemReset/")
+		assertArray(t, actions.SystemResetResetType, []string{ColdBootSystemReset, "AuxCycle"})
+	})
+
+	t.Run("Test_HPE_OEM_data", func(t *testing.T) {
+		data := hpeSystem.OemData
+		assertField(t, string(data.PostState), string(FinishedPostPostState))
+		assertField(t, data.IntelligentProvisioningVersion, "3.64.8")
+		assertInt(t, data.PowerAllocationLimit, 1600)
+		assertInt(t, len(data.SmartStorageConfig), 1)
+		assertLink(t, hpeSystem.links.SmartStorage, "/redfish/v1/Systems/1/SmartStorage/")
+	})
+
+	t.Run("Test_HPE_system_reset", func(t *testing.T) {
+		if err := hpeSystem.SystemReset(ColdBootSystemReset); err != nil {
+			t.Fatalf("SystemReset failed: %s", err)
+		}
+		assertCall(t, client, "POST", "/redfish/v1/Systems/1/Actions/Oem/Hpe/HpeComputerSystemExt.SystemReset/", "map[ResetType:ColdBoot]")
+	})
+}
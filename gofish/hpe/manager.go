@@ -0,0 +1,233 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// ManagerActions stores the OEM Manager actions from HPE. The iLO lists them in the Oem of
+// the Manager rather than in its Actions.
+type ManagerActions struct {
+	// ClearHotKeys
+	ClearHotKeysTarget string
+
+	// ClearRestAPIState
+	ClearRestAPIStateTarget string
+
+	// ResetToFactoryDefaults
+	ResetToFactoryDefaultsTarget    string
+	ResetToFactoryDefaultsResetType []string
+}
+
+// UnmarshalJSON unmarshals Manager Actions object from the raw JSON
+func (m *ManagerActions) UnmarshalJSON(data []byte) error {
+	type Target struct {
+		Target string
+	}
+
+	type ResetToFactoryDefaults struct {
+		Target    string
+		ResetType []string `json:"ResetType@Redfish.AllowableValues"`
+	}
+
+	type Actions struct {
+		ClearHotKeys           Target                 `json:"#HpeiLO.ClearHotKeys"`
+		ClearRestAPIState      Target                 `json:"#HpeiLO.ClearRestApiState"`
+		ResetToFactoryDefaults ResetToFactoryDefaults `json:"#HpeiLO.ResetToFactoryDefaults"`
+	}
+	type Hpe struct {
+		Actions Actions
+	}
+	var tempActions struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempActions)
+	if err != nil {
+		return err
+	}
+
+	// Fill actions
+	m.ClearHotKeysTarget = tempActions.Hpe.Actions.ClearHotKeys.Target
+	m.ClearRestAPIStateTarget = tempActions.Hpe.Actions.ClearRestAPIState.Target
+	m.ResetToFactoryDefaultsTarget = tempActions.Hpe.Actions.ResetToFactoryDefaults.Target
+	m.ResetToFactoryDefaultsResetType = tempActions.Hpe.Actions.ResetToFactoryDefaults.ResetType
+
+	return nil
+}
+
+// ManagerLinks are the OEM links of the HPE Manager
+type ManagerLinks struct {
+	ActiveHealthSystem   common.Link
+	BackupRestoreService common.Link
+	DateTimeService      common.Link
+	EmbeddedMediaService common.Link
+	FederationGroups     common.Link
+	LicenseService       common.Link
+	SecurityService      common.Link
+	SNMPService          common.Link
+}
+
+// UnmarshalJSON unmarshals Manager Links object from the raw JSON
+func (m *ManagerLinks) UnmarshalJSON(data []byte) error {
+	type temp ManagerLinks
+	type Hpe struct {
+		Links temp
+	}
+	var tempLink struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempLink)
+	if err != nil {
+		return err
+	}
+
+	*m = ManagerLinks(tempLink.Hpe.Links)
+
+	return nil
+}
+
+// FirmwareVersion is the version of a firmware of the iLO
+type FirmwareVersion struct {
+	Date          string
+	DebugBuild    bool
+	MajorVersion  int
+	MinorVersion  int
+	VersionString string
+}
+
+// ManagerFirmware holds the firmware versions of the iLO
+type ManagerFirmware struct {
+	Current FirmwareVersion
+}
+
+// License is the license of the iLO
+type License struct {
+	LicenseKey    string
+	LicenseString string
+	LicenseType   string
+}
+
+// SelfTestResult is the result of a self test of the iLO
+type SelfTestResult struct {
+	Notes        string
+	SelfTestName string
+	Status       string
+}
+
+// ManagerOEM hold OEM information regarding HPE Manager (iLO)
+type ManagerOEM struct {
+	Firmware                     ManagerFirmware
+	License                      License
+	IdleConnectionTimeoutMinutes int
+	RequireHostAuthentication    bool
+	RequiredLoginForiLORBSU      bool
+	VirtualNICEnabled            bool
+	SelfTestResults              []SelfTestResult `json:"iLOSelfTestResults"`
+}
+
+// UnmarshalJSON unmarshals Manager OEM object from the raw JSON
+func (m *ManagerOEM) UnmarshalJSON(data []byte) error {
+	type temp ManagerOEM
+	type Hpe struct {
+		temp
+	}
+	var tempOEM struct {
+		Hpe Hpe
+	}
+
+	err := json.Unmarshal(data, &tempOEM)
+	if err != nil {
+		return err
+	}
+
+	*m = ManagerOEM(tempOEM.Hpe.temp)
+	return nil
+}
+
+// ManagerExtended contains gofish Manager data, as well as HPE OEM actions, links and data
+type ManagerExtended struct {
+	*redfish.Manager
+	// Actions will hold all Manager HPE OEM actions
+	Actions ManagerActions
+	// Links will hold all Manager HPE OEM links
+	Links ManagerLinks
+	// OemData will hold all Manager HPE OEM data
+	OemData ManagerOEM
+}
+
+// Manager returns a hpe.Manager pointer given a redfish.Manager pointer from Gofish
+// This is the wrapper that extracts and parses HPE Manager OEM actions, links and data.
+func Manager(manager *redfish.Manager) (*ManagerExtended, error) {
+	hpeManager := &ManagerExtended{Manager: manager}
+	var actions ManagerActions
+	var links ManagerLinks
+	var oemData ManagerOEM
+
+	err := json.Unmarshal(hpeManager.Oem, &actions)
+	if err != nil {
+		return nil, err
+	}
+	hpeManager.Actions = actions
+
+	err = json.Unmarshal(hpeManager.Oem, &links)
+	if err != nil {
+		return nil, err
+	}
+	hpeManager.Links = links
+
+	err = json.Unmarshal(hpeManager.Oem, &oemData)
+	if err != nil {
+		return nil, err
+	}
+	hpeManager.OemData = oemData
+
+	return hpeManager, nil
+}
+
+// ResetToFactoryDefaults resets the iLO to its factory defaults
+func (m *ManagerExtended) ResetToFactoryDefaults() error {
+	if len(m.Actions.ResetToFactoryDefaultsTarget) == 0 {
+		return fmt.Errorf("the manager %s has no ResetToFactoryDefaults action", m.ID)
+	}
+	resp, err := m.GetClient().Post(m.Actions.ResetToFactoryDefaultsTarget, map[string]interface{}{"ResetType": "Default"})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+// ClearRestAPIState clears the state of the REST API of the iLO, such as the BIOS settings it holds
+func (m *ManagerExtended) ClearRestAPIState() error {
+	if len(m.Actions.ClearRestAPIStateTarget) == 0 {
+		return fmt.Errorf("the manager %s has no ClearRestApiState action", m.ID)
+	}
+	resp, err := m.GetClient().Post(m.Actions.ClearRestAPIStateTarget, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
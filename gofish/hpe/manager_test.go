@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestHpeManager(t *testing.T) {
+	client := newFixtureClient()
+	manager, err := redfish.GetManager(client, "/redfish/v1/Managers/1/")
+	if err != nil {
+		t.Fatalf("unable to read the manager: %s", err)
+	}
+	hpeManager, err := Manager(manager)
+	if err != nil {
+		t.Fatalf("error when getting the HPE manager: %s", err)
+	}
+
+	t.Run("Test_HPE_OEM_actions", func(t *testing.T) {
+		actions := hpeManager.Actions
+		assertField(t, actions.ClearHotKeysTarget, "/redfish/v1/Managers/1/Actions/Oem/Hpe/HpeiLO.ClearHotKeys/")
+		assertField(t, actions.ClearRestAPIStateTarget, "/redfish/v1/Managers/1/Actions/Oem/Hpe/HpeiLO.ClearRestApiState/")
+		assertField(t, actions.ResetToFactoryDefaultsTarget, "/redfish/v1/Managers/1/Actions/Oem/Hpe/HpeiLO.ResetToFactoryDefaults/")
+		assertArray(t, actions.ResetToFactoryDefaultsResetType, []string{"Default"})
+	})
+
+	t.Run("Test_HPE_OEM_links", func(t *testing.T) {
+		links := hpeManager.Links
+		assertLink(t, links.ActiveHealthSystem, "/redfish/v1/Managers/1/ActiveHealthSystem/")
+		assertLink(t, links.DateTimeService, "/redfish/v1/Managers/1/DateTime/")
+		assertLink(t, links.LicenseService, "/redfish/v1/Managers/1/LicenseService/")
+		assertLink(t, links.SecurityService, "/redfish/v1/Managers/1/SecurityService/")
+		assertLink(t, links.SNMPService, "/redfish/v1/Managers/1/SnmpService/")
+	})
+
+	t.Run("Test_HPE_OEM_data", func(t *testing.T) {
+		data := hpeManager.OemData
+		assertField(t, data.Firmware.Current.VersionString, "iLO 5 v2.78")
+		assertInt(t, data.Firmware.Current.MajorVersion, 2)
+		assertInt(t, data.Firmware.Current.MinorVersion, 78)
+		assertField(t, data.License.LicenseString, "iLO Advanced")
+		assertInt(t, data.IdleConnectionTimeoutMinutes, 30)
+		assertBool(t, data.VirtualNICEnabled, true)
+		assertInt(t, len(data.SelfTestResults), 2)
+		assertField(t, data.SelfTestResults[1].SelfTestName, "EmbeddedFlash")
+	})
+
+	t.Run("Test_HPE_reset_to_factory_defaults", func(t *testing.T) {
+		if err := hpeManager.ResetToFactoryDefaults(); err != nil {
+			t.Fatalf("ResetToFactoryDefaults failed: %s", err)
+		}
+		assertCall(t, client, "POST", "/redfish/v1/Managers/1/Actions/Oem/Hpe/HpeiLO.ResetToFactoryDefaults/", "map[ResetType:Default]")
+	})
+}
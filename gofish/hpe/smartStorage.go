@@ -0,0 +1,365 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stmcginnis/gofish/common"
+)
+
+const (
+	// StrictDataGuard rejects the configurations destroying the data of a logical drive
+	StrictDataGuard = "Strict"
+	// PermissiveDataGuard allows the destructive changes of the logical drives of the configuration
+	PermissiveDataGuard = "Permissive"
+	// LogicalDriveDeleteAction deletes a logical drive of a configuration
+	LogicalDriveDeleteAction = "LogicalDriveDelete"
+)
+
+// SmartStorage is the HPE smart storage of a system
+type SmartStorage struct {
+	Entity
+	Status common.Status
+	links  smartStorageLinks
+	client common.Client
+}
+
+type smartStorageLinks struct {
+	ArrayControllers common.Link
+	HostBusAdapters  common.Link
+}
+
+// UnmarshalJSON unmarshals SmartStorage object from the raw JSON
+func (s *SmartStorage) UnmarshalJSON(data []byte) error {
+	type temp SmartStorage
+	var t struct {
+		temp
+		Links smartStorageLinks
+	}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+
+	*s = SmartStorage(t.temp)
+	s.links = t.Links
+	return nil
+}
+
+// GetSmartStorage returns a SmartStorage pointer given a client and a uri to query
+func GetSmartStorage(c common.Client, uri string) (*SmartStorage, error) {
+	var smartStorage SmartStorage
+	err := getObject(c, uri, &smartStorage)
+	if err != nil {
+		return nil, err
+	}
+	smartStorage.client = c
+	return &smartStorage, nil
+}
+
+// ArrayControllers returns the Smart Array controllers of the smart storage
+func (s *SmartStorage) ArrayControllers() ([]*ArrayController, error) {
+	links, err := listReferences(s.client, string(s.links.ArrayControllers))
+	if err != nil {
+		return nil, err
+	}
+	var result []*ArrayController
+	for _, link := range links {
+		controller, err := GetArrayController(s.client, link)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, controller)
+	}
+	return result, nil
+}
+
+// ArrayControllerFirmware holds the firmware version of a Smart Array controller
+type ArrayControllerFirmware struct {
+	Current struct {
+		VersionString string
+	}
+}
+
+// ArrayController is a HPE Smart Array controller
+type ArrayController struct {
+	Entity
+	CacheMemorySizeMiB   int
+	ControllerPartNumber string
+	FirmwareVersion      ArrayControllerFirmware
+	// Location is the location of the controller, such as "Slot 0"
+	Location       string
+	LocationFormat string
+	Model          string
+	SerialNumber   string
+	Status         common.Status
+	links          arrayControllerLinks
+	client         common.Client
+}
+
+type arrayControllerLinks struct {
+	LogicalDrives      common.Link
+	PhysicalDrives     common.Link
+	UnconfiguredDrives common.Link
+}
+
+// UnmarshalJSON unmarshals ArrayController object from the raw JSON
+func (a *ArrayController) UnmarshalJSON(data []byte) error {
+	type temp ArrayController
+	var t struct {
+		temp
+		Links arrayControllerLinks
+	}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+
+	*a = ArrayController(t.temp)
+	a.links = t.Links
+	return nil
+}
+
+// GetArrayController returns an ArrayController pointer given a client and a uri to query
+func GetArrayController(c common.Client, uri string) (*ArrayController, error) {
+	var controller ArrayController
+	err := getObject(c, uri, &controller)
+	if err != nil {
+		return nil, err
+	}
+	controller.client = c
+	return &controller, nil
+}
+
+// LogicalDrives returns the logical drives of the controller
+func (a *ArrayController) LogicalDrives() ([]*LogicalDrive, error) {
+	links, err := listReferences(a.client, string(a.links.LogicalDrives))
+	if err != nil {
+		return nil, err
+	}
+	var result []*LogicalDrive
+	for _, link := range links {
+		drive, err := GetLogicalDrive(a.client, link)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, drive)
+	}
+	return result, nil
+}
+
+// PhysicalDrives returns the physical drives attached to the controller
+func (a *ArrayController) PhysicalDrives() ([]*PhysicalDrive, error) {
+	return listPhysicalDrives(a.client, string(a.links.PhysicalDrives))
+}
+
+// LogicalDrive is a logical drive of a Smart Array controller
+type LogicalDrive struct {
+	Entity
+	CapacityMiB        int
+	LogicalDriveName   string
+	LogicalDriveNumber int
+	// Raid is the RAID level of the logical drive, such as "1" or "5"
+	Raid            string
+	StripeSizeBytes int
+	Status          common.Status
+	// VolumeUniqueIdentifier identifies the logical drive in the SmartStorageConfig
+	VolumeUniqueIdentifier string
+	links                  logicalDriveLinks
+	client                 common.Client
+}
+
+type logicalDriveLinks struct {
+	DataDrives common.Link
+}
+
+// UnmarshalJSON unmarshals LogicalDrive object from the raw JSON
+func (l *LogicalDrive) UnmarshalJSON(data []byte) error {
+	type temp LogicalDrive
+	var t struct {
+		temp
+		Links logicalDriveLinks
+	}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+
+	*l = LogicalDrive(t.temp)
+	l.links = t.Links
+	return nil
+}
+
+// GetLogicalDrive returns a LogicalDrive pointer given a client and a uri to query
+func GetLogicalDrive(c common.Client, uri string) (*LogicalDrive, error) {
+	var drive LogicalDrive
+	err := getObject(c, uri, &drive)
+	if err != nil {
+		return nil, err
+	}
+	drive.client = c
+	return &drive, nil
+}
+
+// DataDrives returns the physical drives of the logical drive
+func (l *LogicalDrive) DataDrives() ([]*PhysicalDrive, error) {
+	return listPhysicalDrives(l.client, string(l.links.DataDrives))
+}
+
+// PhysicalDrive is a drive attached to a Smart Array controller
+type PhysicalDrive struct {
+	Entity
+	CapacityMiB   int
+	InterfaceType string
+	// Location is the location of the drive, such as "1I:1:1", which names it in the SmartStorageConfig
+	Location       string
+	LocationFormat string
+	MediaType      string
+	Model          string
+	SerialNumber   string
+	Status         common.Status
+}
+
+// GetPhysicalDrive returns a PhysicalDrive pointer given a client and a uri to query
+func GetPhysicalDrive(c common.Client, uri string) (*PhysicalDrive, error) {
+	var drive PhysicalDrive
+	err := getObject(c, uri, &drive)
+	if err != nil {
+		return nil, err
+	}
+	return &drive, nil
+}
+
+func listPhysicalDrives(c common.Client, uri string) ([]*PhysicalDrive, error) {
+	links, err := listReferences(c, uri)
+	if err != nil {
+		return nil, err
+	}
+	var result []*PhysicalDrive
+	for _, link := range links {
+		drive, err := GetPhysicalDrive(c, link)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, drive)
+	}
+	return result, nil
+}
+
+// ConfigAction is an action on a logical drive of a SmartStorageConfig
+type ConfigAction struct {
+	Action string
+}
+
+// ConfigLogicalDrive is a logical drive of a SmartStorageConfig
+type ConfigLogicalDrive struct {
+	Actions            []ConfigAction `json:",omitempty"`
+	CapacityGiB        int            `json:",omitempty"`
+	DataDrives         []string       `json:",omitempty"`
+	LogicalDriveName   string         `json:",omitempty"`
+	LogicalDriveNumber int            `json:",omitempty"`
+	// Raid is the RAID level of the logical drive, such as "Raid1"
+	Raid                   string `json:",omitempty"`
+	StripSizeBytes         int    `json:",omitempty"`
+	VolumeUniqueIdentifier string `json:",omitempty"`
+}
+
+// SmartStorageConfig is the configuration of a Smart Array controller. Its changes are put into
+// its pending settings, and applied at the next reset of the system.
+type SmartStorageConfig struct {
+	Entity
+	DataGuard     string
+	LogicalDrives []ConfigLogicalDrive
+	// Location is the location of the controller, such as "Slot 0"
+	Location       string
+	LocationFormat string
+	// SettingsObject is the URI of the pending settings
+	SettingsObject string
+	// SettingsMessages are the messages of the last application of the pending settings
+	SettingsMessages []common.Message
+	client           common.Client
+}
+
+// UnmarshalJSON unmarshals SmartStorageConfig object from the raw JSON
+func (s *SmartStorageConfig) UnmarshalJSON(data []byte) error {
+	type temp SmartStorageConfig
+	var t struct {
+		temp
+		Settings common.Settings `json:"@Redfish.Settings"`
+	}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+
+	*s = SmartStorageConfig(t.temp)
+	s.SettingsObject = string(t.Settings.SettingsObject)
+	s.SettingsMessages = t.Settings.Messages
+	return nil
+}
+
+// GetSmartStorageConfig returns a SmartStorageConfig pointer given a client and a uri to query
+func GetSmartStorageConfig(c common.Client, uri string) (*SmartStorageConfig, error) {
+	var config SmartStorageConfig
+	err := getObject(c, uri, &config)
+	if err != nil {
+		return nil, err
+	}
+	config.client = c
+	return &config, nil
+}
+
+// CreateLogicalDrive puts a new logical drive into the pending settings, keeping the existing ones
+func (s *SmartStorageConfig) CreateLogicalDrive(drive ConfigLogicalDrive) error {
+	drives := make([]ConfigLogicalDrive, 0, len(s.LogicalDrives)+1)
+	for _, existing := range s.LogicalDrives {
+		drives = append(drives, ConfigLogicalDrive{VolumeUniqueIdentifier: existing.VolumeUniqueIdentifier})
+	}
+	drives = append(drives, drive)
+	return s.putSettings(StrictDataGuard, drives)
+}
+
+// DeleteLogicalDrive puts the deletion of a logical drive into the pending settings
+func (s *SmartStorageConfig) DeleteLogicalDrive(volumeUniqueIdentifier string) error {
+	return s.putSettings(PermissiveDataGuard, []ConfigLogicalDrive{{
+		Actions:                []ConfigAction{{Action: LogicalDriveDeleteAction}},
+		VolumeUniqueIdentifier: volumeUniqueIdentifier,
+	}})
+}
+
+func (s *SmartStorageConfig) putSettings(dataGuard string, drives []ConfigLogicalDrive) error {
+	if len(s.SettingsObject) == 0 {
+		return fmt.Errorf("the configuration %s has no pending settings", s.ODataID)
+	}
+	payload := map[string]interface{}{
+		"DataGuard":     dataGuard,
+		"LogicalDrives": drives,
+	}
+	resp, err := s.client.Put(s.SettingsObject, payload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
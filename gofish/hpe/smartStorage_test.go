@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpe
+
+import (
+	"testing"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestHpeSmartStorage(t *testing.T) {
+	client := newFixtureClient()
+	system, err := redfish.GetComputerSystem(client, "/redfish/v1/Systems/1/")
+	if err != nil {
+		t.Fatalf("unable to read the system: %s", err)
+	}
+	hpeSystem, err := ComputerSystem(system)
+	if err != nil {
+		t.Fatalf("error when getting the HPE system: %s", err)
+	}
+
+	t.Run("Test_HPE_array_controllers", func(t *testing.T) {
+		controllers, err := hpeSystem.ArrayControllers()
+		if err != nil {
+			t.Fatalf("ArrayControllers failed: %s", err)
+		}
+		assertInt(t, len(controllers), 1)
+		controller := controllers[0]
+		assertField(t, controller.Location, "Slot 0")
+		assertField(t, controller.Model, "HPE Smart Array P408i-a SR Gen10")
+		assertField(t, controller.FirmwareVersion.Current.VersionString, "5.32")
+		assertInt(t, controller.CacheMemorySizeMiB, 2048)
+
+		physicalDrives, err := controller.PhysicalDrives()
+		if err != nil {
+			t.Fatalf("PhysicalDrives failed: %s", err)
+		}
+		assertInt(t, len(physicalDrives), 3)
+		assertField(t, physicalDrives[2].Location, "1I:1:3")
+		assertField(t, physicalDrives[2].MediaType, "SSD")
+
+		logicalDrives, err := controller.LogicalDrives()
+		if err != nil {
+			t.Fatalf("LogicalDrives failed: %s", err)
+		}
+		assertInt(t, len(logicalDrives), 1)
+		logicalDrive := logicalDrives[0]
+		assertField(t, logicalDrive.LogicalDriveName, "os")
+		assertField(t, logicalDrive.Raid, "1")
+		assertInt(t, logicalDrive.CapacityMiB, 457830)
+		assertField(t, logicalDrive.VolumeUniqueIdentifier, "600508B1001C5A9E3F4D4B2C1A0E9F8D")
+
+		dataDrives, err := logicalDrive.DataDrives()
+		if err != nil {
+			t.Fatalf("DataDrives failed: %s", err)
+		}
+		assertInt(t, len(dataDrives), 2)
+		assertField(t, dataDrives[0].Location, "1I:1:1")
+		assertField(t, dataDrives[1].Location, "1I:1:2")
+	})
+
+	t.Run("Test_HPE_smart_storage_config", func(t *testing.T) {
+		configs, err := hpeSystem.SmartStorageConfigs()
+		if err != nil {
+			t.Fatalf("SmartStorageConfigs failed: %s", err)
+		}
+		assertInt(t, len(configs), 1)
+		config := configs[0]
+		assertField(t, config.Location, "Slot 0")
+		assertField(t, config.SettingsObject, "/redfish/v1/systems/1/smartstorageconfig/settings/")
+		assertInt(t, len(SettingsFailures(config.SettingsMessages)), 0)
+		assertInt(t, len(config.LogicalDrives), 1)
+		assertArray(t, config.LogicalDrives[0].DataDrives, []string{"1I:1:1", "1I:1:2"})
+
+		err = config.CreateLogicalDrive(ConfigLogicalDrive{
+			LogicalDriveName: "data",
+			Raid:             "Raid0",
+			DataDrives:       []string{"1I:1:3"},
+		})
+		if err != nil {
+			t.Fatalf("CreateLogicalDrive failed: %s", err)
+		}
+		assertCall(t, client, "PUT", "/redfish/v1/systems/1/smartstorageconfig/settings/",
+			"map[DataGuard:Strict LogicalDrives:[map[VolumeUniqueIdentifier:600508B1001C5A9E3F4D4B2C1A0E9F8D] "+
+				"map[DataDrives:[1I:1:3] LogicalDriveName:data Raid:Raid0]]]")
+
+		err = config.DeleteLogicalDrive("600508B1001C5A9E3F4D4B2C1A0E9F8D")
+		if err != nil {
+			t.Fatalf("DeleteLogicalDrive failed: %s", err)
+		}
+		assertCall(t, client, "PUT", "/redfish/v1/systems/1/smartstorageconfig/settings/",
+			"map[DataGuard:Permissive LogicalDrives:[map[Actions:[map[Action:LogicalDriveDelete]] "+
+				"VolumeUniqueIdentifier:600508B1001C5A9E3F4D4B2C1A0E9F8D]]]")
+	})
+}
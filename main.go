@@ -47,6 +47,8 @@ func main() {
 		Address: "registry.terraform.io/dell/redfish",
 		Debug:   debug,
 	})
+	// Delete the Redfish sessions opened during the run once Terraform stops the provider
+	provider.CloseSessions()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
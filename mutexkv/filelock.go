@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutexkv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileLockInterval is the wait between two attempts to take a file lock held by another process
+const fileLockInterval = 250 * time.Millisecond
+
+// Holder describes the process holding the exclusive file lock of a key
+type Holder struct {
+	PID       int    `json:"pid"`
+	Workspace string `json:"workspace"`
+}
+
+// HeldError is returned when the file lock of a key is still held by another process
+// once the context is done
+type HeldError struct {
+	Key string
+	// Holder is nil when the holder is unknown, for instance when other processes hold shared locks
+	Holder *Holder
+	err    error
+}
+
+// Error implements error
+func (e *HeldError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s is locked by another process", e.Key)
+	}
+	return fmt.Sprintf("%s is locked by process %d of workspace %s", e.Key, e.Holder.PID, e.Holder.Workspace)
+}
+
+// Unwrap returns the error of the context
+func (e *HeldError) Unwrap() error {
+	return e.err
+}
+
+// fileLocker takes advisory file locks in a directory shared by several processes.
+// The lock file of a key holds the description of its exclusive holder.
+type fileLocker struct {
+	dir    string
+	holder Holder
+}
+
+func newFileLocker(dir, workspace string) (*fileLocker, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("unable to create the lock directory %s: %w", dir, err)
+	}
+	return &fileLocker{
+		dir:    dir,
+		holder: Holder{PID: os.Getpid(), Workspace: workspace},
+	}, nil
+}
+
+// path returns the lock file of the key, with the characters which are not allowed in file names replaced
+func (f *fileLocker) path(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	return filepath.Join(f.dir, name+".lock")
+}
+
+// lock waits for the file lock of the key until it is available or the context is done
+func (f *fileLocker) lock(ctx context.Context, key string, exclusive bool) (*os.File, error) {
+	file, err := os.OpenFile(f.path(key), os.O_RDWR|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the lock file of %s: %w", key, err)
+	}
+
+	ticker := time.NewTicker(fileLockInterval)
+	defer ticker.Stop()
+	for {
+		locked, err := tryLockFile(file, exclusive)
+		if err != nil {
+			file.Close() // #nosec G104
+			return nil, fmt.Errorf("unable to lock the lock file of %s: %w", key, err)
+		}
+		if locked {
+			f.setHolder(file, exclusive)
+			return file, nil
+		}
+		log.Printf("[DEBUG] %s is locked by another process, waiting", key)
+
+		select {
+		case <-ctx.Done():
+			held := &HeldError{Key: key, Holder: readHolder(file), err: ctx.Err()}
+			file.Close() // #nosec G104
+			return nil, held
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseFile releases a file lock taken by fileLocker.lock
+func releaseFile(file *os.File, exclusive bool) {
+	if exclusive {
+		file.Truncate(0) // #nosec G104
+	}
+	unlockFile(file) // #nosec G104
+	file.Close()     // #nosec G104
+}
+
+// setHolder writes the description of the exclusive holder in the lock file. The file of a shared lock
+// is emptied instead: no exclusive holder exists, so its content was left by a process which died.
+func (f *fileLocker) setHolder(file *os.File, exclusive bool) {
+	if err := file.Truncate(0); err != nil || !exclusive {
+		return
+	}
+	content, err := json.Marshal(f.holder)
+	if err != nil {
+		return
+	}
+	file.WriteAt(content, 0) // #nosec G104
+}
+
+// readHolder returns the description of the exclusive holder of the lock file, if any
+func readHolder(file *os.File) *Holder {
+	content, err := io.ReadAll(io.NewSectionReader(file, 0, 1<<16))
+	if err != nil || len(content) == 0 {
+		return nil
+	}
+	var holder Holder
+	if err := json.Unmarshal(content, &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
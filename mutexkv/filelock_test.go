@@ -0,0 +1,84 @@
+//go:build unix
+
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutexkv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// Two stores sharing a lock directory behave like two processes, since flock
+// is held per open file
+func newFileLockedKV(t *testing.T, dir, workspace string) *MutexKV {
+	t.Helper()
+	mutex := NewMutexKV()
+	if err := mutex.SetLockDirectory(dir, workspace); err != nil {
+		t.Fatalf("SetLockDirectory failed: %s", err)
+	}
+	return mutex
+}
+
+func TestMutexKVFileLock(t *testing.T) {
+	dir := t.TempDir()
+	first := newFileLockedKV(t, dir, "first")
+	second := newFileLockedKV(t, dir, "second")
+
+	t.Run("test exclusive lock names its holder", func(t *testing.T) {
+		first.Lock("https://host")
+		err := second.LockContext(timeoutContext(t), "host:443")
+		var held *HeldError
+		if !errors.As(err, &held) {
+			t.Fatalf("expected a HeldError, got %v", err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a timeout, got %v", err)
+		}
+		if held.Holder == nil || held.Holder.PID != os.Getpid() || held.Holder.Workspace != "first" {
+			t.Errorf("unexpected holder %+v", held.Holder)
+		}
+		first.Unlock("host")
+		if err := second.LockContext(timeoutContext(t), "host"); err != nil {
+			t.Fatalf("lock failed after the holder left: %s", err)
+		}
+		second.Unlock("host")
+	})
+
+	t.Run("test shared locks are held across processes", func(t *testing.T) {
+		first.RLock("host")
+		if err := second.RLockContext(timeoutContext(t), "host"); err != nil {
+			t.Fatalf("read lock failed: %s", err)
+		}
+		err := first.LockContext(timeoutContext(t), "other")
+		if err != nil {
+			t.Fatalf("lock of another key failed: %s", err)
+		}
+		first.Unlock("other")
+
+		first.RUnlock("host")
+		err = first.LockContext(timeoutContext(t), "host")
+		var held *HeldError
+		if !errors.As(err, &held) || held.Holder != nil {
+			t.Fatalf("expected a HeldError without holder, got %v", err)
+		}
+		second.RUnlock("host")
+	})
+}
@@ -18,50 +18,335 @@ limitations under the License.
 package mutexkv
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
 	"sync"
 )
 
-// MutexKV is a simple key/value store for arbitrary mutexes. It must be used
+// MutexKV is a simple key/value store for arbitrary read/write locks. It must be used
 // when creating resources, since some of them might restart the servers.
 // Not using MutexKV might lead to inconsistances because some resources
 // might reace each other.
+// Keys are canonicalized, so that https://host, host and host:443 are the same key.
 type MutexKV struct {
-	lock  sync.Mutex
-	store map[string]*sync.Mutex
+	lock      sync.Mutex
+	store     map[string]*rwLock
+	readSlots int
+	// files takes the locks across processes too, when set
+	files *fileLocker
 }
 
-// Lock the mutex for the given key. The caller is responsible for calling
+// Lock takes the exclusive lock for the given key. The caller is responsible for calling
 // Unlock for the same key
 func (m *MutexKV) Lock(key string) {
+	_ = m.LockContext(context.Background(), key)
+}
+
+// LockContext takes the exclusive lock for the given key, unless the context is done first.
+// The caller is responsible for calling Unlock for the same key if no error is returned.
+func (m *MutexKV) LockContext(ctx context.Context, key string) error {
+	key = CanonicalKey(key)
 	log.Printf("[DEBUG] Locking %s", key)
-	m.get(key).Lock()
+	if err := m.acquire(ctx, key, true); err != nil {
+		log.Printf("[DEBUG] Unable to lock %s: %s", key, err.Error())
+		return fmt.Errorf("unable to lock %s: %w", key, err)
+	}
 	log.Printf("[DEBUG] Locked %s", key)
+	return nil
 }
 
-// Unlock the mutex for the given key. Caller must have called Lock for the
+// Unlock the exclusive lock for the given key. Caller must have called Lock for the
 // same key first.
 func (m *MutexKV) Unlock(key string) {
+	key = CanonicalKey(key)
 	log.Printf("[DEBUG] Unlocking %s", key)
-	m.get(key).Unlock()
+	m.release(key, true)
 	log.Printf("[DEBUG] Unlocked %s", key)
 }
 
-// Returns a mutex for the given key, no guarantee of its lock status
-func (m *MutexKV) get(key string) *sync.Mutex {
+// RLock takes a shared lock for the given key. Shared locks are held together, up to the
+// number of read slots of the key, but never with the exclusive lock. The caller is
+// responsible for calling RUnlock for the same key
+func (m *MutexKV) RLock(key string) {
+	_ = m.RLockContext(context.Background(), key)
+}
+
+// RLockContext takes a shared lock for the given key, unless the context is done first.
+// The caller is responsible for calling RUnlock for the same key if no error is returned.
+func (m *MutexKV) RLockContext(ctx context.Context, key string) error {
+	key = CanonicalKey(key)
+	log.Printf("[DEBUG] Read locking %s", key)
+	if err := m.acquire(ctx, key, false); err != nil {
+		log.Printf("[DEBUG] Unable to read lock %s: %s", key, err.Error())
+		return fmt.Errorf("unable to read lock %s: %w", key, err)
+	}
+	log.Printf("[DEBUG] Read locked %s", key)
+	return nil
+}
+
+// RUnlock releases a shared lock for the given key. Caller must have called RLock for the
+// same key first.
+func (m *MutexKV) RUnlock(key string) {
+	key = CanonicalKey(key)
+	log.Printf("[DEBUG] Read unlocking %s", key)
+	m.release(key, false)
+	log.Printf("[DEBUG] Read unlocked %s", key)
+}
+
+// SetReadSlots sets the maximum number of shared locks held together for the given key.
+// 0 means unlimited.
+func (m *MutexKV) SetReadSlots(key string, slots int) {
+	m.get(CanonicalKey(key)).setSlots(slots)
+}
+
+// SetDefaultReadSlots sets the maximum number of shared locks held together for the keys
+// without read slots of their own. 0 means unlimited.
+func (m *MutexKV) SetDefaultReadSlots(slots int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.readSlots = slots
+}
+
+// SetLockDirectory makes the locks also take an advisory file lock in dir, so that they are held
+// across the processes sharing the directory. The workspace describes this process in the
+// HeldError returned to the processes waiting for its locks.
+func (m *MutexKV) SetLockDirectory(dir, workspace string) error {
+	if !fileLockSupported {
+		return fmt.Errorf("unable to use the lock directory %s: file locks are not supported on this platform", dir)
+	}
+	files, err := newFileLocker(dir, workspace)
+	if err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.files = files
+	return nil
+}
+
+// acquire takes the lock of the key in this process, then its file lock if a lock directory is set
+func (m *MutexKV) acquire(ctx context.Context, key string, exclusive bool) error {
+	l := m.get(key)
+	if err := l.acquire(ctx, exclusive); err != nil {
+		return err
+	}
+	files := m.fileLocker()
+	if files == nil {
+		return nil
+	}
+	file, err := files.lock(ctx, key, exclusive)
+	if err != nil {
+		l.release(exclusive)
+		return err
+	}
+	l.addFile(file, exclusive)
+	return nil
+}
+
+// release releases the file lock of the key if any, then its lock in this process
+func (m *MutexKV) release(key string, exclusive bool) {
+	l := m.get(key)
+	if file := l.removeFile(exclusive); file != nil {
+		releaseFile(file, exclusive)
+	}
+	l.release(exclusive)
+}
+
+// Returns the lock for the given key, no guarantee of its lock status
+func (m *MutexKV) get(key string) *rwLock {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	mutex, ok := m.store[key]
 	if !ok {
-		mutex = &sync.Mutex{}
+		mutex = newRWLock(m)
 		m.store[key] = mutex
 	}
 	return mutex
 }
 
+func (m *MutexKV) fileLocker() *fileLocker {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.files
+}
+
+func (m *MutexKV) defaultReadSlots() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.readSlots
+}
+
 // NewMutexKV returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.Mutex),
+		store: make(map[string]*rwLock),
+	}
+}
+
+// CanonicalKey returns the host:port form of an endpoint, so that https://host, host
+// and host:443 give the same key. The port defaults to the one of the scheme, https if missing.
+// Any other key is taken as a host as well, e.g. rw gives rw:443; only the empty keys and
+// the keys which cannot be parsed as an URL are returned unchanged.
+func CanonicalKey(key string) string {
+	endpoint := strings.TrimSpace(key)
+	if len(endpoint) == 0 {
+		return key
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Hostname()) == 0 {
+		return key
+	}
+
+	port := u.Port()
+	if len(port) == 0 {
+		switch strings.ToLower(u.Scheme) {
+		case "http":
+			port = "80"
+		default:
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(strings.ToLower(u.Hostname()), port)
+}
+
+// rwLock is a read/write lock which can be waited for with a context. Writers waiting
+// for the lock hold off new readers, so that they are not starved.
+type rwLock struct {
+	kv             *MutexKV
+	lock           sync.Mutex
+	readers        int
+	writer         bool
+	waitingWriters int
+	// slots is the maximum number of readers, -1 means the default of the store
+	slots int
+	// changed is closed when the lock is released, to wake up the waiters
+	changed chan struct{}
+	// files are the file locks held with the lock, the readers share any of theirs
+	writerFile  *os.File
+	readerFiles []*os.File
+}
+
+func newRWLock(kv *MutexKV) *rwLock {
+	return &rwLock{
+		kv:      kv,
+		slots:   -1,
+		changed: make(chan struct{}),
+	}
+}
+
+func (l *rwLock) setSlots(slots int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.slots = slots
+	l.notify()
+}
+
+// acquire waits for the lock until it is available or the context is done
+func (l *rwLock) acquire(ctx context.Context, exclusive bool) error {
+	slots := l.kv.defaultReadSlots()
+	waiting := false
+	for {
+		l.lock.Lock()
+		if l.available(exclusive, slots) {
+			if exclusive {
+				l.writer = true
+			} else {
+				l.readers++
+			}
+			if waiting {
+				l.waitingWriters--
+			}
+			l.lock.Unlock()
+			return nil
+		}
+		if exclusive && !waiting {
+			waiting = true
+			l.waitingWriters++
+		}
+		changed := l.changed
+		l.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			if waiting {
+				l.lock.Lock()
+				l.waitingWriters--
+				l.notify()
+				l.lock.Unlock()
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// available tells whether the lock can be taken. Caller must hold l.lock.
+func (l *rwLock) available(exclusive bool, defaultSlots int) bool {
+	if exclusive {
+		return !l.writer && l.readers == 0
+	}
+	slots := l.slots
+	if slots < 0 {
+		slots = defaultSlots
+	}
+	return !l.writer && l.waitingWriters == 0 && (slots <= 0 || l.readers < slots)
+}
+
+func (l *rwLock) release(exclusive bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if exclusive {
+		if !l.writer {
+			panic("mutexkv: unlock of unlocked key")
+		}
+		l.writer = false
+	} else {
+		if l.readers == 0 {
+			panic("mutexkv: read unlock of unlocked key")
+		}
+		l.readers--
 	}
+	l.notify()
+}
+
+// addFile records the file lock taken with the lock
+func (l *rwLock) addFile(file *os.File, exclusive bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if exclusive {
+		l.writerFile = file
+	} else {
+		l.readerFiles = append(l.readerFiles, file)
+	}
+}
+
+// removeFile returns the file lock to release with the lock, if any
+func (l *rwLock) removeFile(exclusive bool) *os.File {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if exclusive {
+		file := l.writerFile
+		l.writerFile = nil
+		return file
+	}
+	if len(l.readerFiles) == 0 {
+		return nil
+	}
+	file := l.readerFiles[len(l.readerFiles)-1]
+	l.readerFiles = l.readerFiles[:len(l.readerFiles)-1]
+	return file
+}
+
+// notify wakes up the waiters. Caller must hold l.lock.
+func (l *rwLock) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
 }
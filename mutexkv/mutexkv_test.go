@@ -18,8 +18,12 @@ limitations under the License.
 package mutexkv
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestMutexKV(t *testing.T) {
@@ -66,6 +70,98 @@ func TestMutexKV(t *testing.T) {
 	})
 }
 
+func TestMutexKVReadWrite(t *testing.T) {
+	mutex := NewMutexKV()
+	t.Run("test shared locks are held together", func(t *testing.T) {
+		mutex.RLock("rw")
+		if err := mutex.RLockContext(timeoutContext(t), "rw"); err != nil {
+			t.Fatalf("second read lock failed: %s", err)
+		}
+		mutex.RUnlock("rw")
+		mutex.RUnlock("rw")
+	})
+
+	t.Run("test exclusive lock waits for readers", func(t *testing.T) {
+		mutex.RLock("rw")
+		err := mutex.LockContext(timeoutContext(t), "rw")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+		mutex.RUnlock("rw")
+		if err := mutex.LockContext(timeoutContext(t), "rw"); err != nil {
+			t.Fatalf("lock failed after the reader left: %s", err)
+		}
+	})
+
+	t.Run("test readers wait for the exclusive lock", func(t *testing.T) {
+		err := mutex.RLockContext(timeoutContext(t), "rw")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+		mutex.Unlock("rw")
+		if err := mutex.RLockContext(timeoutContext(t), "rw"); err != nil {
+			t.Fatalf("read lock failed after the writer left: %s", err)
+		}
+		mutex.RUnlock("rw")
+	})
+}
+
+func TestMutexKVReadSlots(t *testing.T) {
+	mutex := NewMutexKV()
+	mutex.SetReadSlots("slots", 2)
+	var running, maxRunning int32
+	wg := &sync.WaitGroup{}
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer wg.Done()
+			mutex.RLock("slots")
+			defer mutex.RUnlock("slots")
+			current := atomic.AddInt32(&running, 1)
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	if maxRunning > 2 {
+		t.Errorf("%d readers held the lock together, want at most 2", maxRunning)
+	}
+}
+
+func TestCanonicalKey(t *testing.T) {
+	same := []string{"https://Host", "host", "host:443", "https://host:443/redfish/v1", " host "}
+	for _, key := range same {
+		if got := CanonicalKey(key); got != "host:443" {
+			t.Errorf("CanonicalKey(%q) = %q, want %q", key, got, "host:443")
+		}
+	}
+	if got := CanonicalKey("http://host"); got != "host:80" {
+		t.Errorf("CanonicalKey(%q) = %q, want %q", "http://host", got, "host:80")
+	}
+	if got := CanonicalKey("[fe80::1]"); got != "[fe80::1]:443" {
+		t.Errorf("CanonicalKey(%q) = %q, want %q", "[fe80::1]", got, "[fe80::1]:443")
+	}
+	if got := CanonicalKey("rw"); got != "rw:443" {
+		t.Errorf("CanonicalKey(%q) = %q, want %q", "rw", got, "rw:443")
+	}
+	if got := CanonicalKey(""); got != "" {
+		t.Errorf("CanonicalKey(%q) = %q, want %q", "", got, "")
+	}
+}
+
+func timeoutContext(t testing.TB) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	t.Cleanup(cancel)
+	return ctx
+}
+
 func assertSum(t testing.TB, got, want int) {
 	t.Helper()
 	if got != want {
@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oem
+
+import (
+	"net/http"
+	"path"
+
+	"terraform-provider-redfish/gofish/dell"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	dellInstallFromRepository = "/Oem/Dell/DellSoftwareInstallationService/Actions/DellSoftwareInstallationService.InstallFromRepository"
+	dellRepositoryUpdates     = "/Oem/Dell/DellSoftwareInstallationService/Actions/DellSoftwareInstallationService.GetRepoBasedUpdateList"
+)
+
+// dellVendor implements the OEM extensions of the iDRAC with the gofish/dell package
+type dellVendor struct {
+	standard
+}
+
+func newDell(service *gofish.Service, name string) Vendor {
+	return &dellVendor{standard{service: service, name: name}}
+}
+
+func (*dellVendor) Supports(feature Feature) bool {
+	switch feature {
+	case ManagerAttributes, ConfigurationProfiles, FirmwareRepository, Certificates:
+		return true
+	default:
+		return false
+	}
+}
+
+func (*dellVendor) ManagerAttributes(manager *redfish.Manager) ([]*AttributeGroup, error) {
+	dellManager, err := dell.Manager(manager)
+	if err != nil {
+		return nil, err
+	}
+	dellAttributes, err := dellManager.DellAttributes()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*AttributeGroup, 0, len(dellAttributes))
+	for _, attributes := range dellAttributes {
+		groups = append(groups, &AttributeGroup{ID: attributes.ID, URI: attributes.ODataID, Attributes: attributes.Attributes})
+	}
+	return groups, nil
+}
+
+func (d *dellVendor) ExportConfiguration(manager *redfish.Manager, payload interface{}) (*http.Response, error) {
+	dellManager, err := dell.Manager(manager)
+	if err != nil {
+		return nil, err
+	}
+	return d.service.GetClient().Post(dellManager.Actions.ExportSystemConfigurationTarget, payload)
+}
+
+func (d *dellVendor) ImportConfiguration(manager *redfish.Manager, payload interface{}) (*http.Response, error) {
+	dellManager, err := dell.Manager(manager)
+	if err != nil {
+		return nil, err
+	}
+	return d.service.GetClient().Post(dellManager.Actions.ImportSystemConfigurationTarget, payload)
+}
+
+func (d *dellVendor) InstallFromRepository(system *redfish.ComputerSystem, payload interface{}) (*http.Response, error) {
+	return d.service.GetClient().Post(system.ODataID+dellInstallFromRepository, payload)
+}
+
+func (d *dellVendor) RepositoryUpdates(system *redfish.ComputerSystem) (*http.Response, error) {
+	return d.service.GetClient().Post(system.ODataID+dellRepositoryUpdates, map[string]interface{}{})
+}
+
+// CreateAccount fills the first free slot of the accounts, as the accounts of the iDRAC are fixed slots
+// which are never created nor deleted
+func (d *dellVendor) CreateAccount(accounts []*redfish.ManagerAccount, account *Account) (string, error) {
+	for _, slot := range accounts {
+		if len(slot.UserName) == 0 && slot.ID != "1" { // ID 1 is reserved
+			uri, id := slot.ODataID, slot.ID
+			if len(account.ID) > 0 {
+				dir, _ := path.Split(slot.ODataID)
+				uri, id = dir+account.ID, account.ID
+			}
+			return id, d.patchAccount(uri, account)
+		} else if slot.ID == "17" {
+			return "", ErrNoAccountSlot
+		}
+	}
+	return "", ErrNoAccountSlot
+}
+
+// DeleteAccount empties the slot of the account
+func (d *dellVendor) DeleteAccount(account *redfish.ManagerAccount) error {
+	// First set Role ID as "None" and Enabled as false
+	resp, err := d.service.GetClient().Patch(account.ODataID, map[string]interface{}{"Enable": "false", "RoleId": "None"})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+
+	// second PATCH call to remove username.
+	resp, err = d.service.GetClient().Patch(account.ODataID, map[string]interface{}{"UserName": ""})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+func (*dellVendor) VolumeOem(diskCachePolicy string) interface{} {
+	return map[string]interface{}{
+		"Dell": map[string]interface{}{
+			"DellVolume": map[string]interface{}{
+				"DiskCachePolicy": diskCachePolicy,
+			},
+		},
+	}
+}
@@ -0,0 +1,418 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oem
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/gofish/hpe"
+
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	// hpeCriticalJobStatus is the status of the iLO tasks which completed with errors
+	hpeCriticalJobStatus = "Critical"
+	// hpeNoneRole is the role of the accounts without privileges, which the iLO does not know
+	hpeNoneRole = "None"
+	gib         = 1024 * 1024 * 1024
+	mib         = 1024 * 1024
+)
+
+// hpeVendor implements the OEM extensions of the iLO with the gofish/hpe package. The iLO applies the
+// pending settings at the next POST of the system without job, and configures its Smart Array
+// controllers with logical drives.
+type hpeVendor struct {
+	standard
+}
+
+func newHpe(service *gofish.Service, name string) Vendor {
+	return &hpeVendor{standard{service: service, name: name}}
+}
+
+// WaitForJob waits for a task of the iLO, which may be completed with a critical status
+func (h *hpeVendor) WaitForJob(ctx context.Context, jobURI string, opts common.JobWaitOptions) (*common.JobResult, error) {
+	result, err := h.standard.WaitForJob(ctx, jobURI, opts)
+	if err == nil && result != nil && result.Status == hpeCriticalJobStatus {
+		return result, &common.JobError{URI: result.URI, State: result.Status, Messages: result.Messages}
+	}
+	return result, err
+}
+
+// Reset resets the system, with the ColdBoot SystemReset of the iLO for a PowerCycle, which the iLO lacks
+func (*hpeVendor) Reset(system *redfish.ComputerSystem, resetType redfish.ResetType) error {
+	if resetType != redfish.PowerCycleResetType {
+		return system.Reset(resetType)
+	}
+	hpeSystem, err := hpe.ComputerSystem(system)
+	if err != nil {
+		return err
+	}
+	return hpeSystem.SystemReset(hpe.ColdBootSystemReset)
+}
+
+// BiosSettingsURI returns the pending settings advertised by the bios, such as /redfish/v1/systems/1/bios/settings/
+func (*hpeVendor) BiosSettingsURI(bios *redfish.Bios) (string, error) {
+	hpeBios, err := hpe.Bios(bios)
+	if err != nil {
+		return "", err
+	}
+	if len(hpeBios.SettingsObject) == 0 {
+		return "", fmt.Errorf("the bios %s has no pending settings", bios.ODataID)
+	}
+	return hpeBios.SettingsObject, nil
+}
+
+// SetBootOrder puts the boot order into the pending boot settings of the bios, which name the boot options
+// by their StructuredBootString
+func (*hpeVendor) SetBootOrder(system *redfish.ComputerSystem, bootOrder []string) (string, error) {
+	bootOptions, err := system.BootOptions()
+	if err != nil {
+		return "", err
+	}
+	bootSettings, err := hpeBootSettings(system)
+	if err != nil {
+		return "", err
+	}
+
+	order := make([]string, 0, len(bootOrder))
+	for _, reference := range bootOrder {
+		structuredBootString, err := structuredBootString(bootSettings, bootOptions, reference)
+		if err != nil {
+			return "", err
+		}
+		order = append(order, structuredBootString)
+	}
+	return "", bootSettings.SetPersistentBootConfigOrder(order)
+}
+
+// BootSettingsURI returns the boot settings of the bios, as SetBootOrder patches their pending settings
+func (*hpeVendor) BootSettingsURI(system *redfish.ComputerSystem) (string, error) {
+	bootSettings, err := hpeBootSettings(system)
+	if err != nil {
+		return "", err
+	}
+	return bootSettings.ODataID, nil
+}
+
+func hpeBootSettings(system *redfish.ComputerSystem) (*hpe.BootSettings, error) {
+	bios, err := system.Bios()
+	if err != nil {
+		return nil, err
+	}
+	hpeBios, err := hpe.Bios(bios)
+	if err != nil {
+		return nil, err
+	}
+	return hpeBios.BootSettings()
+}
+
+func structuredBootString(bootSettings *hpe.BootSettings, bootOptions []*redfish.BootOption, reference string) (string, error) {
+	for _, option := range bootOptions {
+		if option.BootOptionReference != reference {
+			continue
+		}
+		if structured, ok := bootSettings.StructuredBootString(option.UefiDevicePath); ok {
+			return structured, nil
+		}
+		return "", fmt.Errorf("the boot option %s is not a boot source of the bios", reference)
+	}
+	return "", fmt.Errorf("the boot option %s does not exist", reference)
+}
+
+// SettingsVersion returns the ETag and time of the last application of the pending settings, which the iLO
+// updates in the @Redfish.Settings of the resource when it applies them
+func (h *hpeVendor) SettingsVersion(resourceURI string) (string, error) {
+	settings, err := hpe.GetSettings(h.service.GetClient(), resourceURI)
+	if err != nil {
+		return "", err
+	}
+	return settingsVersion(settings), nil
+}
+
+func settingsVersion(settings *redfishcommon.Settings) string {
+	return settings.ETag + "@" + settings.Time
+}
+
+// WaitForSettings waits for the iLO to apply the pending settings during the POST of the system, and returns
+// the failures reported in the settings of the resource. The settings are applied when their version changes,
+// or when the system is seen running then finishing its POST, as the POST may already be over at the first check.
+func (*hpeVendor) WaitForSettings(ctx context.Context, system *redfish.ComputerSystem, resourceURI, version string,
+	opts common.JobWaitOptions,
+) error {
+	interval, timeout := opts.Interval, opts.Timeout
+	if interval <= 0 {
+		interval = int64(common.TimeBetweenAttempts)
+	}
+	if timeout <= 0 {
+		timeout = int64(common.Timeout)
+	}
+
+	var settings *redfishcommon.Settings
+	var state hpe.PostState
+	inPost := false
+	for elapsed := int64(0); ; elapsed += interval {
+		hpeSystem, err := hpe.ComputerSystem(system)
+		if err != nil {
+			return err
+		}
+		state = hpeSystem.OemData.PostState
+		if state != hpe.FinishedPostPostState {
+			inPost = true
+		}
+		settings, err = hpe.GetSettings(system.GetClient(), resourceURI)
+		if err != nil {
+			return err
+		}
+		if settingsVersion(settings) != version || (inPost && state == hpe.FinishedPostPostState) {
+			break
+		}
+		if elapsed >= timeout {
+			return fmt.Errorf("the settings of %s were not applied after %d seconds, the POST state of the system is %s",
+				resourceURI, timeout, state)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(interval) * time.Second):
+		}
+	}
+
+	failures := hpe.SettingsFailures(settings.Messages)
+	if len(failures) == 0 {
+		return nil
+	}
+	details := make([]string, 0, len(failures))
+	for _, m := range failures {
+		details = append(details, settingsMessage(m))
+	}
+	return fmt.Errorf("the settings of %s were not applied: %s", resourceURI, strings.Join(details, "; "))
+}
+
+// settingsMessage returns the text of a message of the settings, which the iLO often reports without Message
+func settingsMessage(m redfishcommon.Message) string {
+	if len(m.Message) > 0 {
+		return m.Message
+	}
+	if len(m.MessageArgs) > 0 {
+		return fmt.Sprintf("%s (%s)", m.MessageID, strings.Join(m.MessageArgs, ", "))
+	}
+	return m.MessageID
+}
+
+// CreateAccount posts the account to the collection of accounts, where the iLO chooses its ID
+func (h *hpeVendor) CreateAccount(_ []*redfish.ManagerAccount, account *Account) (string, error) {
+	return h.postAccount(account, hpeAccountPayload(account))
+}
+
+func (h *hpeVendor) UpdateAccount(account *redfish.ManagerAccount, update *Account) error {
+	resp, err := h.service.GetClient().Patch(account.ODataID, hpeAccountPayload(update))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+// hpeAccountPayload returns the account with its iLO login name. The None role is an account without
+// privileges, as the iLO roles always grant the login privilege.
+func hpeAccountPayload(account *Account) map[string]interface{} {
+	payload := map[string]interface{}{
+		"UserName": account.UserName,
+		"Password": account.Password,
+		"Enabled":  account.Enabled,
+	}
+	hpeOem := map[string]interface{}{"LoginName": account.UserName}
+	if account.RoleID == hpeNoneRole {
+		hpeOem["Privileges"] = hpe.Privileges{}
+	} else {
+		payload["RoleId"] = account.RoleID
+	}
+	payload["Oem"] = map[string]interface{}{"Hpe": hpeOem}
+	return payload
+}
+
+// AccountRole returns the role of the account, which is None when the account has no privileges
+func (*hpeVendor) AccountRole(account *redfish.ManagerAccount) (string, error) {
+	hpeAccount, err := hpe.ManagerAccount(account)
+	if err != nil {
+		return "", err
+	}
+	if hpeAccount.OemData.Privileges.None() {
+		return hpeNoneRole, nil
+	}
+	return account.RoleID, nil
+}
+
+// LogicalDriveController tells whether the controller is a Smart Array controller, at the location controllerID
+func (*hpeVendor) LogicalDriveController(system *redfish.ComputerSystem, controllerID string) (bool, error) {
+	config, err := smartStorageConfig(system, controllerID)
+	return config != nil, err
+}
+
+func smartStorageConfig(system *redfish.ComputerSystem, location string) (*hpe.SmartStorageConfig, error) {
+	hpeSystem, err := hpe.ComputerSystem(system)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := hpeSystem.SmartStorageConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.Location == location {
+			return config, nil
+		}
+	}
+	return nil, nil
+}
+
+func arrayController(system *redfish.ComputerSystem, location string) (*hpe.ArrayController, error) {
+	hpeSystem, err := hpe.ComputerSystem(system)
+	if err != nil {
+		return nil, err
+	}
+	controllers, err := hpeSystem.ArrayControllers()
+	if err != nil {
+		return nil, err
+	}
+	for _, controller := range controllers {
+		if controller.Location == location {
+			return controller, nil
+		}
+	}
+	return nil, fmt.Errorf("the system %s has no Smart Array controller at %s", system.ID, location)
+}
+
+// CreateLogicalDrive puts the logical drive into the pending settings of the Smart Array controller, whose
+// capacities are whole GiB: a capacity which is not would be truncated, and read back different
+func (*hpeVendor) CreateLogicalDrive(system *redfish.ComputerSystem, controllerID string, drive *LogicalDrive) (string, error) {
+	if drive.CapacityBytes%gib != 0 {
+		return "", fmt.Errorf("the capacity of the logical drives of the Smart Array controllers must be a multiple of 1 GiB (%d bytes),"+
+			" got %d bytes", gib, drive.CapacityBytes)
+	}
+	raid, err := smartArrayRaid(drive.RAIDType)
+	if err != nil {
+		return "", err
+	}
+	config, err := smartStorageConfig(system, controllerID)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return "", fmt.Errorf("the system %s has no Smart Array controller at %s", system.ID, controllerID)
+	}
+	err = config.CreateLogicalDrive(hpe.ConfigLogicalDrive{
+		CapacityGiB:      int(drive.CapacityBytes / gib),
+		DataDrives:       drive.Drives,
+		LogicalDriveName: drive.Name,
+		Raid:             raid,
+	})
+	if err != nil {
+		return "", err
+	}
+	return config.ODataID, nil
+}
+
+// smartArrayRaid returns the Smart Array RAID level of a Redfish RAID type, such as Raid1 for RAID1
+func smartArrayRaid(raidType string) (string, error) {
+	level, found := strings.CutPrefix(raidType, "RAID")
+	if !found {
+		return "", fmt.Errorf("the RAID type %s is unsupported by the Smart Array controllers", raidType)
+	}
+	return "Raid" + level, nil
+}
+
+func (*hpeVendor) DeleteLogicalDrive(system *redfish.ComputerSystem, controllerID string, driveURI string) (string, error) {
+	drive, err := hpe.GetLogicalDrive(system.GetClient(), driveURI)
+	if err != nil {
+		return "", err
+	}
+	config, err := smartStorageConfig(system, controllerID)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return "", fmt.Errorf("the system %s has no Smart Array controller at %s", system.ID, controllerID)
+	}
+	if err := config.DeleteLogicalDrive(drive.VolumeUniqueIdentifier); err != nil {
+		return "", err
+	}
+	return config.ODataID, nil
+}
+
+func (*hpeVendor) FindLogicalDrive(system *redfish.ComputerSystem, controllerID string, name string) (*LogicalDrive, error) {
+	controller, err := arrayController(system, controllerID)
+	if err != nil {
+		return nil, err
+	}
+	drives, err := controller.LogicalDrives()
+	if err != nil {
+		return nil, err
+	}
+	for _, drive := range drives {
+		if drive.LogicalDriveName == name {
+			return logicalDrive(drive)
+		}
+	}
+	return nil, nil
+}
+
+func (h *hpeVendor) GetLogicalDrive(driveURI string) (*LogicalDrive, error) {
+	drive, err := hpe.GetLogicalDrive(h.service.GetClient(), driveURI)
+	if err != nil {
+		return nil, err
+	}
+	return logicalDrive(drive)
+}
+
+// logicalDrive converts a Smart Array logical drive, whose physical drives are named by their location. Its
+// capacity is rounded to the GiB, the unit of the capacities of the logical drives created by the provider.
+func logicalDrive(drive *hpe.LogicalDrive) (*LogicalDrive, error) {
+	dataDrives, err := drive.DataDrives()
+	if err != nil {
+		return nil, err
+	}
+	locations := make([]string, 0, len(dataDrives))
+	for _, dataDrive := range dataDrives {
+		locations = append(locations, dataDrive.Location)
+	}
+	raidType := ""
+	if _, err := strconv.Atoi(drive.Raid); err == nil {
+		raidType = "RAID" + drive.Raid
+	}
+	return &LogicalDrive{
+		URI:           drive.ODataID,
+		Name:          drive.LogicalDriveName,
+		RAIDType:      raidType,
+		CapacityBytes: logicalDriveCapacity(drive.CapacityMiB),
+		Drives:        locations,
+	}, nil
+}
+
+// logicalDriveCapacity returns the capacity in bytes of a logical drive of the given MiB, rounded to the GiB
+func logicalDriveCapacity(capacityMiB int) int64 {
+	return (int64(capacityMiB)*mib + gib/2) / gib * gib
+}
@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oem selects the implementation of the OEM extensions of a Redfish service from
+// the vendor of the service, so that the resources do not depend on a given vendor.
+package oem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"terraform-provider-redfish/common"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Feature is an OEM extension of a Redfish service
+type Feature string
+
+const (
+	// ManagerAttributes are the attributes of the manager, such as the iDRAC, system and
+	// lifecycle controller attributes of Dell
+	ManagerAttributes Feature = "manager attributes"
+	// ConfigurationProfiles are the export and the import of the configuration of the server
+	ConfigurationProfiles Feature = "server configuration profiles"
+	// FirmwareRepository is the install of the firmware updates listed in a repository catalog
+	FirmwareRepository Feature = "firmware updates from a repository"
+	// Certificates is the import and the reset of the SSL certificate of the manager
+	Certificates Feature = "SSL certificates of the manager"
+)
+
+// UnsupportedError is returned when the vendor of the service has no implementation of a feature
+type UnsupportedError struct {
+	Vendor  string
+	Feature Feature
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s unsupported on vendor %s", e.Feature, e.Vendor)
+}
+
+// Vendor implements the OEM extensions of a vendor for a service
+type Vendor interface {
+	// Name returns the name of the vendor, as reported by the service
+	Name() string
+	// Supports tells whether the vendor implements a feature
+	Supports(feature Feature) bool
+	// WaitForJob waits for a job of the service to finish
+	WaitForJob(ctx context.Context, jobURI string, opts common.JobWaitOptions) (*common.JobResult, error)
+	// ApplySettings patches the pending settings of a resource, and returns the URI of the job
+	// applying them. The URI is empty when the service applies them without job.
+	ApplySettings(settingsURI string, payload interface{}) (string, error)
+	// ManagerAttributes returns the groups of attributes of a manager
+	ManagerAttributes(manager *redfish.Manager) ([]*AttributeGroup, error)
+	// ExportConfiguration starts the export of the configuration of the server
+	ExportConfiguration(manager *redfish.Manager, payload interface{}) (*http.Response, error)
+	// ImportConfiguration starts the import of a configuration into the server
+	ImportConfiguration(manager *redfish.Manager, payload interface{}) (*http.Response, error)
+	// InstallFromRepository starts the install of the firmware updates of a repository
+	InstallFromRepository(system *redfish.ComputerSystem, payload interface{}) (*http.Response, error)
+	// RepositoryUpdates returns the list of the updates found by the last InstallFromRepository
+	RepositoryUpdates(system *redfish.ComputerSystem) (*http.Response, error)
+	// Reset resets the system with a Redfish reset type
+	Reset(system *redfish.ComputerSystem, resetType redfish.ResetType) error
+	// BiosSettingsURI returns the URI of the pending settings of the BIOS
+	BiosSettingsURI(bios *redfish.Bios) (string, error)
+	// SetBootOrder sets the boot order of the system, as BootOptionReference of its boot options, and
+	// returns the URI of the job applying it. The URI is empty when the service applies it without job.
+	SetBootOrder(system *redfish.ComputerSystem, bootOrder []string) (string, error)
+	// BootSettingsURI returns the URI of the resource whose pending settings hold the boot order set by SetBootOrder
+	BootSettingsURI(system *redfish.ComputerSystem) (string, error)
+	// SettingsVersion returns the version of the last application of the pending settings of a resource, which
+	// is read before the reset of the system and given to WaitForSettings
+	SettingsVersion(resourceURI string) (string, error)
+	// WaitForSettings waits for the pending settings of a resource to be applied by a reset of the system,
+	// when the service applies them without job. The settings are applied once their version is no longer
+	// the one read before the reset.
+	WaitForSettings(ctx context.Context, system *redfish.ComputerSystem, resourceURI, version string,
+		opts common.JobWaitOptions) error
+	// CreateAccount creates a user account, and returns its ID
+	CreateAccount(accounts []*redfish.ManagerAccount, account *Account) (string, error)
+	// UpdateAccount updates a user account
+	UpdateAccount(account *redfish.ManagerAccount, update *Account) error
+	// DeleteAccount deletes a user account
+	DeleteAccount(account *redfish.ManagerAccount) error
+	// AccountRole returns the role of a user account
+	AccountRole(account *redfish.ManagerAccount) (string, error)
+	// VolumeOem returns the Oem of the volumes created or updated with a disk cache policy, or nil
+	VolumeOem(diskCachePolicy string) interface{}
+}
+
+// AttributeGroup is a group of OEM attributes of a manager, such as the iDRAC, system or lifecycle
+// controller attributes of Dell
+type AttributeGroup struct {
+	// ID is the ID of the group, such as iDRAC.Embedded.1
+	ID string
+	// URI is the URI the changes of the attributes are patched to
+	URI        string
+	Attributes map[string]interface{}
+}
+
+// ErrNoAccountSlot is returned when all the account slots of the service are in use
+var ErrNoAccountSlot = errors.New("there is no room for new users")
+
+// Account is a user account to create or update
+type Account struct {
+	// ID is the requested ID of the account, or empty for the first free one
+	ID       string
+	UserName string
+	// Password is empty when it does not change
+	Password string
+	RoleID   string
+	Enabled  bool
+}
+
+// LogicalDrives is implemented by the vendors whose storage controllers are configured with logical
+// drives in pending settings, instead of the Volumes of the Redfish standard
+type LogicalDrives interface {
+	// LogicalDriveController tells whether the controller of the system is configured with logical drives
+	LogicalDriveController(system *redfish.ComputerSystem, controllerID string) (bool, error)
+	// CreateLogicalDrive puts a new logical drive into the pending settings of the controller, and returns
+	// the URI of the resource reporting their application
+	CreateLogicalDrive(system *redfish.ComputerSystem, controllerID string, drive *LogicalDrive) (string, error)
+	// DeleteLogicalDrive puts the deletion of a logical drive into the pending settings of the controller,
+	// and returns the URI of the resource reporting their application
+	DeleteLogicalDrive(system *redfish.ComputerSystem, controllerID string, driveURI string) (string, error)
+	// FindLogicalDrive returns the logical drive of the controller with the given name, or nil
+	FindLogicalDrive(system *redfish.ComputerSystem, controllerID string, name string) (*LogicalDrive, error)
+	// GetLogicalDrive returns the logical drive at the URI
+	GetLogicalDrive(driveURI string) (*LogicalDrive, error)
+}
+
+// LogicalDrive is a logical drive of a storage controller
+type LogicalDrive struct {
+	URI  string
+	Name string
+	// RAIDType is the Redfish RAID type of the drive, such as RAID1
+	RAIDType      string
+	CapacityBytes int64
+	// Drives are the names of the physical drives of the logical drive, or their locations when the vendor
+	// names them by location
+	Drives []string
+}
+
+// vendors are the implementations of the vendors, by lower case name
+var vendors = map[string]func(service *gofish.Service, name string) Vendor{
+	"dell": newDell,
+	"hpe":  newHpe,
+}
+
+// New returns the implementation of the vendor of the service. Services of vendors without
+// implementation get the standard Redfish one, which has none of the OEM features.
+func New(service *gofish.Service) Vendor {
+	name := VendorName(service)
+	if newVendor, ok := vendors[strings.ToLower(name)]; ok {
+		return newVendor(service, name)
+	}
+	return &standard{service: service, name: name}
+}
+
+// VendorName returns the vendor of the service, from ServiceRoot.Vendor or, for the services
+// predating it, from the first vendor of the Oem of the service root
+func VendorName(service *gofish.Service) string {
+	if len(service.Vendor) > 0 {
+		return service.Vendor
+	}
+	var oem map[string]json.RawMessage
+	if err := json.Unmarshal(service.Oem, &oem); err == nil && len(oem) > 0 {
+		names := make([]string, 0, len(oem))
+		for name := range oem {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names[0]
+	}
+	return "unknown"
+}
@@ -0,0 +1,391 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/emulator"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// withRoot changes the service root of the emulator
+func withRoot(change func(root map[string]interface{})) func(s *emulator.Server) {
+	return func(s *emulator.Server) {
+		root, _ := s.Resource("/redfish/v1")
+		change(root)
+		s.SetResource("/redfish/v1", root)
+	}
+}
+
+func TestDellVendor(t *testing.T) {
+	_, service := emulator.Connect(t)
+	vendor := New(service)
+	if vendor.Name() != "Dell" {
+		t.Fatalf("expected the Dell vendor, got %s", vendor.Name())
+	}
+	for _, feature := range []Feature{ManagerAttributes, ConfigurationProfiles, FirmwareRepository, Certificates} {
+		if !vendor.Supports(feature) {
+			t.Errorf("expected %s to be supported", feature)
+		}
+	}
+
+	managers, err := service.Managers()
+	if err != nil || len(managers) == 0 {
+		t.Fatalf("unable to read the managers: %v", err)
+	}
+	attributes, err := vendor.ManagerAttributes(managers[0])
+	if err != nil {
+		t.Fatalf("ManagerAttributes failed: %s", err)
+	}
+	if len(attributes) != 3 {
+		t.Errorf("expected 3 groups of attributes, got %d", len(attributes))
+	}
+	for _, group := range attributes {
+		if len(group.ID) == 0 || !strings.HasPrefix(group.URI, managers[0].ODataID) || len(group.Attributes) == 0 {
+			t.Errorf("unexpected group of attributes %+v", group)
+		}
+	}
+
+	// the accounts of the iDRAC are fixed slots
+	accountService, err := service.AccountService()
+	if err != nil {
+		t.Fatalf("unable to read the account service: %s", err)
+	}
+	accounts, err := accountService.Accounts()
+	if err != nil {
+		t.Fatalf("unable to read the accounts: %s", err)
+	}
+	id, err := vendor.CreateAccount(accounts, &Account{UserName: "test", Password: "secret", RoleID: "ReadOnly", Enabled: true})
+	if err != nil || id != "3" {
+		t.Fatalf("expected the account to fill the slot 3, got %s: %v", id, err)
+	}
+	slots, err := accountService.Accounts()
+	if err != nil || len(slots) != len(accounts) {
+		t.Fatalf("expected the same slots, got %d: %v", len(slots), err)
+	}
+}
+
+func TestStandardAccounts(t *testing.T) {
+	_, service := emulator.Connect(t, withRoot(func(root map[string]interface{}) {
+		root["Vendor"] = "Contoso"
+	}), (*emulator.Server).UseAccountCollection)
+	vendor := New(service)
+	accountService, err := service.AccountService()
+	if err != nil {
+		t.Fatalf("unable to read the account service: %s", err)
+	}
+	accounts, err := accountService.Accounts()
+	if err != nil {
+		t.Fatalf("unable to read the accounts: %s", err)
+	}
+
+	// the account is added to the collection, instead of filling a slot
+	id, err := vendor.CreateAccount(accounts, &Account{UserName: "test", Password: "secret", RoleID: "ReadOnly", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateAccount failed: %s", err)
+	}
+	created, err := accountService.Accounts()
+	if err != nil || len(created) != len(accounts)+1 {
+		t.Fatalf("expected one more account, got %d accounts: %v", len(created), err)
+	}
+	var account *redfish.ManagerAccount
+	for _, a := range created {
+		if a.ID == id {
+			account = a
+		}
+	}
+	if account == nil || account.UserName != "test" || account.RoleID != "ReadOnly" {
+		t.Fatalf("unexpected account %s: %+v", id, account)
+	}
+	if _, err := vendor.CreateAccount(accounts, &Account{ID: "4", UserName: "test2"}); err == nil {
+		t.Errorf("expected an error for an account with an ID")
+	}
+
+	if err := vendor.DeleteAccount(account); err != nil {
+		t.Fatalf("DeleteAccount failed: %s", err)
+	}
+	remaining, err := accountService.Accounts()
+	if err != nil || len(remaining) != len(accounts) {
+		t.Errorf("expected the account to be deleted, got %d accounts: %v", len(remaining), err)
+	}
+}
+
+func TestUnsupportedVendor(t *testing.T) {
+	_, service := emulator.Connect(t, withRoot(func(root map[string]interface{}) {
+		root["Vendor"] = "HPE"
+	}))
+	vendor := New(service)
+	if vendor.Name() != "HPE" {
+		t.Fatalf("expected the HPE vendor, got %s", vendor.Name())
+	}
+	if vendor.Supports(ConfigurationProfiles) {
+		t.Errorf("expected the configuration profiles to be unsupported")
+	}
+	managers, err := service.Managers()
+	if err != nil || len(managers) == 0 {
+		t.Fatalf("unable to read the managers: %v", err)
+	}
+	_, err = vendor.ExportConfiguration(managers[0], map[string]interface{}{})
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Feature != ConfigurationProfiles {
+		t.Fatalf("expected an unsupported error, got %v", err)
+	}
+	if err.Error() != "server configuration profiles unsupported on vendor HPE" {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+
+func TestVendorName(t *testing.T) {
+	_, service := emulator.Connect(t, withRoot(func(root map[string]interface{}) {
+		delete(root, "Vendor")
+		root["Oem"] = map[string]interface{}{"Hpe": map[string]interface{}{}}
+	}))
+	if name := VendorName(service); name != "Hpe" {
+		t.Errorf("expected the vendor of the Oem of the service root, got %s", name)
+	}
+	_, service = emulator.Connect(t, withRoot(func(root map[string]interface{}) {
+		delete(root, "Vendor")
+		delete(root, "Oem")
+	}))
+	if name := VendorName(service); name != "unknown" {
+		t.Errorf("expected an unknown vendor, got %s", name)
+	}
+}
+
+// connectHpe starts an emulator of an iLO, whose system has the given POST state
+func connectHpe(t *testing.T, postState string) (*emulator.Server, *gofish.Service) {
+	t.Helper()
+	return emulator.Connect(t, withRoot(func(root map[string]interface{}) {
+		root["Vendor"] = "HPE"
+	}), func(s *emulator.Server) {
+		setHpePostState(s, postState)
+
+		bios, _ := s.Resource(biosURI)
+		bios["@Redfish.Settings"] = map[string]interface{}{
+			"SettingsObject": map[string]interface{}{"@odata.id": biosURI + "/Settings"},
+			"Messages": []interface{}{
+				map[string]interface{}{"MessageId": "Base.1.0.Success"},
+				map[string]interface{}{"MessageId": "iLO.2.14.PropertyValueNotInList", "MessageArgs": []interface{}{"Foo", "ProcHyperthreading"}},
+			},
+		}
+		bios["Oem"] = map[string]interface{}{"Hpe": map[string]interface{}{
+			"Links": map[string]interface{}{"Boot": map[string]interface{}{"@odata.id": biosURI + "/Boot"}},
+		}}
+		s.SetResource(biosURI, bios)
+		s.SetResource(biosURI+"/Boot", map[string]interface{}{
+			"@odata.id": biosURI + "/Boot",
+			"@Redfish.Settings": map[string]interface{}{
+				"SettingsObject": map[string]interface{}{"@odata.id": biosURI + "/Boot/Settings"},
+			},
+			"BootSources": []interface{}{
+				map[string]interface{}{"StructuredBootString": "HD.Emb.1.3", "UEFIDevicePath": "VenHw(3A191845-5F86-4E78-8FCE-C4CFF59F9DAA)"},
+				map[string]interface{}{"StructuredBootString": "Generic.USB.1.1",
+					"UEFIDevicePath": "PciRoot(0x0)/Pci(0x14,0x0)/USB(0xD,0x0)/USB(0x0,0x0)/USB(0x2,0x0)/Unit(0x0)"},
+			},
+			"PersistentBootConfigOrder": []interface{}{"HD.Emb.1.3", "Generic.USB.1.1"},
+		})
+		s.SetResource(biosURI+"/Boot/Settings", map[string]interface{}{
+			"@odata.id":                 biosURI + "/Boot/Settings",
+			"PersistentBootConfigOrder": []interface{}{"HD.Emb.1.3", "Generic.USB.1.1"},
+		})
+	})
+}
+
+const (
+	systemURI = "/redfish/v1/Systems/System.Embedded.1"
+	biosURI   = systemURI + "/Bios"
+)
+
+func setHpePostState(s *emulator.Server, postState string) {
+	system, _ := s.Resource(systemURI)
+	system["Oem"] = map[string]interface{}{"Hpe": map[string]interface{}{"PostState": postState}}
+	s.SetResource(systemURI, system)
+}
+
+func hpeSystem(t *testing.T, service *gofish.Service) *redfish.ComputerSystem {
+	t.Helper()
+	systems, err := service.Systems()
+	if err != nil || len(systems) == 0 {
+		t.Fatalf("unable to read the systems: %v", err)
+	}
+	return systems[0]
+}
+
+func TestHpeVendor(t *testing.T) {
+	_, service := connectHpe(t, "FinishedPost")
+	vendor := New(service)
+	if vendor.Name() != "HPE" {
+		t.Fatalf("expected the HPE vendor, got %s", vendor.Name())
+	}
+	for _, feature := range []Feature{ManagerAttributes, ConfigurationProfiles, FirmwareRepository, Certificates} {
+		if vendor.Supports(feature) {
+			t.Errorf("expected %s to be unsupported", feature)
+		}
+	}
+	if _, ok := vendor.(LogicalDrives); !ok {
+		t.Errorf("expected the HPE vendor to manage logical drives")
+	}
+	if vendor.VolumeOem("Enabled") != nil {
+		t.Errorf("expected no Oem for the volumes")
+	}
+
+	bios, err := hpeSystem(t, service).Bios()
+	if err != nil {
+		t.Fatalf("unable to read the bios: %s", err)
+	}
+	settingsURI, err := vendor.BiosSettingsURI(bios)
+	if err != nil {
+		t.Fatalf("BiosSettingsURI failed: %s", err)
+	}
+	if settingsURI != biosURI+"/Settings" {
+		t.Errorf("expected the settings of the bios, got %s", settingsURI)
+	}
+	bootURI, err := vendor.BootSettingsURI(hpeSystem(t, service))
+	if err != nil {
+		t.Fatalf("BootSettingsURI failed: %s", err)
+	}
+	if bootURI != biosURI+"/Boot" {
+		t.Errorf("expected the boot settings of the bios, got %s", bootURI)
+	}
+}
+
+func TestHpeSetBootOrder(t *testing.T) {
+	s, service := connectHpe(t, "FinishedPost")
+	vendor := New(service)
+	system := hpeSystem(t, service)
+
+	jobURI, err := vendor.SetBootOrder(system, []string{"Boot0005", "Boot0004"})
+	if err != nil {
+		t.Fatalf("SetBootOrder failed: %s", err)
+	}
+	if jobURI != "" {
+		t.Errorf("expected no job, got %s", jobURI)
+	}
+	pending, _ := s.Resource(biosURI + "/Boot/Settings")
+	order := fmt.Sprint(pending["PersistentBootConfigOrder"])
+	if order != "[Generic.USB.1.1 HD.Emb.1.3]" {
+		t.Errorf("unexpected pending boot order %s", order)
+	}
+
+	if _, err := vendor.SetBootOrder(system, []string{"Boot0003"}); err == nil {
+		t.Errorf("expected an error for a boot option which is not a boot source")
+	}
+	if _, err := vendor.SetBootOrder(system, []string{"Boot9999"}); err == nil {
+		t.Errorf("expected an error for an unknown boot option")
+	}
+}
+
+func TestHpeWaitForSettings(t *testing.T) {
+	s, service := connectHpe(t, "InPost")
+	vendor := New(service)
+	system := hpeSystem(t, service)
+	version, err := vendor.SettingsVersion(biosURI)
+	if err != nil {
+		t.Fatalf("SettingsVersion failed: %s", err)
+	}
+
+	time.AfterFunc(1500*time.Millisecond, func() { setHpePostState(s, "FinishedPost") })
+	err = vendor.WaitForSettings(context.Background(), system, biosURI, version, common.JobWaitOptions{Interval: 1, Timeout: 10})
+	if err == nil || !strings.Contains(err.Error(), "iLO.2.14.PropertyValueNotInList (Foo, ProcHyperthreading)") {
+		t.Errorf("expected the failure of the settings, got %v", err)
+	}
+
+	// the POST is never seen, and the settings are not applied
+	err = vendor.WaitForSettings(context.Background(), system, biosURI, version, common.JobWaitOptions{Interval: 1, Timeout: 1})
+	if err == nil || !strings.Contains(err.Error(), "were not applied after 1 seconds") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	// the POST is over before the first check, but the settings were applied
+	bios, _ := s.Resource(biosURI)
+	settings := bios["@Redfish.Settings"].(map[string]interface{})
+	settings["ETag"] = "W/\"2\""
+	settings["Time"] = "2026-10-16T10:00:00Z"
+	settings["Messages"] = []interface{}{map[string]interface{}{"MessageId": "Base.1.0.Success"}}
+	s.SetResource(biosURI, bios)
+	err = vendor.WaitForSettings(context.Background(), system, biosURI, version, common.JobWaitOptions{Interval: 1, Timeout: 1})
+	if err != nil {
+		t.Errorf("expected the settings to be applied, got %s", err)
+	}
+}
+
+func TestHpeLogicalDriveCapacity(t *testing.T) {
+	_, service := connectHpe(t, "FinishedPost")
+	drives := New(service).(LogicalDrives)
+	_, err := drives.CreateLogicalDrive(hpeSystem(t, service), "0", &LogicalDrive{Name: "data", RAIDType: "RAID1", CapacityBytes: gib + 1})
+	if err == nil || !strings.Contains(err.Error(), "must be a multiple of 1 GiB") {
+		t.Errorf("expected an error for a capacity which is not whole GiB, got %v", err)
+	}
+
+	for capacityMiB, expected := range map[int]int64{0: 0, 1024: gib, 102399: 100 * gib, 102400: 100 * gib, 102913: 101 * gib} {
+		if capacity := logicalDriveCapacity(capacityMiB); capacity != expected {
+			t.Errorf("expected %d bytes for %d MiB, got %d", expected, capacityMiB, capacity)
+		}
+	}
+}
+
+func TestHpeAccountRole(t *testing.T) {
+	s, service := connectHpe(t, "FinishedPost")
+	vendor := New(service)
+	accountService, err := service.AccountService()
+	if err != nil {
+		t.Fatalf("unable to read the account service: %s", err)
+	}
+	accounts, err := accountService.Accounts()
+	if err != nil || len(accounts) < 2 {
+		t.Fatalf("unable to read the accounts: %v", err)
+	}
+	uri := accounts[1].ODataID
+	account, _ := s.Resource(uri)
+	account["RoleId"] = "ReadOnly"
+	account["Oem"] = map[string]interface{}{"Hpe": map[string]interface{}{
+		"Privileges": map[string]interface{}{"LoginPriv": false, "RemoteConsolePriv": false},
+	}}
+	s.SetResource(uri, account)
+
+	// the accounts are read concurrently, in any order
+	accounts, _ = accountService.Accounts()
+	var changed *redfish.ManagerAccount
+	for _, account := range accounts {
+		if account.ODataID == uri {
+			changed = account
+		}
+	}
+	if changed == nil {
+		t.Fatalf("the account %s was not found", uri)
+	}
+	role, err := vendor.AccountRole(changed)
+	if err != nil {
+		t.Fatalf("AccountRole failed: %s", err)
+	}
+	if role != "None" {
+		t.Errorf("expected the None role of an account without privileges, got %s", role)
+	}
+	if _, err := vendor.CreateAccount(accounts, &Account{ID: "4", UserName: "test"}); err == nil {
+		t.Errorf("expected an error for an account with an ID")
+	}
+}
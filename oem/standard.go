@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+
+	"terraform-provider-redfish/common"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// standard implements the features of the Redfish standard, and none of the OEM ones
+type standard struct {
+	service *gofish.Service
+	name    string
+}
+
+func (s *standard) Name() string {
+	return s.name
+}
+
+func (*standard) Supports(_ Feature) bool {
+	return false
+}
+
+func (s *standard) unsupported(feature Feature) error {
+	return &UnsupportedError{Vendor: s.name, Feature: feature}
+}
+
+func (s *standard) WaitForJob(ctx context.Context, jobURI string, opts common.JobWaitOptions) (*common.JobResult, error) {
+	return common.WaitForJob(ctx, s.service, jobURI, opts)
+}
+
+func (s *standard) ApplySettings(settingsURI string, payload interface{}) (string, error) {
+	resp, err := s.service.GetClient().Patch(settingsURI, payload)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close() // #nosec G104
+	if location, err := resp.Location(); err == nil {
+		return location.EscapedPath(), nil
+	}
+	return "", nil
+}
+
+func (s *standard) ManagerAttributes(_ *redfish.Manager) ([]*AttributeGroup, error) {
+	return nil, s.unsupported(ManagerAttributes)
+}
+
+func (s *standard) ExportConfiguration(_ *redfish.Manager, _ interface{}) (*http.Response, error) {
+	return nil, s.unsupported(ConfigurationProfiles)
+}
+
+func (s *standard) ImportConfiguration(_ *redfish.Manager, _ interface{}) (*http.Response, error) {
+	return nil, s.unsupported(ConfigurationProfiles)
+}
+
+func (s *standard) InstallFromRepository(_ *redfish.ComputerSystem, _ interface{}) (*http.Response, error) {
+	return nil, s.unsupported(FirmwareRepository)
+}
+
+func (s *standard) RepositoryUpdates(_ *redfish.ComputerSystem) (*http.Response, error) {
+	return nil, s.unsupported(FirmwareRepository)
+}
+
+func (*standard) Reset(system *redfish.ComputerSystem, resetType redfish.ResetType) error {
+	return system.Reset(resetType)
+}
+
+func (*standard) BiosSettingsURI(bios *redfish.Bios) (string, error) {
+	return path.Join(bios.ODataID, "Settings"), nil
+}
+
+func (s *standard) SetBootOrder(system *redfish.ComputerSystem, bootOrder []string) (string, error) {
+	payload := map[string]interface{}{
+		"Boot": map[string]interface{}{
+			"BootOrder": bootOrder,
+		},
+	}
+	resp, err := s.service.GetClient().Patch(system.ODataID, payload)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close() // #nosec G104
+	return resp.Header.Get("Location"), nil
+}
+
+// BootSettingsURI returns the system, whose Boot holds the boot order
+func (*standard) BootSettingsURI(system *redfish.ComputerSystem) (string, error) {
+	return system.ODataID, nil
+}
+
+// SettingsVersion has no version to return, as the standard services apply the pending settings with a job
+func (*standard) SettingsVersion(_ string) (string, error) {
+	return "", nil
+}
+
+// WaitForSettings has nothing to wait for, as the standard services apply the pending settings with a job
+func (*standard) WaitForSettings(_ context.Context, _ *redfish.ComputerSystem, _, _ string, _ common.JobWaitOptions) error {
+	return nil
+}
+
+// CreateAccount posts the account to the collection of accounts, where the service chooses its ID
+func (s *standard) CreateAccount(_ []*redfish.ManagerAccount, account *Account) (string, error) {
+	return s.postAccount(account, accountPayload(account))
+}
+
+// postAccount posts the payload of the account to the collection of accounts, and returns the ID
+// the service gave to the account
+func (s *standard) postAccount(account *Account, payload interface{}) (string, error) {
+	if len(account.ID) > 0 {
+		return "", fmt.Errorf("the %s services choose the ID of the new accounts, which cannot be requested", s.name)
+	}
+	accountService, err := s.service.AccountService()
+	if err != nil {
+		return "", err
+	}
+	collection := path.Join(accountService.ODataID, "Accounts")
+	resp, err := s.service.GetClient().Post(collection, payload)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close() // #nosec G104
+
+	accounts, err := accountService.Accounts()
+	if err != nil {
+		return "", err
+	}
+	for _, created := range accounts {
+		if created.UserName == account.UserName {
+			return created.ID, nil
+		}
+	}
+	return "", fmt.Errorf("the account %s was not found after its creation", account.UserName)
+}
+
+func (s *standard) UpdateAccount(account *redfish.ManagerAccount, update *Account) error {
+	return s.patchAccount(account.ODataID, update)
+}
+
+func (s *standard) patchAccount(uri string, account *Account) error {
+	resp, err := s.service.GetClient().Patch(uri, accountPayload(account))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+// accountPayload returns the account as a ManagerAccount of the Redfish standard
+func accountPayload(account *Account) map[string]interface{} {
+	return map[string]interface{}{
+		"UserName": account.UserName,
+		"Password": account.Password,
+		"Enabled":  account.Enabled,
+		"RoleId":   account.RoleID,
+	}
+}
+
+// DeleteAccount deletes the account from the collection of accounts
+func (s *standard) DeleteAccount(account *redfish.ManagerAccount) error {
+	resp, err := s.service.GetClient().Delete(account.ODataID)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+	return nil
+}
+
+func (*standard) AccountRole(account *redfish.ManagerAccount) (string, error) {
+	return account.RoleID, nil
+}
+
+func (*standard) VolumeOem(_ string) interface{} {
+	return nil
+}
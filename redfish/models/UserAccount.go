@@ -27,6 +27,7 @@ type UserAccount struct {
 	Enabled       types.Bool      `tfsdk:"enabled"`
 	Password      types.String    `tfsdk:"password"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
 	RoleID        types.String    `tfsdk:"role_id"`
 	UserID        types.String    `tfsdk:"user_id"`
 	Username      types.String    `tfsdk:"username"`
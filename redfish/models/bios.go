@@ -26,6 +26,8 @@ type BiosDatasource struct {
 	ID            types.String    `tfsdk:"id"`
 	OdataID       types.String    `tfsdk:"odata_id"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
+	SystemID      types.String    `tfsdk:"system_id"`
 	Attributes    types.Map       `tfsdk:"attributes"`
 }
 
@@ -34,6 +36,8 @@ type Bios struct {
 	ID                types.String    `tfsdk:"id"`
 	Attributes        types.Map       `tfsdk:"attributes"`
 	RedfishServer     []RedfishServer `tfsdk:"redfish_server"`
+	Server            types.String    `tfsdk:"server"`
+	SystemID          types.String    `tfsdk:"system_id"`
 	SettingsApplyTime types.String    `tfsdk:"settings_apply_time"`
 	ResetType         types.String    `tfsdk:"reset_type"`
 	ResetTimeout      types.Int64     `tfsdk:"reset_timeout"`
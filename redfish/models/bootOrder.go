@@ -30,6 +30,8 @@ type BootOrder struct {
 	JobTimeout    types.Int64     `tfsdk:"boot_order_job_timeout"`
 	BootOrder     types.List      `tfsdk:"boot_order"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
+	SystemID      types.String    `tfsdk:"system_id"`
 }
 
 // BootOptions is strut for configuring boot options
@@ -49,4 +51,6 @@ type BootSourceOverride struct {
 	JobTimeout                   types.Int64     `tfsdk:"boot_source_job_timeout"`
 	UefiTargetBootSourceOverride types.String    `tfsdk:"uefi_target_boot_source_override"`
 	RedfishServer                []RedfishServer `tfsdk:"redfish_server"`
+	Server                       types.String    `tfsdk:"server"`
+	SystemID                     types.String    `tfsdk:"system_id"`
 }
@@ -32,6 +32,8 @@ type SSLCertificate struct {
 type RedfishSSLCertificate struct {
 	ID                 types.String    `tfsdk:"id"`
 	RedfishServer      []RedfishServer `tfsdk:"redfish_server"`
+	Server             types.String    `tfsdk:"server"`
+	ManagerID          types.String    `tfsdk:"manager_id"`
 	CertificateType    types.String    `tfsdk:"certificate_type"`
 	Passphrase         types.String    `tfsdk:"passphrase"`
 	SSLCertificateFile types.String    `tfsdk:"ssl_certificate_content"`
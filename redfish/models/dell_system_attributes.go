@@ -25,5 +25,7 @@ import (
 type DellSystemAttributes struct {
 	ID            types.String    `tfsdk:"id"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
+	ManagerID     types.String    `tfsdk:"manager_id"`
 	Attributes    types.Map       `tfsdk:"attributes"`
 }
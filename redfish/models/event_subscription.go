@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// EventSubscription is struct to create schema for the redfish_event_subscription resource
+type EventSubscription struct {
+	ID               types.String    `tfsdk:"id"`
+	OdataID          types.String    `tfsdk:"odata_id"`
+	RedfishServer    []RedfishServer `tfsdk:"redfish_server"`
+	Server           types.String    `tfsdk:"server"`
+	Destination      types.String    `tfsdk:"destination"`
+	Protocol         types.String    `tfsdk:"protocol"`
+	SubscriptionType types.String    `tfsdk:"subscription_type"`
+	EventFormatType  types.String    `tfsdk:"event_format_type"`
+	RegistryPrefixes types.List      `tfsdk:"registry_prefixes"`
+	MessageIDs       types.List      `tfsdk:"message_ids"`
+	ResourceTypes    types.List      `tfsdk:"resource_types"`
+	Context          types.String    `tfsdk:"context"`
+	HTTPHeaders      types.Map       `tfsdk:"http_headers"`
+	ListenerAddress  types.String    `tfsdk:"listener_address"`
+}
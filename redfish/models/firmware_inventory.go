@@ -26,6 +26,7 @@ type FirmwareInventory struct {
 	ID            types.String    `tfsdk:"id"`
 	OdataID       types.String    `tfsdk:"odata_id"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
 	Inventory     []Inventory     `tfsdk:"inventory"`
 }
 
@@ -0,0 +1,34 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// GenericDataSource is struct for the redfish_generic data-source
+type GenericDataSource struct {
+	ID            types.String    `tfsdk:"id"`
+	OdataID       types.String    `tfsdk:"odata_id"`
+	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
+	ExpandMembers types.Bool      `tfsdk:"expand_members"`
+	Select        types.List      `tfsdk:"select"`
+	Body          types.String    `tfsdk:"body"`
+	Flattened     types.Map       `tfsdk:"flattened"`
+}
@@ -27,6 +27,8 @@ import (
 type IdracFirmwareUpdate struct {
 	Id                       types.String    `tfsdk:"id"`
 	RedfishServer            []RedfishServer `tfsdk:"redfish_server"`
+	Server                   types.String    `tfsdk:"server"`
+	SystemID                 types.String    `tfsdk:"system_id"`
 	ShareType                types.String    `tfsdk:"share_type"`
 	IPAddress                types.String    `tfsdk:"ip_address"`
 	ShareName                types.String    `tfsdk:"share_name"`
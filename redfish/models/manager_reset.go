@@ -26,4 +26,5 @@ type RedfishManagerReset struct {
 	Id            types.String    `tfsdk:"id"`
 	ResetType     types.String    `tfsdk:"reset_type"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
 }
@@ -25,6 +25,8 @@ import (
 type Power struct {
 	PowerId            types.String    `tfsdk:"id"`
 	RedfishServer      []RedfishServer `tfsdk:"redfish_server"`
+	Server             types.String    `tfsdk:"server"`
+	SystemID           types.String    `tfsdk:"system_id"`
 	DesiredPowerAction types.String    `tfsdk:"desired_power_action"`
 	MaximumWaitTime    types.Int64     `tfsdk:"maximum_wait_time"`
 	CheckInterval      types.Int64     `tfsdk:"check_interval"`
@@ -23,8 +23,45 @@ import (
 
 // ProviderConfig can be used to store data from the Terraform configuration.
 type ProviderConfig struct {
-	Username types.String `tfsdk:"user"`
-	Password types.String `tfsdk:"password"`
+	Username       types.String `tfsdk:"user"`
+	Password       types.String `tfsdk:"password"`
+	AuthMethod     types.String `tfsdk:"auth_method"`
+	RedfishServers types.Map    `tfsdk:"redfish_servers"`
+	InventoryFile  types.String `tfsdk:"inventory_file"`
+
+	RegistryCacheDirectory types.String `tfsdk:"registry_cache_directory"`
+
+	CACertificate          types.String `tfsdk:"ca_certificate"`
+	TLSServerName          types.String `tfsdk:"tls_server_name"`
+	CertificateFingerprint types.String `tfsdk:"certificate_fingerprint"`
+	ClientCertificate      types.String `tfsdk:"client_certificate"`
+	ClientKey              types.String `tfsdk:"client_key"`
+
+	Retry    []RetryConfig    `tfsdk:"retry"`
+	Locking  []LockingConfig  `tfsdk:"locking"`
+	Cassette []CassetteConfig `tfsdk:"cassette"`
+}
+
+// CassetteConfig to configure the recording of the Redfish traffic to a cassette, or its replay.
+type CassetteConfig struct {
+	Path types.String `tfsdk:"path"`
+	Mode types.String `tfsdk:"mode"`
+}
+
+// LockingConfig to configure how resources and data sources wait for each other on a server.
+type LockingConfig struct {
+	Timeout          types.Int64  `tfsdk:"timeout"`
+	MaxParallelReads types.Int64  `tfsdk:"max_parallel_reads"`
+	Directory        types.String `tfsdk:"directory"`
+}
+
+// RetryConfig to configure the retry of requests the server BMC was temporarily unable to process.
+type RetryConfig struct {
+	MaxAttempts types.Int64 `tfsdk:"max_attempts"`
+	MinBackoff  types.Int64 `tfsdk:"min_backoff"`
+	MaxBackoff  types.Int64 `tfsdk:"max_backoff"`
+	StatusCodes []int64     `tfsdk:"retryable_status_codes"`
+	MessageIDs  []string    `tfsdk:"retryable_message_ids"`
 }
 
 // RedfishServer to configure server config for resource/datasource.
@@ -33,4 +70,11 @@ type RedfishServer struct {
 	Password    types.String `tfsdk:"password"`
 	Endpoint    types.String `tfsdk:"endpoint"`
 	SslInsecure types.Bool   `tfsdk:"ssl_insecure"`
+	AuthMethod  types.String `tfsdk:"auth_method"`
+
+	CACertificate          types.String `tfsdk:"ca_certificate"`
+	TLSServerName          types.String `tfsdk:"tls_server_name"`
+	CertificateFingerprint types.String `tfsdk:"certificate_fingerprint"`
+	ClientCertificate      types.String `tfsdk:"client_certificate"`
+	ClientKey              types.String `tfsdk:"client_key"`
 }
@@ -0,0 +1,36 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// RedfishResource is struct to create schema for the generic redfish_resource resource
+type RedfishResource struct {
+	ID                types.String    `tfsdk:"id"`
+	OdataID           types.String    `tfsdk:"odata_id"`
+	Body              types.String    `tfsdk:"body"`
+	RedfishServer     []RedfishServer `tfsdk:"redfish_server"`
+	Server            types.String    `tfsdk:"server"`
+	SystemID          types.String    `tfsdk:"system_id"`
+	SettingsApplyTime types.String    `tfsdk:"settings_apply_time"`
+	ResetType         types.String    `tfsdk:"reset_type"`
+	ResetTimeout      types.Int64     `tfsdk:"reset_timeout"`
+	JobTimeout        types.Int64     `tfsdk:"job_timeout"`
+}
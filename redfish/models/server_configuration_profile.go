@@ -60,6 +60,8 @@ type ShareParameters struct {
 type RedfishScpImport struct {
 	ID             types.String    `tfsdk:"id"`
 	RedfishServer  []RedfishServer `tfsdk:"redfish_server"`
+	Server         types.String    `tfsdk:"server"`
+	ManagerID      types.String    `tfsdk:"manager_id"`
 	HostPowerState types.String    `tfsdk:"host_power_state"`
 	ImportBuffer   types.String    `tfsdk:"import_buffer"`
 	ShutdownType   types.String    `tfsdk:"shutdown_type"`
@@ -72,6 +74,8 @@ type RedfishScpImport struct {
 type TFRedfishScpExport struct {
 	ID              types.String    `tfsdk:"id"`
 	RedfishServer   []RedfishServer `tfsdk:"redfish_server"`
+	Server          types.String    `tfsdk:"server"`
+	ManagerID       types.String    `tfsdk:"manager_id"`
 	FileContent     types.String    `tfsdk:"file_content"`
 	ExportFormat    types.String    `tfsdk:"export_format"`
 	ExportUse       types.String    `tfsdk:"export_use"`
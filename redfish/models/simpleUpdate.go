@@ -25,6 +25,8 @@ import (
 type SimpleUpdateRes struct {
 	Id            types.String    `tfsdk:"id"`
 	RedfishServer []RedfishServer `tfsdk:"redfish_server"`
+	Server        types.String    `tfsdk:"server"`
+	SystemID      types.String    `tfsdk:"system_id"`
 	Protocol      types.String    `tfsdk:"transfer_protocol"`
 	Image         types.String    `tfsdk:"target_firmware_image"`
 	ResetType     types.String    `tfsdk:"reset_type"`
@@ -25,6 +25,8 @@ import (
 type StorageDatasource struct {
 	ID              types.String    `tfsdk:"id"`
 	RedfishServer   []RedfishServer `tfsdk:"redfish_server"`
+	Server          types.String    `tfsdk:"server"`
+	SystemID        types.String    `tfsdk:"system_id"`
 	Storages        []Storage       `tfsdk:"storage"`
 	ControllerIDs   types.List      `tfsdk:"controller_ids"`
 	ControllerNames types.List      `tfsdk:"controller_names"`
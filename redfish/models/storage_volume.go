@@ -27,6 +27,8 @@ type RedfishStorageVolume struct {
 	Drives              types.List      `tfsdk:"drives"`
 	ID                  types.String    `tfsdk:"id"`
 	RedfishServer       []RedfishServer `tfsdk:"redfish_server"`
+	Server              types.String    `tfsdk:"server"`
+	SystemID            types.String    `tfsdk:"system_id"`
 	OptimumIoSizeBytes  types.Int64     `tfsdk:"optimum_io_size_bytes"`
 	ReadCachePolicy     types.String    `tfsdk:"read_cache_policy"`
 	ResetTimeout        types.Int64     `tfsdk:"reset_timeout"`
@@ -24,6 +24,7 @@ import (
 // SystemBootDataSource struct for datasource
 type SystemBootDataSource struct {
 	RedfishServer                []RedfishServer `tfsdk:"redfish_server"`
+	Server                       types.String    `tfsdk:"server"`
 	ID                           types.String    `tfsdk:"id"`
 	ResourceID                   types.String    `tfsdk:"resource_id"`
 	BootOrder                    types.List      `tfsdk:"boot_order"`
@@ -25,6 +25,8 @@ import (
 type VirtualMedia struct {
 	VirtualMediaID       types.String    `tfsdk:"id"`
 	RedfishServer        []RedfishServer `tfsdk:"redfish_server"`
+	Server               types.String    `tfsdk:"server"`
+	SystemID             types.String    `tfsdk:"system_id"`
 	Image                types.String    `tfsdk:"image"`
 	Inserted             types.Bool      `tfsdk:"inserted"`
 	TransferMethod       types.String    `tfsdk:"transfer_method"`
@@ -36,6 +38,8 @@ type VirtualMedia struct {
 type VirtualMediaDataSource struct {
 	ID               types.String       `tfsdk:"id"`
 	RedfishServer    []RedfishServer    `tfsdk:"redfish_server"`
+	Server           types.String       `tfsdk:"server"`
+	ManagerID        types.String       `tfsdk:"manager_id"`
 	VirtualMediaData []VirtualMediaData `tfsdk:"virtual_media"`
 }
 
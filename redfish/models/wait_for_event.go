@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// WaitForEvent is struct to create schema for the redfish_wait_for_event resource
+type WaitForEvent struct {
+	ID                  types.String    `tfsdk:"id"`
+	RedfishServer       []RedfishServer `tfsdk:"redfish_server"`
+	Server              types.String    `tfsdk:"server"`
+	MessageIDs          types.List      `tfsdk:"message_ids"`
+	OriginOfCondition   types.String    `tfsdk:"origin_of_condition"`
+	Subscription        types.String    `tfsdk:"subscription"`
+	ListenerAddress     types.String    `tfsdk:"listener_address"`
+	Destination         types.String    `tfsdk:"destination"`
+	ListenerCertificate types.String    `tfsdk:"listener_certificate"`
+	ListenerKey         types.String    `tfsdk:"listener_key"`
+	Timeout             types.Int64     `tfsdk:"timeout"`
+	Triggers            types.Map       `tfsdk:"triggers"`
+	Event               types.Object    `tfsdk:"event"`
+}
+
+// WaitForEventRecord is the event received by the redfish_wait_for_event resource
+type WaitForEventRecord struct {
+	EventID           types.String `tfsdk:"event_id"`
+	MessageID         types.String `tfsdk:"message_id"`
+	Message           types.String `tfsdk:"message"`
+	MessageArgs       types.List   `tfsdk:"message_args"`
+	Severity          types.String `tfsdk:"severity"`
+	OriginOfCondition types.String `tfsdk:"origin_of_condition"`
+	Timestamp         types.String `tfsdk:"timestamp"`
+}
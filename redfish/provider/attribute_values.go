@@ -0,0 +1,243 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// The values of the attribute maps of the bios, iDRAC, System and Lifecycle Controller resources are objects
+// setting one of their members, of the type of the attribute in its registry
+const (
+	attributeStringMember = "string"
+	attributeIntMember    = "int"
+	attributeBoolMember   = "bool"
+)
+
+// attributesSchemaVersion is the version of the schemas with typed attribute values. The version 0 had
+// maps of strings.
+const attributesSchemaVersion = 1
+
+// attributeValueType is the type of the values of the attribute maps
+var attributeValueType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	attributeStringMember: types.StringType,
+	attributeIntMember:    types.Int64Type,
+	attributeBoolMember:   types.BoolType,
+}}
+
+// attributeValueSchema returns the schema of the values of the attribute maps
+func attributeValueSchema() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			attributeStringMember: schema.StringAttribute{
+				MarkdownDescription: "Value of the Enumeration, String and Password attributes.",
+				Description:         "Value of the Enumeration, String and Password attributes.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(
+						path.MatchRelative().AtParent().AtName(attributeIntMember),
+						path.MatchRelative().AtParent().AtName(attributeBoolMember),
+					),
+				},
+			},
+			attributeIntMember: schema.Int64Attribute{
+				MarkdownDescription: "Value of the Integer attributes.",
+				Description:         "Value of the Integer attributes.",
+				Optional:            true,
+			},
+			attributeBoolMember: schema.BoolAttribute{
+				MarkdownDescription: "Value of the Boolean attributes.",
+				Description:         "Value of the Boolean attributes.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// newAttributeValue returns the value of an attribute read from a service. The JSON numbers are integers,
+// and the null values, such as the ones of the passwords, are empty strings.
+func newAttributeValue(value interface{}) types.Object {
+	members := map[string]attr.Value{
+		attributeStringMember: types.StringNull(),
+		attributeIntMember:    types.Int64Null(),
+		attributeBoolMember:   types.BoolNull(),
+	}
+	switch v := value.(type) {
+	case nil:
+		members[attributeStringMember] = types.StringValue("")
+	case float64:
+		members[attributeIntMember] = types.Int64Value(int64(v))
+	case int64:
+		members[attributeIntMember] = types.Int64Value(v)
+	case int:
+		members[attributeIntMember] = types.Int64Value(int64(v))
+	case bool:
+		members[attributeBoolMember] = types.BoolValue(v)
+	default:
+		members[attributeStringMember] = types.StringValue(fmt.Sprintf("%v", v))
+	}
+	return types.ObjectValueMust(attributeValueType.AttrTypes, members)
+}
+
+// attributeValues returns the values of an attribute map, as strings, int64 or bools. The values not known
+// yet are left out.
+func attributeValues(attributes types.Map) map[string]interface{} {
+	values := make(map[string]interface{}, len(attributes.Elements()))
+	for name, element := range attributes.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			continue
+		}
+		members := object.Attributes()
+		str, _ := members[attributeStringMember].(types.String)
+		integer, _ := members[attributeIntMember].(types.Int64)
+		boolean, _ := members[attributeBoolMember].(types.Bool)
+		switch {
+		case !integer.IsNull() && !integer.IsUnknown():
+			values[name] = integer.ValueInt64()
+		case !boolean.IsNull() && !boolean.IsUnknown():
+			values[name] = boolean.ValueBool()
+		case !str.IsNull() && !str.IsUnknown():
+			values[name] = str.ValueString()
+		}
+	}
+	return values
+}
+
+// attributeMember returns the member setting the values of the attributes of a registry type
+func attributeMember(attributeType redfish.AttributeType) string {
+	switch attributeType {
+	case redfish.IntegerAttributeType:
+		return attributeIntMember
+	case redfish.BooleanAttributeType:
+		return attributeBoolMember
+	default:
+		return attributeStringMember
+	}
+}
+
+// currentAttributeMember returns the member of the type of the current value of an attribute, for the
+// services whose attribute registry cannot be read
+func currentAttributeMember(current interface{}) string {
+	switch current.(type) {
+	case float64:
+		return attributeIntMember
+	case bool:
+		return attributeBoolMember
+	default:
+		return attributeStringMember
+	}
+}
+
+// checkAttributeMember checks that the value of an attribute is set with the given member, so that the
+// string "10" of an Integer attribute is not taken for the number 10
+func checkAttributeMember(name, member string, value interface{}) error {
+	var ok bool
+	switch member {
+	case attributeIntMember:
+		_, ok = value.(int64)
+	case attributeBoolMember:
+		_, ok = value.(bool)
+	default:
+		_, ok = value.(string)
+	}
+	if !ok {
+		return fmt.Errorf("attribute %s must be set with the %s member", name, member)
+	}
+	return nil
+}
+
+// sameAttributeValue tells whether the value of an attribute is its current value, of the same type
+func sameAttributeValue(current, value interface{}) bool {
+	switch c := current.(type) {
+	case float64:
+		number, ok := value.(int64)
+		return ok && float64(number) == c
+	case bool:
+		boolean, ok := value.(bool)
+		return ok && boolean == c
+	case string:
+		str, ok := value.(string)
+		return ok && str == c
+	default:
+		return false
+	}
+}
+
+// attributesV0Schema returns the version 0 of a schema, whose attribute map had string values
+func attributesV0Schema(current schema.Schema) *schema.Schema {
+	prior := current
+	prior.Version = 0
+	prior.Attributes = make(map[string]schema.Attribute, len(current.Attributes))
+	for name, attribute := range current.Attributes {
+		prior.Attributes[name] = attribute
+	}
+	attributes := current.Attributes["attributes"]
+	prior.Attributes["attributes"] = schema.MapAttribute{
+		ElementType: types.StringType,
+		Required:    attributes.IsRequired(),
+		Optional:    attributes.IsOptional(),
+		Computed:    attributes.IsComputed(),
+	}
+	return &prior
+}
+
+// upgradeAttributeValues converts the string values of the version 0 attribute maps. Their type is not known
+// without the registry, so they are kept as strings, and get the type of the attributes at the next refresh.
+func upgradeAttributeValues(attributes types.Map) types.Map {
+	if attributes.IsNull() || attributes.IsUnknown() {
+		return types.MapNull(attributeValueType)
+	}
+	values := make(map[string]attr.Value, len(attributes.Elements()))
+	for name, element := range attributes.Elements() {
+		value, _ := element.(types.String)
+		values[name] = newAttributeValue(value.ValueString())
+	}
+	return types.MapValueMust(attributeValueType, values)
+}
+
+// attributesStateUpgrader returns the upgrader of the version 0 state of a resource with an attribute map.
+// The state is read into a model of the resource, whose attribute map is returned by attributes.
+func attributesStateUpgrader[T any](r resource.Resource, attributes func(*T) *types.Map) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &current)
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: attributesV0Schema(current.Schema),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state T
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				*attributes(&state) = upgradeAttributeValues(*attributes(&state))
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			},
+		},
+	}
+}
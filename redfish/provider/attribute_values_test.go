@@ -0,0 +1,177 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestAttributeValues(t *testing.T) {
+	attributes := types.MapValueMust(attributeValueType, map[string]attr.Value{
+		"NumLock":             newAttributeValue("On"),
+		"AcPwrRcvryUserDelay": newAttributeValue(float64(70)),
+		"PxeDev1EnDis":        newAttributeValue(true),
+		"AssetTag":            newAttributeValue("10"),
+		"Password":            newAttributeValue(nil),
+	})
+	expected := map[string]interface{}{
+		"NumLock":             "On",
+		"AcPwrRcvryUserDelay": int64(70),
+		"PxeDev1EnDis":        true,
+		"AssetTag":            "10",
+		"Password":            "",
+	}
+	values := attributeValues(attributes)
+	if len(values) != len(expected) {
+		t.Fatalf("expected %d values, got %v", len(expected), values)
+	}
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("expected %#v for %s, got %#v", value, name, values[name])
+		}
+	}
+}
+
+func TestCheckAttributeMember(t *testing.T) {
+	tests := []struct {
+		member string
+		value  interface{}
+		valid  bool
+	}{
+		{attributeMember(redfish.IntegerAttributeType), int64(10), true},
+		{attributeMember(redfish.IntegerAttributeType), "10", false},
+		{attributeMember(redfish.BooleanAttributeType), true, true},
+		{attributeMember(redfish.BooleanAttributeType), "true", false},
+		{attributeMember(redfish.EnumerationAttributeType), "On", true},
+		{attributeMember(redfish.StringAttributeType), int64(10), false},
+		{currentAttributeMember(float64(60)), int64(10), true},
+		{currentAttributeMember("60"), int64(10), false},
+	}
+	for _, test := range tests {
+		err := checkAttributeMember("Attribute", test.member, test.value)
+		if test.valid && err != nil {
+			t.Errorf("expected %#v to be set with the %s member, got %v", test.value, test.member, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected an error for %#v set with the %s member", test.value, test.member)
+		}
+	}
+}
+
+func TestSameAttributeValue(t *testing.T) {
+	tests := []struct {
+		current interface{}
+		value   interface{}
+		same    bool
+	}{
+		{float64(10), int64(10), true},
+		{float64(10), "10", false},
+		{"10", int64(10), false},
+		{"On", "On", true},
+		{false, false, true},
+		{false, "false", false},
+		{nil, "", false},
+	}
+	for _, test := range tests {
+		if same := sameAttributeValue(test.current, test.value); same != test.same {
+			t.Errorf("expected %v comparing %#v to %#v, got %v", test.same, test.current, test.value, same)
+		}
+	}
+}
+
+func TestAttributesUpgradeState(t *testing.T) {
+	version0 := map[string]string{"NumLock": "On", "AcPwrRcvryUserDelay": "70"}
+	t.Run("bios", func(t *testing.T) {
+		testAttributesUpgradeState(t, &BiosResource{}, version0, func(state *models.Bios) *types.Map { return &state.Attributes })
+	})
+	t.Run("idrac", func(t *testing.T) {
+		testAttributesUpgradeState(t, &dellIdracAttributesResource{}, version0,
+			func(state *models.DellIdracAttributes) *types.Map { return &state.Attributes })
+	})
+	t.Run("system", func(t *testing.T) {
+		testAttributesUpgradeState(t, &dellSystemAttributesResource{}, version0,
+			func(state *models.DellSystemAttributes) *types.Map { return &state.Attributes })
+	})
+	t.Run("lifecycle controller", func(t *testing.T) {
+		testAttributesUpgradeState(t, &dellLCAttributesResource{}, version0,
+			func(state *models.DellLCAttributes) *types.Map { return &state.Attributes })
+	})
+}
+
+// testAttributesUpgradeState upgrades a version 0 state of a resource, whose attributes are strings. The
+// upgraded values set their string member until the next refresh reads their type.
+func testAttributesUpgradeState[T any](t *testing.T, r resource.ResourceWithUpgradeState, version0 map[string]string,
+	attributes func(*T) *types.Map,
+) {
+	ctx := context.Background()
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok || upgrader.PriorSchema == nil {
+		t.Fatalf("expected an upgrader of the version 0 state")
+	}
+
+	prior := tfsdk.State{
+		Schema: *upgrader.PriorSchema,
+		Raw:    tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(ctx), nil),
+	}
+	var state T
+	values, diags := types.MapValueFrom(ctx, types.StringType, version0)
+	*attributes(&state) = values
+	diags.Append(prior.Set(ctx, &state)...)
+	if diags.HasError() {
+		t.Fatalf("unable to set the version 0 state: %v", diags)
+	}
+
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+	if current.Schema.Version != attributesSchemaVersion {
+		t.Fatalf("expected the version %d of the schema, got %d", attributesSchemaVersion, current.Schema.Version)
+	}
+	resp := resource.UpgradeStateResponse{State: tfsdk.State{
+		Schema: current.Schema,
+		Raw:    tftypes.NewValue(current.Schema.Type().TerraformType(ctx), nil),
+	}}
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{State: &prior}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unable to upgrade the state: %v", resp.Diagnostics)
+	}
+
+	var upgraded T
+	resp.Diagnostics.Append(resp.State.Get(ctx, &upgraded)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unable to read the upgraded state: %v", resp.Diagnostics)
+	}
+	upgradedValues := attributeValues(*attributes(&upgraded))
+	if len(upgradedValues) != len(version0) {
+		t.Fatalf("expected %d attributes, got %v", len(version0), upgradedValues)
+	}
+	for name, value := range version0 {
+		if upgradedValues[name] != value {
+			t.Errorf("expected the string %q for %s, got %#v", value, name, upgradedValues[name])
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"terraform-provider-redfish/cassette"
+	"terraform-provider-redfish/redfish/models"
+)
+
+const (
+	cassetteModeRecord = "record"
+	cassetteModeReplay = "replay"
+)
+
+// This is a global cassette store, so every resource and data source records to the same
+// cassette during a run, or replays it
+var redfishCassettes = newCassetteStore()
+
+// cassettePolicy tells whether the Redfish traffic is recorded to a cassette or replayed from it
+type cassettePolicy struct {
+	// path of the cassette, empty when the traffic is neither recorded nor replayed
+	path string
+	mode string
+}
+
+// newCassettePolicy builds the cassette policy from the provider cassette block, or from the
+// REDFISH_CASSETTE and REDFISH_CASSETTE_MODE environment variables when the block is not set
+func newCassettePolicy(config []models.CassetteConfig) cassettePolicy {
+	policy := cassettePolicy{path: os.Getenv("REDFISH_CASSETTE"), mode: os.Getenv("REDFISH_CASSETTE_MODE")}
+	if len(config) > 0 {
+		policy = cassettePolicy{path: config[0].Path.ValueString(), mode: config[0].Mode.ValueString()}
+	}
+	if len(policy.mode) == 0 {
+		policy.mode = cassetteModeRecord
+	}
+	return policy
+}
+
+// cassetteStore keeps one recorder or replayer per cassette
+type cassetteStore struct {
+	lock      sync.Mutex
+	recorders map[string]*cassette.Recorder
+	replayers map[string]*cassette.Replayer
+}
+
+func newCassetteStore() *cassetteStore {
+	return &cassetteStore{
+		recorders: make(map[string]*cassette.Recorder),
+		replayers: make(map[string]*cassette.Replayer),
+	}
+}
+
+// transport returns the transport recording the requests sent with base, or replaying them
+// without sending them. base is returned as is when there is no cassette.
+func (s *cassetteStore) transport(policy cassettePolicy, base http.RoundTripper) (http.RoundTripper, error) {
+	if len(policy.path) == 0 {
+		return base, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch policy.mode {
+	case cassetteModeRecord:
+		recorder, ok := s.recorders[policy.path]
+		if !ok {
+			var err error
+			if recorder, err = cassette.NewRecorder(policy.path); err != nil {
+				return nil, err
+			}
+			s.recorders[policy.path] = recorder
+		}
+		return recorder.Transport(base), nil
+	case cassetteModeReplay:
+		replayer, ok := s.replayers[policy.path]
+		if !ok {
+			var err error
+			if replayer, err = cassette.NewReplayer(policy.path); err != nil {
+				return nil, fmt.Errorf("unable to load the cassette: %w", err)
+			}
+			s.replayers[policy.path] = replayer
+		}
+		return replayer, nil
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode %s", policy.mode)
+	}
+}
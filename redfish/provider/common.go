@@ -19,18 +19,29 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	datasourceSchema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	resourceSchema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/stmcginnis/gofish"
 	"github.com/stmcginnis/gofish/redfish"
@@ -46,6 +57,17 @@ const (
 	operationDelete
 	operationImport
 	redfishServerMD string = "List of server BMCs and their respective user credentials"
+	// redfishServerNameMD describes the server attribute
+	redfishServerNameMD string = "Name of a server defined in the provider redfish_servers map or inventory_file. " +
+		"Alternative to the redfish_server block."
+	// managerIDMD describes the manager_id attribute
+	managerIDMD string = "ID of the manager in the Managers collection of the server, such as iDRAC.Embedded.1. " +
+		"Defaults to the manager of the computer system, found in its Links.ManagedBy."
+	// systemIDMD describes the system_id attribute
+	systemIDMD string = "ID of the computer system in the Systems collection of the server, such as System.Embedded.1. " +
+		"Required when the server exposes several systems, like the nodes of a multi-node enclosure or a Redfish aggregator."
+	// tlsHandshakeTimeout is the TLS handshake timeout gofish uses by default
+	tlsHandshakeTimeout = 10 * time.Second
 )
 
 // ServerStatusChecker has required fields for Check() method
@@ -76,6 +98,35 @@ func RedfishServerSchema() map[string]resourceSchema.Attribute {
 			Optional:    true,
 			Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
 		},
+		"auth_method": resourceSchema.StringAttribute{
+			Optional: true,
+			Description: "Authentication method used against the server BMC. Applicable values are 'basic' and 'session'. " +
+				"Overrides the provider level auth_method. Default is \"basic\".",
+			Validators: []validator.String{
+				stringvalidator.OneOf(authMethodBasic, authMethodSession),
+			},
+		},
+		"ca_certificate": resourceSchema.StringAttribute{
+			Optional:    true,
+			Description: caCertificateMD + " Overrides the provider level ca_certificate.",
+		},
+		"tls_server_name": resourceSchema.StringAttribute{
+			Optional:    true,
+			Description: tlsServerNameMD + " Overrides the provider level tls_server_name.",
+		},
+		"certificate_fingerprint": resourceSchema.StringAttribute{
+			Optional:    true,
+			Description: certificateFingerprintMD + " Overrides the provider level certificate_fingerprint.",
+		},
+		"client_certificate": resourceSchema.StringAttribute{
+			Optional:    true,
+			Description: clientCertificateMD + " Overrides the provider level client_certificate.",
+		},
+		"client_key": resourceSchema.StringAttribute{
+			Optional:    true,
+			Description: clientKeyMD + " Overrides the provider level client_key.",
+			Sensitive:   true,
+		},
 	}
 }
 
@@ -99,6 +150,117 @@ func RedfishServerDatasourceSchema() map[string]datasourceSchema.Attribute {
 			Optional:    true,
 			Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
 		},
+		"auth_method": datasourceSchema.StringAttribute{
+			Optional: true,
+			Description: "Authentication method used against the server BMC. Applicable values are 'basic' and 'session'. " +
+				"Overrides the provider level auth_method. Default is \"basic\".",
+			Validators: []validator.String{
+				stringvalidator.OneOf(authMethodBasic, authMethodSession),
+			},
+		},
+		"ca_certificate": datasourceSchema.StringAttribute{
+			Optional:    true,
+			Description: caCertificateMD + " Overrides the provider level ca_certificate.",
+		},
+		"tls_server_name": datasourceSchema.StringAttribute{
+			Optional:    true,
+			Description: tlsServerNameMD + " Overrides the provider level tls_server_name.",
+		},
+		"certificate_fingerprint": datasourceSchema.StringAttribute{
+			Optional:    true,
+			Description: certificateFingerprintMD + " Overrides the provider level certificate_fingerprint.",
+		},
+		"client_certificate": datasourceSchema.StringAttribute{
+			Optional:    true,
+			Description: clientCertificateMD + " Overrides the provider level client_certificate.",
+		},
+		"client_key": datasourceSchema.StringAttribute{
+			Optional:    true,
+			Description: clientKeyMD + " Overrides the provider level client_key.",
+			Sensitive:   true,
+		},
+	}
+}
+
+// RedfishServerNameSchema to construct schema of the server attribute of resources
+func RedfishServerNameSchema() resourceSchema.StringAttribute {
+	return resourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: redfishServerNameMD,
+		Description:         redfishServerNameMD,
+	}
+}
+
+// RedfishServerNameDatasourceSchema to construct schema of the server attribute of data sources
+func RedfishServerNameDatasourceSchema() datasourceSchema.StringAttribute {
+	return datasourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: redfishServerNameMD,
+		Description:         redfishServerNameMD,
+	}
+}
+
+// SystemIDSchema to construct schema of the system_id attribute of resources
+func SystemIDSchema() resourceSchema.StringAttribute {
+	return resourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: systemIDMD,
+		Description:         systemIDMD,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// SystemIDDatasourceSchema to construct schema of the system_id attribute of data sources
+func SystemIDDatasourceSchema() datasourceSchema.StringAttribute {
+	return datasourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: systemIDMD,
+		Description:         systemIDMD,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// ManagerIDSchema to construct schema of the manager_id attribute of resources
+func ManagerIDSchema() resourceSchema.StringAttribute {
+	return resourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: managerIDMD,
+		Description:         managerIDMD,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// ManagerIDDatasourceSchema to construct schema of the manager_id attribute of data sources
+func ManagerIDDatasourceSchema() datasourceSchema.StringAttribute {
+	return datasourceSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: managerIDMD,
+		Description:         managerIDMD,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// redfishServerValidators validates the redfish_server block, which is required unless the server attribute
+// names a server of the provider
+func redfishServerValidators() []validator.List {
+	return []validator.List{
+		listvalidator.SizeAtMost(1),
+		listvalidator.AtLeastOneOf(path.MatchRoot("server")),
+		listvalidator.ConflictsWith(path.MatchRoot("server")),
 	}
 }
 
@@ -108,10 +270,7 @@ func RedfishServerResourceBlockMap() map[string]resourceSchema.Block {
 		"redfish_server": resourceSchema.ListNestedBlock{
 			MarkdownDescription: redfishServerMD,
 			Description:         redfishServerMD,
-			Validators: []validator.List{
-				listvalidator.SizeAtMost(1),
-				listvalidator.IsRequired(),
-			},
+			Validators:          redfishServerValidators(),
 			NestedObject: resourceSchema.NestedBlockObject{
 				Attributes: RedfishServerSchema(),
 			},
@@ -125,10 +284,7 @@ func RedfishServerDatasourceBlockMap() map[string]datasourceSchema.Block {
 		"redfish_server": datasourceSchema.ListNestedBlock{
 			MarkdownDescription: redfishServerMD,
 			Description:         redfishServerMD,
-			Validators: []validator.List{
-				listvalidator.SizeAtMost(1),
-				listvalidator.IsRequired(),
-			},
+			Validators:          redfishServerValidators(),
 			NestedObject: datasourceSchema.NestedBlockObject{
 				Attributes: RedfishServerDatasourceSchema(),
 			},
@@ -136,8 +292,9 @@ func RedfishServerDatasourceBlockMap() map[string]datasourceSchema.Block {
 	}
 }
 
-// Based on an instance of Service from the gofish library, retrieve a concrete system on which we can take action
-func getSystemResource(service *gofish.Service) (*redfish.ComputerSystem, error) {
+// Based on an instance of Service from the gofish library, retrieve a concrete system on which we can take action.
+// The system is selected by its ID, which may only be omitted when the server has a single system.
+func getSystemResource(service *gofish.Service, systemID string) (*redfish.ComputerSystem, error) {
 	systems, err := service.Systems()
 	if err != nil {
 		return nil, err
@@ -146,23 +303,123 @@ func getSystemResource(service *gofish.Service) (*redfish.ComputerSystem, error)
 		return nil, errors.New("no computer systems found")
 	}
 
-	return systems[0], err
+	if len(systemID) == 0 {
+		if len(systems) > 1 {
+			return nil, fmt.Errorf("the server has %d computer systems, set system_id to one of %s",
+				len(systems), systemIDs(systems))
+		}
+		return systems[0], nil
+	}
+	for _, system := range systems {
+		if system.ID == systemID {
+			return system, nil
+		}
+	}
+	return nil, fmt.Errorf("computer system %s not found, set system_id to one of %s", systemID, systemIDs(systems))
+}
+
+// getManagerResource returns the manager with the given ID. When no ID is given, it is the only manager
+// of the server, or the manager of the computer system selected by systemID, found in its Links.ManagedBy.
+func getManagerResource(service *gofish.Service, managerID, systemID string) (*redfish.Manager, error) {
+	managers, err := service.Managers()
+	if err != nil {
+		return nil, err
+	}
+	if len(managers) == 0 {
+		return nil, errors.New("no managers found")
+	}
+
+	if len(managerID) > 0 {
+		for _, manager := range managers {
+			if manager.ID == managerID {
+				return manager, nil
+			}
+		}
+		return nil, fmt.Errorf("manager %s not found, the managers of the server are %s", managerID, managerIDs(managers))
+	}
+	if len(managers) == 1 {
+		return managers[0], nil
+	}
+	system, err := getSystemResource(service, systemID)
+	if err != nil {
+		return nil, fmt.Errorf("the server has %d managers, set manager_id to one of %s: %w",
+			len(managers), managerIDs(managers), err)
+	}
+	for _, uri := range system.ManagedBy {
+		for _, manager := range managers {
+			if manager.ODataID == uri {
+				return manager, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("computer system %s has no manager, set manager_id to one of %s", system.ID, managerIDs(managers))
+}
+
+// managerIDs lists the IDs of the managers for error messages
+func managerIDs(managers []*redfish.Manager) string {
+	ids := make([]string, 0, len(managers))
+	for _, manager := range managers {
+		ids = append(ids, manager.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
+
+// systemIDs lists the IDs of the systems for error messages
+func systemIDs(systems []*redfish.ComputerSystem) string {
+	ids := make([]string, 0, len(systems))
+	for _, system := range systems {
+		ids = append(ids, system.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
+
+// resolveRedfishServer returns the server configuration to connect to. It is either the redfish_server block,
+// or the server from the provider inventory referenced by name.
+func resolveRedfishServer(pconfig *redfishProvider, server types.String, rserver []models.RedfishServer) (models.RedfishServer, error) {
+	name := server.ValueString()
+	if len(name) == 0 {
+		if len(rserver) == 0 {
+			return models.RedfishServer{}, errors.New("redfish server config not present. Either set server or the redfish_server block")
+		}
+		return rserver[0], nil
+	}
+
+	if len(rserver) > 0 {
+		return models.RedfishServer{}, fmt.Errorf("server %s and the redfish_server block cannot be used together", name)
+	}
+	if srv, ok := pconfig.Servers[name]; ok {
+		return srv, nil
+	}
+
+	available := make([]string, 0, len(pconfig.Servers))
+	for k := range pconfig.Servers {
+		available = append(available, k)
+	}
+	sort.Strings(available)
+	return models.RedfishServer{}, fmt.Errorf("server %s is not defined at provider level. Available servers: %s",
+		name, strings.Join(available, ", "))
+}
+
+// getRedfishServerEndpoint returns the endpoint of the server, to be used as the redfishMutexKV key
+func getRedfishServerEndpoint(pconfig *redfishProvider, server types.String, rserver []models.RedfishServer) string {
+	srv, err := resolveRedfishServer(pconfig, server, rserver)
+	if err != nil {
+		return ""
+	}
+	return srv.Endpoint.ValueString()
 }
 
 // NewConfig function creates the needed gofish structs to query the redfish API
 // See https://github.com/stmcginnis/gofish for details. This function returns a Service struct which can then be
 // used to make any required API calls.
 // To-Do: Verify from plan modifier, if required implement wrapper for validation of unknown in redfish_server.
-func NewConfig(pconfig *redfishProvider, rserver *[]models.RedfishServer) (*gofish.Service, error) {
-	if len(*rserver) == 0 {
-		return nil, fmt.Errorf("no provider block was found")
-	}
-
-	// first redfish server block
-	if len(*rserver) == 0 {
-		return nil, errors.New("redfish server config not present")
+func NewConfig(pconfig *redfishProvider, server types.String, rserver *[]models.RedfishServer) (*gofish.Service, error) {
+	rserver1, err := resolveRedfishServer(pconfig, server, *rserver)
+	if err != nil {
+		return nil, err
 	}
-	rserver1 := (*rserver)[0]
 	var redfishClientUser, redfishClientPass string
 
 	if len(rserver1.User.ValueString()) > 0 {
@@ -185,13 +442,49 @@ func NewConfig(pconfig *redfishProvider, rserver *[]models.RedfishServer) (*gofi
 		return nil, fmt.Errorf("error. Either Redfish client username or password has not been set. Please check your configuration")
 	}
 
+	authMethod := authMethodBasic
+	if len(rserver1.AuthMethod.ValueString()) > 0 {
+		authMethod = rserver1.AuthMethod.ValueString()
+	} else if len(pconfig.AuthMethod) > 0 {
+		authMethod = pconfig.AuthMethod
+	}
+
+	tlsOptions := getTLSOptions(pconfig, rserver1)
+	tlsConfig, err := tlsOptions.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error. Invalid TLS configuration: %w", err)
+	}
+	base, err := redfishCassettes.transport(pconfig.Cassette, newHTTPTransport(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	transport := &retryTransport{policy: pconfig.Retry, base: base}
+
 	clientConfig := gofish.ClientConfig{
-		Endpoint:  rserver1.Endpoint.ValueString(),
-		Username:  redfishClientUser,
-		Password:  redfishClientPass,
-		BasicAuth: true,
-		Insecure:  rserver1.SslInsecure.ValueBool(),
+		Endpoint:   rserver1.Endpoint.ValueString(),
+		Insecure:   rserver1.SslInsecure.ValueBool(),
+		HTTPClient: &http.Client{Transport: transport},
+		// the members of the collections the service does not expand are read a few at a time
+		MaxConcurrentRequests: common.CollectionParallelism,
+	}
+
+	switch authMethod {
+	case authMethodSession:
+		// The session is shared with every other resource using the same endpoint and user,
+		// so gofish must not authenticate by itself
+		session := redfishSessions.get(rserver1.Endpoint.ValueString(), redfishClientUser, redfishClientPass,
+			tlsOptions.key(), transport)
+		clientConfig.HTTPClient = &http.Client{
+			Transport: &sessionTransport{session: session, base: transport},
+		}
+	case authMethodBasic:
+		clientConfig.Username = redfishClientUser
+		clientConfig.Password = redfishClientPass
+		clientConfig.BasicAuth = true
+	default:
+		return nil, fmt.Errorf("error. Unsupported auth_method %s. Please check your configuration", authMethod)
 	}
+
 	api, err := gofish.Connect(clientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to redfish API: %w", err)
@@ -200,9 +493,26 @@ func NewConfig(pconfig *redfishProvider, rserver *[]models.RedfishServer) (*gofi
 	return api.Service, nil
 }
 
+// newHTTPTransport returns the same transport gofish builds when no HTTP client is given,
+// with the TLS configuration of the server
+func newHTTPTransport(tlsConfig *tls.Config) *http.Transport {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	return &http.Transport{
+		Proxy:                 defaultTransport.Proxy,
+		DialContext:           defaultTransport.DialContext,
+		MaxIdleConns:          defaultTransport.MaxIdleConns,
+		IdleConnTimeout:       defaultTransport.IdleConnTimeout,
+		ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
 type powerOperator struct {
 	ctx     context.Context
 	service *gofish.Service
+	// systemID is the ID of the system to reset, empty when the server has a single system
+	systemID string
 }
 
 // PowerOperation Executes a power operation against the target server. It takes four arguments. The first is the reset
@@ -215,7 +525,7 @@ type powerOperator struct {
 func (p powerOperator) PowerOperation(resetType string, maximumWaitTime int64, checkInterval int64) (redfish.PowerState, error) {
 	const powerON redfish.PowerState = "On"
 	const powerOFF redfish.PowerState = "Off"
-	system, err := getSystemResource(p.service)
+	system, err := getSystemResource(p.service, p.systemID)
 	if err != nil {
 		tflog.Error(p.ctx, fmt.Sprintf("Failed to identify system: %s", err))
 		return "", fmt.Errorf("failed to identify system: %w", err)
@@ -259,7 +569,7 @@ func (p powerOperator) PowerOperation(resetType string, maximumWaitTime int64, c
 
 	// Run the power operation against the target server
 	tflog.Trace(p.ctx, fmt.Sprintf("Performing system.Reset(%s)", resetType))
-	if err = system.Reset(redfish.ResetType(resetType)); err != nil {
+	if err = oem.New(p.service).Reset(system, redfish.ResetType(resetType)); err != nil {
 		tflog.Warn(p.ctx, fmt.Sprintf("system.Reset returned an error: %s", err))
 		return system.PowerState, err
 	}
@@ -271,7 +581,7 @@ func (p powerOperator) PowerOperation(resetType string, maximumWaitTime int64, c
 		totalTime += checkInterval
 		tflog.Trace(p.ctx, fmt.Sprintf("Total time is %d seconds. Checking power state now.", totalTime))
 
-		system, err := getSystemResource(p.service)
+		system, err := getSystemResource(p.service, p.systemID)
 		if err != nil {
 			tflog.Error(p.ctx, fmt.Sprintf("Failed to identify system: %s", err))
 			return targetPowerState, err
@@ -303,11 +613,16 @@ func (s *ServerStatusChecker) Check(ctx context.Context) error {
 	for start := time.Now(); time.Since(start) < (time.Duration(s.Timeout) * time.Second); {
 		tflog.Trace(ctx, "Checking server status...")
 		time.Sleep(time.Duration(s.Interval) * time.Second)
-		_, err = net.Dial("tcp", net.JoinHostPort(addr.Hostname(), addr.Scheme))
+		port := addr.Port()
+		if len(port) == 0 {
+			port = addr.Scheme
+		}
+		_, err = net.Dial("tcp", net.JoinHostPort(addr.Hostname(), port))
 		if err != nil {
 			continue
 		}
-		_, err := getSystemResource(s.Service)
+		// Any system answering tells the BMC is back
+		_, err := s.Service.Systems()
 		if err == nil {
 			return nil
 		}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// overlapBMC serves a chassis collection without $expand, and holds each member request until as many requests
+// as the collections read at the same time are in flight, or a second has passed
+type overlapBMC struct {
+	lock     sync.Mutex
+	inFlight int
+	peak     int
+	overlap  chan struct{}
+}
+
+func (b *overlapBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	var body interface{}
+	switch {
+	case path == "/redfish/v1":
+		body = map[string]interface{}{"@odata.id": "/redfish/v1", "Chassis": map[string]string{"@odata.id": "/redfish/v1/Chassis"}}
+	case path == "/redfish/v1/Chassis":
+		members := make([]map[string]string, 0, 2*common.CollectionParallelism)
+		for i := 0; i < 2*common.CollectionParallelism; i++ {
+			members = append(members, map[string]string{"@odata.id": path + "/" + string(rune('A'+i))})
+		}
+		body = map[string]interface{}{"@odata.id": path, "Members": members}
+	default:
+		b.lock.Lock()
+		b.inFlight++
+		if b.inFlight > b.peak {
+			b.peak = b.inFlight
+			if b.peak == common.CollectionParallelism {
+				close(b.overlap)
+			}
+		}
+		b.lock.Unlock()
+		select {
+		case <-b.overlap:
+		case <-time.After(time.Second):
+		}
+		b.lock.Lock()
+		b.inFlight--
+		b.lock.Unlock()
+		body = map[string]string{"@odata.id": path, "Id": path[strings.LastIndex(path, "/")+1:]}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body) // #nosec G104
+}
+
+func TestNewConfigParallelRequests(t *testing.T) {
+	bmc := &overlapBMC{overlap: make(chan struct{})}
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	service, err := NewConfig(&redfishProvider{}, types.StringNull(), &[]models.RedfishServer{{
+		User:     types.StringValue("root"),
+		Password: types.StringValue("calvin"),
+		Endpoint: types.StringValue(server.URL),
+	}})
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	chassis, err := common.GetCollection[redfish.Chassis](service, "/redfish/v1/Chassis")
+	if err != nil {
+		t.Fatalf("unable to read the chassis: %s", err)
+	}
+	if len(chassis) != 2*common.CollectionParallelism {
+		t.Fatalf("expected %d chassis, got %d", 2*common.CollectionParallelism, len(chassis))
+	}
+	// the members are read a few at a time, never more
+	if bmc.peak != common.CollectionParallelism {
+		t.Errorf("expected %d requests at the same time, got %d", common.CollectionParallelism, bmc.peak)
+	}
+}
@@ -75,6 +75,8 @@ func (*BiosDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, res
 // BiosDatasourceSchema to define the bios data-source schema
 func BiosDatasourceSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameDatasourceSchema(),
+		"system_id": SystemIDDatasourceSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the BIOS data-source",
 			Description:         "ID of the BIOS data-source",
@@ -99,7 +101,14 @@ func (g *BiosDatasource) Read(ctx context.Context, req datasource.ReadRequest, r
 	var plan models.BiosDatasource
 	diags := req.Config.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
-	service, err := NewConfig(g.p, &plan.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -117,13 +126,13 @@ func (g *BiosDatasource) Read(ctx context.Context, req datasource.ReadRequest, r
 func (g *BiosDatasource) readDatasourceRedfishBios(d models.BiosDatasource) (models.BiosDatasource, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	systems, err := g.service.Systems()
+	system, err := getSystemResource(g.service, d.SystemID.ValueString())
 	if err != nil {
-		diags.AddError("Error fetching computer systems collection", err.Error())
+		diags.AddError("Error fetching computer system", err.Error())
 		return d, diags
 	}
 
-	bios, err := systems[0].Bios()
+	bios, err := system.Bios()
 	if err != nil {
 		diags.AddError("Error fetching bios", err.Error())
 		return d, diags
@@ -20,7 +20,7 @@ package provider
 import (
 	"context"
 	"fmt"
-	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -74,6 +74,8 @@ func (*DellIdracAttributesDatasource) Schema(_ context.Context, _ datasource.Sch
 // DellIdracAttributesSchemaDatasource to define the idrac attribute schema
 func DellIdracAttributesSchemaDatasource() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameDatasourceSchema(),
+		"manager_id": ManagerIDDatasourceSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the iDRAC attributes resource",
 			Description:         "ID of the iDRAC attributes resource",
@@ -104,7 +106,14 @@ func (g *DellIdracAttributesDatasource) Read(ctx context.Context, req datasource
 	if state.ID.IsUnknown() {
 		state.ID = types.StringValue("placeholder")
 	}
-	service, err := NewConfig(g.p, &state.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -118,22 +127,15 @@ func (g *DellIdracAttributesDatasource) Read(ctx context.Context, req datasource
 func readDatasourceRedfishDellIdracAttributes(service *gofish.Service, d *models.DellIdracAttributes) diag.Diagnostics {
 	var diags diag.Diagnostics
 	idracError := "there was an issue when reading idrac attributes"
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
-	if err != nil {
-		diags.AddError(idracError, err.Error())
-		return diags
-	}
-
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
 	}
 
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
@@ -160,6 +162,6 @@ func readDatasourceRedfishDellIdracAttributes(service *gofish.Service, d *models
 		return diags
 	}
 
-	d.ID = types.StringValue(idracAttributes.ODataID)
+	d.ID = types.StringValue(idracAttributes.URI)
 	return diags
 }
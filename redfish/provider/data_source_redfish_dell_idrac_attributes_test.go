@@ -19,6 +19,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -36,6 +37,26 @@ func TestAccRedfishiDRACDataSource_fetch(t *testing.T) {
 	})
 }
 
+func TestAccRedfishiDRACDataSource_managerID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDataSourceiDRACConfigWithManagerID(creds, "iDRAC.Embedded.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redfish_dell_idrac_attributes.idrac", "manager_id", "iDRAC.Embedded.1"),
+					resource.TestCheckResourceAttrSet("data.redfish_dell_idrac_attributes.idrac", "attributes.%"),
+				),
+			},
+			{
+				Config:      testAccRedfishDataSourceiDRACConfigWithManagerID(creds, "iDRAC.Embedded.0"),
+				ExpectError: regexp.MustCompile("manager iDRAC.Embedded.0 not found"),
+			},
+		},
+	})
+}
+
 func testAccRedfishDataSourceiDRACConfig(testingInfo TestingServerCredentials) string {
 	return fmt.Sprintf(`
 	data "redfish_dell_idrac_attributes" "idrac" {
@@ -52,3 +73,22 @@ func testAccRedfishDataSourceiDRACConfig(testingInfo TestingServerCredentials) s
 		testingInfo.Endpoint,
 	)
 }
+
+func testAccRedfishDataSourceiDRACConfigWithManagerID(testingInfo TestingServerCredentials, managerID string) string {
+	return fmt.Sprintf(`
+	data "redfish_dell_idrac_attributes" "idrac" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+		manager_id = "%s"
+	  }
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		managerID,
+	)
+}
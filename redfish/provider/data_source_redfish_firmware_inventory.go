@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"terraform-provider-redfish/common"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -73,6 +74,7 @@ func (*FirmwareInventoryDatasource) Schema(_ context.Context, _ datasource.Schem
 // FirmwareInventoryDatasourceSchema to define the Firmware Inventory data-source schema
 func FirmwareInventoryDatasourceSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server": RedfishServerNameDatasourceSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the Firmware Inventory data-source",
 			Description:         "ID of the Firmware Inventory data-source",
@@ -115,7 +117,14 @@ func (g *FirmwareInventoryDatasource) Read(ctx context.Context, req datasource.R
 	var plan models.FirmwareInventory
 	diags := req.Config.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
-	service, err := NewConfig(g.p, &plan.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -152,7 +161,7 @@ func readRedfishFirmwareInventory(service *gofish.Service) (*models.FirmwareInve
 		return nil, fmt.Errorf("error fetching UpdateService collection: %w", err)
 	}
 
-	fwInventories, err := updateService.FirmwareInventories()
+	fwInventories, err := common.GetCollection[redfish.SoftwareInventory](service, updateService.FirmwareInventory)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching Firmware Inventory: %w", err)
 	}
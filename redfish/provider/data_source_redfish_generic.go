@@ -0,0 +1,259 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stmcginnis/gofish"
+)
+
+var (
+	_ datasource.DataSource              = &GenericDatasource{}
+	_ datasource.DataSourceWithConfigure = &GenericDatasource{}
+)
+
+// NewGenericDatasource is new datasource for any Redfish resource
+func NewGenericDatasource() datasource.DataSource {
+	return &GenericDatasource{}
+}
+
+// GenericDatasource is struct for the generic datasource
+type GenericDatasource struct {
+	p *redfishProvider
+}
+
+// Configure implements datasource.DataSourceWithConfigure
+func (g *GenericDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	g.p = req.ProviderData.(*redfishProvider)
+}
+
+// Metadata implements datasource.DataSource
+func (*GenericDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "generic"
+}
+
+// Schema implements datasource.DataSource
+func (*GenericDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This Terraform datasource is used to query any Redfish resource, for the properties no other" +
+			" datasource of the provider exposes.",
+		Description: "This Terraform datasource is used to query any Redfish resource, for the properties no other" +
+			" datasource of the provider exposes.",
+		Attributes: map[string]schema.Attribute{
+			"server": RedfishServerNameDatasourceSchema(),
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the generic datasource",
+				Description:         "ID of the generic datasource",
+				Computed:            true,
+			},
+			"odata_id": schema.StringAttribute{
+				MarkdownDescription: "The `@odata.id` of the Redfish resource, such as `/redfish/v1/Chassis/System.Embedded.1`.",
+				Description:         "The '@odata.id' of the Redfish resource, such as '/redfish/v1/Chassis/System.Embedded.1'.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(odataIDRegex, "must be a Redfish URI starting with /redfish/"),
+				},
+			},
+			"expand_members": schema.BoolAttribute{
+				MarkdownDescription: "When the resource is a collection, return its members with their properties instead of" +
+					" their references. The members are read with the collection through `$expand` when the service supports it.",
+				Description: "When the resource is a collection, return its members with their properties instead of" +
+					" their references. The members are read with the collection through $expand when the service supports it.",
+				Optional: true,
+			},
+			"select": schema.ListAttribute{
+				MarkdownDescription: "Top level properties to return, such as `[\"AssetTag\", \"Status\"]`, requested with" +
+					" `$select` when the service supports it. With `expand_members`, the properties of each member are selected.",
+				Description: "Top level properties to return, such as [\"AssetTag\", \"Status\"], requested with" +
+					" $select when the service supports it. With expand_members, the properties of each member are selected.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "JSON document of the Redfish resource, to be read with `jsondecode`.",
+				Description:         "JSON document of the Redfish resource, to be read with jsondecode.",
+				Computed:            true,
+			},
+			"flattened": schema.MapAttribute{
+				MarkdownDescription: "Properties of the Redfish resource by path, the names of the nested properties and the" +
+					" indexes of the arrays being joined with dots, such as `Status.Health` or `Members.0.Id`." +
+					" Null values, empty objects and empty arrays are left out.",
+				Description: "Properties of the Redfish resource by path, the names of the nested properties and the" +
+					" indexes of the arrays being joined with dots, such as 'Status.Health' or 'Members.0.Id'." +
+					" Null values, empty objects and empty arrays are left out.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+		Blocks: RedfishServerDatasourceBlockMap(),
+	}
+}
+
+// Read implements datasource.DataSource
+func (g *GenericDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state models.GenericDataSource
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, state.Server, &state.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError("service error", err.Error())
+		return
+	}
+	var properties []string
+	resp.Diagnostics.Append(state.Select.ElementsAs(ctx, &properties, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(readRedfishGeneric(service, &state, properties)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func readRedfishGeneric(service *gofish.Service, d *models.GenericDataSource, properties []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	uri := d.OdataID.ValueString()
+	expandMembers := d.ExpandMembers.ValueBool()
+
+	var query []string
+	// with expand_members, the properties are selected on the members
+	if selected := selectQuery(service, properties); len(selected) > 0 && !expandMembers {
+		query = append(query, selected)
+	}
+	document, err := getRedfishDocument(service, uri, query...)
+	if err != nil {
+		diags.AddError("Error retrieving the Redfish resource", err.Error())
+		return diags
+	}
+
+	_, isCollection := document["Members"]
+	if expandMembers && isCollection {
+		members, err := common.GetCollectionMembers(service, uri)
+		if err != nil {
+			diags.AddError("Error retrieving the members of the Redfish collection", err.Error())
+			return diags
+		}
+		expanded := make([]interface{}, 0, len(members))
+		for _, data := range members {
+			var member map[string]interface{}
+			if err := json.Unmarshal(data, &member); err != nil {
+				diags.AddError("Error retrieving the members of the Redfish collection", err.Error())
+				return diags
+			}
+			expanded = append(expanded, selectProperties(member, properties))
+		}
+		document["Members"] = expanded
+	} else {
+		// the service may not support $select
+		document = selectProperties(document, properties)
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		diags.AddError("Error encoding the Redfish resource", err.Error())
+		return diags
+	}
+	flattened := make(map[string]attr.Value)
+	flattenRedfishValue("", document, flattened)
+
+	d.ID = types.StringValue(uri)
+	d.Body = types.StringValue(string(body))
+	d.Flattened = types.MapValueMust(types.StringType, flattened)
+	return diags
+}
+
+// selectProperties keeps the given top level properties of a document and its OData annotations, all of them
+// when no property is given
+func selectProperties(document map[string]interface{}, properties []string) map[string]interface{} {
+	if len(properties) == 0 {
+		return document
+	}
+	selected := make(map[string]interface{})
+	for name, value := range document {
+		if odataAnnotation(name) {
+			selected[name] = value
+		}
+	}
+	for _, name := range properties {
+		if value, ok := document[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected
+}
+
+// odataAnnotation tells whether a property is an annotation of the resource, such as @odata.id
+func odataAnnotation(name string) bool {
+	switch name {
+	case "@odata.id", "@odata.type", "@odata.context", "@odata.etag":
+		return true
+	}
+	return false
+}
+
+// flattenRedfishValue adds the scalar values of a JSON value to the map, by path
+func flattenRedfishValue(path string, value interface{}, flattened map[string]attr.Value) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, nested := range v {
+			flattenRedfishValue(joinFlattenedPath(path, name), nested, flattened)
+		}
+	case []interface{}:
+		for i, element := range v {
+			flattenRedfishValue(joinFlattenedPath(path, strconv.Itoa(i)), element, flattened)
+		}
+	case string:
+		flattened[path] = types.StringValue(v)
+	case float64:
+		flattened[path] = types.StringValue(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		flattened[path] = types.StringValue(strconv.FormatBool(v))
+	}
+}
+
+func joinFlattenedPath(path, name string) string {
+	if len(path) == 0 {
+		return name
+	}
+	return path + "." + name
+}
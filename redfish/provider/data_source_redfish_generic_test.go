@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedfishGeneric_fetch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDatasourceGenericConfig(creds, "/redfish/v1/Chassis/System.Embedded.1",
+					`select = ["SerialNumber", "Status"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_generic.generic", "flattened.SerialNumber"),
+					resource.TestCheckResourceAttrSet("data.redfish_generic.generic", "flattened.Status.Health"),
+					resource.TestCheckNoResourceAttr("data.redfish_generic.generic", "flattened.Model"),
+					resource.TestCheckResourceAttr("data.redfish_generic.generic", "flattened.@odata.id",
+						"/redfish/v1/Chassis/System.Embedded.1"),
+				),
+			},
+			{
+				Config: testAccRedfishDatasourceGenericConfig(creds, "/redfish/v1/Systems/System.Embedded.1/Storage",
+					`expand_members = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_generic.generic", "flattened.Members.0.Id"),
+					resource.TestCheckResourceAttrSet("data.redfish_generic.generic", "flattened.Members.0.Drives.0.@odata.id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedfishGeneric_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishDatasourceGenericConfig(creds, "/redfish/v1/Chassis/Unknown", ""),
+				ExpectError: regexp.MustCompile("Error retrieving the Redfish resource"),
+			},
+		},
+	})
+}
+
+func testAccRedfishDatasourceGenericConfig(testingInfo TestingServerCredentials, odataID string, options string) string {
+	return fmt.Sprintf(`
+	data "redfish_generic" "generic" {
+		redfish_server {
+			user         = "%s"
+			password     = "%s"
+			endpoint     = "https://%s"
+			ssl_insecure = true
+		}
+
+		odata_id = "%s"
+		%s
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		odataID,
+		options,
+	)
+}
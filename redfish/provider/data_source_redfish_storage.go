@@ -20,12 +20,13 @@ package provider
 import (
 	"context"
 	"fmt"
+	"terraform-provider-redfish/common"
 	"terraform-provider-redfish/gofish/dell"
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
 	"github.com/stmcginnis/gofish"
-	"github.com/stmcginnis/gofish/common"
+	redfishcommon "github.com/stmcginnis/gofish/common"
 	"github.com/stmcginnis/gofish/redfish"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -79,6 +80,8 @@ func (*StorageDatasource) Schema(_ context.Context, _ datasource.SchemaRequest,
 // StorageDatasourceSchema to define the storage data-source schema
 func StorageDatasourceSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameDatasourceSchema(),
+		"system_id": SystemIDDatasourceSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the storage data-source",
 			Description:         "ID of the storage data-source",
@@ -112,7 +115,14 @@ func (g *StorageDatasource) Read(ctx context.Context, req datasource.ReadRequest
 	var plan models.StorageDatasource
 	diags := req.Config.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
-	service, err := NewConfig(g.p, &plan.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -135,13 +145,14 @@ func (g *StorageDatasource) readDatasourceRedfishStorage(d models.StorageDatasou
 	controllers := append(controllerIDs, controllerNames...)
 	d.ID = types.StringValue(fmt.Sprintf("%d", time.Now().Unix()))
 
-	systems, err := g.service.Systems()
+	system, err := getSystemResource(g.service, d.SystemID.ValueString())
 	if err != nil {
-		diags.AddError("Error fetching computer systems collection", err.Error())
+		diags.AddError("Error fetching computer system", err.Error())
 		return d, diags
 	}
 
-	storage, err := systems[0].Storage()
+	// the storage controllers and their drives are expanded when the service supports it
+	storage, err := common.GetLinkedCollection[redfish.Storage](g.service, system.ODataID, "Storage")
 	if err != nil {
 		diags.AddError("Error fetching storage", err.Error())
 		return d, diags
@@ -158,7 +169,7 @@ func (g *StorageDatasource) readDatasourceRedfishStorage(d models.StorageDatasou
 		}
 		dellStorage, _ := dell.Storage(s)
 		terraformData := newStorage(*dellStorage)
-		drives, err := s.Drives()
+		drives, err := common.GetReferenced[redfish.Drive](g.service, s.ODataID, "Drives")
 		if err != nil {
 			diags.AddError(fmt.Sprintf("Error when retrieving drives: %s", s.ID), err.Error())
 			continue
@@ -246,7 +257,7 @@ func newStorageControllers(input redfish.StorageController) models.StorageContro
 	}
 }
 
-func newProtocols(inputs []common.Protocol) []types.String {
+func newProtocols(inputs []redfishcommon.Protocol) []types.String {
 	out := make([]types.String, 0)
 	for _, input := range inputs {
 		out = append(out, types.StringValue(string(input)))
@@ -331,7 +342,7 @@ func newOem(input dell.StorageOEM) models.Oem {
 }
 
 // newStatus converts redfish.Status to models.Status
-func newStatus(input common.Status) models.Status {
+func newStatus(input redfishcommon.Status) models.Status {
 	return models.Status{
 		Health:       types.StringValue(string(input.Health)),
 		HealthRollup: types.StringValue(string(input.HealthRollup)),
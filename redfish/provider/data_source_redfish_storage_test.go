@@ -19,6 +19,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -45,6 +46,23 @@ func TestAccRedfishStorageDataSource_fetch(t *testing.T) {
 	})
 }
 
+func TestAccRedfishStorageDataSource_systemID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStorageDatasourceWithSystemID(creds, "System.Embedded.1"),
+				Check:  resource.TestCheckResourceAttr("data.redfish_storage.storage", "system_id", "System.Embedded.1"),
+			},
+			{
+				Config:      testAccStorageDatasourceWithSystemID(creds, "System.Embedded.9"),
+				ExpectError: regexp.MustCompile("computer system System.Embedded.9 not found, set system_id to one of System.Embedded.1"),
+			},
+		},
+	})
+}
+
 // controller_names = ["PERC H730P Mini"]
 // controller_ids = ["AHCI.Embedded.2-1"]
 
@@ -119,3 +137,22 @@ func testAccStorageDatasourceWithBothConfig(testingInfo TestingServerCredentials
 		testingInfo.Endpoint,
 	)
 }
+
+func testAccStorageDatasourceWithSystemID(testingInfo TestingServerCredentials, systemID string) string {
+	return fmt.Sprintf(`
+	data "redfish_storage" "storage" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+		system_id = "%s"
+	  }
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		systemID,
+	)
+}
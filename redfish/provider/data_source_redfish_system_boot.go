@@ -28,7 +28,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stmcginnis/gofish"
-	"github.com/stmcginnis/gofish/redfish"
 )
 
 var (
@@ -74,16 +73,19 @@ func (*SystemBootDatasource) Schema(_ context.Context, _ datasource.SchemaReques
 // SystemBootDatasourceSchema to define the system boot datasource schema
 func SystemBootDatasourceSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server": RedfishServerNameDatasourceSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "Resource ID of the computer system used.",
 			Description:         "Resource ID of the computer system used.",
 			Computed:            true,
 		},
 		"resource_id": schema.StringAttribute{
-			MarkdownDescription: "Resource ID of the computer system. If not provided, the first system resource is used",
-			Description:         "Resource ID of the computer system. If not provided, the first system resource is used",
-			Optional:            true,
-			Computed:            true,
+			MarkdownDescription: "Resource ID of the computer system, such as System.Embedded.1. " +
+				"Required when the server exposes several systems, like the nodes of a multi-node enclosure or a Redfish aggregator.",
+			Description: "Resource ID of the computer system, such as System.Embedded.1. " +
+				"Required when the server exposes several systems, like the nodes of a multi-node enclosure or a Redfish aggregator.",
+			Optional: true,
+			Computed: true,
 		},
 		"boot_order": schema.ListAttribute{
 			MarkdownDescription: "An array of BootOptionReference strings that represent the persistent boot order for this computer system",
@@ -119,7 +121,14 @@ func (g *SystemBootDatasource) Read(ctx context.Context, req datasource.ReadRequ
 	var plan models.SystemBootDataSource
 	diags := req.Config.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
-	service, err := NewConfig(g.p, &plan.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -135,34 +144,13 @@ func (g *SystemBootDatasource) Read(ctx context.Context, req datasource.ReadRequ
 func readRedfishSystemBoot(service *gofish.Service, d models.SystemBootDataSource) (models.SystemBootDataSource, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	systems, err := service.Systems()
+	// get the boot resource
+	computerSystem, err := getSystemResource(service, d.ResourceID.ValueString())
 	if err != nil {
-		diags.AddError("Error when retrieving systems", err.Error())
+		diags.AddError("Could not find a ComputerSystem", err.Error())
 		return d, diags
 	}
-
-	// get the boot resource
-	var computerSystem *redfish.ComputerSystem
-	var boot redfish.Boot
-	if d.ResourceID.ValueString() != "" {
-		for key := range systems {
-			if systems[key].ID == d.ResourceID.ValueString() {
-				computerSystem = systems[key]
-				boot = systems[key].Boot
-				break
-			}
-		}
-
-		if computerSystem == nil {
-			diags.AddError("Could not find a ComputerSystem", "")
-			return d, diags
-		}
-	} else {
-		// use the first system resource in the collection if resource
-		// ID is not provided
-		computerSystem = systems[0]
-		boot = systems[0].Boot
-	}
+	boot := computerSystem.Boot
 
 	bootOrder := []attr.Value{}
 	for _, bootOptionReference := range boot.BootOrder {
@@ -21,6 +21,7 @@ import (
 	"context"
 	"log"
 	"strconv"
+	"terraform-provider-redfish/common"
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
 )
 
 var (
@@ -67,6 +69,8 @@ func (*DellVirtualMediaDatasource) Schema(_ context.Context, _ datasource.Schema
 		Description: "This Terraform datasource is used to query existing virtual media details." +
 			" The information fetched from this block can be further used for resource block.",
 		Attributes: map[string]schema.Attribute{
+			"server":     RedfishServerNameDatasourceSchema(),
+			"manager_id": ManagerIDDatasourceSchema(),
 			"id": schema.StringAttribute{
 				MarkdownDescription: "ID of the virtual media datasource",
 				Description:         "ID of the virtual media datasource",
@@ -102,7 +106,14 @@ func (g *DellVirtualMediaDatasource) Read(ctx context.Context, req datasource.Re
 	if state.ID.IsUnknown() {
 		state.ID = types.StringValue("placeholder")
 	}
-	service, err := NewConfig(g.p, &state.RedfishServer)
+	// Reads wait for the resources changing the server, but not for each other
+	unlock, err := rLockServer(ctx, g.p, getRedfishServerEndpoint(g.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+	service, err := NewConfig(g.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -116,15 +127,15 @@ func (g *DellVirtualMediaDatasource) Read(ctx context.Context, req datasource.Re
 func readRedfishDellVirtualMediaCollection(service *gofish.Service, d *models.VirtualMediaDataSource) diag.Diagnostics {
 	var diags diag.Diagnostics
 	const intBase = 10
-	// Get manager.Since this provider is thought to work with individual servers, should be only one.
-	manager, err := service.Managers()
+	// Get manager
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError("Error retrieving the managers:", err.Error())
 		return diags
 	}
 
 	// Get virtual media
-	dellvirtualMedia, err := manager[0].VirtualMedia()
+	dellvirtualMedia, err := common.GetLinkedCollection[redfish.VirtualMedia](service, manager.ODataID, "VirtualMedia")
 	if err != nil {
 		diags.AddError("Error retrieving the virtual media instances", err.Error())
 		return diags
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"terraform-provider-redfish/emulator"
+)
+
+const (
+	emulatorUsername = "root"
+	emulatorPassword = "calvin"
+)
+
+// startEmulators runs the acceptance tests against two emulated servers when TF_TESTING_EMULATOR is set,
+// by setting the environment variables the tests read to values the emulator knows. The emulators live
+// as long as the test binary.
+func startEmulators() {
+	if len(os.Getenv("TF_TESTING_EMULATOR")) == 0 {
+		return
+	}
+	var endpoints []string
+	for i := 0; i < 2; i++ {
+		server, err := emulator.New(emulatorUsername, emulatorPassword)
+		if err != nil {
+			panic(fmt.Sprintf("unable to start the emulator: %s", err))
+		}
+		endpoints = append(endpoints, server.Endpoint())
+	}
+
+	// The local firmware package only needs a Dell Update Package name
+	dir, err := os.MkdirTemp("", "redfish-emulator")
+	if err != nil {
+		panic(fmt.Sprintf("unable to create the firmware package: %s", err))
+	}
+	firmware := filepath.Join(dir, "BIOS_EMUL_WN64_2.19.1.EXE")
+	if err := os.WriteFile(firmware, []byte("firmware"), 0o600); err != nil {
+		panic(fmt.Sprintf("unable to create the firmware package: %s", err))
+	}
+
+	testData, _ := filepath.Abs(filepath.Join("..", "..", "test-data")) // #nosec G104
+	variables := map[string]string{
+		"TF_TESTING_USERNAME":                         emulatorUsername,
+		"TF_TESTING_PASSWORD":                         emulatorPassword,
+		"TF_TESTING_ENDPOINT":                         endpoints[0],
+		"TF_TESTING_ENDPOINT2":                        endpoints[1],
+		"TF_TESTING_STORAGE_VOLUME_DRIVE":             "Solid State Disk 0:1:0",
+		"TF_TESTING_BOOT_OPTION_REFERENCE":            "Boot0004",
+		"TF_TESTING_VIRTUAL_MEDIA_IMAGE_PATH_64Boot":  "http://images.example.com/ubuntu-22.04-live-server-amd64.iso",
+		"TF_TESTING_VIRTUAL_MEDIA_IMAGE_PATH_EfiBoot": "http://images.example.com/efiboot.img",
+		"TF_TESTING_FIRMWARE_IMAGE_LOCAL":             firmware,
+		"TF_TESTING_FIRMWARE_IMAGE_HTTP":              "http://downloads.example.com/BIOS_EMUL_WN64_2.20.1.EXE",
+		"TF_TESTING_FIRMWARE_IMAGE_NFS":               "nfs://nfs.example.com/share/BIOS_EMUL_WN64_2.21.1.EXE",
+		"TF_TESTING_FIRMWARE_IMAGE_INVALID":           filepath.Join(dir, "missing.EXE"),
+		"TF_TESTING_EVENT_LISTENER":                   "127.0.0.1:0",
+		"VALID_CERT":                                  filepath.Join(testData, "valid-cert.txt"),
+		"INVALID_CERT":                                filepath.Join(testData, "invalid-cert.txt"),
+		"TF_NFS_IP_ADDRESS":                           "nfs.example.com",
+		"TF_NFS_SHARE_NAME":                           "/share",
+		"TF_CIFS_IP_ADDRESS":                          "cifs.example.com",
+		"TF_CIFS_SHARE_NAME":                          "share",
+	}
+	for name, value := range variables {
+		os.Setenv(name, value) // #nosec G104
+	}
+}
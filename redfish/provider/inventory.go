@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// inventoryServer represents a server entry in the inventory file
+type inventoryServer struct {
+	User        string `json:"user" yaml:"user"`
+	Password    string `json:"password" yaml:"password"`
+	Endpoint    string `json:"endpoint" yaml:"endpoint"`
+	SslInsecure bool   `json:"ssl_insecure" yaml:"ssl_insecure"`
+	AuthMethod  string `json:"auth_method" yaml:"auth_method"`
+
+	CACertificate          string `json:"ca_certificate" yaml:"ca_certificate"`
+	TLSServerName          string `json:"tls_server_name" yaml:"tls_server_name"`
+	CertificateFingerprint string `json:"certificate_fingerprint" yaml:"certificate_fingerprint"`
+	ClientCertificate      string `json:"client_certificate" yaml:"client_certificate"`
+	ClientKey              string `json:"client_key" yaml:"client_key"`
+}
+
+func (s inventoryServer) toRedfishServer() models.RedfishServer {
+	return models.RedfishServer{
+		User:        types.StringValue(s.User),
+		Password:    types.StringValue(s.Password),
+		Endpoint:    types.StringValue(s.Endpoint),
+		SslInsecure: types.BoolValue(s.SslInsecure),
+		AuthMethod:  types.StringValue(s.AuthMethod),
+
+		CACertificate:          types.StringValue(s.CACertificate),
+		TLSServerName:          types.StringValue(s.TLSServerName),
+		CertificateFingerprint: types.StringValue(s.CertificateFingerprint),
+		ClientCertificate:      types.StringValue(s.ClientCertificate),
+		ClientKey:              types.StringValue(s.ClientKey),
+	}
+}
+
+// loadServerInventory reads the named servers from an inventory file.
+// The format is chosen from the file extension: .yaml/.yml, .json or .csv
+func loadServerInventory(file string) (map[string]models.RedfishServer, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open inventory file: %w", err)
+	}
+	defer f.Close()
+
+	var servers map[string]inventoryServer
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(f).Decode(&servers)
+	case ".json":
+		servers, err = readJSONInventory(f)
+	case ".csv":
+		servers, err = readCSVInventory(f)
+	default:
+		return nil, fmt.Errorf("unsupported inventory file extension %q, use .yaml, .yml, .json or .csv", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse inventory file %s: %w", file, err)
+	}
+
+	inventory := make(map[string]models.RedfishServer, len(servers))
+	for name, server := range servers {
+		if len(server.Endpoint) == 0 {
+			return nil, fmt.Errorf("server %s in inventory file %s has no endpoint", name, file)
+		}
+		inventory[name] = server.toRedfishServer()
+	}
+	return inventory, nil
+}
+
+// readJSONInventory reads a JSON inventory. The object is read key by key, since decoding it into a map
+// would silently keep the last of the servers defined more than once.
+func readJSONInventory(r io.Reader) (map[string]inventoryServer, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("the inventory must be an object of server names to servers")
+	}
+	servers := make(map[string]inventoryServer)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, _ := token.(string)
+		if _, ok := servers[name]; ok {
+			return nil, fmt.Errorf("server %s is defined more than once", name)
+		}
+		var server inventoryServer
+		if err := decoder.Decode(&server); err != nil {
+			return nil, fmt.Errorf("server %s: %w", name, err)
+		}
+		servers[name] = server
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// readCSVInventory reads a CSV inventory. The first row is the header and must contain
+// the name and endpoint columns. The other columns are optional and named after the redfish_server attributes.
+func readCSVInventory(r io.Reader) (map[string]inventoryServer, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the header row is missing")
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(column))] = i
+	}
+	for _, required := range []string{"name", "endpoint"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("the %s column is missing", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	servers := make(map[string]inventoryServer)
+	for line, record := range records[1:] {
+		name := field(record, "name")
+		if len(name) == 0 {
+			return nil, fmt.Errorf("row %d has no name", line+2)
+		}
+		if _, ok := servers[name]; ok {
+			return nil, fmt.Errorf("server %s is defined more than once", name)
+		}
+		server := inventoryServer{
+			User:       field(record, "user"),
+			Password:   field(record, "password"),
+			Endpoint:   field(record, "endpoint"),
+			AuthMethod: field(record, "auth_method"),
+
+			CACertificate:          field(record, "ca_certificate"),
+			TLSServerName:          field(record, "tls_server_name"),
+			CertificateFingerprint: field(record, "certificate_fingerprint"),
+			ClientCertificate:      field(record, "client_certificate"),
+			ClientKey:              field(record, "client_key"),
+		}
+		if insecure := field(record, "ssl_insecure"); len(insecure) > 0 {
+			server.SslInsecure, err = strconv.ParseBool(insecure)
+			if err != nil {
+				return nil, fmt.Errorf("row %d has an invalid ssl_insecure value: %w", line+2, err)
+			}
+		}
+		servers[name] = server
+	}
+	return servers, nil
+}
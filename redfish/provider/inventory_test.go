@@ -0,0 +1,206 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestLoadServerInventory(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// servers are the expected endpoints by server name
+		servers map[string]string
+		err     string
+	}{
+		{
+			name: "yaml",
+			file: "servers.yaml",
+			content: `
+server-1:
+  user: admin
+  password: passw0rd
+  endpoint: https://server-1
+  ssl_insecure: true
+server-2:
+  endpoint: https://server-2
+  auth_method: session
+`,
+			servers: map[string]string{"server-1": "https://server-1", "server-2": "https://server-2"},
+		},
+		{
+			name:    "yml",
+			file:    "servers.yml",
+			content: "server-1:\n  endpoint: https://server-1\n",
+			servers: map[string]string{"server-1": "https://server-1"},
+		},
+		{
+			name:    "json",
+			file:    "servers.json",
+			content: `{"server-1": {"endpoint": "https://server-1", "ssl_insecure": true}, "server-2": {"endpoint": "https://server-2"}}`,
+			servers: map[string]string{"server-1": "https://server-1", "server-2": "https://server-2"},
+		},
+		{
+			name:    "csv",
+			file:    "servers.CSV",
+			content: "Name,Endpoint,User,Password,SSL_Insecure\nserver-1,https://server-1,admin,passw0rd,true\nserver-2,https://server-2,,,\n",
+			servers: map[string]string{"server-1": "https://server-1", "server-2": "https://server-2"},
+		},
+		{name: "empty csv", file: "servers.csv", content: "", err: "the header row is missing"},
+		{name: "unknown extension", file: "servers.toml", content: "", err: "unsupported inventory file extension"},
+		{name: "missing endpoint", file: "servers.yaml", content: "server-1:\n  user: admin\n", err: "server server-1 in inventory file"},
+		{name: "malformed yaml", file: "servers.yaml", content: "server-1: [endpoint", err: "unable to parse inventory file"},
+		{name: "malformed json", file: "servers.json", content: `{"server-1": {"endpoint": }`, err: "unable to parse inventory file"},
+		{name: "json array", file: "servers.json", content: `[{"endpoint": "https://server-1"}]`, err: "must be an object"},
+		{name: "json wrong type", file: "servers.json", content: `{"server-1": {"ssl_insecure": "yes"}}`, err: "server server-1"},
+		{name: "csv without endpoint column", file: "servers.csv", content: "name,user\nserver-1,admin\n", err: "the endpoint column is missing"},
+		{name: "csv without name", file: "servers.csv", content: "name,endpoint\n,https://server-1\n", err: "row 2 has no name"},
+		{name: "csv invalid ssl_insecure", file: "servers.csv", content: "name,endpoint,ssl_insecure\nserver-1,https://server-1,maybe\n",
+			err: "row 2 has an invalid ssl_insecure value"},
+		{name: "csv wrong number of fields", file: "servers.csv", content: "name,endpoint\nserver-1\n", err: "wrong number of fields"},
+		{name: "duplicate yaml", file: "servers.yaml", content: "server-1:\n  endpoint: https://a\nserver-1:\n  endpoint: https://b\n",
+			err: "already defined"},
+		{name: "duplicate json", file: "servers.json", content: `{"server-1": {"endpoint": "https://a"}, "server-1": {"endpoint": "https://b"}}`,
+			err: "server server-1 is defined more than once"},
+		{name: "duplicate csv", file: "servers.csv", content: "name,endpoint\nserver-1,https://a\nserver-1,https://b\n",
+			err: "server server-1 is defined more than once"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), test.file)
+			if err := os.WriteFile(file, []byte(test.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			inventory, err := loadServerInventory(file)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error with %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to load the inventory: %s", err)
+			}
+			if len(inventory) != len(test.servers) {
+				t.Errorf("expected %d servers, got %d", len(test.servers), len(inventory))
+			}
+			for name, endpoint := range test.servers {
+				if server, ok := inventory[name]; !ok || server.Endpoint.ValueString() != endpoint {
+					t.Errorf("expected the server %s with the endpoint %s, got %v", name, endpoint, server.Endpoint)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadServerInventoryFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "servers.csv")
+	content := "name,endpoint,user,password,ssl_insecure,auth_method,tls_server_name\n" +
+		" server-1 , https://server-1 ,admin,passw0rd,true,session,bmc.example.com\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inventory, err := loadServerInventory(file)
+	if err != nil {
+		t.Fatalf("unable to load the inventory: %s", err)
+	}
+	server := inventory["server-1"]
+	if server.Endpoint.ValueString() != "https://server-1" || server.User.ValueString() != "admin" ||
+		server.Password.ValueString() != "passw0rd" || !server.SslInsecure.ValueBool() ||
+		server.AuthMethod.ValueString() != "session" || server.TLSServerName.ValueString() != "bmc.example.com" {
+		t.Errorf("unexpected server %+v", server)
+	}
+	if _, err := loadServerInventory(filepath.Join(t.TempDir(), "missing.yaml")); err == nil ||
+		!strings.Contains(err.Error(), "unable to open inventory file") {
+		t.Errorf("expected an error for a missing file, got %v", err)
+	}
+}
+
+func TestAccRedfishInventory_serverName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishInventoryServerConfig(creds),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios", "odata_id"),
+				),
+			},
+			{
+				Config:      testAccRedfishInventoryUnknownServerConfig(creds),
+				ExpectError: regexp.MustCompile("server unknown-server is not defined"),
+			},
+		},
+	})
+}
+
+func testAccRedfishInventoryServerConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  redfish_servers = {
+			"server-1" = {
+			  user = "%s"
+			  password = "%s"
+			  endpoint = "https://%s"
+			  ssl_insecure = true
+			}
+		  }
+		}
+
+		data "redfish_bios" "bios" {
+		  server = "server-1"
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishInventoryUnknownServerConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  redfish_servers = {
+			"server-1" = {
+			  user = "%s"
+			  password = "%s"
+			  endpoint = "https://%s"
+			  ssl_insecure = true
+			}
+		  }
+		}
+
+		data "redfish_bios" "bios" {
+		  server = "unknown-server"
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
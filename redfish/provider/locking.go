@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"terraform-provider-redfish/mutexkv"
+	"terraform-provider-redfish/redfish/models"
+	"time"
+)
+
+const lockErrorMsg = "Unable to lock the server"
+
+// lockPolicy tells how long operations wait for the lock of a server
+type lockPolicy struct {
+	// timeout is the maximum wait for the lock, 0 waits until the operation is cancelled
+	timeout time.Duration
+}
+
+// newLockPolicy builds the lock policy from the provider locking block and sets the
+// number of parallel reads and the lock directory of redfishMutexKV
+func newLockPolicy(config []models.LockingConfig) (lockPolicy, error) {
+	var cfg models.LockingConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	redfishMutexKV.SetDefaultReadSlots(int(cfg.MaxParallelReads.ValueInt64()))
+	policy := lockPolicy{
+		timeout: time.Duration(cfg.Timeout.ValueInt64()) * time.Second,
+	}
+	if len(cfg.Directory.ValueString()) == 0 {
+		return policy, nil
+	}
+	return policy, redfishMutexKV.SetLockDirectory(cfg.Directory.ValueString(), lockWorkspace())
+}
+
+// lockWorkspace describes this run to the other runs waiting for its locks: the working
+// directory of terraform, and the workspace if it is selected with TF_WORKSPACE
+func lockWorkspace() string {
+	workspace, err := os.Getwd()
+	if err != nil {
+		workspace = "unknown"
+	}
+	if name := os.Getenv("TF_WORKSPACE"); len(name) > 0 {
+		workspace = fmt.Sprintf("%s (%s)", name, workspace)
+	}
+	return workspace
+}
+
+// lockServer takes the exclusive lock of the server endpoint, for operations which change the server.
+// The returned function releases the lock.
+func lockServer(ctx context.Context, pconfig *redfishProvider, endpoint string) (func(), error) {
+	ctx, cancel := pconfig.Locking.context(ctx)
+	defer cancel()
+	if err := redfishMutexKV.LockContext(ctx, endpoint); err != nil {
+		return nil, pconfig.Locking.error(endpoint, err)
+	}
+	return func() { redfishMutexKV.Unlock(endpoint) }, nil
+}
+
+// rLockServer takes a shared lock of the server endpoint, for operations which only read the server.
+// Reads run in parallel, but not while the server is being changed. The returned function releases the lock.
+func rLockServer(ctx context.Context, pconfig *redfishProvider, endpoint string) (func(), error) {
+	ctx, cancel := pconfig.Locking.context(ctx)
+	defer cancel()
+	if err := redfishMutexKV.RLockContext(ctx, endpoint); err != nil {
+		return nil, pconfig.Locking.error(endpoint, err)
+	}
+	return func() { redfishMutexKV.RUnlock(endpoint) }, nil
+}
+
+// context returns the context bounding the wait for a lock
+func (p lockPolicy) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.timeout)
+}
+
+// error explains why the lock could not be taken
+func (p lockPolicy) error(endpoint string, err error) error {
+	if p.timeout == 0 || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var held *mutexkv.HeldError
+	if errors.As(err, &held) {
+		// The lock is held by another terraform run sharing the lock directory
+		return fmt.Errorf("timed out after %s waiting for another terraform run on %s to complete: %s. "+
+			"Increase locking.timeout if the operations of the server take longer", p.timeout, endpoint, held.Error())
+	}
+	return fmt.Errorf("timed out after %s waiting for another operation on %s to complete. "+
+		"Increase locking.timeout if the operations of the server take longer", p.timeout, endpoint)
+}
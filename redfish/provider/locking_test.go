@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://mozilla.org/MPL/2.0/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// endpointWithPort returns the host and port of the endpoint, with the HTTPS port unless it has a port
+func endpointWithPort(endpoint string) string {
+	u, err := url.Parse("https://" + endpoint)
+	if err != nil {
+		return endpoint
+	}
+	if len(u.Port()) > 0 {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+func TestEndpointWithPort(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.1":       "10.0.0.1:443",
+		"10.0.0.1:8443":  "10.0.0.1:8443",
+		"bmc.example":    "bmc.example:443",
+		"[fd00::1]":      "[fd00::1]:443",
+		"[fd00::1]:8443": "[fd00::1]:8443",
+	}
+	for endpoint, expected := range tests {
+		if got := endpointWithPort(endpoint); got != expected {
+			t.Errorf("endpointWithPort(%s) = %s, expected %s", endpoint, got, expected)
+		}
+	}
+}
+
+func TestAccRedfishLocking_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishLockingConfig(creds),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios", "odata_id"),
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios_port", "odata_id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedfishLocking_directory(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishLockDirectoryConfig(creds, t.TempDir()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios", "odata_id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedfishLockingConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  locking {
+			timeout = 600
+			max_parallel_reads = 1
+		  }
+		}
+
+		data "redfish_bios" "bios" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+
+		data "redfish_bios" "bios_port" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		testingInfo.Username,
+		testingInfo.Password,
+		endpointWithPort(testingInfo.Endpoint),
+	)
+}
+
+func testAccRedfishLockDirectoryConfig(testingInfo TestingServerCredentials, directory string) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  locking {
+			directory = "%s"
+			timeout = 600
+		  }
+		}
+
+		data "redfish_bios" "bios" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+		`,
+		directory,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/stmcginnis/gofish"
+)
+
+// selectQuery returns the $select query of the properties, or an empty string when the service root does not
+// advertise it in ProtocolFeaturesSupported
+func selectQuery(service *gofish.Service, properties []string) string {
+	if len(properties) == 0 || !service.ProtocolFeaturesSupported.SelectQuery {
+		return ""
+	}
+	return "$select=" + strings.Join(properties, ",")
+}
+
+// getRedfishDocument returns the JSON document of a Redfish resource, with the optional query parameters
+func getRedfishDocument(service *gofish.Service, uri string, query ...string) (map[string]interface{}, error) {
+	target := uri
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+	resp, err := service.GetClient().Get(target)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var document map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", uri, err)
+	}
+	return document, nil
+}
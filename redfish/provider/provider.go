@@ -22,19 +22,34 @@ import (
 	// "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"context"
+	"terraform-provider-redfish/events"
 	"terraform-provider-redfish/mutexkv"
 	"terraform-provider-redfish/redfish/models"
+	"terraform-provider-redfish/registry"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // This is a global MutexKV for use within this plugin
 var redfishMutexKV = mutexkv.NewMutexKV()
 
+// redfishRegistries caches the registries of the servers for the whole plugin, so that the servers
+// running the same firmware share them
+var redfishRegistries = registry.NewCache()
+
+// redfishEventListeners shares the event listeners between the resources waiting on the same address
+var redfishEventListeners = events.NewPool()
+
 // Ensure the implementation satisfies the provider.Provider interface.
 var _ provider.Provider = &redfishProvider{}
 
@@ -44,8 +59,23 @@ func New() provider.Provider {
 }
 
 type redfishProvider struct {
-	Username string
-	Password string
+	Username   string
+	Password   string
+	AuthMethod string
+	// TLS settings used by servers which do not set their own
+	CACertificate          string
+	TLSServerName          string
+	CertificateFingerprint string
+	ClientCertificate      string
+	ClientKey              string
+	// Retry tells which requests are sent again when the BMC is temporarily unable to process them
+	Retry retryPolicy
+	// Locking tells how long operations wait for each other on a server
+	Locking lockPolicy
+	// Cassette tells whether the Redfish traffic is recorded or replayed
+	Cassette cassettePolicy
+	// Servers holds the named servers that resources can reference with the server attribute
+	Servers map[string]models.RedfishServer
 }
 
 // Metadata - provider metadata AKA name.
@@ -69,6 +99,251 @@ func (*redfishProvider) Schema(ctx context.Context, _ provider.SchemaRequest, re
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"auth_method": schema.StringAttribute{
+				MarkdownDescription: "This field is the authentication method used against the redfish API. " +
+					"Applicable values are 'basic' and 'session'. With 'session', one Redfish session is created per endpoint " +
+					"and shared by all resources and data sources. Default is \"basic\".",
+				Description: "This field is the authentication method used against the redfish API. " +
+					"Applicable values are 'basic' and 'session'. With 'session', one Redfish session is created per endpoint " +
+					"and shared by all resources and data sources. Default is \"basic\".",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(authMethodBasic, authMethodSession),
+				},
+			},
+			"redfish_servers": schema.MapNestedAttribute{
+				MarkdownDescription: "Map of named servers. Resources and data sources can reference a server by its name " +
+					"with the server attribute instead of using the redfish_server block. " +
+					"Servers defined here take precedence over servers with the same name in the inventory_file.",
+				Description: "Map of named servers. Resources and data sources can reference a server by its name " +
+					"with the server attribute instead of using the redfish_server block. " +
+					"Servers defined here take precedence over servers with the same name in the inventory_file.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"user": schema.StringAttribute{
+							Optional:    true,
+							Description: "User name for login",
+						},
+						"password": schema.StringAttribute{
+							Optional:    true,
+							Description: "User password for login",
+							Sensitive:   true,
+						},
+						"endpoint": schema.StringAttribute{
+							Required:    true,
+							Description: "Server BMC IP address or hostname",
+						},
+						"ssl_insecure": schema.BoolAttribute{
+							Optional:    true,
+							Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
+						},
+						"auth_method": schema.StringAttribute{
+							Optional:    true,
+							Description: "Authentication method used against the server BMC. Applicable values are 'basic' and 'session'.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(authMethodBasic, authMethodSession),
+							},
+						},
+						"ca_certificate": schema.StringAttribute{
+							Optional:    true,
+							Description: caCertificateMD,
+						},
+						"tls_server_name": schema.StringAttribute{
+							Optional:    true,
+							Description: tlsServerNameMD,
+						},
+						"certificate_fingerprint": schema.StringAttribute{
+							Optional:    true,
+							Description: certificateFingerprintMD,
+						},
+						"client_certificate": schema.StringAttribute{
+							Optional:    true,
+							Description: clientCertificateMD,
+						},
+						"client_key": schema.StringAttribute{
+							Optional:    true,
+							Description: clientKeyMD,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+			"inventory_file": schema.StringAttribute{
+				MarkdownDescription: "Path to an inventory file of named servers, in YAML (.yaml, .yml), JSON (.json) or CSV (.csv) format. " +
+					"YAML and JSON files hold a map of server names to their redfish_server attributes. " +
+					"CSV files have a header row with the name and endpoint columns, and optionally columns named after " +
+					"the other redfish_server attributes.",
+				Description: "Path to an inventory file of named servers, in YAML (.yaml, .yml), JSON (.json) or CSV (.csv) format. " +
+					"YAML and JSON files hold a map of server names to their redfish_server attributes. " +
+					"CSV files have a header row with the name and endpoint columns, and optionally columns named after " +
+					"the other redfish_server attributes.",
+				Optional: true,
+			},
+			"registry_cache_directory": schema.StringAttribute{
+				MarkdownDescription: "Directory where the attribute and message registries read from the servers are kept, so that " +
+					"the next runs do not download them again. The registries are always shared by the servers of a run " +
+					"with the same registry version and model. Default is no directory.",
+				Description: "Directory where the attribute and message registries read from the servers are kept, so that " +
+					"the next runs do not download them again. The registries are always shared by the servers of a run " +
+					"with the same registry version and model. Default is no directory.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"ca_certificate": schema.StringAttribute{
+				MarkdownDescription: caCertificateMD + " Applies to every server which does not set its own.",
+				Description:         caCertificateMD + " Applies to every server which does not set its own.",
+				Optional:            true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: tlsServerNameMD + " Applies to every server which does not set its own.",
+				Description:         tlsServerNameMD + " Applies to every server which does not set its own.",
+				Optional:            true,
+			},
+			"certificate_fingerprint": schema.StringAttribute{
+				MarkdownDescription: certificateFingerprintMD + " Applies to every server which does not set its own.",
+				Description:         certificateFingerprintMD + " Applies to every server which does not set its own.",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: clientCertificateMD + " Applies to every server which does not set its own.",
+				Description:         clientCertificateMD + " Applies to every server which does not set its own.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: clientKeyMD + " Applies to every server which does not set its own.",
+				Description:         clientKeyMD + " Applies to every server which does not set its own.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"cassette": schema.ListNestedBlock{
+				MarkdownDescription: "Recording of the Redfish requests and responses to a cassette file, to reproduce the " +
+					"behavior of a server without it. Credentials are redacted from the cassette. " +
+					"The `REDFISH_CASSETTE` and `REDFISH_CASSETTE_MODE` environment variables are used when the block is not set.",
+				Description: "Recording of the Redfish requests and responses to a cassette file, to reproduce the " +
+					"behavior of a server without it. Credentials are redacted from the cassette. " +
+					"The REDFISH_CASSETTE and REDFISH_CASSETTE_MODE environment variables are used when the block is not set.",
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Required:    true,
+							Description: "Path of the cassette file.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"mode": schema.StringAttribute{
+							Optional: true,
+							Description: "record sends the requests to the servers and writes them with their responses to the " +
+								"cassette, replacing it. replay answers the requests with the responses of the cassette, " +
+								"without sending them. Default is record.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(cassetteModeRecord, cassetteModeReplay),
+							},
+						},
+					},
+				},
+			},
+			"locking": schema.ListNestedBlock{
+				MarkdownDescription: "Locking of the servers. Operations changing a server wait for every other operation on it, " +
+					"while data sources read a server in parallel.",
+				Description: "Locking of the servers. Operations changing a server wait for every other operation on it, " +
+					"while data sources read a server in parallel.",
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"timeout": schema.Int64Attribute{
+							Optional: true,
+							Description: "Maximum wait in seconds for the lock of a server, so that an operation stuck on a server " +
+								"does not hold the whole run. Default is 0, which waits until the operation completes.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+						"max_parallel_reads": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Maximum number of data sources reading a server in parallel. Default is 0, which is unlimited.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+						"directory": schema.StringAttribute{
+							Optional: true,
+							Description: "Directory shared by the terraform runs managing the same servers, such as the CI jobs of " +
+								"several workspaces. When set, the locks of a server are also taken as advisory file locks (flock) " +
+								"in this directory, so that the runs wait for each other. Not supported on Windows.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+			"retry": schema.ListNestedBlock{
+				MarkdownDescription: "Retry of the requests the server BMCs are temporarily unable to process. " +
+					"Requests are retried with an exponential backoff and a random jitter. " +
+					"Retries are enabled with the default values when the block is not set.",
+				Description: "Retry of the requests the server BMCs are temporarily unable to process. " +
+					"Requests are retried with an exponential backoff and a random jitter. " +
+					"Retries are enabled with the default values when the block is not set.",
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"max_attempts": schema.Int64Attribute{
+							Optional: true,
+							Description: "Maximum number of attempts of a request, including the first one. " +
+								"1 disables the retries. Default is 3.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"min_backoff": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Wait before the first retry in seconds, doubled at each retry. Default is 1.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+						"max_backoff": schema.Int64Attribute{
+							Optional: true,
+							Description: "Maximum wait between two attempts in seconds, at least min_backoff. " +
+								"It also bounds the wait asked by the Retry-After header. Default is 30, or min_backoff if greater.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+								int64validator.AtLeastSumOf(path.MatchRelative().AtParent().AtName("min_backoff")),
+							},
+						},
+						"retryable_status_codes": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.Int64Type,
+							Description: "HTTP status codes of the responses to retry. Default is [429, 502, 503, 504]. " +
+								"Requests with side effects, such as POST and PATCH, are only retried on 429 and 503, " +
+								"which tell that the BMC did not process them, so that they are never run twice.",
+							Validators: []validator.List{
+								listvalidator.ValueInt64sAre(int64validator.Between(400, 599)),
+							},
+						},
+						"retryable_message_ids": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Redfish MessageIds of the error responses to retry. An ID matches either the full MessageId " +
+								"or its last segment, so that SYS410 matches IDRAC.2.8.SYS410. " +
+								"Default is [\"SYS410\", \"ServiceTemporarilyUnavailable\"].",
+						},
+					},
+				},
+			},
 		},
 	}
 	tflog.Trace(ctx, "resource schema created")
@@ -101,8 +376,59 @@ func (p *redfishProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	if config.RedfishServers.IsUnknown() || config.InventoryFile.IsUnknown() {
+		// Cannot resolve servers with an unknown value
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown value as redfish_servers or inventory_file",
+		)
+		return
+	}
+
 	p.Username = config.Username.ValueString()
 	p.Password = config.Password.ValueString()
+	p.AuthMethod = config.AuthMethod.ValueString()
+	p.CACertificate = config.CACertificate.ValueString()
+	p.TLSServerName = config.TLSServerName.ValueString()
+	p.CertificateFingerprint = config.CertificateFingerprint.ValueString()
+	p.ClientCertificate = config.ClientCertificate.ValueString()
+	p.ClientKey = config.ClientKey.ValueString()
+	p.Retry = newRetryPolicy(config.Retry)
+	locking, err := newLockPolicy(config.Locking)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("locking").AtListIndex(0).AtName("directory"),
+			"Unable to set the lock directory", err.Error())
+		return
+	}
+	p.Locking = locking
+	p.Cassette = newCassettePolicy(config.Cassette)
+	if err := redfishRegistries.SetDirectory(config.RegistryCacheDirectory.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("registry_cache_directory"),
+			"Unable to set the registry cache directory", err.Error())
+		return
+	}
+
+	p.Servers = make(map[string]models.RedfishServer)
+	if len(config.InventoryFile.ValueString()) > 0 {
+		inventory, err := loadServerInventory(config.InventoryFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("inventory_file"), "Unable to load inventory file", err.Error())
+			return
+		}
+		for name, server := range inventory {
+			p.Servers[name] = server
+		}
+	}
+	if !config.RedfishServers.IsNull() {
+		servers := make(map[string]models.RedfishServer)
+		resp.Diagnostics.Append(config.RedfishServers.ElementsAs(ctx, &servers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for name, server := range servers {
+			p.Servers[name] = server
+		}
+	}
 
 	resp.ResourceData = p
 	resp.DataSourceData = p
@@ -131,6 +457,9 @@ func (*redfishProvider) Resources(_ context.Context) []func() resource.Resource
 		NewUserAccountPasswordResource,
 		NewScpImportResource,
 		NewScpExportResource,
+		NewRedfishResource,
+		NewEventSubscriptionResource,
+		NewWaitForEventResource,
 	}
 }
 
@@ -143,5 +472,6 @@ func (*redfishProvider) DataSources(_ context.Context) []func() datasource.DataS
 		NewDellVirtualMediaDatasource,
 		NewSystemBootDatasource,
 		NewFirmwareInventoryDatasource,
+		NewGenericDatasource,
 	}
 }
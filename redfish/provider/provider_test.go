@@ -51,6 +51,7 @@ func init() {
 	if err != nil {
 		fmt.Println(err.Error())
 	}
+	startEmulators()
 
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 		// newProvider is an example function that returns a tfsdk.Provider
@@ -0,0 +1,257 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/registry"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+)
+
+const (
+	// redfishErrorStart marks the beginning of a Redfish error response in an error message
+	redfishErrorStart = `{"error"`
+	severityOK        = "OK"
+	// severityInformational is the severity of the informational messages of the Dell registries
+	severityInformational = "Informational"
+)
+
+// redfishErrorMessage is a message of the @Message.ExtendedInfo of a Redfish error response
+type redfishErrorMessage struct {
+	MessageID       string `json:"MessageId"`
+	Message         string
+	MessageArgs     []string
+	Resolution      string
+	MessageSeverity string
+	// Severity is deprecated in favor of MessageSeverity, older services only have it
+	Severity          string
+	RelatedProperties []string
+}
+
+// severity returns the severity of the message, from MessageSeverity or else from the deprecated Severity
+func (m redfishErrorMessage) severity() string {
+	if len(m.MessageSeverity) > 0 {
+		return m.MessageSeverity
+	}
+	return m.Severity
+}
+
+// redfishError is the error object of a Redfish error response
+type redfishError struct {
+	Code         string                `json:"code"`
+	Message      string                `json:"message"`
+	ExtendedInfo []redfishErrorMessage `json:"@Message.ExtendedInfo"`
+}
+
+// propertyPathFunc returns the path of the schema attribute holding the Redfish property
+// designated by a JSON pointer of RelatedProperties, such as "#/Attributes/BootMode"
+type propertyPathFunc func(pointer string) (path.Path, bool)
+
+// propertyPaths returns a propertyPathFunc for top level Redfish properties.
+// The map keys are the Redfish property names and the values the schema attribute names.
+func propertyPaths(attributes map[string]string) propertyPathFunc {
+	return func(pointer string) (path.Path, bool) {
+		attribute, ok := attributes[strings.TrimLeft(pointer, "#/")]
+		if !ok {
+			return path.Empty(), false
+		}
+		return path.Root(attribute), true
+	}
+}
+
+// attributesPropertyPath returns the element of the attributes map of the schema for the
+// properties of an Attributes object, such as "#/Attributes/BootMode"
+func attributesPropertyPath(pointer string) (path.Path, bool) {
+	attribute, ok := strings.CutPrefix(strings.TrimLeft(pointer, "#/"), "Attributes/")
+	if !ok {
+		return path.Empty(), false
+	}
+	return path.Root("attributes").AtMapKey(attribute), true
+}
+
+// parseRedfishError returns the Redfish error response held by err, if any.
+// gofish errors keep the response body, it is also looked for in the message of wrapped errors.
+func parseRedfishError(err error) (*redfishError, bool) {
+	body := err.Error()
+	var gofishErr *redfishcommon.Error
+	if errors.As(err, &gofishErr) {
+		body = strings.TrimPrefix(gofishErr.Error(), fmt.Sprintf("%d: ", gofishErr.HTTPReturnedStatusCode))
+	}
+
+	start := strings.Index(body, redfishErrorStart)
+	if start < 0 {
+		return nil, false
+	}
+	var response struct {
+		Error *redfishError `json:"error"`
+	}
+	// The decoder stops at the end of the response, whatever follows in the message
+	if err := json.NewDecoder(strings.NewReader(body[start:])).Decode(&response); err != nil || response.Error == nil {
+		return nil, false
+	}
+	return response.Error, true
+}
+
+// redfishErrorDiagnostics converts err into diagnostics. A Redfish error response gives one diagnostic per message
+// of its @Message.ExtendedInfo, with the Resolution as detail. The diagnostic is attached to the attribute returned by
+// paths for the first RelatedProperties it knows, paths may be nil. Other errors give a single diagnostic.
+func redfishErrorDiagnostics(summary string, err error, paths propertyPathFunc) diag.Diagnostics {
+	var diags diag.Diagnostics
+	response, ok := parseRedfishError(err)
+	if !ok {
+		diags.AddError(summary, err.Error())
+		return diags
+	}
+
+	for _, msg := range response.ExtendedInfo {
+		msgSummary := fmt.Sprintf("%s: %s", summary, msg.Message)
+		detail := msg.Resolution
+		if len(detail) == 0 {
+			detail = fmt.Sprintf("MessageId: %s", msg.MessageID)
+		}
+		attrPath, hasPath := relatedPath(msg.RelatedProperties, paths)
+		// Messages of an error response are errors, even with a Warning severity such as Base.PropertyValueNotInList
+		informational := msg.severity() == severityOK
+		switch {
+		case informational && hasPath:
+			diags.AddAttributeWarning(attrPath, msgSummary, detail)
+		case informational:
+			diags.AddWarning(msgSummary, detail)
+		case hasPath:
+			diags.AddAttributeError(attrPath, msgSummary, detail)
+		default:
+			diags.AddError(msgSummary, detail)
+		}
+	}
+
+	// The request failed even if the service only reported informational messages
+	if !diags.HasError() {
+		message := response.Message
+		if len(message) == 0 {
+			message = err.Error()
+		}
+		diags.AddError(summary, message)
+	}
+	return diags
+}
+
+// relatedPath returns the schema attribute of the first related property known by paths
+func relatedPath(properties []string, paths propertyPathFunc) (path.Path, bool) {
+	if paths == nil {
+		return path.Empty(), false
+	}
+	for _, property := range properties {
+		if attrPath, ok := paths(property); ok {
+			return attrPath, true
+		}
+	}
+	return path.Empty(), false
+}
+
+// resolveJobError expands the messages of a failed job with the message registries of the service. The messages
+// get the text, severity and resolution of their MessageId when the service left them out. The MessageIds without
+// registry of the Dell jobs, such as SYS051, are looked up in the registry of the other messages of the job. Other
+// errors are returned unchanged.
+func resolveJobError(service *gofish.Service, err error) error {
+	var jobErr *common.JobError
+	if !errors.As(err, &jobErr) {
+		return err
+	}
+	var jobRegistry string
+	for _, msg := range jobErr.Messages {
+		if jobRegistry = registry.MessageRegistry(msg.MessageID); len(jobRegistry) > 0 {
+			break
+		}
+	}
+	resolver := redfishRegistries.MessageResolver(service)
+	for i := range jobErr.Messages {
+		msg := &jobErr.Messages[i]
+		if len(msg.MessageID) == 0 || (len(msg.Message) > 0 && len(msg.Severity) > 0 && len(msg.Resolution) > 0) {
+			continue
+		}
+		resolved, resolveErr := resolver.ResolveIn(jobRegistry, msg.MessageID, msg.MessageArgs)
+		if resolveErr != nil {
+			continue
+		}
+		if len(msg.Message) == 0 {
+			msg.Message = resolved.Message
+		}
+		if len(msg.Severity) == 0 {
+			msg.Severity = resolved.Severity
+		}
+		if len(msg.Resolution) == 0 {
+			msg.Resolution = resolved.Resolution
+		}
+	}
+	return err
+}
+
+// jobErrorDiagnostics converts the error of a job into diagnostics. A failed job gives one diagnostic per message,
+// expanded with the message registries of the service, with its severity, resolution and MessageId as detail.
+// Other errors give a single diagnostic.
+func jobErrorDiagnostics(service *gofish.Service, summary string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var jobErr *common.JobError
+	if !errors.As(resolveJobError(service, err), &jobErr) || len(jobErr.Messages) == 0 {
+		diags.AddError(summary, err.Error())
+		return diags
+	}
+
+	for _, msg := range jobErr.Messages {
+		text := msg.Message
+		if len(text) == 0 {
+			text = msg.MessageID
+		}
+		var details []string
+		for _, detail := range [][2]string{
+			{"Severity", msg.Severity}, {"Resolution", msg.Resolution}, {"MessageId", msg.MessageID},
+		} {
+			if len(detail[1]) > 0 {
+				details = append(details, fmt.Sprintf("%s: %s", detail[0], detail[1]))
+			}
+		}
+		msgSummary := fmt.Sprintf("%s: %s", summary, text)
+		if msg.Severity == severityOK || msg.Severity == severityInformational {
+			diags.AddWarning(msgSummary, strings.Join(details, "\n"))
+		} else {
+			diags.AddError(msgSummary, strings.Join(details, "\n"))
+		}
+	}
+
+	// The job failed even if it only reported informational messages
+	if !diags.HasError() {
+		diags.AddError(summary, fmt.Sprintf(common.JobErrorWithState, jobErr.State))
+	}
+	return diags
+}
+
+// isNotFound tells whether the error is a Redfish 404 Not Found
+func isNotFound(err error) bool {
+	var redfishErr *redfishcommon.Error
+	return errors.As(err, &redfishErr) && redfishErr.HTTPReturnedStatusCode == http.StatusNotFound
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/emulator"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+)
+
+const extendedError = `{"error": {"code": "Base.1.12.GeneralError", "message": "A general error has occurred.",
+	"@Message.ExtendedInfo": [%s]}}`
+
+func TestParseRedfishError(t *testing.T) {
+	body := fmt.Sprintf(extendedError, `{"MessageId": "Base.1.12.PropertyUnknown", "Message": "The property Foo is unknown."}`)
+	tests := []struct {
+		name     string
+		err      error
+		ok       bool
+		messages int
+	}{
+		{name: "gofish error", err: redfishcommon.ConstructError(400, []byte(body)), ok: true, messages: 1},
+		{name: "wrapped error", err: fmt.Errorf("unable to patch: %w", redfishcommon.ConstructError(400, []byte(body))), ok: true, messages: 1},
+		{name: "error message", err: fmt.Errorf("400: %s and then some", body), ok: true, messages: 1},
+		{name: "without extended info", err: errors.New(`{"error": {"code": "Base.1.12.GeneralError", "message": "Failed."}}`), ok: true},
+		{name: "plain error", err: errors.New("connection refused")},
+		{name: "malformed response", err: errors.New(`400: {"error": {"code": `)},
+		{name: "other JSON", err: errors.New(`{"error": "unexpected"}`)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, ok := parseRedfishError(test.err)
+			if ok != test.ok {
+				t.Fatalf("expected %t, got %t", test.ok, ok)
+			}
+			if ok && len(response.ExtendedInfo) != test.messages {
+				t.Errorf("expected %d messages, got %v", test.messages, response.ExtendedInfo)
+			}
+		})
+	}
+}
+
+func TestRedfishErrorDiagnostics(t *testing.T) {
+	paths := propertyPaths(map[string]string{"UserName": "username"})
+	tests := []struct {
+		name     string
+		messages string
+		// diagnostics are the expected severity, summary, detail and attribute of the diagnostics
+		diagnostics []expectedDiagnostic
+	}{
+		{
+			name: "message severity",
+			messages: `{"MessageId": "Base.1.12.PropertyValueNotInList", "Message": "The value Maybe is not in the list.",
+				"MessageSeverity": "Warning", "Resolution": "Choose a value from the list.", "RelatedProperties": ["#/UserName"]}`,
+			diagnostics: []expectedDiagnostic{{diag.SeverityError, "Failed: The value Maybe is not in the list.",
+				"Choose a value from the list.", path.Root("username")}},
+		},
+		{
+			name:     "deprecated severity",
+			messages: `{"MessageId": "Base.1.12.Success", "Message": "Done.", "Severity": "OK"}`,
+			diagnostics: []expectedDiagnostic{
+				{diag.SeverityWarning, "Failed: Done.", "MessageId: Base.1.12.Success", path.Empty()},
+				{diag.SeverityError, "Failed", "A general error has occurred.", path.Empty()},
+			},
+		},
+		{
+			name:        "message severity over the deprecated severity",
+			messages:    `{"MessageId": "IDRAC.2.8.SYS410", "Message": "iDRAC is not ready.", "MessageSeverity": "Critical", "Severity": "OK"}`,
+			diagnostics: []expectedDiagnostic{{diag.SeverityError, "Failed: iDRAC is not ready.", "MessageId: IDRAC.2.8.SYS410", path.Empty()}},
+		},
+		{
+			name: "informational message of a property",
+			messages: `{"MessageId": "Base.1.12.PropertyValueModified", "Message": "The property was modified.", "MessageSeverity": "OK",
+				"RelatedProperties": ["#/Unknown", "#/UserName"]}`,
+			diagnostics: []expectedDiagnostic{
+				{diag.SeverityWarning, "Failed: The property was modified.", "MessageId: Base.1.12.PropertyValueModified", path.Root("username")},
+				{diag.SeverityError, "Failed", "A general error has occurred.", path.Empty()},
+			},
+		},
+		{
+			name:     "several messages",
+			messages: `{"Message": "First.", "MessageSeverity": "Critical"}, {"Message": "Second.", "MessageSeverity": "Warning"}`,
+			diagnostics: []expectedDiagnostic{
+				{diag.SeverityError, "Failed: First.", "MessageId: ", path.Empty()},
+				{diag.SeverityError, "Failed: Second.", "MessageId: ", path.Empty()},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := redfishcommon.ConstructError(400, []byte(fmt.Sprintf(extendedError, test.messages)))
+			checkDiagnostics(t, redfishErrorDiagnostics("Failed", err, paths), test.diagnostics)
+		})
+	}
+
+	// other errors give a single diagnostic
+	checkDiagnostics(t, redfishErrorDiagnostics("Failed", errors.New("connection refused"), paths),
+		[]expectedDiagnostic{{diag.SeverityError, "Failed", "connection refused", path.Empty()}})
+}
+
+func TestJobErrorDiagnostics(t *testing.T) {
+	err := fmt.Errorf("the import failed: %w", &common.JobError{State: "Exception", Messages: []redfishcommon.Message{
+		{Message: "Import of the profile completed with errors.", MessageID: "IDRAC.2.8.SYS053", Severity: "Warning", Resolution: "Review the profile."},
+		{Message: "The job was created.", MessageID: "IDRAC.2.8.JCP001", Severity: "Informational", Resolution: "No response action is required."},
+	}})
+	checkDiagnostics(t, jobErrorDiagnostics(nil, "Failed", err), []expectedDiagnostic{
+		{diag.SeverityError, "Failed: Import of the profile completed with errors.",
+			"Severity: Warning\nResolution: Review the profile.\nMessageId: IDRAC.2.8.SYS053", path.Empty()},
+		{diag.SeverityWarning, "Failed: The job was created.",
+			"Severity: Informational\nResolution: No response action is required.\nMessageId: IDRAC.2.8.JCP001", path.Empty()},
+	})
+
+	checkDiagnostics(t, jobErrorDiagnostics(nil, "Failed", &common.JobError{State: "Killed"}), []expectedDiagnostic{
+		{diag.SeverityError, "Failed", "the job has finished unsucessfully with a Killed state", path.Empty()},
+	})
+}
+
+func TestResolveJobError(t *testing.T) {
+	_, service := emulator.Connect(t)
+	err := resolveJobError(service, &common.JobError{State: "Failed", Messages: []redfishcommon.Message{
+		{MessageID: "IDRAC.2.8.SYS053"},
+		// the message of the Dell job, without registry
+		{MessageID: "SYS044", MessageArgs: []string{"profile.xml"}},
+	}})
+	var jobErr *common.JobError
+	if !errors.As(err, &jobErr) {
+		t.Fatalf("expected a job error, got %v", err)
+	}
+	msg := jobErr.Messages[1]
+	if msg.Message != "Unable to find the Server Configuration Profile file profile.xml on the network share." ||
+		msg.Severity != "Critical" || msg.MessageID != "SYS044" {
+		t.Errorf("the message without registry was not resolved: %+v", msg)
+	}
+	if len(jobErr.Messages[0].Message) == 0 {
+		t.Errorf("the message with a registry was not resolved: %+v", jobErr.Messages[0])
+	}
+}
+
+type expectedDiagnostic struct {
+	severity  diag.Severity
+	summary   string
+	detail    string
+	attribute path.Path
+}
+
+func checkDiagnostics(t *testing.T, diags diag.Diagnostics, expected []expectedDiagnostic) {
+	t.Helper()
+	if len(diags) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), diags)
+	}
+	for i, d := range diags {
+		e := expected[i]
+		attribute := path.Empty()
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			attribute = withPath.Path()
+		}
+		if d.Severity() != e.severity || d.Summary() != e.summary || d.Detail() != e.detail || !attribute.Equal(e.attribute) {
+			t.Errorf("expected %v %q %q %s, got %v %q %q %s", e.severity, e.summary, e.detail, e.attribute,
+				d.Severity(), d.Summary(), d.Detail(), attribute)
+		}
+	}
+}
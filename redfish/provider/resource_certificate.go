@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -84,6 +85,8 @@ func (*certificateResource) Schema(_ context.Context, _ resource.SchemaRequest,
 // RedfishSSLCertificateSchema is a function that returns the schema for RedfishSSLCertificate
 func RedfishSSLCertificateSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID",
 			Description:         "ID",
@@ -151,8 +154,12 @@ func (r *certificateResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
 	payload := models.SSLCertificate{
 		CertificateType:    plan.CertificateType.ValueString(),
@@ -163,7 +170,9 @@ func (r *certificateResource) Create(ctx context.Context, req resource.CreateReq
 	params := CertUtilsParam{
 		ctx:     ctx,
 		pconfig: r.p,
+		server:  plan.Server,
 		rserver: &plan.RedfishServer,
+		manager: plan.ManagerID,
 		api:     createSSLCertAPI,
 		payload: payload,
 	}
@@ -208,15 +217,21 @@ func (r *certificateResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	redfishMutexKV.Lock(state.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(state.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
 	payload := strings.NewReader(`{}`)
 
 	params := CertUtilsParam{
 		ctx:     ctx,
 		pconfig: r.p,
+		server:  state.Server,
 		rserver: &state.RedfishServer,
+		manager: state.ManagerID,
 		api:     resetSSLCertAPI,
 		payload: payload,
 	}
@@ -235,22 +250,27 @@ func (r *certificateResource) Delete(ctx context.Context, req resource.DeleteReq
 type CertUtilsParam struct {
 	ctx     context.Context
 	pconfig *redfishProvider
+	server  types.String
 	rserver *[]models.RedfishServer
+	manager types.String
 	api     string
 	payload interface{}
 }
 
 func certutils(params CertUtilsParam) (ok bool, summary string, details string) {
 	// Get service
-	service, err := NewConfig(params.pconfig, params.rserver)
+	service, err := NewConfig(params.pconfig, params.server, params.rserver)
 	if err != nil {
 		return false, ServiceErrorMsg, err.Error()
 	}
-	managers, err := service.Managers()
+	if _, err := vendorSupport(service, oem.Certificates); err != nil {
+		return false, unsupportedVendorMsg, err.Error()
+	}
+	manager, err := getManagerResource(service, params.manager.ValueString(), "")
 	if err != nil {
 		return false, "Couldn't retrieve managers from redfish API: ", err.Error()
 	}
-	uri := managers[0].ODataID + params.api
+	uri := manager.ODataID + params.api
 	res, err1 := service.GetClient().Post(uri, params.payload)
 	if err1 != nil {
 		return false, "Couldn't upload certificate from redfish API: ", err1.Error()
@@ -267,7 +287,7 @@ func certutils(params CertUtilsParam) (ok bool, summary string, details string)
 	// Check iDRAC status
 	checker := ServerStatusChecker{
 		Service:  service,
-		Endpoint: (*params.rserver)[0].Endpoint.ValueString(),
+		Endpoint: getRedfishServerEndpoint(params.pconfig, params.server, *params.rserver),
 		Interval: defaultCheckInterval,
 		Timeout:  defaultCheckTimeout,
 	}
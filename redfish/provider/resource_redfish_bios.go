@@ -21,12 +21,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
-	"path"
-	"strconv"
+	"sort"
 	"strings"
 	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
+	"terraform-provider-redfish/registry"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -57,7 +57,9 @@ const (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &BiosResource{}
+	_ resource.Resource                 = &BiosResource{}
+	_ resource.ResourceWithModifyPlan   = &BiosResource{}
+	_ resource.ResourceWithUpgradeState = &BiosResource{}
 )
 
 // NewBiosResource is a helper function to simplify the provider implementation.
@@ -91,8 +93,11 @@ func (*BiosResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 			" We can Read the existing configurations or modify them using this resource.",
 		Description: "This Terraform resource is used to configure Bios attributes of the iDRAC Server." +
 			" We can Read the existing configurations or modify them using this resource.",
+		Version: attributesSchemaVersion,
 
 		Attributes: map[string]schema.Attribute{
+			"server":    RedfishServerNameSchema(),
+			"system_id": SystemIDSchema(),
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The ID of the resource.",
 				Description:         "The ID of the resource.",
@@ -101,12 +106,16 @@ func (*BiosResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"attributes": schema.MapAttribute{
-				MarkdownDescription: "The Bios attribute map.",
-				Description:         "The Bios attribute map.",
-				ElementType:         types.StringType,
-				Optional:            true,
-				Computed:            true,
+			"attributes": schema.MapNestedAttribute{
+				MarkdownDescription: "The Bios attribute map. Each value sets the member of the type of the attribute " +
+					"in the BIOS attribute registry: `int` for the Integer attributes, `bool` for the Boolean ones " +
+					"and `string` for the others.",
+				Description: "The Bios attribute map. Each value sets the member of the type of the attribute " +
+					"in the BIOS attribute registry: 'int' for the Integer attributes, 'bool' for the Boolean ones " +
+					"and 'string' for the others.",
+				NestedObject: attributeValueSchema(),
+				Optional:     true,
+				Computed:     true,
 				PlanModifiers: []planmodifier.Map{
 					mapplanmodifier.UseStateForUnknown(),
 				},
@@ -157,6 +166,64 @@ func (*BiosResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 	}
 }
 
+// UpgradeState upgrades the state of the version 0 schema, whose attributes were strings
+func (r *BiosResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return attributesStateUpgrader(r, func(state *models.Bios) *types.Map { return &state.Attributes })
+}
+
+// ModifyPlan checks the planned attributes against the BIOS attribute registry of the system, so that an
+// invalid attribute fails the plan instead of the BIOS job after a reboot. The check is skipped when the
+// server is not known yet or cannot be reached, the apply reporting the issue.
+func (r *BiosResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.ctx = ctx
+	if r.p == nil || req.Plan.Raw.IsNull() || !serverKnown(ctx, req.Plan) {
+		return
+	}
+	var plan models.Bios
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Attributes.IsUnknown() || plan.SystemID.IsUnknown() {
+		return
+	}
+
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the registry check of the plan", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	bios, err := r.getBiosResource(service, plan.SystemID.ValueString())
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the registry check of the plan", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	attributeRegistry, err := redfishRegistries.AttributeRegistry(service, bios.AttributeRegistry)
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the registry check of the plan", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	resp.Diagnostics.Append(checkBiosAttributes(attributeRegistry, bios, plan.Attributes)...)
+}
+
+// checkBiosAttributes checks the attributes changing the BIOS against its registry
+func checkBiosAttributes(attributeRegistry *registry.AttributeRegistry, bios *redfish.Bios, attributes types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	values := attributeValues(attributes)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// the attributes keeping their value are not patched
+		if sameAttributeValue(bios.Attributes[name], values[name]) {
+			continue
+		}
+		if err := attributeRegistry.CheckAttribute(name, values[name]); err != nil {
+			diags.AddAttributeError(tfpath.Root("attributes").AtMapKey(name), "Invalid BIOS attribute", err.Error())
+		}
+	}
+	return diags
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *BiosResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	r.ctx = ctx
@@ -169,7 +236,7 @@ func (r *BiosResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -199,7 +266,7 @@ func (r *BiosResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -233,7 +300,7 @@ func (r *BiosResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -274,6 +341,7 @@ func (*BiosResource) ImportState(ctx context.Context, req resource.ImportStateRe
 		Password    string `json:"password"`
 		Endpoint    string `json:"endpoint"`
 		SslInsecure bool   `json:"ssl_insecure"`
+		SystemID    string `json:"system_id"`
 	}
 
 	var c creds
@@ -291,6 +359,9 @@ func (*BiosResource) ImportState(ctx context.Context, req resource.ImportStateRe
 
 	redfishServer := tfpath.Root("redfish_server")
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.SystemID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, tfpath.Root("system_id"), c.SystemID)...)
+	}
 }
 
 func (r *BiosResource) updateRedfishDellBiosAttributes(ctx context.Context, service *gofish.Service, plan *models.Bios,
@@ -299,23 +370,27 @@ func (r *BiosResource) updateRedfishDellBiosAttributes(ctx context.Context, serv
 	state := plan
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
-
-	bios, err := r.getBiosResource(service)
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
 	if err != nil {
-		diags.AddError("error fetching bios resource", err.Error())
+		diags.AddError(lockErrorMsg, err.Error())
 		return nil, diags
 	}
+	defer unlock()
 
-	attributes := make(map[string]string)
-	err = copyBiosAttributes(bios, attributes)
+	bios, err := r.getBiosResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		diags.AddError("error fetching bios resource", err.Error())
 		return nil, diags
 	}
 
-	attrsPayload, diagsAttr := getBiosAttrsToPatch(ctx, plan, attributes)
+	// the values are checked against the type of the attributes in the registry, or of their current value
+	attributeRegistry, err := redfishRegistries.AttributeRegistry(service, bios.AttributeRegistry)
+	if err != nil {
+		tflog.Debug(ctx, "Checking the BIOS attributes without registry", map[string]interface{}{"error": err.Error()})
+		attributeRegistry = nil
+	}
+
+	attrsPayload, diagsAttr := getBiosAttrsToPatch(plan, bios.Attributes, attributeRegistry)
 	diags.Append(diagsAttr...)
 	if diags.HasError() {
 		return nil, diags
@@ -330,17 +405,24 @@ func (r *BiosResource) updateRedfishDellBiosAttributes(ctx context.Context, serv
 	var biosTaskURI string
 	if len(attrsPayload) != 0 {
 		tflog.Info(ctx, "Submitting patch request for bios attributes")
-		biosTaskURI, err = r.patchBiosAttributes(plan, bios, attrsPayload)
+		vendor := oem.New(service)
+		biosTaskURI, err = r.patchBiosAttributes(vendor, plan, bios, attrsPayload)
 		if err != nil {
-			diags.AddError("error updating bios attributes", err.Error())
+			diags.Append(redfishErrorDiagnostics("error updating bios attributes", err, biosPropertyPath)...)
 			return nil, diags
 		}
 
 		tflog.Info(ctx, "Submitting patch request for bios attributes completed successfully")
+		// the version of the settings tells when the services applying them without job have applied them
+		version, err := vendor.SettingsVersion(bios.ODataID)
+		if err != nil {
+			diags.AddError("error reading the bios settings", err.Error())
+			return nil, diags
+		}
 		tflog.Info(ctx, "rebooting the server")
 		// reboot the server
-		pOp := powerOperator{ctx, service}
-		_, err := pOp.PowerOperation(resetType, resetTimeout, intervalBiosConfigJobCheckTime)
+		pOp := powerOperator{ctx, service, plan.SystemID.ValueString()}
+		_, err = pOp.PowerOperation(resetType, resetTimeout, intervalBiosConfigJobCheckTime)
 		if err != nil {
 			// TODO: handle this scenario
 			diags.AddError("there was an issue restarting the server", err.Error())
@@ -348,15 +430,27 @@ func (r *BiosResource) updateRedfishDellBiosAttributes(ctx context.Context, serv
 		}
 
 		tflog.Info(ctx, "rebooting the server completed successfully")
-		tflog.Info(ctx, "Waiting for the bios config job to finish")
-		// wait for the bios config job to finish
-		err = common.WaitForTaskToFinish(service, biosTaskURI, intervalBiosConfigJobCheckTime, biosConfigJobTimeout)
-		if err != nil {
-			diags.AddError("error waiting for Bios config monitor task to be completed", err.Error())
-			return nil, diags
+		// the services applying the settings without job have applied them during the reboot
+		if len(biosTaskURI) != 0 {
+			tflog.Info(ctx, "Waiting for the bios config job to finish")
+			// wait for the bios config job to finish
+			_, err = vendor.WaitForJob(ctx, biosTaskURI, common.JobWaitOptions{
+				Interval:          intervalBiosConfigJobCheckTime,
+				Timeout:           biosConfigJobTimeout,
+				CancelOnInterrupt: true,
+			})
+			if err != nil {
+				diags.Append(jobErrorDiagnostics(service, "error waiting for Bios config monitor task to be completed", err)...)
+				return nil, diags
+			}
+			tflog.Info(ctx, "Bios config job has completed successfully")
+			time.Sleep(60 * time.Second)
+		} else {
+			diags.Append(r.waitForBiosSettings(service, vendor, plan, bios, version)...)
+			if diags.HasError() {
+				return nil, diags
+			}
 		}
-		tflog.Info(ctx, "Bios config job has completed successfully")
-		time.Sleep(60 * time.Second)
 	} else {
 		tflog.Info(ctx, "BIOS attributes are already set")
 	}
@@ -373,40 +467,63 @@ func (r *BiosResource) updateRedfishDellBiosAttributes(ctx context.Context, serv
 	return state, nil
 }
 
-func (r *BiosResource) readRedfishDellBiosAttributes(service *gofish.Service, d *models.Bios) error {
-	bios, err := r.getBiosResource(service)
+// waitForBiosSettings waits for the services applying the pending settings without job to apply them
+func (r *BiosResource) waitForBiosSettings(service *gofish.Service, vendor oem.Vendor, plan *models.Bios, bios *redfish.Bios,
+	version string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
-		return fmt.Errorf("error fetching BIOS resource: %w", err)
+		diags.AddError("error fetching system resource", err.Error())
+		return diags
+	}
+	tflog.Info(r.ctx, "Waiting for the bios settings to be applied")
+	err = vendor.WaitForSettings(r.ctx, system, bios.ODataID, version, common.JobWaitOptions{
+		Interval: intervalBiosConfigJobCheckTime,
+		Timeout:  plan.JobTimeout.ValueInt64(),
+	})
+	if err != nil {
+		diags.AddError("error waiting for the bios settings to be applied", err.Error())
 	}
+	return diags
+}
 
-	attributes := make(map[string]string)
-	err = copyBiosAttributes(bios, attributes)
+func (r *BiosResource) readRedfishDellBiosAttributes(service *gofish.Service, d *models.Bios) error {
+	bios, err := r.getBiosResource(service, d.SystemID.ValueString())
 	if err != nil {
-		return fmt.Errorf("error fetching BIOS attributes: %w", err)
+		return fmt.Errorf("error fetching BIOS resource: %w", err)
 	}
 
 	attributesTF := make(map[string]attr.Value)
 	if !d.Attributes.IsNull() {
 		old := d.Attributes.Elements()
-		for key, value := range attributes {
-			if _, ok := old[key]; ok {
-				attributesTF[key] = types.StringValue(value)
+		for key, value := range bios.Attributes {
+			oldValue, ok := old[key]
+			if !ok {
+				continue
+			}
+			// This is done to avoid triggering an update when reading Password values,
+			// that are shown as null (nil to Go)
+			if value == nil {
+				attributesTF[key] = oldValue
+			} else {
+				attributesTF[key] = newAttributeValue(value)
 			}
 		}
 	} else {
 		// only in case of import
-		for key, value := range attributes {
-			attributesTF[key] = types.StringValue(value)
+		for key, value := range bios.Attributes {
+			attributesTF[key] = newAttributeValue(value)
 		}
 	}
 
-	d.Attributes = types.MapValueMust(types.StringType, attributesTF)
+	d.Attributes = types.MapValueMust(attributeValueType, attributesTF)
 	d.ID = types.StringValue(bios.ID)
 	return nil
 }
 
-func (r *BiosResource) getBiosResource(service *gofish.Service) (*redfish.Bios, error) {
-	system, err := getSystemResource(service)
+func (r *BiosResource) getBiosResource(service *gofish.Service, systemID string) (*redfish.Bios, error) {
+	system, err := getSystemResource(service, systemID)
 	if err != nil {
 		tflog.Trace(r.ctx, "[ERROR]: Failed to get system resource: "+err.Error())
 		return nil, err
@@ -421,58 +538,51 @@ func (r *BiosResource) getBiosResource(service *gofish.Service) (*redfish.Bios,
 	return bios, nil
 }
 
-func copyBiosAttributes(bios *redfish.Bios, attributes map[string]string) error {
-	// TODO: BIOS Attributes' values might be any of several types.
-	// terraform-sdk currently does not support a map with different
-	// value types. So we will convert int and float values to string.
-	// copy from the BIOS attributes to the new bios attributes map
-	// for key, value := range bios.Attributes {
-	for key, value := range bios.Attributes {
-		if attrVal, ok := value.(string); ok {
-			attributes[key] = attrVal
-		} else {
-			attributes[key] = fmt.Sprintf("%v", value)
-		}
-	}
-	return nil
-}
-
-func getBiosAttrsToPatch(ctx context.Context, d *models.Bios, attributes map[string]string) (map[string]interface{}, diag.Diagnostics) {
+// getBiosAttrsToPatch returns the attributes whose value changes. Their values must be set with the member of the
+// type of the attribute in the registry, or of the type of their current value when the registry is nil.
+func getBiosAttrsToPatch(d *models.Bios, attributes redfish.SettingsAttributes, attributeRegistry *registry.AttributeRegistry,
+) (map[string]interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	attrs := make(map[string]string)
 	attrsToPatch := make(map[string]interface{})
-	diags.Append(d.Attributes.ElementsAs(ctx, &attrs, true)...)
 
-	for key, newVal := range attrs {
+	for key, newVal := range attributeValues(d.Attributes) {
 		oldVal, ok := attributes[key]
 		if !ok {
 			diags.AddError("There was an issue while creating/updating bios attriutes", fmt.Sprintf("BIOS attribute %s not found", key))
 			continue
 		}
-		// check if the original value is an integer
-		// if yes, then we need to convert accordingly
-		if intOldVal, err := strconv.Atoi(attributes[key]); err == nil {
-			intNewVal, err := strconv.Atoi(newVal)
-			if err != nil {
-				err = fmt.Errorf("BIOS attribute %s is expected to be an integer: %w", key, err)
-				diags.AddError("There was an issue while creating/updating bios attriutes", err.Error())
-				continue
+		member := currentAttributeMember(oldVal)
+		if attributeRegistry != nil {
+			if attribute, err := attributeRegistry.Attribute(key); err == nil {
+				member = attributeMember(attribute.Type)
 			}
+		}
+		if err := checkAttributeMember(key, member, newVal); err != nil {
+			diags.AddError("There was an issue while creating/updating bios attriutes", err.Error())
+			continue
+		}
 
-			// Add to patch list if attribute value has changed
-			if intNewVal != intOldVal {
-				attrsToPatch[key] = intNewVal
-			}
-		} else {
-			if newVal != oldVal {
-				attrsToPatch[key] = newVal
-			}
+		// Add to patch list if attribute value has changed
+		if !sameAttributeValue(oldVal, newVal) {
+			attrsToPatch[key] = newVal
 		}
 	}
 	return attrsToPatch, diags
 }
 
-func (r *BiosResource) patchBiosAttributes(d *models.Bios, bios *redfish.Bios, attributes map[string]interface{}) (biosTaskURI string, err error) {
+// biosPropertyPath returns the schema attribute of a property of the BIOS settings
+func biosPropertyPath(pointer string) (tfpath.Path, bool) {
+	if attrPath, ok := attributesPropertyPath(pointer); ok {
+		return attrPath, true
+	}
+	if strings.HasPrefix(strings.TrimLeft(pointer, "#/"), "@Redfish.SettingsApplyTime") {
+		return tfpath.Root("settings_apply_time"), true
+	}
+	return tfpath.Empty(), false
+}
+
+func (r *BiosResource) patchBiosAttributes(vendor oem.Vendor, d *models.Bios, bios *redfish.Bios, attributes map[string]interface{},
+) (biosTaskURI string, err error) {
 	payload := make(map[string]interface{})
 	payload["Attributes"] = attributes
 
@@ -496,25 +606,17 @@ func (r *BiosResource) patchBiosAttributes(d *models.Bios, bios *redfish.Bios, a
 		"ApplyTime": settingsApplyTime,
 	}
 
-	oDataURI, err := url.Parse(bios.ODataID)
+	settingsObjectURI, err := vendor.BiosSettingsURI(bios)
 	if err != nil {
 		tflog.Trace(r.ctx, "error fetching data: "+err.Error())
 		return "", err
 	}
-	oDataURI.Path = path.Join(oDataURI.Path, "Settings")
-	settingsObjectURI := oDataURI.String()
 
-	resp, err := bios.GetClient().Patch(settingsObjectURI, payload)
+	biosTaskURI, err = vendor.ApplySettings(settingsObjectURI, payload)
 	if err != nil {
 		tflog.Trace(r.ctx, "[DEBUG] error sending the patch request:"+err.Error())
 		return "", err
 	}
-
-	// check if location is present in the response header
-	if location, err := resp.Location(); err == nil {
-		tflog.Trace(r.ctx, "[DEBUG] BIOS configuration job uri: "+location.String())
-		taskURI := location.EscapedPath()
-		return taskURI, nil
-	}
-	return "", nil
+	tflog.Trace(r.ctx, "[DEBUG] BIOS configuration job uri: "+biosTaskURI)
+	return biosTaskURI, nil
 }
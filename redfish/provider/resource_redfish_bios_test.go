@@ -35,14 +35,14 @@ func TestAccRedfishBios_basic(t *testing.T) {
 				Config: testAccRedfishResourceBiosConfigOn(
 					creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "On"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock.string", "On"),
 				),
 			},
 			{
 				Config: testAccRedfishResourceBiosConfigOff(
 					creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock.string", "Off"),
 				),
 			},
 		},
@@ -77,6 +77,28 @@ func TestAccRedfishBios_InvalidAttributes(t *testing.T) {
 	})
 }
 
+// Test to check the attributes against the registry during the plan - negative
+func TestAccRedfishBios_InvalidAttributeValue(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigInvalidAttributeValue(
+					creds),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("value Maybe of attribute NumLock is not permitted"),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigInvalidAttributeType(
+					creds),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("attribute AcPwrRcvryUserDelay is an integer"),
+			},
+		},
+	})
+}
+
 // Test to import bios - positive
 func TestAccRedfishBios_Import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
@@ -108,7 +130,7 @@ func testAccRedfishResourceBiosConfigOn(testingInfo TestingServerCredentials) st
 		  }
 
 		  attributes = {
-			"NumLock" = "On"
+			"NumLock" = { string = "On" }
 		  }
 		  reset_type = "ForceRestart"
 		}
@@ -132,8 +154,8 @@ func testAccRedfishResourceBiosConfigOff(testingInfo TestingServerCredentials) s
 		  }
 
 		  attributes = {
-			"NumLock" = "Off"
-			"AcPwrRcvryUserDelay" = 70
+			"NumLock" = { string = "Off" }
+			"AcPwrRcvryUserDelay" = { int = 70 }
 		  }
 		  reset_type = "ForceRestart"
    		  bios_job_timeout = 1200
@@ -191,3 +213,49 @@ func testAccRedfishResourceBiosConfigInvalidAttributes(testingInfo TestingServer
 		testingInfo.Endpoint,
 	)
 }
+
+func testAccRedfishResourceBiosConfigInvalidAttributeValue(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			"NumLock" = { string = "Maybe" }
+		  }
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishResourceBiosConfigInvalidAttributeType(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			"AcPwrRcvryUserDelay" = { string = "70" }
+		  }
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
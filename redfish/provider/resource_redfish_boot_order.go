@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"strings"
 	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
@@ -79,6 +80,8 @@ func (*BootOrderResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 // BootOrderSchema to define the Boot Order resource schema
 func BootOrderSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the Boot Order Resource",
 			Description:         "ID of the Boot Order Resource",
@@ -179,7 +182,7 @@ func (r *BootOrderResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -216,7 +219,7 @@ func (r *BootOrderResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -256,13 +259,13 @@ func (r *BootOrderResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, state.SystemID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[ERROR]: Failed to get updated system resource", err.Error())
 		return
@@ -286,6 +289,7 @@ func (*BootOrderResource) ImportState(ctx context.Context, req resource.ImportSt
 		Password    string `json:"password"`
 		Endpoint    string `json:"endpoint"`
 		SslInsecure bool   `json:"ssl_insecure"`
+		SystemID    string `json:"system_id"`
 	}
 
 	var c creds
@@ -302,35 +306,40 @@ func (*BootOrderResource) ImportState(ctx context.Context, req resource.ImportSt
 
 	redfishServer := tfpath.Root("redfish_server")
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.SystemID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, tfpath.Root("system_id"), c.SystemID)...)
+	}
 }
 
 func (r *BootOrderResource) bootOperation(ctx context.Context, service *gofish.Service, plan *models.BootOrder) diag.Diagnostics {
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		return diag.Diagnostics{diag.NewErrorDiagnostic(lockErrorMsg, err.Error())}
+	}
+	defer unlock()
 
-	resp, diags := r.updateRedfishDellBootAttributes(service, plan)
+	jobURI, diags := r.updateRedfishDellBootAttributes(service, plan)
 	if diags.HasError() {
 		return diags
 	}
-	diags.Append(r.restartServer(ctx, service, resp, plan)...)
+	diags.Append(r.restartServer(ctx, service, jobURI, plan)...)
 	return diags
 }
 
-func (r *BootOrderResource) updateRedfishDellBootAttributes(service *gofish.Service, d *models.BootOrder) (*http.Response, diag.Diagnostics) {
-	var resp *http.Response
+// updateRedfishDellBootAttributes updates the boot options or the boot order, and returns the URI of the job
+// applying them, which is empty when the service applies them without job
+func (r *BootOrderResource) updateRedfishDellBootAttributes(service *gofish.Service, d *models.BootOrder) (string, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	var err error
 
 	if len(d.BootOptions.Elements()) > 0 {
-		resp, diags = r.updateBootOptions(service, d)
-		return resp, diags
+		return r.updateBootOptions(service, d)
 	}
-	resp, err = r.setBootOrder(service, d)
+	jobURI, err := r.setBootOrder(service, d)
 	if err != nil {
 		diags.AddError("Boot Operation Failed", err.Error())
 	}
-	return resp, diags
+	return jobURI, diags
 }
 
 func (r *BootOrderResource) readRedfishBootAttributes(system *redfish.ComputerSystem, d,
@@ -351,6 +360,8 @@ func (r *BootOrderResource) readRedfishBootAttributes(system *redfish.ComputerSy
 
 	d.ID = types.StringValue(system.ODataID)
 	d.RedfishServer = plan.RedfishServer
+	d.Server = plan.Server
+	d.SystemID = plan.SystemID
 	if plan.JobTimeout.ValueInt64() > 0 {
 		d.JobTimeout = plan.JobTimeout
 	} else {
@@ -444,14 +455,14 @@ func getUpdatedValues(responseBootOption *redfish.BootOption, bootOptionsTypes m
 	return objVal, diags
 }
 
-func (r *BootOrderResource) updateBootOptions(service *gofish.Service, d *models.BootOrder) (*http.Response, diag.Diagnostics) {
+func (r *BootOrderResource) updateBootOptions(service *gofish.Service, d *models.BootOrder) (string, diag.Diagnostics) {
 	var url string
 	var diags diag.Diagnostics
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, d.SystemID.ValueString())
 	if err != nil {
 		diags.AddError("[ERROR]: Failed to get system resource", err.Error())
-		return nil, diags
+		return "", diags
 	}
 
 	type Payload struct {
@@ -464,12 +475,12 @@ func (r *BootOrderResource) updateBootOptions(service *gofish.Service, d *models
 	bootOptions, err := system.BootOptions()
 	if err != nil {
 		diags.AddError("unable to fetch boot Options", err.Error())
-		return nil, diags
+		return "", diags
 	}
 
 	if len(bootOptions) == 0 {
 		diags.AddError("unable to fetch boot Options Boot Options are not specified", "")
-		return nil, diags
+		return "", diags
 	}
 	url = bootOptions[0].Entity.ODataID
 	lastIndx := strings.LastIndex(url, "/")
@@ -477,7 +488,7 @@ func (r *BootOrderResource) updateBootOptions(service *gofish.Service, d *models
 
 	serverBootOptions, diags := r.getBootOptionsList(d)
 	if diags.HasError() {
-		return nil, diags
+		return "", diags
 	}
 	for _, ele := range serverBootOptions {
 		payload.BootOptionEnabled = ele.BootOptionEnabled.ValueBool()
@@ -485,17 +496,20 @@ func (r *BootOrderResource) updateBootOptions(service *gofish.Service, d *models
 		resp, err = service.GetClient().Patch(finalURL, payload)
 		if err != nil {
 			diags.AddError("Unable to update boot option data", err.Error())
-			return nil, diags
+			return "", diags
 		}
+		resp.Body.Close() // #nosec G104
+	}
+	if resp == nil {
+		return "", nil
 	}
-	return resp, nil
+	return resp.Header.Get("Location"), nil
 }
 
-func (*BootOrderResource) setBootOrder(service *gofish.Service, d *models.BootOrder) (*http.Response, error) {
-	var resp *http.Response
-	system, err := getSystemResource(service)
+func (*BootOrderResource) setBootOrder(service *gofish.Service, d *models.BootOrder) (string, error) {
+	system, err := getSystemResource(service, d.SystemID.ValueString())
 	if err != nil {
-		return nil, fmt.Errorf("[ERROR]: Failed to get system resource %w", err)
+		return "", fmt.Errorf("[ERROR]: Failed to get system resource %w", err)
 	}
 
 	boot := system.Boot
@@ -513,40 +527,30 @@ func (*BootOrderResource) setBootOrder(service *gofish.Service, d *models.BootOr
 				}
 			}
 			if !flag {
-				return nil, fmt.Errorf("new boot order and old boot order must be equal")
+				return "", fmt.Errorf("new boot order and old boot order must be equal")
 			}
 		}
 		// check if all boot devices are present
 		if len(newBootOrder) != len(existingBootOrder) {
-			return nil, fmt.Errorf("unable to complete the operation because all boot devices are required for this operation")
+			return "", fmt.Errorf("unable to complete the operation because all boot devices are required for this operation")
 		}
 	}
 
-	type Boot struct {
-		BootOrder []string
+	var bootOrder []string
+	for _, d := range newBootOrder {
+		bootOrder = append(bootOrder, strings.Trim(d.String(), "\""))
 	}
-	type Payload struct {
-		Boot Boot `json:"Boot"`
-	}
-	var payload Payload
-	if len(newBootOrder) > 0 {
-		for _, d := range newBootOrder {
-			payload.Boot.BootOrder = append(
-				payload.Boot.BootOrder, strings.Trim(d.String(), "\""),
-			)
-		}
-	}
-	resp, err = service.GetClient().Patch(system.ODataID, payload)
+	jobURI, err := oem.New(service).SetBootOrder(system, bootOrder)
 	if err != nil {
-		return resp, fmt.Errorf("cannot update boot order %w", err)
+		return "", fmt.Errorf("cannot update boot order %w", err)
 	}
-	return resp, nil
+	return jobURI, nil
 }
 
 func (r *BootOrderResource) updateServer(service *gofish.Service, plan models.BootOrder) (*models.BootOrder, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	// Fetch Updated details
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		diags.AddError("[ERROR]: Failed to get updated system resource", err.Error())
 		return nil, diags
@@ -561,28 +565,57 @@ func (r *BootOrderResource) updateServer(service *gofish.Service, plan models.Bo
 	return &state, diags
 }
 
-func (*BootOrderResource) restartServer(ctx context.Context, service *gofish.Service, resp *http.Response, plan *models.BootOrder) diag.Diagnostics {
+func (*BootOrderResource) restartServer(ctx context.Context, service *gofish.Service, jobURI string, plan *models.BootOrder) diag.Diagnostics {
 	// Power Operation parameters
 	var diags diag.Diagnostics
 	resetType := plan.ResetType.ValueString()
 	resetTimeout := plan.ResetTimeout.ValueInt64()
 	bootOrderJobTimeout := plan.JobTimeout.ValueInt64()
 
+	vendor := oem.New(service)
+	var system *redfish.ComputerSystem
+	var settingsURI, version string
+	if jobURI == "" {
+		// the services applying the settings without job apply them during the reboot, which changes their version
+		var err error
+		if system, err = getSystemResource(service, plan.SystemID.ValueString()); err != nil {
+			diags.AddError("[ERROR]: Failed to get system resource", err.Error())
+			return diags
+		}
+		if settingsURI, err = vendor.BootSettingsURI(system); err != nil {
+			diags.AddError("error reading the boot settings", err.Error())
+			return diags
+		}
+		if version, err = vendor.SettingsVersion(settingsURI); err != nil {
+			diags.AddError("error reading the boot settings", err.Error())
+			return diags
+		}
+	}
+
 	// reboot the server
-	pOp := powerOperator{ctx, service}
+	pOp := powerOperator{ctx, service, plan.SystemID.ValueString()}
 	_, err := pOp.PowerOperation(resetType, resetTimeout, intervalBootOrderJobCheckTime)
 	if err != nil {
 		diags.AddError("there was an issue restarting the server ", err.Error())
 		return diags
 	}
 
-	jobID := resp.Header.Get("Location")
-	if jobID == "" {
-		diags.AddError("this configuration is already set ", "Update the configuration and run again")
-		return nil
+	if jobURI == "" {
+		err = vendor.WaitForSettings(ctx, system, settingsURI, version, common.JobWaitOptions{
+			Interval: intervalBootOrderJobCheckTime,
+			Timeout:  bootOrderJobTimeout,
+		})
+		if err != nil {
+			diags.AddError("error waiting for the boot settings to be applied", err.Error())
+		}
+		return diags
 	}
 	// wait for the bios config job to finish
-	err = common.WaitForJobToFinish(service, jobID, intervalBootOrderJobCheckTime, bootOrderJobTimeout)
+	_, err = vendor.WaitForJob(ctx, jobURI, common.JobWaitOptions{
+		Interval:          intervalBootOrderJobCheckTime,
+		Timeout:           bootOrderJobTimeout,
+		CancelOnInterrupt: true,
+	})
 	if err != nil {
 		diags.AddError("error waiting for Bios config monitor task to be completed", err.Error())
 		return diags
@@ -24,7 +24,6 @@ import (
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -72,10 +71,7 @@ func (*BootSourceOverrideResource) Schema(_ context.Context, _ resource.SchemaRe
 			"redfish_server": schema.ListNestedBlock{
 				MarkdownDescription: "List of server BMCs and their respective user credentials",
 				Description:         "List of server BMCs and their respective user credentials",
-				Validators: []validator.List{
-					listvalidator.SizeAtMost(1),
-					listvalidator.IsRequired(),
-				},
+				Validators:          redfishServerValidators(),
 				NestedObject: schema.NestedBlockObject{
 					Attributes: RedfishServerSchema(),
 				},
@@ -90,6 +86,8 @@ func (*BootSourceOverrideResource) Schema(_ context.Context, _ resource.SchemaRe
 // BootSourceOverrideSchema to define the Boot Source Override resource schema
 func BootSourceOverrideSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the Boot Source Override Resource",
 			Description:         "ID of the Boot Source Override Resource",
@@ -221,7 +219,7 @@ func (r *BootSourceOverrideResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -281,13 +279,16 @@ func (*BootSourceOverrideResource) Read(ctx context.Context, req resource.ReadRe
 
 func (r *BootSourceOverrideResource) bootOperation(ctx context.Context, service *gofish.Service, plan *models.BootSourceOverride) diag.Diagnostics {
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		return diag.Diagnostics{diag.NewErrorDiagnostic(lockErrorMsg, err.Error())}
+	}
+	defer unlock()
 
 	var resp *http.Response
 	var diags diag.Diagnostics
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		diags.AddError("[ERROR]: Failed to get system resource", err.Error())
 		return diags
@@ -327,7 +328,7 @@ func (*BootSourceOverrideResource) restartServer(ctx context.Context, service *g
 	bootSourceOverrideJobTimeout := plan.JobTimeout.ValueInt64()
 
 	// reboot the server
-	pOp := powerOperator{ctx, service}
+	pOp := powerOperator{ctx, service, plan.SystemID.ValueString()}
 	_, err := pOp.PowerOperation(resetType, resetTimeout, intervalBootSourceOverrideJobCheckTime)
 	if err != nil {
 		diags.AddError("there was an issue restarting the server ", err.Error())
@@ -336,7 +337,11 @@ func (*BootSourceOverrideResource) restartServer(ctx context.Context, service *g
 
 	jobID := resp.Header.Get("Location")
 	// wait for the bios config job to finish
-	err = common.WaitForJobToFinish(service, jobID, intervalBootSourceOverrideJobCheckTime, bootSourceOverrideJobTimeout)
+	_, err = common.WaitForJob(ctx, service, jobID, common.JobWaitOptions{
+		Interval:          intervalBootSourceOverrideJobCheckTime,
+		Timeout:           bootSourceOverrideJobTimeout,
+		CancelOnInterrupt: true,
+	})
 	if err != nil {
 		diags.AddError("error waiting for Bios config monitor task to be completed", err.Error())
 		return diags
@@ -22,9 +22,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -39,7 +39,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &dellIdracAttributesResource{}
+	_ resource.Resource                 = &dellIdracAttributesResource{}
+	_ resource.ResourceWithUpgradeState = &dellIdracAttributesResource{}
 )
 
 // NewDellIdracAttributesResource is a helper function to simplify the provider implementation.
@@ -73,33 +74,45 @@ func (*dellIdracAttributesResource) Schema(_ context.Context, _ resource.SchemaR
 			" We can Read the existing configurations or modify them using this resource.",
 		Description: "This Terraform resource is used to configure iDRAC attributes of the iDRAC Server." +
 			" We can Read the existing configurations or modify them using this resource.",
+		Version: attributesSchemaVersion,
 
 		Attributes: DellIdracAttributesSchema(),
 		Blocks:     RedfishServerResourceBlockMap(),
 	}
 }
 
+// UpgradeState upgrades the state of the version 0 schema, whose attributes were strings
+func (r *dellIdracAttributesResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return attributesStateUpgrader(r, func(state *models.DellIdracAttributes) *types.Map { return &state.Attributes })
+}
+
 // DellIdracAttributesSchema to define the idrac attribute schema
 func DellIdracAttributesSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the iDRAC attributes resource",
 			Description:         "ID of the iDRAC attributes resource",
 			Computed:            true,
 		},
-		"attributes": schema.MapAttribute{
+		"attributes": schema.MapNestedAttribute{
 			MarkdownDescription: "iDRAC attributes. " +
 				"To check allowed attributes please either use the datasource for dell idrac attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1. " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: `int` for the Integer attributes " +
+				"and `string` for the others.",
 			Description: "iDRAC attributes. " +
 				"To check allowed attributes please either use the datasource for dell idrac attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1. " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
-			ElementType: types.StringType,
-			Required:    true,
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: 'int' for the Integer attributes " +
+				"and 'string' for the others.",
+			NestedObject: attributeValueSchema(),
+			Required:     true,
 		},
 	}
 }
@@ -115,7 +128,7 @@ func (r *dellIdracAttributesResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -144,7 +157,7 @@ func (r *dellIdracAttributesResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -176,7 +189,7 @@ func (r *dellIdracAttributesResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -217,6 +230,7 @@ func (r *dellIdracAttributesResource) ImportState(ctx context.Context, req resou
 		Password    string   `json:"password"`
 		Endpoint    string   `json:"endpoint"`
 		SslInsecure bool     `json:"ssl_insecure"`
+		ManagerID   string   `json:"manager_id"`
 		Attributes  []string `json:"attributes"`
 	}
 
@@ -246,10 +260,13 @@ func (r *dellIdracAttributesResource) ImportState(ctx context.Context, req resou
 
 	redfishServer := path.Root("redfish_server")
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.ManagerID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manager_id"), c.ManagerID)...)
+	}
 
 	attributes := path.Root("attributes")
 	if c.Attributes == nil {
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(types.StringType))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(attributeValueType))...)
 		return
 	}
 
@@ -261,7 +278,7 @@ func (r *dellIdracAttributesResource) ImportState(ctx context.Context, req resou
 
 	readAttributes := make(map[string]attr.Value)
 	for _, k := range c.Attributes {
-		readAttributes[k] = types.StringValue("")
+		readAttributes[k] = newAttributeValue("")
 	}
 
 	var idracAttr []string
@@ -277,13 +294,13 @@ func (r *dellIdracAttributesResource) ImportState(ctx context.Context, req resou
 		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(types.StringType, readAttributes))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(attributeValueType, readAttributes))...)
 }
 
 func (r *dellIdracAttributesResource) getiDRACEnv(rserver *[]models.RedfishServer) (*gofish.Service, diag.Diagnostics) {
 	var d diag.Diagnostics
 	// Get service
-	service, err := NewConfig(r.p, rserver)
+	service, err := NewConfig(r.p, types.StringNull(), rserver)
 	if err != nil {
 		d.AddError(ServiceErrorMsg, err.Error())
 		return nil, d
@@ -296,8 +313,7 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 	idracError := "there was an issue when creating/updating idrac attributes"
 	d.ID = types.StringValue("placeholder")
 	// Get attributes
-	attributesTf := make(map[string]string)
-	diags.Append(d.Attributes.ElementsAs(ctx, &attributesTf, true)...)
+	attributesTf := attributeValues(d.Attributes)
 	// get managerAttributeRegistry to check parameters before posting them to redfish
 	managerAttributeRegistry, err := getManagerAttributeRegistry(service)
 	if err != nil {
@@ -318,22 +334,15 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 		return diags
 	}
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
-	if err != nil {
-		diags.AddError(idracError, err.Error())
-		return diags
-	}
-
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
 	}
 
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
@@ -344,22 +353,12 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 		return diags
 	}
 
-	// Set the body to send
-	patchBody := struct {
-		ApplyTime  string `json:"@Redfish.OperationApplyTime"`
-		Attributes map[string]interface{}
-	}{
-		ApplyTime:  "Immediate",
-		Attributes: attributesToPatch,
-	}
-
-	response, err := service.GetClient().Patch(idracAttributes.ODataID, patchBody)
+	err = patchManagerAttributes(service, managerAttributeRegistry, idracAttributes, attributesToPatch)
 	if err != nil {
-		diags.AddError(idracError, err.Error())
+		diags.Append(redfishErrorDiagnostics(idracError, err, attributesPropertyPath)...)
 		return diags
 	}
-	response.Body.Close() // #nosec G104
-	d.ID = types.StringValue(idracAttributes.ODataID)
+	d.ID = types.StringValue(idracAttributes.URI)
 	diags = readRedfishDellIdracAttributes(ctx, service, d)
 	return diags
 }
@@ -367,22 +366,15 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 func readRedfishDellIdracAttributes(_ context.Context, service *gofish.Service, d *models.DellIdracAttributes) diag.Diagnostics {
 	var diags diag.Diagnostics
 	idracError := "there was an issue when reading idrac attributes"
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
-	if err != nil {
-		diags.AddError(idracError, err.Error())
-		return diags
-	}
-
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
 	}
 
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
@@ -404,53 +396,36 @@ func readRedfishDellIdracAttributes(_ context.Context, service *gofish.Service,
 			// This is done to avoid triggering an update when reading Password values,
 			// that are shown as null (nil to Go)
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = v.(types.String)
+				readAttributes[k] = v
 			}
 		}
 	} else {
 		for k, attrValue := range idracAttributes.Attributes {
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = types.StringValue("")
+				readAttributes[k] = newAttributeValue("")
 			}
 		}
 	}
-	d.Attributes = types.MapValueMust(types.StringType, readAttributes)
+	d.Attributes = types.MapValueMust(attributeValueType, readAttributes)
 	return diags
 }
 
-func attributeValue(attrValue interface{}, readAttributes map[string]attr.Value, k string) {
-	if _, ok := attrValue.(float64); ok {
-		readAttributes[k] = types.StringValue(fmt.Sprintf("%.0f", attrValue))
-	} else {
-		readAttributes[k] = types.StringValue(attrValue.(string))
-	}
-}
-
+// getManagerAttributeRegistry returns the manager attribute registry of the service, from the registry cache
+// when the servers share its version
 func getManagerAttributeRegistry(service *gofish.Service) (*dell.ManagerAttributeRegistry, error) {
-	registries, err := service.Registries()
-	if err != nil {
+	var managerAttributeRegistry dell.ManagerAttributeRegistry
+	if err := redfishRegistries.Decode(service, "ManagerAttributeRegistry", &managerAttributeRegistry); err != nil {
 		return nil, err
 	}
-
-	for _, r := range registries {
-		if r.ID == "ManagerAttributeRegistry" {
-			// Get ManagerAttributesRegistry
-			managerAttrRegistry, err := dell.GetDellManagerAttributeRegistry(service.GetClient(), r.Location[0].URI)
-			if err != nil {
-				return nil, err
-			}
-			return managerAttrRegistry, nil
-		}
-	}
-
-	return nil, fmt.Errorf("error. Couldn't retrieve ManagerAttributeRegistry")
+	managerAttributeRegistry.SetClient(service.GetClient())
+	return &managerAttributeRegistry, nil
 }
 
-func getIdracAttributes(attributes []*dell.Attributes) (*dell.Attributes, error) {
+func getIdracAttributes(attributes []*oem.AttributeGroup) (*oem.AttributeGroup, error) {
 	for _, a := range attributes {
 		if strings.Contains(a.ID, "iDRAC") {
 			return a, nil
@@ -475,9 +450,38 @@ func checkManagerAttributes(attrRegistry *dell.ManagerAttributeRegistry, attribu
 	return nil
 }
 
-// setManagerAttributesRightType gets a map[string]interface{} from terraform, where all keys are strings,
-// and returns a map[string]interface{} where values are either string or ints, and can be used for PATCH
-func setManagerAttributesRightType(rawAttributes map[string]string, registry *dell.ManagerAttributeRegistry) (map[string]interface{}, error) {
+// patchManagerAttributes patches the attributes changing their current value, once checked against the
+// dependencies of the registry. The attributes the other changes depend on, such as the ones enabling a feature,
+// are patched first, in their own request.
+func patchManagerAttributes(service *gofish.Service, registry *dell.ManagerAttributeRegistry, attributes *oem.AttributeGroup, values map[string]interface{}) error {
+	changes := dell.AttributesMap(attributes.Attributes).Changes(values)
+	err := registry.CheckDependencies(attributes.Attributes, changes)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range registry.PatchOrder(changes) {
+		// Set the body to send
+		patchBody := struct {
+			ApplyTime  string `json:"@Redfish.OperationApplyTime"`
+			Attributes map[string]interface{}
+		}{
+			ApplyTime:  "Immediate",
+			Attributes: batch,
+		}
+
+		response, err := service.GetClient().Patch(attributes.URI, patchBody)
+		if err != nil {
+			return err
+		}
+		response.Body.Close() // #nosec G104
+	}
+	return nil
+}
+
+// setManagerAttributesRightType checks that the values from terraform are of the type of the attributes in the
+// registry, and returns a map[string]interface{} where values are either string or ints, and can be used for PATCH
+func setManagerAttributesRightType(rawAttributes map[string]interface{}, registry *dell.ManagerAttributeRegistry) (map[string]interface{}, error) {
 	patchMap := make(map[string]interface{})
 
 	for k, v := range rawAttributes {
@@ -487,13 +491,17 @@ func setManagerAttributesRightType(rawAttributes map[string]string, registry *de
 		}
 		switch attrType {
 		case "int":
-			t, err := strconv.Atoi(v)
-			if err != nil {
-				return nil, fmt.Errorf("property %s must be an integer", k)
+			t, ok := v.(int64)
+			if !ok {
+				return nil, fmt.Errorf("property %s must be an integer, set with the %s member", k, attributeIntMember)
 			}
-			patchMap[k] = t
+			patchMap[k] = int(t)
 		case "string":
-			patchMap[k] = v
+			t, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("property %s must be a string, set with the %s member", k, attributeStringMember)
+			}
+			patchMap[k] = t
 		}
 	}
 
@@ -34,16 +34,16 @@ func TestAccRedfishIDRACAttributesBasic(t *testing.T) {
 				Config: testAccRedfishResourceIDracAttributesConfig(
 					creds, "avengers"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Users.3.Enable", "Disabled"),
-					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Time.1.Timezone", "CST6CDT"),
+					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Users.3.Enable.string", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Time.1.Timezone.string", "CST6CDT"),
 				),
 			},
 			{
 				Config: testAccRedfishResourceIDracAttributesConfig(
 					creds, "ironman"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Users.3.Enable", "Disabled"),
-					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Time.1.Timezone", "CST6CDT"),
+					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Users.3.Enable.string", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Time.1.Timezone.string", "CST6CDT"),
 				),
 			},
 		},
@@ -109,12 +109,12 @@ func testAccRedfishResourceIDracAttributesConfig(testingInfo TestingServerCreden
 		}
 	  
 		attributes = {
-		  "Users.3.Enable"    		  = "Disabled"
-		  "Users.3.UserName"  		  = "%s"
-		  "Users.3.Password"  		  = "test1234"
-		  "Users.3.Privilege" 		  = 511
-		  "Time.1.Timezone"   		  = "CST6CDT",
-		  "SysLog.1.PowerLogInterval" = 5,
+		  "Users.3.Enable"    		  = { string = "Disabled" }
+		  "Users.3.UserName"  		  = { string = "%s" }
+		  "Users.3.Password"  		  = { string = "test1234" }
+		  "Users.3.Privilege" 		  = { int = 511 }
+		  "Time.1.Timezone"   		  = { string = "CST6CDT" },
+		  "SysLog.1.PowerLogInterval" = { int = 5 },
 		}
 	  }
 	  `,
@@ -136,13 +136,13 @@ func testAccRedfishResourceIDracAttributesConfigInvalid(testingInfo TestingServe
 		}
 	  
 		attributes = {
-		  "Users.3.Enable"            = "Disabled"
-		  "Users.3.UserName"          = "mike"
-		  "Users.3.Password"          = "test1234"
-		  "Users.3.Privilege"         = 511
-		  "Time.1.Timezone"			  = "CST6CDT",
-		  "SysLog.1.PowerLogInterval" = 5,
-		  "InvalidAttribute" 		  = "invalid",
+		  "Users.3.Enable"            = { string = "Disabled" }
+		  "Users.3.UserName"          = { string = "mike" }
+		  "Users.3.Password"          = { string = "test1234" }
+		  "Users.3.Privilege"         = { int = 511 }
+		  "Time.1.Timezone"			  = { string = "CST6CDT" },
+		  "SysLog.1.PowerLogInterval" = { int = 5 },
+		  "InvalidAttribute" 		  = { string = "invalid" },
 		}
 	  }
 	  `,
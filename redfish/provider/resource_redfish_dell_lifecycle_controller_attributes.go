@@ -21,10 +21,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"slices"
 	"strings"
-	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -39,7 +38,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &dellLCAttributesResource{}
+	_ resource.Resource                 = &dellLCAttributesResource{}
+	_ resource.ResourceWithUpgradeState = &dellLCAttributesResource{}
 )
 
 // NewDellLCAttributesResource is a helper function to simplify the provider implementation.
@@ -73,33 +73,45 @@ func (*dellLCAttributesResource) Schema(_ context.Context, _ resource.SchemaRequ
 			" We can Read the existing configurations or modify them using this resource.",
 		Description: "This Terraform resource is used to configure Lifecycle Controller attributes of the iDRAC Server." +
 			" We can Read the existing configurations or modify them using this resource.",
+		Version: attributesSchemaVersion,
 
 		Attributes: DellLCAttributesSchema(),
 		Blocks:     RedfishServerResourceBlockMap(),
 	}
 }
 
+// UpgradeState upgrades the state of the version 0 schema, whose attributes were strings
+func (r *dellLCAttributesResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return attributesStateUpgrader(r, func(state *models.DellLCAttributes) *types.Map { return &state.Attributes })
+}
+
 // DellLCAttributesSchema to define the lifecycle controller attribute schema
 func DellLCAttributesSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the LC attributes resource",
 			Description:         "ID of the LC attributes resource",
 			Computed:            true,
 		},
-		"attributes": schema.MapAttribute{
+		"attributes": schema.MapNestedAttribute{
 			MarkdownDescription: "Lifecycle Controller attributes. " +
 				"To check allowed attributes please either use the datasource for dell LC attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/LifecycleController.Embedded.1 " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: `int` for the Integer attributes " +
+				"and `string` for the others.",
 			Description: "Lifecycle Controller attributes. " +
 				"To check allowed attributes please either use the datasource for dell LC attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/LifecycleController.Embedded.1 " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
-			ElementType: types.StringType,
-			Required:    true,
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: 'int' for the Integer attributes " +
+				"and 'string' for the others.",
+			NestedObject: attributeValueSchema(),
+			Required:     true,
 		},
 	}
 }
@@ -115,7 +127,7 @@ func (r *dellLCAttributesResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -144,7 +156,7 @@ func (r *dellLCAttributesResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -176,7 +188,7 @@ func (r *dellLCAttributesResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -210,6 +222,7 @@ func (r *dellLCAttributesResource) ImportState(ctx context.Context, req resource
 		Password    string   `json:"password"`
 		Endpoint    string   `json:"endpoint"`
 		SslInsecure bool     `json:"ssl_insecure"`
+		ManagerID   string   `json:"manager_id"`
 		Attributes  []string `json:"attributes"`
 	}
 
@@ -232,15 +245,18 @@ func (r *dellLCAttributesResource) ImportState(ctx context.Context, req resource
 
 	redfishServer := path.Root("redfish_server")
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.ManagerID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manager_id"), c.ManagerID)...)
+	}
 
 	attributes := path.Root("attributes")
 	if c.Attributes == nil {
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(types.StringType))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(attributeValueType))...)
 		return
 	}
 	readAttributes := make(map[string]attr.Value)
 	for _, k := range c.Attributes {
-		readAttributes[k] = types.StringValue("")
+		readAttributes[k] = newAttributeValue("")
 	}
 
 	service, d := r.getLCEnv(&srv)
@@ -268,13 +284,13 @@ func (r *dellLCAttributesResource) ImportState(ctx context.Context, req resource
 		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(types.StringType, readAttributes))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(attributeValueType, readAttributes))...)
 }
 
 func (r *dellLCAttributesResource) getLCEnv(rserver *[]models.RedfishServer) (*gofish.Service, diag.Diagnostics) {
 	var d diag.Diagnostics
 	// Get service
-	service, err := NewConfig(r.p, rserver)
+	service, err := NewConfig(r.p, types.StringNull(), rserver)
 	if err != nil {
 		d.AddError(ServiceErrorMsg, err.Error())
 		return nil, d
@@ -287,8 +303,7 @@ func updateRedfishDellLCAttributes(ctx context.Context, service *gofish.Service,
 	idracError := "there was an issue when creating/updating LC attributes"
 	d.ID = types.StringValue("placeholder")
 	// Get attributes
-	attributesTf := make(map[string]string)
-	diags.Append(d.Attributes.ElementsAs(ctx, &attributesTf, true)...)
+	attributesTf := attributeValues(d.Attributes)
 	// get managerAttributeRegistry to check parameters before posting them to redfish
 	managerAttributeRegistry, err := getManagerAttributeRegistry(service)
 	if err != nil {
@@ -309,22 +324,15 @@ func updateRedfishDellLCAttributes(ctx context.Context, service *gofish.Service,
 		return diags
 	}
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
-	if err != nil {
-		diags.AddError(idracError, err.Error())
-		return diags
-	}
-
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
 	}
 
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
@@ -335,45 +343,12 @@ func updateRedfishDellLCAttributes(ctx context.Context, service *gofish.Service,
 		return diags
 	}
 
-	// Set the body to send
-	patchBody := struct {
-		ApplyTime  string `json:"@Redfish.OperationApplyTime"`
-		Attributes map[string]interface{}
-	}{
-		ApplyTime:  "Immediate",
-		Attributes: attributesToPatch,
-	}
-
-	response, err := service.GetClient().Patch(lcAttributes.ODataID, patchBody)
-
-	if response != nil {
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			diags.AddError("error reading response body", err.Error())
-			return diags
-		}
-
-		readResponse := make(map[string]json.RawMessage)
-		err = json.Unmarshal(body, &readResponse)
-		if err != nil {
-			diags.AddError("Error unmarshalling response body", err.Error())
-			return diags
-		}
-
-		// check for extended error message in response
-		errorMsg, ok := readResponse["error"]
-		if ok {
-			diags.AddError("Error updating lifecycle controller attributes", string(errorMsg))
-			return diags
-		}
-	}
-
+	err = patchManagerAttributes(service, managerAttributeRegistry, lcAttributes, attributesToPatch)
 	if err != nil {
-		diags.AddError(idracError, err.Error())
+		diags.Append(redfishErrorDiagnostics(idracError, err, attributesPropertyPath)...)
 		return diags
 	}
-	response.Body.Close() // #nosec G104
-	d.ID = types.StringValue(lcAttributes.ODataID)
+	d.ID = types.StringValue(lcAttributes.URI)
 	diags = readRedfishDellLCAttributes(ctx, service, d)
 	return diags
 }
@@ -381,22 +356,15 @@ func updateRedfishDellLCAttributes(ctx context.Context, service *gofish.Service,
 func readRedfishDellLCAttributes(_ context.Context, service *gofish.Service, d *models.DellLCAttributes) diag.Diagnostics {
 	var diags diag.Diagnostics
 	idracError := "there was an issue when reading LC attributes"
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
-	if err != nil {
-		diags.AddError(idracError, err.Error())
-		return diags
-	}
-
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
 	}
 
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(idracError, err.Error())
 		return diags
@@ -418,25 +386,25 @@ func readRedfishDellLCAttributes(_ context.Context, service *gofish.Service, d *
 			// This is done to avoid triggering an update when reading Password values,
 			// that are shown as null (nil to Go)
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = v.(types.String)
+				readAttributes[k] = v
 			}
 		}
 	} else {
 		for k, attrValue := range lcAttributes.Attributes {
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = types.StringValue("")
+				readAttributes[k] = newAttributeValue("")
 			}
 		}
 	}
-	d.Attributes = types.MapValueMust(types.StringType, readAttributes)
+	d.Attributes = types.MapValueMust(attributeValueType, readAttributes)
 	return diags
 }
 
-func getLCAttributes(attributes []*dell.Attributes) (*dell.Attributes, error) {
+func getLCAttributes(attributes []*oem.AttributeGroup) (*oem.AttributeGroup, error) {
 	for _, a := range attributes {
 		if strings.Contains(a.ID, "LCAttributes") || strings.Contains(a.ID, "LifecycleController.Embedded.1") {
 			return a, nil
@@ -33,8 +33,8 @@ func TestAccRedfishLCAttributesBasic(t *testing.T) {
 			{
 				Config: testAccRedfishResourceLCAttributesConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning", "On"),
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning.string", "On"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart.string", "Disabled"),
 				),
 			},
 			{
@@ -71,15 +71,15 @@ func TestAccRedfishLCAttributesUpdate(t *testing.T) {
 			{
 				Config: testAccRedfishResourceLCAttributesConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning", "On"),
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning.string", "On"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart.string", "Disabled"),
 				),
 			},
 			{
 				Config: testAccRedfishResourceLCAttributesUpdateConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning", "Off"),
-					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart", "Enabled"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.IgnoreCertWarning.string", "Off"),
+					resource.TestCheckResourceAttr("redfish_dell_lc_attributes.lc", "attributes.LCAttributes.1.CollectSystemInventoryOnRestart.string", "Enabled"),
 				),
 			},
 		},
@@ -114,8 +114,8 @@ func testAccRedfishResourceLCAttributesConfig(testingInfo TestingServerCredentia
 		}
 	  
 		attributes = {
-			"LCAttributes.1.CollectSystemInventoryOnRestart" = "Disabled"
-			"LCAttributes.1.IgnoreCertWarning" = "On"
+			"LCAttributes.1.CollectSystemInventoryOnRestart" = { string = "Disabled" }
+			"LCAttributes.1.IgnoreCertWarning" = { string = "On" }
 		}
 	  }
 	  `,
@@ -136,8 +136,8 @@ func testAccRedfishResourceLCAttributesUpdateConfig(testingInfo TestingServerCre
 		}
 
 		attributes = {
-			"LCAttributes.1.CollectSystemInventoryOnRestart" = "Enabled"
-			"LCAttributes.1.IgnoreCertWarning" = "Off"
+			"LCAttributes.1.CollectSystemInventoryOnRestart" = { string = "Enabled" }
+			"LCAttributes.1.IgnoreCertWarning" = { string = "Off" }
 		}
 	  }
 	  `,
@@ -158,10 +158,10 @@ func testAccRedfishResourceLCConfigInvalid(testingInfo TestingServerCredentials)
 		}
 	  
 		attributes = {
-			"LCAttributes.1.CollectSystemInventoryOnRestart" = "Disabled",
-			"LCAttributes.1.IgnoreCertWarning" = "On",
-		  	"SysLog.1.PowerLogInterval" = 5,
-		  	"InvalidAttribute" 		  = "invalid",
+			"LCAttributes.1.CollectSystemInventoryOnRestart" = { string = "Disabled" },
+			"LCAttributes.1.IgnoreCertWarning" = { string = "On" },
+		  	"SysLog.1.PowerLogInterval" = { int = 5 },
+		  	"InvalidAttribute" 		  = { string = "invalid" },
 		}
 	  }
 	  `,
@@ -25,6 +25,7 @@ import (
 	"slices"
 	"strings"
 	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -39,7 +40,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &dellSystemAttributesResource{}
+	_ resource.Resource                 = &dellSystemAttributesResource{}
+	_ resource.ResourceWithUpgradeState = &dellSystemAttributesResource{}
 )
 
 // NewDellSystemAttributesResource is a helper function to simplify the provider implementation.
@@ -73,33 +75,45 @@ func (*dellSystemAttributesResource) Schema(_ context.Context, _ resource.Schema
 			" We can Read the existing configurations or modify them using this resource.",
 		Description: "This Terraform resource is used to configure System attributes of the iDRAC Server." +
 			" We can Read the existing configurations or modify them using this resource.",
+		Version: attributesSchemaVersion,
 
 		Attributes: DellSystemAttributesSchema(),
 		Blocks:     RedfishServerResourceBlockMap(),
 	}
 }
 
+// UpgradeState upgrades the state of the version 0 schema, whose attributes were strings
+func (r *dellSystemAttributesResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return attributesStateUpgrader(r, func(state *models.DellSystemAttributes) *types.Map { return &state.Attributes })
+}
+
 // DellSystemAttributesSchema to define the system attribute schema
 func DellSystemAttributesSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the System attributes resource",
 			Description:         "ID of the System attributes resource",
 			Computed:            true,
 		},
-		"attributes": schema.MapAttribute{
+		"attributes": schema.MapNestedAttribute{
 			MarkdownDescription: "System attributes. " +
 				"To check allowed attributes please either use the datasource for dell System attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/System.Embedded.1 " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: `int` for the Integer attributes " +
+				"and `string` for the others.",
 			Description: "System attributes. " +
 				"To check allowed attributes please either use the datasource for dell System attributes or query " +
 				"/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/System.Embedded.1 " +
 				"To get allowed values for those attributes, check " +
-				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance",
-			ElementType: types.StringType,
-			Required:    true,
+				"/redfish/v1/Registries/ManagerAttributeRegistry/ManagerAttributeRegistry.v1_0_0.json from a Redfish Instance. " +
+				"Each value sets the member of the type of the attribute: 'int' for the Integer attributes " +
+				"and 'string' for the others.",
+			NestedObject: attributeValueSchema(),
+			Required:     true,
 		},
 	}
 }
@@ -115,7 +129,7 @@ func (r *dellSystemAttributesResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -144,7 +158,7 @@ func (r *dellSystemAttributesResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -176,7 +190,7 @@ func (r *dellSystemAttributesResource) Update(ctx context.Context, req resource.
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -210,6 +224,7 @@ func (r *dellSystemAttributesResource) ImportState(ctx context.Context, req reso
 		Password    string   `json:"password"`
 		Endpoint    string   `json:"endpoint"`
 		SslInsecure bool     `json:"ssl_insecure"`
+		ManagerID   string   `json:"manager_id"`
 		Attributes  []string `json:"attributes"`
 	}
 
@@ -232,15 +247,18 @@ func (r *dellSystemAttributesResource) ImportState(ctx context.Context, req reso
 
 	redfishServer := path.Root("redfish_server")
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.ManagerID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manager_id"), c.ManagerID)...)
+	}
 
 	attributes := path.Root("attributes")
 	if c.Attributes == nil {
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(types.StringType))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapNull(attributeValueType))...)
 		return
 	}
 	readAttributes := make(map[string]attr.Value)
 	for _, k := range c.Attributes {
-		readAttributes[k] = types.StringValue("")
+		readAttributes[k] = newAttributeValue("")
 	}
 
 	service, d := r.getEnv(&srv)
@@ -268,13 +286,13 @@ func (r *dellSystemAttributesResource) ImportState(ctx context.Context, req reso
 		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(types.StringType, readAttributes))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attributes, types.MapValueMust(attributeValueType, readAttributes))...)
 }
 
 func (r *dellSystemAttributesResource) getEnv(rserver *[]models.RedfishServer) (*gofish.Service, diag.Diagnostics) {
 	var d diag.Diagnostics
 	// Get service
-	service, err := NewConfig(r.p, rserver)
+	service, err := NewConfig(r.p, types.StringNull(), rserver)
 	if err != nil {
 		d.AddError(ServiceErrorMsg, err.Error())
 		return nil, d
@@ -288,8 +306,7 @@ func updateRedfishDellSystemAttributes(ctx context.Context, service *gofish.Serv
 	idracError := "there was an issue when creating/updating System attributes"
 	d.ID = types.StringValue("placeholder")
 	// Get attributes
-	attributesTf := make(map[string]string)
-	diags.Append(d.Attributes.ElementsAs(ctx, &attributesTf, true)...)
+	attributesTf := attributeValues(d.Attributes)
 	// get managerAttributeRegistry to check parameters before posting them to redfish
 	managerAttributeRegistry, err := getManagerAttributeRegistry(service)
 	if err != nil {
@@ -315,22 +332,15 @@ func updateRedfishDellSystemAttributes(ctx context.Context, service *gofish.Serv
 		return diags
 	}
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(fmt.Sprintf("%s: Could not get manager from iDRAC", idracError), err.Error())
 		return diags
 	}
 
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
-	if err != nil {
-		diags.AddError(fmt.Sprintf("%s: Could not get OEM from iDRAC manager", idracError), err.Error())
-		return diags
-	}
-
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(fmt.Sprintf("%s: Could not get dell manager attributes", idracError), err.Error())
 		return diags
@@ -341,22 +351,12 @@ func updateRedfishDellSystemAttributes(ctx context.Context, service *gofish.Serv
 		return diags
 	}
 
-	// Set the body to send
-	patchBody := struct {
-		ApplyTime  string `json:"@Redfish.OperationApplyTime"`
-		Attributes map[string]interface{}
-	}{
-		ApplyTime:  "Immediate",
-		Attributes: attributesToPatch,
-	}
-
-	response, err := service.GetClient().Patch(systemAttributes.ODataID, patchBody)
+	err = patchManagerAttributes(service, managerAttributeRegistry, systemAttributes, attributesToPatch)
 	if err != nil {
-		diags.AddError(fmt.Sprintf("%s: patch request to iDRAC failed", idracError), err.Error())
+		diags.Append(redfishErrorDiagnostics(fmt.Sprintf("%s: patch request to iDRAC failed", idracError), err, attributesPropertyPath)...)
 		return diags
 	}
-	response.Body.Close() // #nosec G104
-	d.ID = types.StringValue(systemAttributes.ODataID)
+	d.ID = types.StringValue(systemAttributes.URI)
 	diags = readRedfishDellSystemAttributes(ctx, service, d)
 	return diags
 }
@@ -365,22 +365,15 @@ func readRedfishDellSystemAttributes(ctx context.Context, service *gofish.Servic
 	tflog.Info(ctx, "readRedfishDellSystemAttributes: started")
 	var diags diag.Diagnostics
 	idracError := "there was an issue when reading System attributes"
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
+	// get the iDRAC
+	manager, err := getManagerResource(service, d.ManagerID.ValueString(), "")
 	if err != nil {
 		diags.AddError(fmt.Sprintf("%s: Could not get manager from iDRAC", idracError), err.Error())
 		return diags
 	}
 
-	// Get OEM
-	dellManager, err := dell.Manager(managers[0])
-	if err != nil {
-		diags.AddError(fmt.Sprintf("%s: Could not get OEM from iDRAC manager", idracError), err.Error())
-		return diags
-	}
-
-	// Get Dell attributes
-	dellAttributes, err := dellManager.DellAttributes()
+	// Get the attributes of the manager
+	dellAttributes, err := oem.New(service).ManagerAttributes(manager)
 	if err != nil {
 		diags.AddError(fmt.Sprintf("%s: Could not get dell manager attributes", idracError), err.Error())
 		return diags
@@ -402,25 +395,25 @@ func readRedfishDellSystemAttributes(ctx context.Context, service *gofish.Servic
 			// This is done to avoid triggering an update when reading Password values,
 			// that are shown as null (nil to Go)
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = v.(types.String)
+				readAttributes[k] = v
 			}
 		}
 	} else {
 		for k, attrValue := range systemAttributes.Attributes {
 			if attrValue != nil {
-				attributeValue(attrValue, readAttributes, k)
+				readAttributes[k] = newAttributeValue(attrValue)
 			} else {
-				readAttributes[k] = types.StringValue("")
+				readAttributes[k] = newAttributeValue("")
 			}
 		}
 	}
-	d.Attributes = types.MapValueMust(types.StringType, readAttributes)
+	d.Attributes = types.MapValueMust(attributeValueType, readAttributes)
 	return diags
 }
 
-func getSystemAttributes(attributes []*dell.Attributes) (*dell.Attributes, error) {
+func getSystemAttributes(attributes []*oem.AttributeGroup) (*oem.AttributeGroup, error) {
 	for _, a := range attributes {
 		if strings.Contains(a.ID, "System") {
 			return a, nil
@@ -429,7 +422,7 @@ func getSystemAttributes(attributes []*dell.Attributes) (*dell.Attributes, error
 	return nil, fmt.Errorf("couldn't find SystemAttributes")
 }
 
-func assertSystemAttributes(rawAttributes map[string]string, managerAttributeRegistry *dell.ManagerAttributeRegistry) error {
+func assertSystemAttributes(rawAttributes map[string]interface{}, managerAttributeRegistry *dell.ManagerAttributeRegistry) error {
 	var err error
 	// make map of name to ID of attributes
 	attributes := make(map[string]string)
@@ -33,14 +33,35 @@ func TestAccRedfishSystemAttributesBasic(t *testing.T) {
 			{
 				Config: testAccRedfishResourceSystemAttributesConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced", "Yes"),
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced.string", "Yes"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled.string", "Disabled"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccRedfishSystemAttributesRegistryCache(t *testing.T) {
+	directory := t.TempDir()
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSystemAttributesRegistryCacheConfig(creds, directory),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced.string", "Yes"),
+				),
+			},
+			{
+				// the registry is then read from the directory
+				Config:   testAccRedfishResourceSystemAttributesRegistryCacheConfig(creds, directory),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccRedfishSystemAttributesInvalidAttribute(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -55,6 +76,34 @@ func TestAccRedfishSystemAttributesInvalidAttribute(t *testing.T) {
 	})
 }
 
+func TestAccRedfishSystemAttributesReadOnlyDependency(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSystemConfigReadOnlyDependency(
+					creds),
+				ExpectError: regexp.MustCompile("ServerPwr.1.PowerCapValue cannot be written as it is read only"),
+			},
+		},
+	})
+}
+
+func TestAccRedfishSystemAttributesInvalidType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSystemConfigInvalidType(
+					creds),
+				ExpectError: regexp.MustCompile("property ServerPwr.1.PowerCapValue must be an integer"),
+			},
+		},
+	})
+}
+
 func TestAccRedfishSystemAttributesUpdate(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -63,15 +112,15 @@ func TestAccRedfishSystemAttributesUpdate(t *testing.T) {
 			{
 				Config: testAccRedfishResourceSystemAttributesConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced", "Yes"),
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled", "Disabled"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced.string", "Yes"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled.string", "Disabled"),
 				),
 			},
 			{
 				Config: testAccRedfishResourceSystemAttributesUpdateConfig(creds),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced", "No"),
-					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled", "Enabled"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.SupportInfo.1.Outsourced.string", "No"),
+					resource.TestCheckResourceAttr("redfish_dell_system_attributes.system", "attributes.ServerPwr.1.PSPFCEnabled.string", "Enabled"),
 				),
 			},
 		},
@@ -106,8 +155,8 @@ func testAccRedfishResourceSystemAttributesConfig(testingInfo TestingServerCrede
 		}
 	  
 		attributes = {
-			"ServerPwr.1.PSPFCEnabled" = "Disabled"
-			"SupportInfo.1.Outsourced" = "Yes"
+			"ServerPwr.1.PSPFCEnabled" = { string = "Disabled" }
+			"SupportInfo.1.Outsourced" = { string = "Yes" }
 		}
 	  }
 	  `,
@@ -128,8 +177,8 @@ func testAccRedfishResourceSystemAttributesUpdateConfig(testingInfo TestingServe
 		}
 
 		attributes = {
-			"ServerPwr.1.PSPFCEnabled" = "Enabled"
-			"SupportInfo.1.Outsourced" = "No"
+			"ServerPwr.1.PSPFCEnabled" = { string = "Enabled" }
+			"SupportInfo.1.Outsourced" = { string = "No" }
 		}
 	  }
 	  `,
@@ -150,13 +199,82 @@ func testAccRedfishResourceSystemConfigInvalid(testingInfo TestingServerCredenti
 		}
 	  
 		attributes = {
-			"ServerPwr.1.PSPFCEnabled" = "Disabled",
-			"SupportInfo.1.Outsourced" = "Yes",
-		  	"SysLog.1.PowerLogInterval" = 5,
-		  	"InvalidAttribute" 		  = "invalid",
+			"ServerPwr.1.PSPFCEnabled" = { string = "Disabled" },
+			"SupportInfo.1.Outsourced" = { string = "Yes" },
+		  	"SysLog.1.PowerLogInterval" = { int = 5 },
+		  	"InvalidAttribute" 		  = { string = "invalid" },
+		}
+	  }
+	  `,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishResourceSystemConfigReadOnlyDependency(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+	resource "redfish_dell_system_attributes" "system" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+
+		attributes = {
+			"ServerPwr.1.PowerCapSetting" = { string = "Disabled" },
+			"ServerPwr.1.PowerCapValue"   = { int = 400 },
+		}
+	  }
+	  `,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishResourceSystemConfigInvalidType(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+	resource "redfish_dell_system_attributes" "system" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+
+		attributes = {
+			"ServerPwr.1.PowerCapValue" = { string = "400" },
+		}
+	  }
+	  `,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishResourceSystemAttributesRegistryCacheConfig(testingInfo TestingServerCredentials, directory string) string {
+	return fmt.Sprintf(`
+	provider "redfish" {
+		registry_cache_directory = "%s"
+	}
+
+	resource "redfish_dell_system_attributes" "system" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+
+		attributes = {
+			"SupportInfo.1.Outsourced" = { string = "Yes" }
 		}
 	  }
 	  `,
+		directory,
 		testingInfo.Username,
 		testingInfo.Password,
 		testingInfo.Endpoint,
@@ -0,0 +1,593 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &EventSubscriptionResource{}
+	_ resource.ResourceWithImportState = &EventSubscriptionResource{}
+)
+
+// eventSubscriptionPropertyPaths maps the subscription properties reported in Redfish errors to the schema attributes
+var eventSubscriptionPropertyPaths = propertyPaths(map[string]string{
+	"Destination":      "destination",
+	"Protocol":         "protocol",
+	"SubscriptionType": "subscription_type",
+	"EventFormatType":  "event_format_type",
+	"RegistryPrefixes": "registry_prefixes",
+	"MessageIds":       "message_ids",
+	"ResourceTypes":    "resource_types",
+	"Context":          "context",
+	"HttpHeaders":      "http_headers",
+})
+
+// NewEventSubscriptionResource is a helper function to simplify the provider implementation.
+func NewEventSubscriptionResource() resource.Resource {
+	return &EventSubscriptionResource{}
+}
+
+// EventSubscriptionResource is the resource implementation.
+type EventSubscriptionResource struct {
+	p *redfishProvider
+}
+
+// Configure implements resource.ResourceWithConfigure
+func (r *EventSubscriptionResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.p = req.ProviderData.(*redfishProvider)
+}
+
+// Metadata returns the resource type name.
+func (*EventSubscriptionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "event_subscription"
+}
+
+// Schema defines the schema for the resource.
+func (*EventSubscriptionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This Terraform resource is used to subscribe a destination, such as a monitoring collector," +
+			" to the events of the EventService of the server.",
+		Description: "This Terraform resource is used to subscribe a destination, such as a monitoring collector," +
+			" to the events of the EventService of the server.",
+
+		Attributes: map[string]schema.Attribute{
+			"server": RedfishServerNameSchema(),
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the subscription in the Subscriptions collection of the EventService.",
+				Description:         "The ID of the subscription in the Subscriptions collection of the EventService.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"odata_id": schema.StringAttribute{
+				MarkdownDescription: "The `@odata.id` of the subscription.",
+				Description:         "The '@odata.id' of the subscription.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"destination": schema.StringAttribute{
+				MarkdownDescription: "URI of the destination the events are sent to, such as `https://collector.example.com/events`." +
+					" Cannot be updated.",
+				Description: "URI of the destination the events are sent to, such as 'https://collector.example.com/events'." +
+					" Cannot be updated.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				MarkdownDescription: "Protocol of the destination. Applicable values are 'Redfish', 'SNMPv1', 'SNMPv2c', 'SNMPv3'" +
+					" and 'SMTP'. Default is \"Redfish\". Cannot be updated.",
+				Description: "Protocol of the destination. Applicable values are 'Redfish', 'SNMPv1', 'SNMPv2c', 'SNMPv3'" +
+					" and 'SMTP'. Default is \"Redfish\". Cannot be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(string(redfish.RedfishEventDestinationProtocol)),
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						string(redfish.RedfishEventDestinationProtocol),
+						string(redfish.SNMPv1EventDestinationProtocol),
+						string(redfish.SNMPv2cEventDestinationProtocol),
+						string(redfish.SNMPv3EventDestinationProtocol),
+						string(redfish.SMTPEventDestinationProtocol),
+					}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subscription_type": schema.StringAttribute{
+				MarkdownDescription: "Type of the subscription. Applicable values are 'RedfishEvent', 'SNMPTrap' and" +
+					" 'SNMPInform'. Default is \"RedfishEvent\". Cannot be updated.",
+				Description: "Type of the subscription. Applicable values are 'RedfishEvent', 'SNMPTrap' and" +
+					" 'SNMPInform'. Default is \"RedfishEvent\". Cannot be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(string(redfish.RedfishEventSubscriptionType)),
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						string(redfish.RedfishEventSubscriptionType),
+						string(redfish.SNMPTrapSubscriptionType),
+						string(redfish.SNMPInformSubscriptionType),
+					}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"event_format_type": schema.StringAttribute{
+				MarkdownDescription: "Format of the events sent to the destination. Applicable values are 'Event' and" +
+					" 'MetricReport'. Default is \"Event\". Cannot be updated.",
+				Description: "Format of the events sent to the destination. Applicable values are 'Event' and" +
+					" 'MetricReport'. Default is \"Event\". Cannot be updated.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(string(redfish.EventEventFormatType)),
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						string(redfish.EventEventFormatType),
+						string(redfish.MetricReportEventFormatType),
+					}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"registry_prefixes": eventSubscriptionFilterSchema("Prefixes of the message registries of the events sent," +
+				" such as `[\"IDRAC\"]`. All the events are sent when no filter is set."),
+			"message_ids": eventSubscriptionFilterSchema("MessageIds of the events sent, such as `[\"IDRAC.2.8.SYS1003\"]`."),
+			"resource_types": eventSubscriptionFilterSchema("Types of the resources whose events are sent," +
+				" such as `[\"ComputerSystem\"]`."),
+			"context": schema.StringAttribute{
+				MarkdownDescription: "Opaque string sent back in the Context property of the events, to identify the subscription.",
+				Description:         "Opaque string sent back in the Context property of the events, to identify the subscription.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"http_headers": schema.MapAttribute{
+				MarkdownDescription: "HTTP headers sent with the events, such as an `Authorization` header expected by the" +
+					" destination. The service never returns them, so that their changes outside Terraform are not detected.",
+				Description: "HTTP headers sent with the events, such as an Authorization header expected by the" +
+					" destination. The service never returns them, so that their changes outside Terraform are not detected.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"listener_address": schema.StringAttribute{
+				MarkdownDescription: "`listener_address` of the `redfish_wait_for_event` resources waiting for the events of the" +
+					" subscription. The provider starts listening before the subscription is created, so that the events sent" +
+					" before the waits start are received. Requires a `context`.",
+				Description: "listener_address of the redfish_wait_for_event resources waiting for the events of the" +
+					" subscription. The provider starts listening before the subscription is created, so that the events sent" +
+					" before the waits start are received. Requires a context.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("context")),
+				},
+			},
+		},
+		Blocks: RedfishServerResourceBlockMap(),
+	}
+}
+
+// eventSubscriptionFilterSchema returns the schema of a filter of the events, set when the subscription is created
+func eventSubscriptionFilterSchema(description string) schema.ListAttribute {
+	description += " Cannot be updated."
+	return schema.ListAttribute{
+		MarkdownDescription: description,
+		Description:         description,
+		ElementType:         types.StringType,
+		Optional:            true,
+		PlanModifiers: []planmodifier.List{
+			listplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *EventSubscriptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Trace(ctx, "resource_event_subscription create: started")
+	var plan models.EventSubscription
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
+		return
+	}
+
+	payload, diags := eventSubscriptionPayload(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(listenForEventSubscription(&plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	uri, err := createEventSubscription(service, payload)
+	if err != nil {
+		resp.Diagnostics.Append(redfishErrorDiagnostics("Error creating the event subscription", err, eventSubscriptionPropertyPaths)...)
+		return
+	}
+	plan.OdataID = types.StringValue(uri)
+
+	diags, _ = readEventSubscription(ctx, service, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "resource_event_subscription create: updating state finished, saving ...")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_event_subscription create: finish")
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *EventSubscriptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Trace(ctx, "resource_event_subscription read: started")
+	var state models.EventSubscription
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
+		return
+	}
+
+	unlock, err := rLockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	diags, cleanup := readEventSubscription(ctx, service, &state)
+	if cleanup {
+		// the subscription was deleted outside Terraform, it is created again by the next apply
+		tflog.Warn(ctx, "The event subscription no longer exists", map[string]interface{}{"odata_id": state.OdataID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "resource_event_subscription read: finished reading state")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Trace(ctx, "resource_event_subscription read: finished")
+}
+
+// Update updates the resource and sets the updated Terraform state on success. Only the context and the HTTP
+// headers can be changed, the other attributes replace the subscription.
+func (r *EventSubscriptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Trace(ctx, "resource_event_subscription update: started")
+	var plan, state models.EventSubscription
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
+		return
+	}
+
+	payload := make(map[string]interface{})
+	if !plan.Context.Equal(state.Context) {
+		payload["Context"] = plan.Context.ValueString()
+	}
+	if !plan.HTTPHeaders.Equal(state.HTTPHeaders) {
+		headers := make(map[string]string)
+		resp.Diagnostics.Append(plan.HTTPHeaders.ElementsAs(ctx, &headers, false)...)
+		payload["HttpHeaders"] = headers
+	}
+	if !plan.ListenerAddress.Equal(state.ListenerAddress) || !plan.Context.Equal(state.Context) {
+		resp.Diagnostics.Append(listenForEventSubscription(&plan)...)
+	}
+	if len(payload) > 0 && !resp.Diagnostics.HasError() {
+		tflog.Info(ctx, "Updating the event subscription", map[string]interface{}{"odata_id": state.OdataID.ValueString()})
+		response, err := service.GetClient().Patch(state.OdataID.ValueString(), payload)
+		if err != nil {
+			resp.Diagnostics.Append(redfishErrorDiagnostics("Error updating the event subscription", err, eventSubscriptionPropertyPaths)...)
+			return
+		}
+		response.Body.Close() // #nosec G104
+	}
+
+	plan.ID, plan.OdataID = state.ID, state.OdataID
+	diags, _ := readEventSubscription(ctx, service, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "resource_event_subscription update: finished state update")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_event_subscription update: finished")
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *EventSubscriptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Trace(ctx, "resource_event_subscription delete: started")
+	var state models.EventSubscription
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
+		return
+	}
+
+	err = redfish.DeleteEventDestination(service.GetClient(), state.OdataID.ValueString())
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(redfishErrorDiagnostics("Error deleting the event subscription", err, eventSubscriptionPropertyPaths)...)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+	tflog.Trace(ctx, "resource_event_subscription delete: finished")
+}
+
+// ImportState imports an existing subscription by its ID
+func (*EventSubscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	type creds struct {
+		Username    string `json:"username"`
+		Password    string `json:"password"`
+		Endpoint    string `json:"endpoint"`
+		SslInsecure bool   `json:"ssl_insecure"`
+		Id          string `json:"id"`
+	}
+
+	var c creds
+	err := json.Unmarshal([]byte(req.ID), &c)
+	if err != nil {
+		resp.Diagnostics.AddError("Error while unmarshalling id", err.Error())
+		return
+	}
+	if len(c.Id) == 0 {
+		resp.Diagnostics.AddError("Error while importing the event subscription", "the id of the subscription is required")
+		return
+	}
+
+	server := models.RedfishServer{
+		User:        types.StringValue(c.Username),
+		Password:    types.StringValue(c.Password),
+		Endpoint:    types.StringValue(c.Endpoint),
+		SslInsecure: types.BoolValue(c.SslInsecure),
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), c.Id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("redfish_server"), []models.RedfishServer{server})...)
+}
+
+// listenForEventSubscription starts the listener of the waits using the subscription, and marks the subscription
+// before it is created. The listener is kept until the provider exits, as the waits may start at any time of the
+// apply.
+func listenForEventSubscription(plan *models.EventSubscription) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(plan.ListenerAddress.ValueString()) == 0 {
+		return diags
+	}
+	if len(plan.Context.ValueString()) == 0 {
+		diags.AddAttributeError(path.Root("context"), "Invalid event subscription",
+			"the context of the subscription is required with listener_address, to tell its events apart")
+		return diags
+	}
+	listener, err := redfishEventListeners.Listen(plan.ListenerAddress.ValueString(), nil)
+	if err != nil {
+		diags.AddAttributeError(path.Root("listener_address"), "Unable to start the event listener", err.Error())
+		return diags
+	}
+	listener.MarkSubscription(plan.Context.ValueString())
+	return diags
+}
+
+// eventSubscriptionPayload returns the body of the POST creating the subscription
+func eventSubscriptionPayload(ctx context.Context, plan *models.EventSubscription) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	payload := map[string]interface{}{
+		"Destination":      plan.Destination.ValueString(),
+		"Protocol":         plan.Protocol.ValueString(),
+		"SubscriptionType": plan.SubscriptionType.ValueString(),
+		"EventFormatType":  plan.EventFormatType.ValueString(),
+		"Context":          plan.Context.ValueString(),
+	}
+	for property, filter := range map[string]types.List{
+		"RegistryPrefixes": plan.RegistryPrefixes,
+		"MessageIds":       plan.MessageIDs,
+		"ResourceTypes":    plan.ResourceTypes,
+	} {
+		if filter.IsNull() || filter.IsUnknown() {
+			continue
+		}
+		values := make([]string, 0)
+		diags.Append(filter.ElementsAs(ctx, &values, false)...)
+		payload[property] = values
+	}
+	if !plan.HTTPHeaders.IsNull() && !plan.HTTPHeaders.IsUnknown() {
+		headers := make(map[string]string)
+		diags.Append(plan.HTTPHeaders.ElementsAs(ctx, &headers, false)...)
+		payload["HttpHeaders"] = headers
+	}
+	return payload, diags
+}
+
+// createEventSubscription adds a subscription to the EventService and returns its URI
+func createEventSubscription(service *gofish.Service, payload map[string]interface{}) (string, error) {
+	eventService, err := service.EventService()
+	if err != nil {
+		return "", fmt.Errorf("unable to read the EventService: %w", err)
+	}
+	if len(eventService.Subscriptions) == 0 {
+		return "", fmt.Errorf("the EventService has no Subscriptions collection")
+	}
+	resp, err := service.GetClient().Post(eventService.Subscriptions, payload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if len(location) == 0 {
+		// the body of the response may hold the subscription
+		var subscription struct {
+			ODataID string `json:"@odata.id"`
+		}
+		json.NewDecoder(resp.Body).Decode(&subscription) // #nosec G104
+		location = subscription.ODataID
+	}
+	if len(location) == 0 {
+		return "", fmt.Errorf("the service did not return the location of the subscription")
+	}
+	// the location may be an absolute URL
+	if parsed, err := url.Parse(location); err == nil && parsed.IsAbs() {
+		location = parsed.RequestURI()
+	}
+	return location, nil
+}
+
+// readEventSubscription sets the state from the subscription. It tells to remove the resource when the subscription
+// no longer exists. The HTTP headers are never returned by the service, and the filters are only read when they are
+// set, as services may report their own defaults.
+func readEventSubscription(ctx context.Context, service *gofish.Service, d *models.EventSubscription) (diags diag.Diagnostics, cleanup bool) {
+	uri := d.OdataID.ValueString()
+	imported := len(uri) == 0
+	if imported {
+		// imported subscriptions only have their ID
+		eventService, err := service.EventService()
+		if err != nil {
+			diags.AddError("Unable to read the EventService", err.Error())
+			return diags, false
+		}
+		uri = eventService.Subscriptions + "/" + d.ID.ValueString()
+	}
+
+	subscription, err := redfish.GetEventDestination(service.GetClient(), uri)
+	if err != nil {
+		if isNotFound(err) {
+			diags.AddError("The event subscription does not exist", uri)
+			return diags, true
+		}
+		diags.AddError("Unable to read the event subscription", err.Error())
+		return diags, false
+	}
+
+	d.ID = types.StringValue(subscription.ID)
+	d.OdataID = types.StringValue(subscription.ODataID)
+	d.Destination = types.StringValue(subscription.Destination)
+	d.Context = types.StringValue(subscription.Context)
+	if len(subscription.Protocol) > 0 {
+		d.Protocol = types.StringValue(string(subscription.Protocol))
+	}
+	if len(subscription.SubscriptionType) > 0 {
+		d.SubscriptionType = types.StringValue(string(subscription.SubscriptionType))
+	}
+	if len(subscription.EventFormatType) > 0 {
+		d.EventFormatType = types.StringValue(string(subscription.EventFormatType))
+	}
+	d.RegistryPrefixes = eventSubscriptionFilter(ctx, d.RegistryPrefixes, subscription.RegistryPrefixes, imported, &diags)
+	d.MessageIDs = eventSubscriptionFilter(ctx, d.MessageIDs, subscription.MessageIDs, imported, &diags)
+	d.ResourceTypes = eventSubscriptionFilter(ctx, d.ResourceTypes, subscription.ResourceTypes, imported, &diags)
+	return diags, false
+}
+
+// eventSubscriptionFilter returns the value of a filter of the state, which is left null when it is not set. The
+// filters of an imported subscription are the ones of the service, null when the service has none.
+func eventSubscriptionFilter(ctx context.Context, current types.List, values []string, imported bool,
+	diags *diag.Diagnostics,
+) types.List {
+	if imported && len(values) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	if !imported && (current.IsNull() || current.IsUnknown()) {
+		return types.ListNull(types.StringType)
+	}
+	if values == nil {
+		values = []string{}
+	}
+	list, d := types.ListValueFrom(ctx, types.StringType, values)
+	diags.Append(d...)
+	return list
+}
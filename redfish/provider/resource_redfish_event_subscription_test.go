@@ -0,0 +1,252 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	testAccEventSubscriptionResName = "redfish_event_subscription.collector"
+	eventSubscriptionDestination    = "https://collector.example.com/redfish/events"
+)
+
+func init() {
+	resource.AddTestSweepers("redfish_event_subscription", &resource.Sweeper{
+		Name: "redfish_event_subscription",
+		F: func(region string) error {
+			service, err := getSweeperClient(region)
+			if err != nil {
+				log.Println("Error getting sweeper client ", err.Error())
+				return nil
+			}
+			if err := deleteTestEventSubscriptions(service); err != nil {
+				log.Println("failed to sweep dangling event subscriptions.")
+			}
+			return nil
+		},
+	})
+}
+
+// deleteTestEventSubscriptions removes the subscriptions of the destination of the tests
+func deleteTestEventSubscriptions(service *gofish.Service) error {
+	eventService, err := service.EventService()
+	if err != nil {
+		return err
+	}
+	subscriptions, err := eventService.GetEventSubscriptions()
+	if err != nil {
+		return err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.Destination == eventSubscriptionDestination {
+			if err := redfish.DeleteEventDestination(service.GetClient(), subscription.ODataID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getEventSubscriptionImportConf(d *terraform.State, creds TestingServerCredentials) (string, error) {
+	id, err := getID(d, testAccEventSubscriptionResName)
+	if err != nil {
+		return id, err
+	}
+	return fmt.Sprintf("{\"id\":\"%s\",\"username\":\"%s\",\"password\":\"%s\",\"endpoint\":\"https://%s\",\"ssl_insecure\":true}",
+		id, creds.Username, creds.Password, creds.Endpoint), nil
+}
+
+// Test to create, update, import and recreate an event subscription - Positive
+func TestAccRedfishEventSubscription_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishEventSubscriptionConfig(creds, "collector-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "destination", eventSubscriptionDestination),
+					resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "protocol", "Redfish"),
+					resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "subscription_type", "RedfishEvent"),
+					resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "registry_prefixes.0", "IDRAC"),
+					resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "context", "collector-1"),
+					resource.TestCheckResourceAttrSet(testAccEventSubscriptionResName, "odata_id"),
+				),
+			},
+			{
+				Config: testAccRedfishEventSubscriptionConfig(creds, "collector-2"),
+				Check:  resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "context", "collector-2"),
+			},
+			{
+				ResourceName:            testAccEventSubscriptionResName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"http_headers"},
+				ImportStateIdFunc: func(d *terraform.State) (string, error) {
+					return getEventSubscriptionImportConf(d, creds)
+				},
+			},
+			{
+				// the subscription deleted out of band is created again
+				PreConfig: func() {
+					service, err := getSweeperClient("")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := deleteTestEventSubscriptions(service); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccRedfishEventSubscriptionConfig(creds, "collector-2"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedfishEventSubscriptionConfig(creds, "collector-2"),
+				Check:  resource.TestCheckResourceAttr(testAccEventSubscriptionResName, "context", "collector-2"),
+			},
+		},
+	})
+}
+
+// Test to import a subscription which does not exist - Negative
+func TestAccRedfishEventSubscription_importInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccRedfishEventSubscriptionConfig(creds, "collector-1"),
+				ResourceName:  testAccEventSubscriptionResName,
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("{\"id\":\"invalid\",\"username\":\"%s\",\"password\":\"%s\",\"endpoint\":\"https://%s\",\"ssl_insecure\":true}", creds.Username, creds.Password, creds.Endpoint),
+				ExpectError:   regexp.MustCompile("Cannot import non-existent remote object"),
+			},
+		},
+	})
+}
+
+// Test to create a subscription with an invalid type - Negative
+func TestAccRedfishEventSubscription_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishEventSubscriptionTypeConfig(creds, "Syslog"),
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Match"),
+			},
+			{
+				// the provider cannot hold the stream of a server-sent events subscription
+				Config:      testAccRedfishEventSubscriptionTypeConfig(creds, "SSE"),
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Match"),
+			},
+		},
+	})
+}
+
+func TestEventSubscriptionFilter(t *testing.T) {
+	ctx := context.Background()
+	set, _ := types.ListValueFrom(ctx, types.StringType, []string{"IDRAC"})
+	empty, _ := types.ListValueFrom(ctx, types.StringType, []string{})
+	tests := []struct {
+		name     string
+		current  types.List
+		values   []string
+		imported bool
+		want     types.List
+	}{
+		{"set", set, []string{"IDRAC"}, false, set},
+		{"set empty", empty, nil, false, empty},
+		{"not set", types.ListNull(types.StringType), []string{"IDRAC"}, false, types.ListNull(types.StringType)},
+		{"imported", types.ListNull(types.StringType), []string{"IDRAC"}, true, set},
+		{"imported without filter", types.ListNull(types.StringType), nil, true, types.ListNull(types.StringType)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := eventSubscriptionFilter(ctx, test.current, test.values, test.imported, &diags)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("eventSubscriptionFilter() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func testAccRedfishEventSubscriptionConfig(testingInfo TestingServerCredentials, context string) string {
+	return fmt.Sprintf(`
+	resource "redfish_event_subscription" "collector" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		destination       = "%s"
+		registry_prefixes = ["IDRAC"]
+		context           = "%s"
+		http_headers = {
+			Authorization = "Bearer collector-token"
+		}
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		eventSubscriptionDestination,
+		context,
+	)
+}
+
+func testAccRedfishEventSubscriptionTypeConfig(testingInfo TestingServerCredentials, subscriptionType string) string {
+	return fmt.Sprintf(`
+	resource "redfish_event_subscription" "collector" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		destination       = "%s"
+		subscription_type = "%s"
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		eventSubscriptionDestination,
+		subscriptionType,
+	)
+}
@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"strings"
 	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -45,11 +46,6 @@ import (
 	"github.com/stmcginnis/gofish/redfish"
 )
 
-const (
-	installURLLink    = "/Oem/Dell/DellSoftwareInstallationService/Actions/DellSoftwareInstallationService.InstallFromRepository"
-	getUpdatesURLLink = "/Oem/Dell/DellSoftwareInstallationService/Actions/DellSoftwareInstallationService.GetRepoBasedUpdateList"
-)
-
 const (
 	timeBetweenAttemptsCatalogUpdate = 20
 	timeoutForCatalogUpdate          = 720
@@ -87,6 +83,8 @@ func (*idracFirmwareUpdateResource) Metadata(_ context.Context, req resource.Met
 
 func idracFirmwareUpdateSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			Description:         "ID of the iDRAC Firmware Update Resource.",
 			MarkdownDescription: "ID of the iDRAC Firmware Update Resource.",
@@ -288,20 +286,23 @@ func (r *idracFirmwareUpdateResource) Create(ctx context.Context, req resource.C
 		return
 	}
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("system error", err.Error())
 		return
 	}
-	systemId := system.ODataID
 	plan.Id = types.StringValue("idrac_firmware_update")
 
 	payload, payloadError := GetInstallFirmwareUpdatePayload(plan)
@@ -311,7 +312,8 @@ func (r *idracFirmwareUpdateResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	res, err := service.GetClient().Post(fmt.Sprintf("%v%v", systemId, installURLLink), payload)
+	vendor := oem.New(service)
+	res, err := vendor.InstallFromRepository(system, payload)
 	if err != nil {
 		resp.Diagnostics.AddError("Post Install error", err.Error())
 		return
@@ -325,21 +327,24 @@ func (r *idracFirmwareUpdateResource) Create(ctx context.Context, req resource.C
 		resp.Diagnostics.AddError("Check repository Updates job error", "job id not found")
 		return
 	}
-	repoUpdateJob, err := common.GetJobDetailsOnFinish(service, fmt.Sprintf("/redfish/v1/JobService/Jobs/%s", repoUpdateJobId),
-		int64(common.TimeBetweenAttempts), int64(common.Timeout))
+	repoUpdateJob, err := vendor.WaitForJob(ctx, fmt.Sprintf("/redfish/v1/JobService/Jobs/%s", repoUpdateJobId),
+		common.JobWaitOptions{
+			Interval:         int64(common.TimeBetweenAttempts),
+			Timeout:          int64(common.Timeout),
+			PendingOnTimeout: true,
+		})
 	if err != nil {
-		resp.Diagnostics.AddError("Check repository Updates job error", err.Error())
+		resp.Diagnostics.Append(jobErrorDiagnostics(service, "Check repository Updates job error", err)...)
 		return
 	}
-	if repoUpdateJob != nil {
-		if repoUpdateJob.JobStatus == "Critical" {
-			resp.Diagnostics.AddError("Check repository Updates job error", repoUpdateJob.Messages[0].Message)
+	if repoUpdateJob.Job != nil {
+		if repoUpdateJob.Job.JobStatus == "Critical" {
+			resp.Diagnostics.AddError("Check repository Updates job error", repoUpdateJob.Job.Messages[0].Message)
 			return
 		}
 	}
 
-	getpayload := map[string]interface{}{}
-	getres, err := service.GetClient().Post(fmt.Sprintf("%v%v", systemId, getUpdatesURLLink), getpayload)
+	getres, err := vendor.RepositoryUpdates(system)
 	if err != nil {
 		resp.Diagnostics.AddError("install service error", err.Error())
 		return
@@ -430,12 +435,16 @@ func TrackJobs(ctx context.Context, result []map[string]interface{}, service *go
 				jobID := prop["value"].(string)
 				if jobID != "" {
 					jobURI := fmt.Sprintf("/redfish/v1/JobService/Jobs/%s", jobID)
-					job, err := common.GetJobDetailsOnFinish(service, jobURI, timeBetweenAttemptsCatalogUpdate, timeoutForCatalogUpdate)
+					job, err := common.WaitForJob(ctx, service, jobURI, common.JobWaitOptions{
+						Interval:         timeBetweenAttemptsCatalogUpdate,
+						Timeout:          timeoutForCatalogUpdate,
+						PendingOnTimeout: true,
+					})
 					if err != nil {
-						jobErrors = append(jobErrors, fmt.Sprintf("Job %s failed: %v", jobID, err.Error()))
+						jobErrors = append(jobErrors, fmt.Sprintf("Job %s failed: %v", jobID, resolveJobError(service, err).Error()))
 					}
-					if job != nil {
-						jobs = append(jobs, *job)
+					if job.Job != nil {
+						jobs = append(jobs, *job.Job)
 					}
 				}
 			}
@@ -19,7 +19,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -70,6 +69,7 @@ func (*managerResetResource) Metadata(_ context.Context, req resource.MetadataRe
 // ManagerResetSchema to design the schema for manager reset resource.
 func ManagerResetSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server": RedfishServerNameSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "The value of the Id property of the Manager resource",
 			Description:         "The value of the Id property of the Manager resource",
@@ -119,8 +119,12 @@ func (r *managerResetResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
 	resetType := plan.ResetType.ValueString()
 	managerID := plan.Id.ValueString()
@@ -139,7 +143,7 @@ func (r *managerResetResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("Error while getting service", err.Error())
 		return
@@ -148,7 +152,7 @@ func (r *managerResetResource) Create(ctx context.Context, req resource.CreateRe
 	// Check iDRAC status
 	checker := ServerStatusChecker{
 		Service:  service,
-		Endpoint: (plan.RedfishServer)[0].Endpoint.ValueString(),
+		Endpoint: getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer),
 		Interval: defaultCheckInterval,
 		Timeout:  defaultCheckTimeout,
 	}
@@ -216,29 +220,11 @@ func (*managerResetResource) Delete(ctx context.Context, req resource.DeleteRequ
 	tflog.Trace(ctx, "resource_manager_reset delete: finished")
 }
 
-func getManagerFromCollection(managers []*redfish.Manager, managerID string) (*redfish.Manager, error) {
-	for _, manager := range managers {
-		if manager.ID == managerID {
-			return manager, nil
-		}
-	}
-	return nil, fmt.Errorf("invalid Manager ID provided")
-}
-
 func getManager(r *managerResetResource, d models.RedfishManagerReset, managerID string) (*redfish.Manager, error) {
-	service, err := NewConfig(r.p, &d.RedfishServer)
+	service, err := NewConfig(r.p, d.Server, &d.RedfishServer)
 	if err != nil {
 		return nil, err
 	}
 
-	managers, err := service.Managers()
-	if err != nil {
-		return nil, err
-	}
-
-	manager, err := getManagerFromCollection(managers, managerID)
-	if err != nil {
-		return nil, err
-	}
-	return manager, nil
+	return getManagerResource(service, managerID, "")
 }
@@ -47,7 +47,7 @@ func TestAccRedfishManagerReset_Invalid_ManagerID_Negative(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config:      testAccRedfishResourceManagerResetConfig(creds, "iDRAC.Embedded.0", "GracefulRestart"),
-				ExpectError: regexp.MustCompile("Invalid Manager ID provided"),
+				ExpectError: regexp.MustCompile("manager iDRAC.Embedded.0 not found, the managers of the server are iDRAC.Embedded.1"),
 			},
 		},
 	})
@@ -67,7 +67,7 @@ func TestAccRedfishManagerReset_Update_Negative(t *testing.T) {
 			},
 			{
 				Config:      testAccRedfishResourceManagerResetConfig(creds, "iDRAC.Embedded", "GracefulRestart"),
-				ExpectError: regexp.MustCompile("Invalid Manager ID provided"),
+				ExpectError: regexp.MustCompile("manager iDRAC.Embedded not found"),
 			},
 		},
 	})
@@ -68,6 +68,8 @@ func PowerSchema() map[string]schema.Attribute {
 	const waitTime = 120
 	const checkInterval = 10
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the power resource",
 			Description:         "ID of the power resource",
@@ -149,15 +151,19 @@ func (r *powerResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	// 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("system error", err.Error())
 		return
@@ -166,7 +172,7 @@ func (r *powerResource) Create(ctx context.Context, req resource.CreateRequest,
 	plan.PowerId = types.StringValue(system.SerialNumber + "_power")
 
 	resetType := plan.DesiredPowerAction.ValueString()
-	pOp := powerOperator{ctx, service}
+	pOp := powerOperator{ctx, service, plan.SystemID.ValueString()}
 	powerState, pErr := pOp.PowerOperation(resetType, plan.MaximumWaitTime.ValueInt64(), plan.CheckInterval.ValueInt64())
 	if pErr != nil {
 		return
@@ -197,13 +203,13 @@ func (r *powerResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, state.SystemID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("system error", err.Error())
 		return
@@ -239,6 +245,7 @@ func (*powerResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	state.MaximumWaitTime = plan.MaximumWaitTime
 	state.CheckInterval = plan.CheckInterval
 	state.RedfishServer = plan.RedfishServer
+	state.Server = plan.Server
 
 	tflog.Trace(ctx, "resource_power update: finished state update")
 	// Save into State
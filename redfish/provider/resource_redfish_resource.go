@@ -0,0 +1,536 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	defaultRedfishResourceResetTimeout  = 120
+	defaultRedfishResourceJobTimeout    = 1200
+	intervalRedfishResourceJobCheckTime = 10
+	settingsApplyTimeProperty           = "@Redfish.SettingsApplyTime"
+)
+
+// odataIDRegex matches the URIs of Redfish resources
+var odataIDRegex = regexp.MustCompile(`^/redfish/`)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &RedfishResource{}
+	_ resource.ResourceWithValidateConfig = &RedfishResource{}
+)
+
+// NewRedfishResource is a helper function to simplify the provider implementation.
+func NewRedfishResource() resource.Resource {
+	return &RedfishResource{}
+}
+
+// RedfishResource is the resource implementation.
+type RedfishResource struct {
+	p   *redfishProvider
+	ctx context.Context
+}
+
+// Configure implements resource.ResourceWithConfigure
+func (r *RedfishResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.p = req.ProviderData.(*redfishProvider)
+}
+
+// Metadata returns the resource type name.
+func (*RedfishResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "resource"
+}
+
+// Schema defines the schema for the resource.
+func (*RedfishResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This Terraform resource is used to set the properties of any Redfish resource supporting PATCH," +
+			" for the settings no other resource of the provider covers.",
+		Description: "This Terraform resource is used to set the properties of any Redfish resource supporting PATCH," +
+			" for the settings no other resource of the provider covers.",
+
+		Attributes: map[string]schema.Attribute{
+			"server":    RedfishServerNameSchema(),
+			"system_id": SystemIDSchema(),
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the resource.",
+				Description:         "The ID of the resource.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"odata_id": schema.StringAttribute{
+				MarkdownDescription: "The `@odata.id` of the Redfish resource, such as `/redfish/v1/Chassis/System.Embedded.1`.",
+				Description:         "The '@odata.id' of the Redfish resource, such as '/redfish/v1/Chassis/System.Embedded.1'.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(odataIDRegex, "must be a Redfish URI starting with /redfish/"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "JSON object of the properties to PATCH, usually given with `jsonencode`." +
+					" Only these properties are compared with the Redfish resource to detect drift.",
+				Description: "JSON object of the properties to PATCH, usually given with jsonencode." +
+					" Only these properties are compared with the Redfish resource to detect drift.",
+				Required: true,
+			},
+			"settings_apply_time": schema.StringAttribute{
+				MarkdownDescription: "When set, the properties are PATCHed to the settings object of the `@Redfish.Settings`" +
+					" of the resource, to be applied at this time. Applicable values are 'Immediate', 'OnReset'," +
+					" 'AtMaintenanceWindowStart' and 'InMaintenanceWindowOnReset'.",
+				Description: "When set, the properties are PATCHed to the settings object of the '@Redfish.Settings'" +
+					" of the resource, to be applied at this time. Applicable values are 'Immediate', 'OnReset'," +
+					" 'AtMaintenanceWindowStart' and 'InMaintenanceWindowOnReset'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						string(redfishcommon.ImmediateApplyTime),
+						string(redfishcommon.OnResetApplyTime),
+						string(redfishcommon.AtMaintenanceWindowStartApplyTime),
+						string(redfishcommon.InMaintenanceWindowOnResetApplyTime),
+					}...),
+				},
+			},
+			"reset_type": schema.StringAttribute{
+				MarkdownDescription: "Reset type to apply on the computer system after the properties are PATCHed." +
+					" Applicable values are 'ForceRestart', 'GracefulRestart', and 'PowerCycle'. No reset is done by default.",
+				Description: "Reset type to apply on the computer system after the properties are PATCHed." +
+					" Applicable values are 'ForceRestart', 'GracefulRestart', and 'PowerCycle'. No reset is done by default.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						string(redfish.ForceRestartResetType),
+						string(redfish.GracefulRestartResetType),
+						string(redfish.PowerCycleResetType),
+					}...),
+				},
+			},
+			"reset_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(int64(defaultRedfishResourceResetTimeout)),
+				Description: "Time in seconds that the provider waits for the server to be reset before timing out.",
+			},
+			"job_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(int64(defaultRedfishResourceJobTimeout)),
+				Description: "Time in seconds that the provider waits for the job started by the PATCH to be completed before timing out.",
+			},
+		},
+		Blocks: RedfishServerResourceBlockMap(),
+	}
+}
+
+// ValidateConfig checks that the body is a JSON object
+func (*RedfishResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var body types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, tfpath.Root("body"), &body)...)
+	if resp.Diagnostics.HasError() || body.IsNull() || body.IsUnknown() {
+		return
+	}
+	if _, err := parseRedfishResourceBody(body.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(tfpath.Root("body"), "Invalid body", err.Error())
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *RedfishResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Trace(ctx, "resource_redfish_resource create: started")
+	var plan models.RedfishResource
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyRedfishResource(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "resource_redfish_resource create: updating state finished, saving ...")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_redfish_resource create: finish")
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *RedfishResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Trace(ctx, "resource_redfish_resource read: started")
+	r.ctx = ctx
+	var state models.RedfishResource
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError("service error", err.Error())
+		return
+	}
+
+	unlock, err := rLockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	cleanup, err := readRedfishResource(service, &state)
+	if cleanup {
+		// the resource was deleted outside Terraform, it is patched again by the next apply once it exists
+		tflog.Warn(ctx, "The Redfish resource no longer exists", map[string]interface{}{"odata_id": state.OdataID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("unable to read the Redfish resource", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "resource_redfish_resource read: finished reading state")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Trace(ctx, "resource_redfish_resource read: finished")
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *RedfishResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Trace(ctx, "resource_redfish_resource update: started")
+	var plan models.RedfishResource
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyRedfishResource(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "resource_redfish_resource update: finished state update")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_redfish_resource update: finished")
+}
+
+// Delete only removes the resource from the Terraform state, the properties of the Redfish resource are left as they are.
+func (*RedfishResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Trace(ctx, "resource_redfish_resource delete: started")
+	resp.State.RemoveResource(ctx)
+	tflog.Trace(ctx, "resource_redfish_resource delete: finished")
+}
+
+// applyRedfishResource PATCHes the properties of the body which differ from the Redfish resource, resets the
+// system when asked and waits for the job started by the PATCH
+func (r *RedfishResource) applyRedfishResource(ctx context.Context, plan *models.RedfishResource) diag.Diagnostics {
+	var diags diag.Diagnostics
+	r.ctx = ctx
+
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		diags.AddError("service error", err.Error())
+		return diags
+	}
+
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		diags.AddError(lockErrorMsg, err.Error())
+		return diags
+	}
+	defer unlock()
+
+	body, err := parseRedfishResourceBody(plan.Body.ValueString())
+	if err != nil {
+		diags.AddAttributeError(tfpath.Root("body"), "Invalid body", err.Error())
+		return diags
+	}
+	current, err := getRedfishDocument(service, plan.OdataID.ValueString())
+	if err != nil {
+		diags.AddError("unable to read the Redfish resource", err.Error())
+		return diags
+	}
+	plan.ID = plan.OdataID
+
+	applyTime := plan.SettingsApplyTime.ValueString()
+	uri := plan.OdataID.ValueString()
+	if len(applyTime) > 0 {
+		if uri, err = settingsObjectURI(current, applyTime); err != nil {
+			diags.AddAttributeError(tfpath.Root("settings_apply_time"), "unable to apply the settings", err.Error())
+			return diags
+		}
+		// the settings already pending are not PATCHed again
+		pending, err := getRedfishDocument(service, uri)
+		if err != nil {
+			diags.AddError("unable to read the settings object of the Redfish resource", err.Error())
+			return diags
+		}
+		current = pendingValue(current, pending).(map[string]interface{})
+	}
+
+	payload := changedProperties(body, current)
+	if len(payload) == 0 {
+		tflog.Info(ctx, "The properties of the Redfish resource are already set")
+		return diags
+	}
+	if len(applyTime) > 0 {
+		payload[settingsApplyTimeProperty] = map[string]interface{}{"ApplyTime": applyTime}
+	}
+
+	tflog.Info(ctx, "Submitting patch request for the Redfish resource", map[string]interface{}{"uri": uri})
+	vendor := oem.New(service)
+	jobURI, err := vendor.ApplySettings(uri, payload)
+	if err != nil {
+		diags.Append(redfishErrorDiagnostics("error updating the Redfish resource", err, redfishResourcePropertyPath)...)
+		return diags
+	}
+
+	var version string
+	if resetType := plan.ResetType.ValueString(); len(resetType) > 0 {
+		// the version of the settings tells when the services applying them without job have applied them
+		if version, err = vendor.SettingsVersion(plan.OdataID.ValueString()); err != nil {
+			diags.AddError("unable to read the settings of the Redfish resource", err.Error())
+			return diags
+		}
+		tflog.Info(ctx, "rebooting the server")
+		pOp := powerOperator{ctx, service, plan.SystemID.ValueString()}
+		if _, err := pOp.PowerOperation(resetType, plan.ResetTimeout.ValueInt64(), intervalRedfishResourceJobCheckTime); err != nil {
+			diags.AddError("there was an issue restarting the server", err.Error())
+			return diags
+		}
+	}
+
+	if !appliedNow(applyTime, len(plan.ResetType.ValueString()) > 0) {
+		tflog.Info(ctx, "The settings of the Redfish resource are pending", map[string]interface{}{"apply_time": applyTime})
+		return diags
+	}
+	diags.Append(r.waitForRedfishResource(service, vendor, plan, jobURI, version)...)
+	return diags
+}
+
+// waitForRedfishResource waits for the job started by the PATCH, or for the settings applied without job
+func (r *RedfishResource) waitForRedfishResource(service *gofish.Service, vendor oem.Vendor, plan *models.RedfishResource,
+	jobURI, version string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	opts := common.JobWaitOptions{
+		Interval:          intervalRedfishResourceJobCheckTime,
+		Timeout:           plan.JobTimeout.ValueInt64(),
+		CancelOnInterrupt: true,
+	}
+	if len(jobURI) > 0 {
+		tflog.Info(r.ctx, "Waiting for the job of the Redfish resource to finish", map[string]interface{}{"uri": jobURI})
+		if _, err := vendor.WaitForJob(r.ctx, jobURI, opts); err != nil {
+			diags.Append(jobErrorDiagnostics(service, "error waiting for the job of the Redfish resource to be completed", err)...)
+		}
+		return diags
+	}
+	if plan.SettingsApplyTime.IsNull() || plan.ResetType.IsNull() {
+		return diags
+	}
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
+	if err != nil {
+		diags.AddError("error fetching system resource", err.Error())
+		return diags
+	}
+	if err := vendor.WaitForSettings(r.ctx, system, plan.OdataID.ValueString(), version, opts); err != nil {
+		diags.AddError("error waiting for the settings of the Redfish resource to be applied", err.Error())
+	}
+	return diags
+}
+
+// readRedfishResource sets the body of the state to the values of its properties in the Redfish resource, so
+// that a change of a managed property shows as drift. The body is kept as is when the values are the same.
+// With a settings apply time, the values pending in the settings object count as the values of the resource.
+// cleanup tells that the Redfish resource no longer exists, and must be removed from the state.
+func readRedfishResource(service *gofish.Service, state *models.RedfishResource) (cleanup bool, err error) {
+	body, err := parseRedfishResourceBody(state.Body.ValueString())
+	if err != nil {
+		return false, err
+	}
+	current, err := getRedfishDocument(service, state.OdataID.ValueString())
+	if err != nil {
+		return isNotFound(err), err
+	}
+	if applyTime := state.SettingsApplyTime.ValueString(); len(applyTime) > 0 {
+		if uri, err := settingsObjectURI(current, applyTime); err == nil {
+			pending, err := getRedfishDocument(service, uri)
+			if err != nil {
+				return false, err
+			}
+			current = pendingValue(current, pending).(map[string]interface{})
+		}
+	}
+	managed := managedValue(body, current)
+	if !reflect.DeepEqual(managed, body) {
+		data, err := json.Marshal(managed)
+		if err != nil {
+			return false, err
+		}
+		state.Body = types.StringValue(string(data))
+	}
+	state.ID = state.OdataID
+	return false, nil
+}
+
+// parseRedfishResourceBody returns the properties of a body, which must be a JSON object
+func parseRedfishResourceBody(body string) (map[string]interface{}, error) {
+	var properties map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &properties); err != nil {
+		return nil, fmt.Errorf("the body must be a JSON object: %w", err)
+	}
+	if properties == nil {
+		return nil, fmt.Errorf("the body must be a JSON object, not null")
+	}
+	return properties, nil
+}
+
+// managedValue returns the value of the Redfish resource with the shape of the desired value: only the members
+// of the desired objects are kept. The properties the service leaves out or shows as null, such as passwords,
+// cannot be compared and keep their desired value.
+func managedValue(desired, current interface{}) interface{} {
+	if current == nil {
+		return desired
+	}
+	desiredObject, ok := desired.(map[string]interface{})
+	if !ok {
+		return current
+	}
+	currentObject, ok := current.(map[string]interface{})
+	if !ok {
+		return current
+	}
+	managed := make(map[string]interface{}, len(desiredObject))
+	for key, value := range desiredObject {
+		managed[key] = managedValue(value, currentObject[key])
+	}
+	return managed
+}
+
+// pendingValue returns the value of the Redfish resource once the pending value of its settings object is
+// applied: the members of the pending objects replace the ones of the current objects, and the values the
+// settings object leaves out or shows as null keep their current value.
+func pendingValue(current, pending interface{}) interface{} {
+	if pending == nil {
+		return current
+	}
+	pendingObject, ok := pending.(map[string]interface{})
+	if !ok {
+		return pending
+	}
+	currentObject, ok := current.(map[string]interface{})
+	if !ok {
+		return pending
+	}
+	value := make(map[string]interface{}, len(currentObject))
+	for key, currentValue := range currentObject {
+		value[key] = currentValue
+	}
+	for key, pendingMember := range pendingObject {
+		value[key] = pendingValue(currentObject[key], pendingMember)
+	}
+	return value
+}
+
+// changedProperties returns the properties of the body whose value differs from the Redfish resource. The
+// properties the service does not show are always sent.
+func changedProperties(body, current map[string]interface{}) map[string]interface{} {
+	changed := make(map[string]interface{})
+	for key, value := range body {
+		currentValue, ok := current[key]
+		if !ok || currentValue == nil || !reflect.DeepEqual(managedValue(value, currentValue), value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// settingsObjectURI returns the settings object of the @Redfish.Settings of a resource, checking that it
+// supports the apply time
+func settingsObjectURI(document map[string]interface{}, applyTime string) (string, error) {
+	data, err := json.Marshal(document["@Redfish.Settings"])
+	if err != nil {
+		return "", err
+	}
+	var settings struct {
+		SettingsObject struct {
+			ODataID string `json:"@odata.id"`
+		}
+		SupportedApplyTimes []string
+	}
+	if err := json.Unmarshal(data, &settings); err != nil || len(settings.SettingsObject.ODataID) == 0 {
+		return "", fmt.Errorf("the resource has no @Redfish.Settings settings object")
+	}
+	if len(settings.SupportedApplyTimes) > 0 && !slices.Contains(settings.SupportedApplyTimes, applyTime) {
+		return "", fmt.Errorf("\"%s\" is not allowed as settings apply time", applyTime)
+	}
+	return settings.SettingsObject.ODataID, nil
+}
+
+// appliedNow tells whether the settings are applied by the end of the apply, the ones applied at a maintenance
+// window or at a reset the resource does not do being left pending
+func appliedNow(applyTime string, reset bool) bool {
+	switch redfishcommon.ApplyTime(applyTime) {
+	case "", redfishcommon.ImmediateApplyTime:
+		return true
+	case redfishcommon.OnResetApplyTime:
+		return reset
+	default:
+		return false
+	}
+}
+
+// redfishResourcePropertyPath attaches the messages on the apply time to settings_apply_time, and the others to body
+func redfishResourcePropertyPath(pointer string) (tfpath.Path, bool) {
+	if strings.HasPrefix(strings.TrimLeft(pointer, "#/"), settingsApplyTimeProperty) {
+		return tfpath.Root("settings_apply_time"), true
+	}
+	return tfpath.Root("body"), true
+}
@@ -0,0 +1,324 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-redfish/emulator"
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const redfishResourceChassis = "/redfish/v1/Chassis/System.Embedded.1"
+
+// test the generic redfish_resource on a property set immediately
+func TestAccRedfishResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceConfig(creds, redfishResourceChassis, `{ AssetTag = "terraform-1" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_resource.chassis", "id", redfishResourceChassis),
+					resource.TestCheckResourceAttr("redfish_resource.chassis", "body", `{"AssetTag":"terraform-1"}`),
+				),
+			},
+			{
+				Config: testAccRedfishResourceConfig(creds, redfishResourceChassis, `{ AssetTag = "terraform-2" }`),
+				Check:  resource.TestCheckResourceAttr("redfish_resource.chassis", "body", `{"AssetTag":"terraform-2"}`),
+			},
+			{
+				// the property changed out of band is set again
+				PreConfig: func() {
+					service, err := getSweeperClient("")
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp, err := service.GetClient().Patch(redfishResourceChassis, map[string]interface{}{"AssetTag": "changed"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp.Body.Close() // #nosec G104
+				},
+				Config:             testAccRedfishResourceConfig(creds, redfishResourceChassis, `{ AssetTag = "terraform-2" }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedfishResourceConfig(creds, redfishResourceChassis, `{ AssetTag = "terraform-2" }`),
+				Check:  resource.TestCheckResourceAttr("redfish_resource.chassis", "body", `{"AssetTag":"terraform-2"}`),
+			},
+		},
+	})
+}
+
+// test the generic redfish_resource on the settings object of the BIOS, applied by a job after a reset
+func TestAccRedfishResource_settings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSettingsConfig(creds, "Off"),
+				Check: resource.TestCheckResourceAttr("redfish_resource.bios", "body",
+					`{"Attributes":{"NumLock":"Off"}}`),
+			},
+			{
+				Config: testAccRedfishResourceSettingsConfig(creds, "On"),
+				Check: resource.TestCheckResourceAttr("redfish_resource.bios", "body",
+					`{"Attributes":{"NumLock":"On"}}`),
+			},
+			// the settings left pending for the next reset do not show as drift
+			{
+				Config: testAccRedfishResourcePendingConfig(creds, "Off"),
+				Check: resource.TestCheckResourceAttr("redfish_resource.bios", "body",
+					`{"Attributes":{"NumLock":"Off"}}`),
+			},
+			{
+				Config:   testAccRedfishResourcePendingConfig(creds, "Off"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestManagedValue(t *testing.T) {
+	current := map[string]interface{}{
+		"AssetTag": "tag",
+		"Location": map[string]interface{}{"Rack": "R1", "Row": "A"},
+		"Password": nil,
+	}
+	tests := []struct {
+		name    string
+		desired interface{}
+		want    interface{}
+	}{
+		{"same value", map[string]interface{}{"AssetTag": "tag"}, map[string]interface{}{"AssetTag": "tag"}},
+		{"changed value", map[string]interface{}{"AssetTag": "new"}, map[string]interface{}{"AssetTag": "tag"}},
+		{
+			"members of the desired objects",
+			map[string]interface{}{"Location": map[string]interface{}{"Rack": "R2"}},
+			map[string]interface{}{"Location": map[string]interface{}{"Rack": "R1"}},
+		},
+		{"null value", map[string]interface{}{"Password": "secret"}, map[string]interface{}{"Password": "secret"}},
+		{"missing value", map[string]interface{}{"Unknown": 1.0}, map[string]interface{}{"Unknown": 1.0}},
+		{"object replaced", map[string]interface{}{"AssetTag": map[string]interface{}{}}, map[string]interface{}{"AssetTag": "tag"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := managedValue(test.desired, current); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("managedValue() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestChangedProperties(t *testing.T) {
+	current := map[string]interface{}{
+		"AssetTag":   "tag",
+		"Attributes": map[string]interface{}{"NumLock": "On", "BootMode": "Uefi"},
+		"Password":   nil,
+	}
+	body := map[string]interface{}{
+		"AssetTag":   "tag",
+		"Attributes": map[string]interface{}{"NumLock": "Off", "BootMode": "Uefi"},
+		"Password":   "secret",
+		"Unknown":    true,
+	}
+	want := map[string]interface{}{
+		// the objects are sent whole
+		"Attributes": map[string]interface{}{"NumLock": "Off", "BootMode": "Uefi"},
+		"Password":   "secret",
+		"Unknown":    true,
+	}
+	if got := changedProperties(body, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("changedProperties() = %v, want %v", got, want)
+	}
+	if got := changedProperties(map[string]interface{}{"AssetTag": "tag"}, current); len(got) != 0 {
+		t.Errorf("changedProperties() = %v, want no change", got)
+	}
+}
+
+func TestPendingValue(t *testing.T) {
+	current := map[string]interface{}{
+		"Id":         "BIOS",
+		"Attributes": map[string]interface{}{"NumLock": "On", "BootMode": "Uefi"},
+	}
+	pending := map[string]interface{}{
+		"Id":         "Settings",
+		"Attributes": map[string]interface{}{"NumLock": "Off"},
+		"Password":   nil,
+	}
+	want := map[string]interface{}{
+		"Id":         "Settings",
+		"Attributes": map[string]interface{}{"NumLock": "Off", "BootMode": "Uefi"},
+		"Password":   nil,
+	}
+	if got := pendingValue(current, pending); !reflect.DeepEqual(got, want) {
+		t.Errorf("pendingValue() = %v, want %v", got, want)
+	}
+	if got := pendingValue(current, nil); !reflect.DeepEqual(got, current) {
+		t.Errorf("pendingValue() = %v, want %v", got, current)
+	}
+}
+
+func TestReadRedfishResourcePending(t *testing.T) {
+	s, service := emulator.Connect(t)
+	settingsURI := "/redfish/v1/Systems/System.Embedded.1/Bios/Settings"
+	settings, _ := s.Resource(settingsURI)
+	settings["Attributes"] = map[string]interface{}{"NumLock": "Off"}
+	s.SetResource(settingsURI, settings)
+
+	tests := []struct {
+		name      string
+		applyTime types.String
+		want      string
+	}{
+		{"pending value", types.StringValue("OnReset"), `{"Attributes":{"NumLock":"Off"}}`},
+		{"without apply time", types.StringNull(), `{"Attributes":{"NumLock":"On"}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := models.RedfishResource{
+				OdataID:           types.StringValue("/redfish/v1/Systems/System.Embedded.1/Bios"),
+				Body:              types.StringValue(`{"Attributes":{"NumLock":"Off"}}`),
+				SettingsApplyTime: test.applyTime,
+			}
+			if _, err := readRedfishResource(service, &state); err != nil {
+				t.Fatal(err)
+			}
+			var got, want interface{}
+			_ = json.Unmarshal([]byte(state.Body.ValueString()), &got) // #nosec G104
+			_ = json.Unmarshal([]byte(test.want), &want)               // #nosec G104
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %s, want %s", state.Body.ValueString(), test.want)
+			}
+		})
+	}
+}
+
+func TestReadRedfishResourceNotFound(t *testing.T) {
+	_, service := emulator.Connect(t)
+	state := models.RedfishResource{
+		OdataID: types.StringValue("/redfish/v1/Chassis/Unknown"),
+		Body:    types.StringValue(`{"AssetTag":"tag"}`),
+	}
+	cleanup, err := readRedfishResource(service, &state)
+	if !cleanup {
+		t.Errorf("expected the missing resource to be removed from the state, got %v", err)
+	}
+
+	// the other errors are reported
+	state.OdataID = types.StringValue(redfishResourceChassis)
+	state.Body = types.StringValue(`["AssetTag"]`)
+	if cleanup, err := readRedfishResource(service, &state); cleanup || err == nil {
+		t.Errorf("expected an error without cleanup, got %v and %v", cleanup, err)
+	}
+}
+
+func TestAccRedfishResource_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceConfig(creds, redfishResourceChassis, `["AssetTag"]`),
+				ExpectError: regexp.MustCompile("the body must be a JSON object"),
+			},
+			{
+				Config:      testAccRedfishResourceConfig(creds, redfishResourceChassis, `{ UnknownProperty = "value" }`),
+				ExpectError: regexp.MustCompile("error updating the Redfish resource"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceConfig(testingInfo TestingServerCredentials, odataID string, body string) string {
+	return fmt.Sprintf(`
+	resource "redfish_resource" "chassis" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		odata_id = "%s"
+		body     = jsonencode(%s)
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		odataID,
+		body,
+	)
+}
+
+func testAccRedfishResourceSettingsConfig(testingInfo TestingServerCredentials, numLock string) string {
+	return fmt.Sprintf(`
+	resource "redfish_resource" "bios" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		odata_id            = "/redfish/v1/Systems/System.Embedded.1/Bios"
+		body                = jsonencode({ Attributes = { NumLock = "%s" } })
+		settings_apply_time = "OnReset"
+		reset_type          = "ForceRestart"
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		numLock,
+	)
+}
+
+func testAccRedfishResourcePendingConfig(testingInfo TestingServerCredentials, numLock string) string {
+	return fmt.Sprintf(`
+	resource "redfish_resource" "bios" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		odata_id            = "/redfish/v1/Systems/System.Embedded.1/Bios"
+		body                = jsonencode({ Attributes = { NumLock = "%s" } })
+		settings_apply_time = "OnReset"
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		numLock,
+	)
+}
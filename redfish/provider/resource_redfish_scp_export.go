@@ -23,7 +23,7 @@ import (
 	"fmt"
 	"strconv"
 	"terraform-provider-redfish/common"
-	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
@@ -94,6 +94,8 @@ func (*ScpExportResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 // RedfishScpExportSchema defines the schema for the resource.
 func RedfishScpExportSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the export SCP resource",
 			Description:         "ID of the export SCP resource",
@@ -455,10 +457,14 @@ func (r *ScpExportResource) Create(ctx context.Context, req resource.CreateReque
 	var sp models.TFShareParameters
 	plan.ShareParameters.As(ctx, &sp, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})
 
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error - config create", err.Error())
 		return
@@ -466,7 +472,7 @@ func (r *ScpExportResource) Create(ctx context.Context, req resource.CreateReque
 
 	content, err := scpExportExecutor(ctx, service, plan)
 	if err != nil {
-		resp.Diagnostics.AddError("executor error", err.Error())
+		resp.Diagnostics.Append(jobErrorDiagnostics(service, "executor error", err)...)
 		return
 	}
 	plan.FileContent = types.StringValue(content)
@@ -501,31 +507,24 @@ func (*ScpExportResource) Delete(ctx context.Context, _ resource.DeleteRequest,
 func scpExportExecutor(ctx context.Context, service *gofish.Service, plan models.TFRedfishScpExport) (string, error) {
 	var sp models.TFShareParameters
 	plan.ShareParameters.As(ctx, &sp, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})
-	managers, err := service.Managers()
+	manager, err := getManagerResource(service, plan.ManagerID.ValueString(), "")
 	if err != nil {
 		return "", err
 	}
-	dellManager, err := dell.Manager(managers[0])
+	resp, err := oem.New(service).ExportConfiguration(manager, constructExportPayload(ctx, plan))
 	if err != nil {
 		return "", err
 	}
-	exportURL := dellManager.Actions.ExportSystemConfigurationTarget
-	resp, err := service.GetClient().Post(exportURL, constructExportPayload(ctx, plan))
+	// For a local export, the task monitor returns the exported file once the job is completed
+	job, err := common.WaitForJobResponse(ctx, service, resp, common.JobWaitOptions{
+		Interval: intervalJobCheckTime,
+		Timeout:  defaultJobTimeout,
+	})
 	if err != nil {
 		return "", err
 	}
-	if location, err := resp.Location(); err == nil {
-		taskURI := location.EscapedPath()
-		if sp.ShareType.ValueString() == "LOCAL" {
-			fileContent, err := common.GetJobAttachment(service, taskURI, intervalJobCheckTime, defaultJobTimeout)
-			if err != nil {
-				return "", err
-			}
-			return base64.StdEncoding.EncodeToString(fileContent), nil
-		}
-		if err := common.WaitForDellJobToFinish(service, taskURI, intervalJobCheckTime, defaultJobTimeout); err != nil {
-			return "", err
-		}
+	if sp.ShareType.ValueString() == "LOCAL" {
+		return base64.StdEncoding.EncodeToString(job.Body), nil
 	}
 	return "SCP exported successfully", nil
 }
@@ -22,7 +22,7 @@ import (
 	"fmt"
 	"strconv"
 	"terraform-provider-redfish/common"
-	"terraform-provider-redfish/gofish/dell"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
@@ -95,6 +95,8 @@ func (*ScpImportResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 // RedfishScpImportSchema defines the schema for the resource.
 func RedfishScpImportSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":     RedfishServerNameSchema(),
+		"manager_id": ManagerIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the Import SCP resource",
 			Description:         "ID of the Import SCP resource",
@@ -491,18 +493,22 @@ func (r *ScpImportResource) Create(ctx context.Context, req resource.CreateReque
 	var sp models.TFShareParameters
 	plan.ShareParameters.As(ctx, &sp, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})
 
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
 
-	log, err := scpImportExecutor(service, constructPayload(ctx, plan), intervalJobCheckTime, plan.TimeToWait.ValueInt64())
+	log, err := scpImportExecutor(ctx, service, plan)
 	if err != nil {
-		resp.Diagnostics.AddError(log, err.Error())
+		resp.Diagnostics.Append(jobErrorDiagnostics(service, log, err)...)
 		return
 	}
 
@@ -536,36 +542,27 @@ func (*ScpImportResource) Delete(ctx context.Context, _ resource.DeleteRequest,
 //
 // Parameters:
 // - service: a pointer to a gofish.Service object representing the Redfish service.
-// - scpImportPayload: a models.SCPImport object containing the payload for the SCP import.
-// - jobCheckIntervalTime: an int64 representing the interval time for checking the job status.
-// - jobDefaultTimeout: an int64 representing the default timeout for the job.
+// - plan: a models.RedfishScpImport object holding the manager, the payload and the timeout of the SCP import.
 //
 // Returns:
 // - string: a message indicating the result of the SCP import.
 // - error: an error object if there was an error during the import process.
-func scpImportExecutor(service *gofish.Service, scpImportPayload models.SCPImport,
-	jobCheckIntervalTime int64, jobDefaultTimeout int64,
-) (string, error) {
-	managers, err := service.Managers()
+func scpImportExecutor(ctx context.Context, service *gofish.Service, plan models.RedfishScpImport) (string, error) {
+	manager, err := getManagerResource(service, plan.ManagerID.ValueString(), "")
 	if err != nil {
 		return "error while retrieving managers", err
 	}
-	dellManager, err := dell.Manager(managers[0])
-	if err != nil {
-		return "error while retrieving dell manager", err
-	}
-	importURL := dellManager.Actions.ImportSystemConfigurationTarget
-	response, err := service.GetClient().Post(importURL, scpImportPayload)
+	response, err := oem.New(service).ImportConfiguration(manager, constructPayload(ctx, plan))
 	if err != nil {
 		return "error during import", err
 	}
 
-	if location, err := response.Location(); err == nil {
-		taskURI := location.EscapedPath()
-		err = common.WaitForDellJobToFinish(service, taskURI, jobCheckIntervalTime, jobDefaultTimeout)
-		if err != nil {
-			return "error waiting for SCP Export monitor task to be completed", err
-		}
+	_, err = common.WaitForJobResponse(ctx, service, response, common.JobWaitOptions{
+		Interval: intervalJobCheckTime,
+		Timeout:  plan.TimeToWait.ValueInt64(),
+	})
+	if err != nil {
+		return "error waiting for SCP Import monitor task to be completed", err
 	}
 	return "The server configuration profile was successfully imported", nil
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package provider
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"regexp"
@@ -31,7 +32,8 @@ const importBuffer = `
 import_buffer = base64decode(redfish_idrac_server_configuration_profile_export.config_1.file_content)
 `
 
-var redfishServer = fmt.Sprintf(`
+func redfishServer() string {
+	return fmt.Sprintf(`
 redfish_server {
 	user = "%s"
 	password = "%s"
@@ -39,6 +41,7 @@ redfish_server {
 	ssl_insecure = true
   }
 `, os.Getenv("TF_TESTING_USERNAME"), os.Getenv("TF_TESTING_PASSWORD"), os.Getenv("TF_TESTING_ENDPOINT"))
+}
 
 func getSP(shareType string, addOn map[string]string) string {
 	parameters := make([]string, 0, len(addOn))
@@ -64,12 +67,24 @@ func createSCPConfig(configType, configName, shareParameter, addOnConfig string)
 		}`,
 		configType,
 		configName,
-		redfishServer,
+		redfishServer(),
 		shareParameter,
 		addOnConfig,
 	)
 }
 
+// checkExportedProfile checks that the file content of a local export is a Server Configuration Profile
+func checkExportedProfile(value string) error {
+	content, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("the exported file is not base64 encoded: %w", err)
+	}
+	if !strings.Contains(string(content), "<SystemConfiguration") || !strings.Contains(string(content), "<Component FQDD=") {
+		return fmt.Errorf("the exported file is not a Server Configuration Profile: %q", content)
+	}
+	return nil
+}
+
 func dependsOnExport(configName string) string {
 	return fmt.Sprintf(`
 		depends_on = [
@@ -104,6 +119,7 @@ func TestAccRedfishSCP(t *testing.T) {
 					createSCPConfig("export", "config_1", getSP("LOCAL", nil), ""), createSCPConfig("import", "config_1", getSP("LOCAL", nil), importBuffer+"\n"+dependsOnExport("config_1"))),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("redfish_idrac_server_configuration_profile_export.config_1", "share_parameters.share_type", "LOCAL"),
+					resource.TestCheckResourceAttrWith("redfish_idrac_server_configuration_profile_export.config_1", "file_content", checkExportedProfile),
 					resource.TestCheckResourceAttr("redfish_idrac_server_configuration_profile_import.config_1", "share_parameters.share_type", "LOCAL"),
 				),
 			},
@@ -135,6 +151,30 @@ func TestAccRedfishSCP(t *testing.T) {
 	})
 }
 
+func TestAccRedfishSCPImportMissingFile(t *testing.T) {
+	share := fmt.Sprintf(`
+	share_parameters = {
+		filename   = "missing_profile.xml"
+		target     = ["EventFilters"]
+		share_type = "NFS"
+		ip_address = "%s"
+		share_name = "%s"
+	}
+	`, os.Getenv("TF_NFS_IP_ADDRESS"), os.Getenv("TF_NFS_SHARE_NAME"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the job error has the message and the resolution of its MessageId
+				Config:      createSCPConfig("import", "missing", share, ""),
+				ExpectError: regexp.MustCompile(`(?s)Unable to find the Server Configuration Profile file.*Make sure the file is available`),
+			},
+		},
+	})
+}
+
 func TestAccRedfishSCPInvalid(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -29,7 +29,6 @@ import (
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -82,6 +81,8 @@ func (*simpleUpdateResource) Metadata(_ context.Context, req resource.MetadataRe
 
 func simpleUpdateSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			Description:         "ID of the simple update resource",
 			MarkdownDescription: "ID of the simple update resource",
@@ -187,10 +188,7 @@ func (*simpleUpdateResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"redfish_server": schema.ListNestedBlock{
 				MarkdownDescription: "List of server BMCs and their respective user credentials",
 				Description:         "List of server BMCs and their respective user credentials",
-				Validators: []validator.List{
-					listvalidator.SizeAtMost(1),
-					listvalidator.IsRequired(),
-				},
+				Validators:          redfishServerValidators(),
 				NestedObject: schema.NestedBlockObject{
 					Attributes: RedfishServerSchema(),
 				},
@@ -213,15 +211,19 @@ func (r *simpleUpdateResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
 	}
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, plan.SystemID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("system error", err.Error())
 		return
@@ -253,7 +255,7 @@ func (r *simpleUpdateResource) Read(ctx context.Context, req resource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("service error", err.Error())
 		return
@@ -316,7 +318,7 @@ func (u *simpleUpdater) updateRedfishSimpleUpdate(d models.SimpleUpdateRes) (dia
 	resetType := d.ResetType.ValueString()
 
 	// Check if chosen reset type is supported before doing anything else
-	systems, err := u.service.Systems()
+	system, err := getSystemResource(u.service, d.SystemID.ValueString())
 	if err != nil {
 		diags.AddError(
 			"Couldn't retrieve allowed reset types from systems",
@@ -326,7 +328,7 @@ func (u *simpleUpdater) updateRedfishSimpleUpdate(d models.SimpleUpdateRes) (dia
 	}
 	tflog.Debug(u.ctx, "resource_simple_update : found system")
 
-	if ok := checkResetType(resetType, systems[0].SupportedResetTypes); !ok {
+	if ok := checkResetType(resetType, system.SupportedResetTypes); !ok {
 		diags.AddError(
 			fmt.Sprintf("Reset type %s is not available in this redfish implementation", resetType),
 			err.Error(),
@@ -586,7 +588,7 @@ func (u *simpleUpdater) updateJobStatus(d models.SimpleUpdateRes) error {
 
 	// Reboot the server
 	tflog.Debug(u.ctx, "Rebooting the server")
-	pOp := powerOperator{u.ctx, u.service}
+	pOp := powerOperator{u.ctx, u.service, d.SystemID.ValueString()}
 	_, err := pOp.PowerOperation(d.ResetType.ValueString(), resetTimeout, intervalSimpleUpdateJobCheckTime)
 	if err != nil {
 		// Delete uploaded package - TBD
@@ -595,10 +597,13 @@ func (u *simpleUpdater) updateJobStatus(d models.SimpleUpdateRes) error {
 	tflog.Debug(u.ctx, "Reboot Complete")
 
 	// Check JID
-	err = common.WaitForTaskToFinish(u.service, jobID, intervalSimpleUpdateJobCheckTime, simpleUpdateJobTimeout)
+	_, err = common.WaitForJob(u.ctx, u.service, jobID, common.JobWaitOptions{
+		Interval: intervalSimpleUpdateJobCheckTime,
+		Timeout:  simpleUpdateJobTimeout,
+	})
 	if err != nil {
 		// Delete uploaded package - TBD
-		return fmt.Errorf("there was an issue when waiting for the job to complete - %w", err)
+		return fmt.Errorf("there was an issue when waiting for the job to complete - %w", resolveJobError(u.service, err))
 	}
 	tflog.Debug(u.ctx, "Job has been completed")
 
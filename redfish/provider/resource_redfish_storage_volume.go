@@ -20,11 +20,11 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
 	"terraform-provider-redfish/common"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 	"time"
 
@@ -49,7 +49,8 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &RedfishStorageVolumeResource{}
+	_ resource.Resource               = &RedfishStorageVolumeResource{}
+	_ resource.ResourceWithModifyPlan = &RedfishStorageVolumeResource{}
 )
 
 var volumeTypeMap = map[string]string{
@@ -60,6 +61,22 @@ var volumeTypeMap = map[string]string{
 	string(redfish.SpannedStripesWithParityVolumeType): "RAID50",
 }
 
+// storageVolumePropertyPaths maps the volume properties reported in Redfish errors to the schema attributes
+var storageVolumePropertyPaths = propertyPaths(map[string]string{
+	"Name":                                 "volume_name",
+	"DisplayName":                          "volume_name",
+	"ReadCachePolicy":                      "read_cache_policy",
+	"WriteCachePolicy":                     "write_cache_policy",
+	"CapacityBytes":                        "capacity_bytes",
+	"OptimumIOSizeBytes":                   "optimum_io_size_bytes",
+	"RAIDType":                             "raid_type",
+	"Encrypted":                            "encrypted",
+	"Drives":                               "drives",
+	"Oem/Dell/DellVolume/DiskCachePolicy":  "disk_cache_policy",
+	"@Redfish.OperationApplyTime":          "settings_apply_time",
+	"@Redfish.SettingsApplyTime/ApplyTime": "settings_apply_time",
+})
+
 const (
 	defaultStorageVolumeResetTimeout  int64 = 120
 	defaultStorageVolumeJobTimeout    int64 = 1200
@@ -94,6 +111,8 @@ func (*RedfishStorageVolumeResource) Metadata(_ context.Context, req resource.Me
 // VolumeSchema defines the schema for the storage volume resource.
 func VolumeSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"capacity_bytes": schema.Int64Attribute{
 			MarkdownDescription: "Capacity Bytes",
 			Description:         "Capacity Bytes",
@@ -276,6 +295,38 @@ func (*RedfishStorageVolumeResource) Schema(_ context.Context, _ resource.Schema
 	}
 }
 
+// ModifyPlan replaces the volumes of the controllers managing logical drives when their name, drives, capacity
+// or RAID type change, as the logical drives cannot be changed in place
+func (r *RedfishStorageVolumeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.p == nil || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || !serverKnown(ctx, req.Plan) {
+		return
+	}
+	var plan, state models.RedfishStorageVolume
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changes := logicalDriveChanges(&plan, &state)
+	if len(changes) == 0 {
+		return
+	}
+
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the check of the logical drives", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	operator, err := newLogicalDriveOperator(ctx, service, &state)
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the check of the logical drives", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if operator != nil {
+		resp.RequiresReplace = append(resp.RequiresReplace, changes...)
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *RedfishStorageVolumeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	tflog.Trace(ctx, "resource_RedfishStorageVolume create : Started")
@@ -287,13 +338,30 @@ func (r *RedfishStorageVolumeResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
 	}
 
-	diags = createRedfishStorageVolume(ctx, service, &plan)
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	operator, err := newLogicalDriveOperator(ctx, service, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error when retreiving the Storage from the Redfish API", err.Error())
+		return
+	}
+	if operator != nil {
+		diags = operator.create(&plan)
+	} else {
+		diags = createRedfishStorageVolume(ctx, service, &plan)
+	}
 	resp.Diagnostics.Append(diags...)
 
 	tflog.Trace(ctx, "resource_RedfishStorageVolume create: updating state finished, saving ...")
@@ -316,13 +384,23 @@ func (r *RedfishStorageVolumeResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
 	}
 
-	diags, cleanup := readRedfishStorageVolume(service, &state)
+	operator, err := newLogicalDriveOperator(ctx, service, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error when retreiving the Storage from the Redfish API", err.Error())
+		return
+	}
+	var cleanup bool
+	if operator != nil {
+		diags, cleanup = operator.read(&state)
+	} else {
+		diags, cleanup = readRedfishStorageVolume(service, &state)
+	}
 	if cleanup {
 		resp.State.RemoveResource(ctx)
 		return
@@ -362,13 +440,30 @@ func (r *RedfishStorageVolumeResource) Update(ctx context.Context, req resource.
 			"Cannot disable encryption, once a disk is encrypted it cannot be transformed back into an non-encrypted state.")
 		return
 	}
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
 	}
 
-	diags = updateRedfishStorageVolume(ctx, service, &plan, &state)
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	operator, err := newLogicalDriveOperator(ctx, service, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error when retreiving the Storage from the Redfish API", err.Error())
+		return
+	}
+	if operator != nil {
+		diags = operator.update(&plan, &state)
+	} else {
+		diags = updateRedfishStorageVolume(ctx, service, &plan, &state)
+	}
 	resp.Diagnostics.Append(diags...)
 
 	tflog.Trace(ctx, "resource_RedfishStorageVolume update: finished state update")
@@ -391,13 +486,30 @@ func (r *RedfishStorageVolumeResource) Delete(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
 	}
 
-	diags = deleteRedfishStorageVolume(ctx, service, &state)
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	operator, err := newLogicalDriveOperator(ctx, service, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error when retreiving the Storage from the Redfish API", err.Error())
+		return
+	}
+	if operator != nil {
+		diags = operator.delete(&state)
+	} else {
+		diags = deleteRedfishStorageVolume(ctx, service, &state)
+	}
 	resp.Diagnostics.Append(diags...)
 	if diags.HasError() {
 		return
@@ -414,6 +526,7 @@ func (*RedfishStorageVolumeResource) ImportState(ctx context.Context, req resour
 		Endpoint    string `json:"endpoint"`
 		SslInsecure bool   `json:"ssl_insecure"`
 		Id          string `json:"id"`
+		SystemID    string `json:"system_id"`
 	}
 
 	var c creds
@@ -441,23 +554,18 @@ func (*RedfishStorageVolumeResource) ImportState(ctx context.Context, req resour
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, settingsApplyTime, string(redfishcommon.ImmediateApplyTime))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, idAttrPath, c.Id)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, redfishServer, []models.RedfishServer{server})...)
+	if len(c.SystemID) > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("system_id"), c.SystemID)...)
+	}
 }
 
 func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d *models.RedfishStorageVolume) diag.Diagnostics {
 	var diags diag.Diagnostics
-	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(d.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(d.RedfishServer[0].Endpoint.ValueString())
 
 	// Get user config
 	storageID := d.StorageControllerID.ValueString()
 
-	// Map from the deprecated volume type to raid type
-	// If the raid_type is set, that will override the volume_type
-	raidType := volumeTypeMap[d.VolumeType.ValueString()]
-	if d.RaidType.ValueString() != "" {
-		raidType = d.RaidType.ValueString()
-	}
+	raidType := volumeRaidType(d)
 	volumeName := d.VolumeName.ValueString()
 	optimumIOSizeBytes := int(d.OptimumIoSizeBytes.ValueInt64())
 	capacityBytes := int(d.CapacityBytes.ValueInt64())
@@ -472,7 +580,7 @@ func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	diags.Append(d.Drives.ElementsAs(ctx, &driveNames, true)...)
 
 	// Get storage
-	storage, err := getStorage(service, storageID)
+	storage, err := getStorage(service, d.SystemID.ValueString(), storageID)
 	if err != nil {
 		diags.AddError("Error when retreiving the Storage from the Redfish API", err.Error())
 		return diags
@@ -498,23 +606,19 @@ func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	}
 
 	newVolume := map[string]interface{}{
-		"DisplayName":        volumeName,
-		"Name":               volumeName,
-		"ReadCachePolicy":    readCachePolicy,
-		"WriteCachePolicy":   writeCachePolicy,
-		"CapacityBytes":      capacityBytes,
-		"OptimumIOSizeBytes": optimumIOSizeBytes,
-		"RAIDType":           raidType,
-		"Encrypted":          encrypted,
-		"Oem": map[string]map[string]map[string]interface{}{
-			"Dell": {
-				"DellVolume": {
-					"DiskCachePolicy": diskCachePolicy,
-				},
-			},
-		},
+		"DisplayName":                 volumeName,
+		"Name":                        volumeName,
+		"ReadCachePolicy":             readCachePolicy,
+		"WriteCachePolicy":            writeCachePolicy,
+		"CapacityBytes":               capacityBytes,
+		"OptimumIOSizeBytes":          optimumIOSizeBytes,
+		"RAIDType":                    raidType,
+		"Encrypted":                   encrypted,
 		"@Redfish.OperationApplyTime": applyTime,
 	}
+	if volumeOem := oem.New(service).VolumeOem(diskCachePolicy); volumeOem != nil {
+		newVolume["Oem"] = volumeOem
+	}
 
 	var listDrives []map[string]string
 	for _, drive := range drives {
@@ -527,7 +631,7 @@ func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	// Create volume job
 	jobID, err := createVolume(service, storage.ODataID, newVolume)
 	if err != nil {
-		diags.AddError("Error when creating the virtual disk on disk controller", err.Error())
+		diags.Append(redfishErrorDiagnostics("Error when creating the virtual disk on disk controller", err, storageVolumePropertyPaths)...)
 		return diags
 	}
 
@@ -538,7 +642,7 @@ func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 		resetTimeout := d.ResetTimeout.ValueInt64()
 
 		// Reboot the server
-		pOp := powerOperator{ctx, service}
+		pOp := powerOperator{ctx, service, d.SystemID.ValueString()}
 		_, err := pOp.PowerOperation(resetType, resetTimeout, intervalStorageVolumeJobCheckTime)
 		if err != nil {
 			diags.AddError(RedfishJobErrorMsg, err.Error())
@@ -547,9 +651,13 @@ func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	}
 
 	// Wait for the job to finish
-	err = common.WaitForTaskToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout)
+	_, err = common.WaitForJob(ctx, service, jobID, common.JobWaitOptions{
+		Interval:          intervalStorageVolumeJobCheckTime,
+		Timeout:           volumeJobTimeout,
+		CancelOnInterrupt: true,
+	})
 	if err != nil {
-		diags.AddError(RedfishJobErrorMsg, err.Error())
+		diags.Append(jobErrorDiagnostics(service, RedfishJobErrorMsg, err)...)
 		return diags
 	}
 	time.Sleep(60 * time.Second)
@@ -574,16 +682,11 @@ func readRedfishStorageVolume(service *gofish.Service, d *models.RedfishStorageV
 	// Check if the volume exists
 	volume, err := redfish.GetVolume(service.GetClient(), d.ID.ValueString())
 	if err != nil {
-		var redfishErr *redfishcommon.Error
-		if !errors.As(err, &redfishErr) {
-			diags.AddError("There was an error with the API", err.Error())
-			return diags, false
-		}
-		if redfishErr.HTTPReturnedStatusCode == http.StatusNotFound {
+		if isNotFound(err) {
 			diags.AddError("Volume doesn't exist", "")
 			return diags, true
 		}
-		diags.AddError("Status code", err.Error())
+		diags.AddError("There was an error with the API", err.Error())
 		return diags, false
 	}
 
@@ -615,10 +718,6 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(d.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(d.RedfishServer[0].Endpoint.ValueString())
-
 	// Get user config
 	storageID := d.StorageControllerID.ValueString()
 	volumeName := d.VolumeName.ValueString()
@@ -634,7 +733,7 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 	volumeJobTimeout := d.ResetTimeout.ValueInt64()
 
 	// Get storage
-	storage, err := getStorage(service, storageID)
+	storage, err := getStorage(service, d.SystemID.ValueString(), storageID)
 	if err != nil {
 		diags.AddError("Error when retreiving storage details from the Redfish API", err.Error())
 		return diags
@@ -654,23 +753,19 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 		"Encrypted":        encrypted,
 		// This can be hard coded since the other values are deprecated, this is the only supported value
 		"EncryptionTypes": []string{"NativeDriveEncryption"},
-		"Oem": map[string]map[string]map[string]interface{}{
-			"Dell": {
-				"DellVolume": {
-					"DiskCachePolicy": diskCachePolicy,
-				},
-			},
-		},
-		"Name": volumeName,
+		"Name":            volumeName,
 		"@Redfish.SettingsApplyTime": map[string]interface{}{
 			"ApplyTime": applyTime,
 		},
 	}
+	if volumeOem := oem.New(service).VolumeOem(diskCachePolicy); volumeOem != nil {
+		payload["Oem"] = volumeOem
+	}
 
 	// Update volume job
 	jobID, err := updateVolume(service, state.ID.ValueString(), payload)
 	if err != nil {
-		diags.AddError("Error when updating the virtual disk on disk controller", err.Error())
+		diags.Append(redfishErrorDiagnostics("Error when updating the virtual disk on disk controller", err, storageVolumePropertyPaths)...)
 		return diags
 	}
 
@@ -680,7 +775,7 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 		resetTimeout := d.ResetTimeout.ValueInt64()
 
 		// Reboot the server
-		pOp := powerOperator{ctx, service}
+		pOp := powerOperator{ctx, service, d.SystemID.ValueString()}
 		_, err := pOp.PowerOperation(resetType, resetTimeout, intervalStorageVolumeJobCheckTime)
 		if err != nil {
 			diags.AddError(RedfishJobErrorMsg, err.Error())
@@ -689,9 +784,13 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 	}
 
 	// Wait for the job to finish
-	err = common.WaitForTaskToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout)
+	_, err = common.WaitForJob(ctx, service, jobID, common.JobWaitOptions{
+		Interval:          intervalStorageVolumeJobCheckTime,
+		Timeout:           volumeJobTimeout,
+		CancelOnInterrupt: true,
+	})
 	if err != nil {
-		diags.AddError(RedfishJobErrorMsg, err.Error())
+		diags.Append(jobErrorDiagnostics(service, RedfishJobErrorMsg, err)...)
 		return diags
 	}
 	time.Sleep(60 * time.Second)
@@ -715,17 +814,13 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service,
 func deleteRedfishStorageVolume(ctx context.Context, service *gofish.Service, d *models.RedfishStorageVolume) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(d.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(d.RedfishServer[0].Endpoint.ValueString())
-
 	// Get vars from schema
 	applyTime := d.SettingsApplyTime.ValueString()
 	volumeJobTimeout := d.VolumeJobTimeout.ValueInt64()
 
 	jobID, err := deleteVolume(service, d.ID.ValueString())
 	if err != nil {
-		diags.AddError("Error when deleting volume", err.Error())
+		diags.Append(redfishErrorDiagnostics("Error when deleting volume", err, nil)...)
 		return diags
 	}
 
@@ -735,7 +830,7 @@ func deleteRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 		resetTimeout := d.ResetTimeout.ValueInt64()
 
 		// Reboot the server
-		pOp := powerOperator{ctx, service}
+		pOp := powerOperator{ctx, service, d.SystemID.ValueString()}
 		_, err := pOp.PowerOperation(resetType, resetTimeout, intervalStorageVolumeJobCheckTime)
 		if err != nil {
 			diags.AddError(RedfishJobErrorMsg, err.Error())
@@ -744,15 +839,27 @@ func deleteRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	}
 
 	// WAIT FOR VOLUME TO DELETE
-	err = common.WaitForTaskToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout)
+	_, err = common.WaitForJob(ctx, service, jobID, common.JobWaitOptions{
+		Interval:          intervalStorageVolumeJobCheckTime,
+		Timeout:           volumeJobTimeout,
+		CancelOnInterrupt: true,
+	})
 	if err != nil {
-		diags.AddError("Timeout reached when waiting for job to finish", err.Error())
+		diags.Append(jobErrorDiagnostics(service, "Timeout reached when waiting for job to finish", err)...)
 		return diags
 	}
 
 	return diags
 }
 
+// volumeRaidType maps the deprecated volume type to raid type. If the raid_type is set, that will override the volume_type.
+func volumeRaidType(d *models.RedfishStorageVolume) string {
+	if d.RaidType.ValueString() != "" {
+		return d.RaidType.ValueString()
+	}
+	return volumeTypeMap[d.VolumeType.ValueString()]
+}
+
 func getStorageController(storageControllers []*redfish.Storage, diskControllerID string) (*redfish.Storage, error) {
 	for _, storage := range storageControllers {
 		if storage.Entity.ID == diskControllerID {
@@ -766,7 +873,7 @@ func deleteVolume(service *gofish.Service, volumeURI string) (jobID string, err
 	// TODO - Check if we can delete immediately or if we need to schedule a job
 	res, err := service.GetClient().Delete(volumeURI)
 	if err != nil {
-		return "", fmt.Errorf("error while deleting the volume %s", volumeURI)
+		return "", fmt.Errorf("error while deleting the volume %s: %w", volumeURI, err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusAccepted {
@@ -805,13 +912,13 @@ func checkSettingsApplyTime(storage *redfish.Storage, applyTime string) error {
 	return nil
 }
 
-func getStorage(service *gofish.Service, storageID string) (*redfish.Storage, error) {
-	systems, err := service.Systems()
+func getStorage(service *gofish.Service, systemID, storageID string) (*redfish.Storage, error) {
+	system, err := getSystemResource(service, systemID)
 	if err != nil {
-		return nil, fmt.Errorf("error when retreiving the Systems from the Redfish API: %w", err)
+		return nil, fmt.Errorf("error when retreiving the System from the Redfish API: %w", err)
 	}
 
-	storageControllers, err := systems[0].Storage()
+	storageControllers, err := system.Storage()
 	if err != nil {
 		return nil, fmt.Errorf("error when retreiving the Storage from the Redfish API: %w", err)
 	}
@@ -886,3 +993,167 @@ func checkOperationApplyTimes(optionToCheck string, storageOperationApplyTimes [
 	}
 	return false
 }
+
+// logicalDriveOperator manages the volumes of the controllers configured with logical drives in pending settings,
+// such as the HPE Smart Array controllers, which apply them at the next reset of the system
+type logicalDriveOperator struct {
+	ctx     context.Context
+	service *gofish.Service
+	vendor  oem.Vendor
+	drives  oem.LogicalDrives
+	system  *redfish.ComputerSystem
+}
+
+// newLogicalDriveOperator returns the operator of the logical drives of the controller of the volume, or nil when
+// the controller has Redfish volumes
+func newLogicalDriveOperator(ctx context.Context, service *gofish.Service, d *models.RedfishStorageVolume,
+) (*logicalDriveOperator, error) {
+	vendor := oem.New(service)
+	drives, ok := vendor.(oem.LogicalDrives)
+	if !ok {
+		return nil, nil
+	}
+	system, err := getSystemResource(service, d.SystemID.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("error when retreiving the System from the Redfish API: %w", err)
+	}
+	managed, err := drives.LogicalDriveController(system, d.StorageControllerID.ValueString())
+	if err != nil || !managed {
+		return nil, err
+	}
+	return &logicalDriveOperator{ctx: ctx, service: service, vendor: vendor, drives: drives, system: system}, nil
+}
+
+func (o *logicalDriveOperator) create(d *models.RedfishStorageVolume) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.SettingsApplyTime.ValueString() != string(redfishcommon.OnResetApplyTime) {
+		diags.AddAttributeError(path.Root("settings_apply_time"), "Invalid settings_apply_time",
+			"The logical drives of this controller are applied at the next reset of the system, settings_apply_time must be OnReset")
+		return diags
+	}
+
+	var driveNames []string
+	diags.Append(d.Drives.ElementsAs(o.ctx, &driveNames, true)...)
+	if diags.HasError() {
+		return diags
+	}
+	settingsURI, err := o.drives.CreateLogicalDrive(o.system, d.StorageControllerID.ValueString(), &oem.LogicalDrive{
+		Name:          d.VolumeName.ValueString(),
+		RAIDType:      volumeRaidType(d),
+		CapacityBytes: d.CapacityBytes.ValueInt64(),
+		Drives:        driveNames,
+	})
+	if err != nil {
+		diags.Append(redfishErrorDiagnostics("Error when creating the logical drive on disk controller", err, nil)...)
+		return diags
+	}
+	diags.Append(o.applySettings(d, settingsURI)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	drive, err := o.drives.FindLogicalDrive(o.system, d.StorageControllerID.ValueString(), d.VolumeName.ValueString())
+	if err != nil {
+		diags.AddError("there was an issue when retrieving logical drives", err.Error())
+		return diags
+	}
+	if drive == nil {
+		diags.AddError("Error. The logical drive with given volume name was not found", "The controller did not create the logical drive")
+		return diags
+	}
+	d.ID = types.StringValue(drive.URI)
+	return diags
+}
+
+func (o *logicalDriveOperator) read(d *models.RedfishStorageVolume) (diags diag.Diagnostics, cleanup bool) {
+	drive, err := o.drives.GetLogicalDrive(d.ID.ValueString())
+	if err != nil {
+		if isNotFound(err) {
+			diags.AddError("Volume doesn't exist", "")
+			return diags, true
+		}
+		diags.AddError("There was an error with the API", err.Error())
+		return diags, false
+	}
+
+	d.CapacityBytes = types.Int64Value(drive.CapacityBytes)
+	d.ID = types.StringValue(drive.URI)
+	d.VolumeName = types.StringValue(drive.Name)
+	drivesList := []attr.Value{}
+	for _, name := range drive.Drives {
+		drivesList = append(drivesList, types.StringValue(name))
+	}
+	d.Drives, _ = types.ListValue(types.StringType, drivesList)
+	return diags, false
+}
+
+// update accepts the changes of the options of the resource only, as the plan replaces the logical drives whose
+// name, drives, capacity or RAID type change
+func (*logicalDriveOperator) update(d, state *models.RedfishStorageVolume) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if changes := logicalDriveChanges(d, state); len(changes) > 0 {
+		diags.AddAttributeError(changes[0], "Logical drives cannot be updated",
+			"The name, drives, capacity and RAID type of the logical drives of this controller are changed by replacing the volume")
+		return diags
+	}
+	d.ID = state.ID
+	return diags
+}
+
+// logicalDriveChanges returns the paths of the changed attributes which cannot be changed on a logical drive
+func logicalDriveChanges(d, state *models.RedfishStorageVolume) path.Paths {
+	var changes path.Paths
+	if !d.VolumeName.Equal(state.VolumeName) {
+		changes = append(changes, path.Root("volume_name"))
+	}
+	if !d.Drives.Equal(state.Drives) {
+		changes = append(changes, path.Root("drives"))
+	}
+	if !d.CapacityBytes.Equal(state.CapacityBytes) {
+		changes = append(changes, path.Root("capacity_bytes"))
+	}
+	if d.RaidType.IsUnknown() || d.VolumeType.IsUnknown() || volumeRaidType(d) != volumeRaidType(state) {
+		if !d.RaidType.Equal(state.RaidType) {
+			changes = append(changes, path.Root("raid_type"))
+		}
+		if !d.VolumeType.Equal(state.VolumeType) {
+			changes = append(changes, path.Root("volume_type"))
+		}
+	}
+	return changes
+}
+
+func (o *logicalDriveOperator) delete(d *models.RedfishStorageVolume) diag.Diagnostics {
+	var diags diag.Diagnostics
+	settingsURI, err := o.drives.DeleteLogicalDrive(o.system, d.StorageControllerID.ValueString(), d.ID.ValueString())
+	if err != nil {
+		diags.Append(redfishErrorDiagnostics("Error when deleting volume", err, nil)...)
+		return diags
+	}
+	diags.Append(o.applySettings(d, settingsURI)...)
+	return diags
+}
+
+// applySettings resets the system, and waits for the pending settings of the controller to be applied
+func (o *logicalDriveOperator) applySettings(d *models.RedfishStorageVolume, settingsURI string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	version, err := o.vendor.SettingsVersion(settingsURI)
+	if err != nil {
+		diags.AddError(RedfishJobErrorMsg, err.Error())
+		return diags
+	}
+	pOp := powerOperator{o.ctx, o.service, d.SystemID.ValueString()}
+	_, err = pOp.PowerOperation(d.ResetType.ValueString(), d.ResetTimeout.ValueInt64(), intervalStorageVolumeJobCheckTime)
+	if err != nil {
+		diags.AddError(RedfishJobErrorMsg, err.Error())
+		return diags
+	}
+	err = o.vendor.WaitForSettings(o.ctx, o.system, settingsURI, version, common.JobWaitOptions{
+		Interval: intervalStorageVolumeJobCheckTime,
+		Timeout:  d.VolumeJobTimeout.ValueInt64(),
+	})
+	if err != nil {
+		diags.AddError(RedfishJobErrorMsg, err.Error())
+	}
+	return diags
+}
@@ -18,10 +18,13 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"terraform-provider-redfish/redfish/models"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -36,6 +39,33 @@ func getVolumeImportConf(d *terraform.State, creds TestingServerCredentials) (st
 		id, creds.Username, creds.Password, creds.Endpoint), nil
 }
 
+func TestLogicalDriveChanges(t *testing.T) {
+	drives, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"1I:1:1", "1I:1:2"})
+	state := models.RedfishStorageVolume{
+		VolumeName:       types.StringValue("data"),
+		Drives:           drives,
+		CapacityBytes:    types.Int64Value(107374182400),
+		RaidType:         types.StringNull(),
+		VolumeType:       types.StringValue("Mirrored"),
+		WriteCachePolicy: types.StringValue("WriteThrough"),
+	}
+
+	plan := state
+	plan.WriteCachePolicy = types.StringValue("ProtectedWriteBack")
+	plan.RaidType = types.StringValue("RAID1")
+	if changes := logicalDriveChanges(&plan, &state); len(changes) != 0 {
+		t.Errorf("expected no change of the logical drive, got %v", changes)
+	}
+
+	plan.VolumeName = types.StringValue("logs")
+	plan.CapacityBytes = types.Int64Unknown()
+	plan.RaidType = types.StringValue("RAID0")
+	changes := logicalDriveChanges(&plan, &state)
+	if fmt.Sprint(changes) != "[volume_name,capacity_bytes,raid_type]" {
+		t.Errorf("unexpected changes of the logical drive %v", changes)
+	}
+}
+
 func TestAccRedfishStorageVolume_InvalidController(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -20,14 +20,16 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"terraform-provider-redfish/oem"
 	"terraform-provider-redfish/redfish/models"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -53,6 +55,14 @@ var (
 	_ resource.Resource = &UserAccountResource{}
 )
 
+// userAccountPropertyPaths maps the account properties reported in Redfish errors to the schema attributes
+var userAccountPropertyPaths = propertyPaths(map[string]string{
+	"UserName": "username",
+	"Password": "password",
+	"Enabled":  "enabled",
+	"RoleId":   "role_id",
+})
+
 // NewUserAccountResource is a helper function to simplify the provider implementation.
 func NewUserAccountResource() resource.Resource {
 	return &UserAccountResource{}
@@ -84,6 +94,7 @@ func (*UserAccountResource) Schema(_ context.Context, _ resource.SchemaRequest,
 		Description: "This Terraform resource is used to manage user entity of the iDRAC Server. We can create, read, " +
 			"modify and delete an existing user using this resource.",
 		Attributes: map[string]schema.Attribute{
+			"server": RedfishServerNameSchema(),
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The ID of the resource. Cannot be updated.",
 				Description:         "The ID of the resource. Cannot be updated.",
@@ -152,10 +163,14 @@ func (r *UserAccountResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -206,33 +221,22 @@ func (r *UserAccountResource) Create(ctx context.Context, req resource.CreateReq
 		}
 	}
 
-	payload := make(map[string]interface{})
-	for _, account := range accountList {
-		if len(account.UserName) == 0 && account.ID != "1" { // ID 1 is reserved
-			payload["UserName"] = userName
-			payload["Password"] = password
-			payload["Enabled"] = plan.Enabled.ValueBool()
-			payload["RoleId"] = plan.RoleID.ValueString()
-			if len(userID) > 0 {
-				// update the account.ODataID URL to new account ID
-				account.ID = userID
-				url, _ := filepath.Split(account.ODataID)
-				account.ODataID = url + account.ID
-			} else {
-				userID = account.ID
-			}
-			// Ideally a go routine for each server should be done
-			_, err = service.GetClient().Patch(account.ODataID, payload)
-			if err != nil {
-				resp.Diagnostics.AddError(RedfishAPIErrorMsg, err.Error()) // This error might happen when a user was created outside terraform
-				return
-			}
-			break
-		} else if account.ID == "17" {
-			// No room for new users
-			resp.Diagnostics.AddError("There is no room for new users", "Please remove an existing user to proceed")
-			return
-		}
+	vendor := oem.New(service)
+	userID, err = vendor.CreateAccount(accountList, &oem.Account{
+		ID:       userID,
+		UserName: userName,
+		Password: password,
+		RoleID:   plan.RoleID.ValueString(),
+		Enabled:  plan.Enabled.ValueBool(),
+	})
+	if errors.Is(err, oem.ErrNoAccountSlot) {
+		resp.Diagnostics.AddError("There is no room for new users", "Please remove an existing user to proceed")
+		return
+	}
+	if err != nil {
+		// This error might happen when a user was created outside terraform
+		resp.Diagnostics.Append(redfishErrorDiagnostics(RedfishAPIErrorMsg, err, userAccountPropertyPaths)...)
+		return
 	}
 
 	_, account, err := GetUserAccountFromID(service, userID)
@@ -243,6 +247,7 @@ func (r *UserAccountResource) Create(ctx context.Context, req resource.CreateReq
 
 	result := models.UserAccount{}
 	r.updateServer(&plan, &result, account, operationCreate)
+	resp.Diagnostics.Append(updateAccountRole(vendor, &result, account)...)
 
 	// Save into State
 	diags = resp.State.Set(ctx, result)
@@ -261,7 +266,7 @@ func (r *UserAccountResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -278,6 +283,7 @@ func (r *UserAccountResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 
 	r.updateServer(nil, &state, account, operationRead)
+	resp.Diagnostics.Append(updateAccountRole(oem.New(service), &state, account)...)
 
 	tflog.Trace(ctx, "resource_user_account read: finished reading state")
 	// Save into State
@@ -307,10 +313,14 @@ func (r *UserAccountResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	redfishMutexKV.Lock(plan.RedfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(plan.RedfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -342,14 +352,15 @@ func (r *UserAccountResource) Update(ctx context.Context, req resource.UpdateReq
 		}
 	}
 
-	payload := make(map[string]interface{})
-	payload["UserName"] = plan.Username.ValueString()
-	payload["Password"] = plan.Password.ValueString()
-	payload["Enabled"] = plan.Enabled.ValueBool()
-	payload["RoleId"] = plan.RoleID.ValueString()
-	_, err = service.GetClient().Patch(account.ODataID, payload)
+	vendor := oem.New(service)
+	err = vendor.UpdateAccount(account, &oem.Account{
+		UserName: plan.Username.ValueString(),
+		Password: plan.Password.ValueString(),
+		RoleID:   plan.RoleID.ValueString(),
+		Enabled:  plan.Enabled.ValueBool(),
+	})
 	if err != nil {
-		resp.Diagnostics.AddError(RedfishAPIErrorMsg, err.Error())
+		resp.Diagnostics.Append(redfishErrorDiagnostics(RedfishAPIErrorMsg, err, userAccountPropertyPaths)...)
 		return
 	}
 
@@ -363,6 +374,7 @@ func (r *UserAccountResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 	r.updateServer(&plan, &state, account, operationUpdate)
+	resp.Diagnostics.Append(updateAccountRole(vendor, &state, account)...)
 
 	tflog.Trace(ctx, "resource_user_account update: finished state update")
 	// Save into State
@@ -382,7 +394,7 @@ func (r *UserAccountResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -393,22 +405,9 @@ func (r *UserAccountResource) Delete(ctx context.Context, req resource.DeleteReq
 		resp.Diagnostics.AddError(RedfishFetchErrorMsg, err.Error())
 	}
 
-	// First set Role ID as "" and Enabled as false
-	payload := make(map[string]interface{})
-	payload["Enable"] = "false"
-	payload["RoleId"] = "None"
-	_, err = service.GetClient().Patch(account.ODataID, payload)
+	err = oem.New(service).DeleteAccount(account)
 	if err != nil {
-		resp.Diagnostics.AddError(RedfishAPIErrorMsg, err.Error())
-		return
-	}
-
-	// second PATCH call to remove username.
-	payload = make(map[string]interface{})
-	payload["UserName"] = ""
-	_, err = service.GetClient().Patch(account.ODataID, payload)
-	if err != nil {
-		resp.Diagnostics.AddError(RedfishAPIErrorMsg, err.Error())
+		resp.Diagnostics.Append(redfishErrorDiagnostics(RedfishAPIErrorMsg, err, userAccountPropertyPaths)...)
 		return
 	}
 
@@ -453,7 +452,20 @@ func (UserAccountResource) updateServer(plan, state *models.UserAccount, account
 	if operation != operationRead {
 		state.Password = plan.Password
 		state.RedfishServer = plan.RedfishServer
+		state.Server = plan.Server
+	}
+}
+
+// updateAccountRole sets the role of the state from the account, whose role depends on the vendor of the service
+func updateAccountRole(vendor oem.Vendor, state *models.UserAccount, account *redfish.ManagerAccount) diag.Diagnostics {
+	var diags diag.Diagnostics
+	roleID, err := vendor.AccountRole(account)
+	if err != nil {
+		diags.AddError(RedfishFetchErrorMsg, err.Error())
+		return diags
 	}
+	state.RoleID = types.StringValue(roleID)
+	return diags
 }
 
 // GetAccountList returns the list of all the user accounts
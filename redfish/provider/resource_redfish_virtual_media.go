@@ -68,6 +68,8 @@ func (*virtualMediaResource) Metadata(_ context.Context, req resource.MetadataRe
 // VirtualMediaSchema defines the schema for the resource.
 func VirtualMediaSchema() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
+		"server":    RedfishServerNameSchema(),
+		"system_id": SystemIDSchema(),
 		"id": schema.StringAttribute{
 			MarkdownDescription: "ID of the virtual media resource",
 			Description:         "ID of the virtual media resource",
@@ -165,7 +167,15 @@ func (r *virtualMediaResource) Create(ctx context.Context, req resource.CreateRe
 		TransferProtocolType: redfish.TransferProtocolType(plan.TransferProtocolType.ValueString()),
 		WriteProtected:       plan.WriteProtected.ValueBool(),
 	}
-	env, d := r.getVMEnv(&plan.RedfishServer)
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
+	env, d := r.getVMEnv(plan.Server, &plan.RedfishServer, plan.SystemID.ValueString())
 	resp.Diagnostics = append(resp.Diagnostics, d...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -190,7 +200,6 @@ func (r *virtualMediaResource) Create(ctx context.Context, req resource.CreateRe
 		}
 	} else {
 		// This implementation is added to support iDRAC firmware version 5.x. As virtual media can only be accessed through Managers card on 5.x.
-		// Get OOB Manager card - managers[0] will be our oob card
 		var virtualMediaID string
 		if strings.HasSuffix(plan.Image.ValueString(), ".iso") {
 			virtualMediaID = "CD"
@@ -224,18 +233,18 @@ type virtualMediaEnvironment struct {
 	service    *gofish.Service
 }
 
-func (r *virtualMediaResource) getVMEnv(rserver *[]models.RedfishServer) (virtualMediaEnvironment, diag.Diagnostics) {
+func (r *virtualMediaResource) getVMEnv(server types.String, rserver *[]models.RedfishServer, systemID string) (virtualMediaEnvironment, diag.Diagnostics) {
 	var d diag.Diagnostics
 	var env virtualMediaEnvironment
 	// Get service
-	service, err := NewConfig(r.p, rserver)
+	service, err := NewConfig(r.p, server, rserver)
 	if err != nil {
 		d.AddError(ServiceErrorMsg, err.Error())
 		return env, d
 	}
 	env.service = service
 	// Get Systems details
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, systemID)
 	if err != nil {
 		d.AddError("Error when retrieving systems", err.Error())
 		return env, d
@@ -254,15 +263,15 @@ func (r *virtualMediaResource) getVMEnv(rserver *[]models.RedfishServer) (virtua
 		return env, d
 	}
 	// This implementation is added to support iDRAC firmware version 5.x. As virtual media can only be accessed through Managers card on 5.x.
-	// Get OOB Manager card - managers[0] will be our oob card
+	// Get the OOB Manager card managing the system
 	env.isManager = true
-	managers, err := service.Managers()
+	manager, err := getManagerResource(service, "", systemID)
 	if err != nil {
 		d.AddError("Couldn't retrieve managers from redfish API: ", err.Error())
 		return env, d
 	}
 	// Get virtual media collection from manager
-	virtualMediaCollection, err = managers[0].VirtualMedia()
+	virtualMediaCollection, err = manager.VirtualMedia()
 	if err != nil {
 		d.AddError("Couldn't retrieve virtual media collection from redfish API: ", err.Error())
 		return env, d
@@ -282,8 +291,16 @@ func (r *virtualMediaResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	// Take the read lock, so that the virtual media is not changed while it is read
+	unlock, err := rLockServer(ctx, r.p, getRedfishServerEndpoint(r.p, state.Server, state.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
 	// Get service
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -313,7 +330,8 @@ func (r *virtualMediaResource) Read(ctx context.Context, req resource.ReadReques
 // VMediaImportConfig is the JSON configuration for importing a virtual media
 type VMediaImportConfig struct {
 	ServerConf
-	ID string `json:"id"`
+	ID       string `json:"id"`
+	SystemID string `json:"system_id"`
 }
 
 // ImportState is the RPC called to import state for existing Virtual Media
@@ -334,7 +352,7 @@ func (r *virtualMediaResource) ImportState(ctx context.Context, req resource.Imp
 
 	creds := []models.RedfishServer{server}
 
-	env, d := r.getVMEnv(&creds)
+	env, d := r.getVMEnv(types.StringNull(), &creds, c.SystemID)
 	resp.Diagnostics = append(resp.Diagnostics, d...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -360,8 +378,13 @@ func (r *virtualMediaResource) ImportState(ctx context.Context, req resource.Imp
 	}
 
 	// Save into State
+	systemID := types.StringNull()
+	if len(c.SystemID) > 0 {
+		systemID = types.StringValue(c.SystemID)
+	}
 	result := r.updateVirtualMediaState(media, models.VirtualMedia{
 		RedfishServer: creds,
+		SystemID:      systemID,
 	})
 	diags := resp.State.Set(ctx, &result)
 	resp.Diagnostics.Append(diags...)
@@ -369,10 +392,6 @@ func (r *virtualMediaResource) ImportState(ctx context.Context, req resource.Imp
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *virtualMediaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Lock the mutex to avoid race conditions with other resources
-	// redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	// defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
-
 	tflog.Trace(ctx, "resource_virtual_media update: started")
 	// Get state Data
 	var plan, state models.VirtualMedia
@@ -389,8 +408,16 @@ func (r *virtualMediaResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	// Lock the mutex to avoid race conditions with other resources
+	unlock, err := lockServer(ctx, r.p, getRedfishServerEndpoint(r.p, plan.Server, plan.RedfishServer))
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
+
 	// Get service
-	service, err := NewConfig(r.p, &plan.RedfishServer)
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -477,7 +504,7 @@ func (r *virtualMediaResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 
 	// Get service
-	service, err := NewConfig(r.p, &state.RedfishServer)
+	service, err := NewConfig(r.p, state.Server, &state.RedfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -542,5 +569,7 @@ func (*virtualMediaResource) updateVirtualMediaState(response *redfish.VirtualMe
 		TransferProtocolType: types.StringValue(string(response.TransferProtocolType)),
 		WriteProtected:       types.BoolValue(response.WriteProtected),
 		RedfishServer:        plan.RedfishServer,
+		Server:               plan.Server,
+		SystemID:             plan.SystemID,
 	}
 }
@@ -0,0 +1,482 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-redfish/events"
+	"terraform-provider-redfish/redfish/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	// defaultEventTimeout is the time in seconds waited for the event by default
+	defaultEventTimeout = 600
+	// waitForEventContextPrefix starts the context of the subscriptions of the resource, to tell them apart
+	waitForEventContextPrefix = "terraform-wait-for-event-"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &WaitForEventResource{}
+	_ resource.ResourceWithValidateConfig = &WaitForEventResource{}
+)
+
+// waitForEventRecordType is the type of the event received
+var waitForEventRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"event_id":            types.StringType,
+	"message_id":          types.StringType,
+	"message":             types.StringType,
+	"message_args":        types.ListType{ElemType: types.StringType},
+	"severity":            types.StringType,
+	"origin_of_condition": types.StringType,
+	"timestamp":           types.StringType,
+}}
+
+// NewWaitForEventResource is a helper function to simplify the provider implementation.
+func NewWaitForEventResource() resource.Resource {
+	return &WaitForEventResource{}
+}
+
+// WaitForEventResource is the resource implementation.
+type WaitForEventResource struct {
+	p *redfishProvider
+}
+
+// Configure implements resource.ResourceWithConfigure
+func (r *WaitForEventResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.p = req.ProviderData.(*redfishProvider)
+}
+
+// Metadata returns the resource type name.
+func (*WaitForEventResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "wait_for_event"
+}
+
+// Schema defines the schema for the resource.
+func (*WaitForEventResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This Terraform resource is used to wait for an event of the server, such as the end of the POST or" +
+			" of a job. The provider listens for the events on HTTPS and subscribes to the EventService of the server" +
+			" until the event is received.",
+		Description: "This Terraform resource is used to wait for an event of the server, such as the end of the POST or" +
+			" of a job. The provider listens for the events on HTTPS and subscribes to the EventService of the server" +
+			" until the event is received.",
+
+		Attributes: map[string]schema.Attribute{
+			"server": RedfishServerNameSchema(),
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the resource, the context of the subscription created to receive the event.",
+				Description:         "The ID of the resource, the context of the subscription created to receive the event.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"message_ids": schema.ListAttribute{
+				MarkdownDescription: "MessageIds of the events waited for, such as `[\"IDRAC.2.8.SYS1003\"]`. The version of the" +
+					" registry is ignored and may be left out, such as `IDRAC.SYS1003`.",
+				Description: "MessageIds of the events waited for, such as '[\"IDRAC.2.8.SYS1003\"]'. The version of the" +
+					" registry is ignored and may be left out, such as 'IDRAC.SYS1003'.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"origin_of_condition": schema.StringAttribute{
+				MarkdownDescription: "`@odata.id` of the resource the event is about, such as" +
+					" `/redfish/v1/Systems/System.Embedded.1`. Any resource matches when it is not set.",
+				Description: "'@odata.id' of the resource the event is about, such as" +
+					" '/redfish/v1/Systems/System.Embedded.1'. Any resource matches when it is not set.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subscription": schema.StringAttribute{
+				MarkdownDescription: "`odata_id` of a `redfish_event_subscription` sending the events to the listener, such as" +
+					" `redfish_event_subscription.waiter.odata_id`. The subscription must have a `context` unique to the" +
+					" listener, and the same `listener_address`, so that the provider listens from its creation. The resources" +
+					" causing the event can then `depends_on` the subscription. A subscription is created while waiting when" +
+					" it is not set.",
+				Description: "odata_id of a redfish_event_subscription sending the events to the listener, such as" +
+					" redfish_event_subscription.waiter.odata_id. The subscription must have a context unique to the" +
+					" listener, and the same listener_address, so that the provider listens from its creation. The resources" +
+					" causing the event can then depends_on the subscription. A subscription is created while waiting when" +
+					" it is not set.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"listener_address": schema.StringAttribute{
+				MarkdownDescription: "Address the provider listens on for the events, such as `10.0.0.5:8443`. The server" +
+					" BMC must be able to reach it, see `destination`. An address listening on all the addresses of the host," +
+					" such as `:8443` or `0.0.0.0:8443`, requires `destination`.",
+				Description: "Address the provider listens on for the events, such as '10.0.0.5:8443'. The server" +
+					" BMC must be able to reach it, see destination. An address listening on all the addresses of the host," +
+					" such as ':8443' or '0.0.0.0:8443', requires destination.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"destination": schema.StringAttribute{
+				MarkdownDescription: "URI the server BMC sends the events to, when the listener is reached through another address," +
+					" such as a NAT or a proxy. Default is the https URL of `listener_address`.",
+				Description: "URI the server BMC sends the events to, when the listener is reached through another address," +
+					" such as a NAT or a proxy. Default is the https URL of listener_address.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("subscription")),
+				},
+			},
+			"listener_certificate": schema.StringAttribute{
+				MarkdownDescription: "Certificate served by the listener, given either as PEM content or as the path to a PEM" +
+					" file. Requires `listener_key`. A self signed certificate is served when it is not set.",
+				Description: "Certificate served by the listener, given either as PEM content or as the path to a PEM" +
+					" file. Requires listener_key. A self signed certificate is served when it is not set.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("listener_key")),
+				},
+			},
+			"listener_key": schema.StringAttribute{
+				MarkdownDescription: "Private key of the certificate of the listener, given either as PEM content or as the path" +
+					" to a PEM file.",
+				Description: "Private key of the certificate of the listener, given either as PEM content or as the path" +
+					" to a PEM file.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("listener_certificate")),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Time in seconds waited for the event. Default is 600.",
+				Description:         "Time in seconds waited for the event. Default is 600.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultEventTimeout),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values which wait for the event again when they change, such as the ID of" +
+					" the job or of the power operation the event follows.",
+				Description: "Arbitrary values which wait for the event again when they change, such as the ID of" +
+					" the job or of the power operation the event follows.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"event": schema.SingleNestedAttribute{
+				MarkdownDescription: "The event received.",
+				Description:         "The event received.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"event_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the event.",
+						Description:         "ID of the event.",
+						Computed:            true,
+					},
+					"message_id": schema.StringAttribute{
+						MarkdownDescription: "MessageId of the event.",
+						Description:         "MessageId of the event.",
+						Computed:            true,
+					},
+					"message": schema.StringAttribute{
+						MarkdownDescription: "Message of the event.",
+						Description:         "Message of the event.",
+						Computed:            true,
+					},
+					"message_args": schema.ListAttribute{
+						MarkdownDescription: "Arguments of the message of the event.",
+						Description:         "Arguments of the message of the event.",
+						ElementType:         types.StringType,
+						Computed:            true,
+					},
+					"severity": schema.StringAttribute{
+						MarkdownDescription: "Severity of the event.",
+						Description:         "Severity of the event.",
+						Computed:            true,
+					},
+					"origin_of_condition": schema.StringAttribute{
+						MarkdownDescription: "`@odata.id` of the resource the event is about.",
+						Description:         "'@odata.id' of the resource the event is about.",
+						Computed:            true,
+					},
+					"timestamp": schema.StringAttribute{
+						MarkdownDescription: "Time of the event.",
+						Description:         "Time of the event.",
+						Computed:            true,
+					},
+				},
+			},
+		},
+		Blocks: RedfishServerResourceBlockMap(),
+	}
+}
+
+// ValidateConfig checks that the subscription created while waiting has a destination the server BMC can reach
+func (*WaitForEventResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.WaitForEvent
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ListenerAddress.IsUnknown() || !config.Subscription.IsNull() ||
+		!config.Destination.IsNull() {
+		return
+	}
+	if events.UnspecifiedHost(config.ListenerAddress.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("listener_address"), "Invalid listener address",
+			fmt.Sprintf("%s listens on all the addresses of the host, which the server BMC cannot send the events to."+
+				" Set destination, or listen on the address the BMC reaches.", config.ListenerAddress.ValueString()))
+	}
+}
+
+// Create waits for the event and sets the initial Terraform state.
+func (r *WaitForEventResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Trace(ctx, "resource_wait_for_event create: started")
+	var plan models.WaitForEvent
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the server is not locked while waiting, as other resources of the apply may cause the event
+	service, err := NewConfig(r.p, plan.Server, &plan.RedfishServer)
+	if err != nil {
+		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
+		return
+	}
+
+	filter := events.Filter{OriginOfCondition: plan.OriginOfCondition.ValueString()}
+	resp.Diagnostics.Append(plan.MessageIDs.ElementsAs(ctx, &filter.MessageIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	id, err := waitForEventContext()
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating the context of the subscription", err.Error())
+		return
+	}
+
+	certificate, err := listenerCertificate(&plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("listener_certificate"), "Invalid listener certificate", err.Error())
+		return
+	}
+	// the resources waiting on the same address share the listener, and tell their events apart by the context
+	// of their subscription
+	listener, err := redfishEventListeners.Listen(plan.ListenerAddress.ValueString(), certificate)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("listener_address"), "Unable to start the event listener", err.Error())
+		return
+	}
+	defer listener.Close() // #nosec G104
+
+	// the records received before the subscription are not looked at
+	var position int64
+	uri := plan.Subscription.ValueString()
+	if len(uri) > 0 {
+		subscription, err := redfish.GetEventDestination(service.GetClient(), uri)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("subscription"), "Unable to read the event subscription", err.Error())
+			return
+		}
+		// the events of the other subscriptions of the listener would match an empty context
+		if len(subscription.Context) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("subscription"), "Invalid event subscription",
+				fmt.Sprintf("the subscription %s has no context to tell its events apart, set its context", uri))
+			return
+		}
+		filter.Context = subscription.Context
+		position = listener.SubscriptionPosition(filter.Context)
+	} else {
+		filter.Context = id
+		position = listener.Position()
+		destination := listener.URL()
+		if !plan.Destination.IsNull() && !plan.Destination.IsUnknown() {
+			destination = plan.Destination.ValueString()
+		}
+		uri, err = createEventSubscription(service, map[string]interface{}{
+			"Destination": destination,
+			"Protocol":    string(redfish.RedfishEventDestinationProtocol),
+			"Context":     filter.Context,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(redfishErrorDiagnostics("Error subscribing to the events of the server", err, nil)...)
+			return
+		}
+		defer func() {
+			if err := redfish.DeleteEventDestination(service.GetClient(), uri); err != nil && !isNotFound(err) {
+				tflog.Warn(ctx, "Unable to delete the event subscription", map[string]interface{}{"odata_id": uri, "error": err.Error()})
+			}
+		}()
+	}
+
+	timeout := time.Duration(plan.Timeout.ValueInt64()) * time.Second
+	tflog.Info(ctx, "Waiting for the event", map[string]interface{}{
+		"message_ids": filter.MessageIDs, "origin_of_condition": filter.OriginOfCondition, "subscription": uri,
+	})
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	record, err := listener.Wait(waitCtx, position, filter)
+	if err != nil {
+		detail := err.Error()
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			detail = fmt.Sprintf("no event %s was received within %s", strings.Join(filter.MessageIDs, ", "), timeout)
+		}
+		resp.Diagnostics.AddError("Error waiting for the event", detail)
+		return
+	}
+
+	event, diags := waitForEventRecord(ctx, record)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = types.StringValue(id)
+	plan.Event = event
+
+	tflog.Trace(ctx, "resource_wait_for_event create: updating state finished, saving ...")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_wait_for_event create: finish")
+}
+
+// Read keeps the state, the event received does not change.
+func (*WaitForEventResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Trace(ctx, "resource_wait_for_event read: started")
+	var state models.WaitForEvent
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Trace(ctx, "resource_wait_for_event read: finished")
+}
+
+// Update sets the attributes which do not wait for the event again, such as the timeout.
+func (*WaitForEventResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Trace(ctx, "resource_wait_for_event update: started")
+	var plan, state models.WaitForEvent
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID, plan.Event = state.ID, state.Event
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	tflog.Trace(ctx, "resource_wait_for_event update: finished")
+}
+
+// Delete removes the Terraform state, the subscription is already deleted.
+func (*WaitForEventResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Trace(ctx, "resource_wait_for_event delete: started")
+	resp.State.RemoveResource(ctx)
+	tflog.Trace(ctx, "resource_wait_for_event delete: finished")
+}
+
+// waitForEventContext returns a random context identifying the subscription of the resource
+func waitForEventContext() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return waitForEventContextPrefix + hex.EncodeToString(b), nil
+}
+
+// listenerCertificate returns the certificate of the listener, or nil for a self signed one
+func listenerCertificate(plan *models.WaitForEvent) (*tls.Certificate, error) {
+	if len(plan.ListenerCertificate.ValueString()) == 0 {
+		return nil, nil
+	}
+	certificate, err := readPEM(plan.ListenerCertificate.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the listener certificate: %w", err)
+	}
+	key, err := readPEM(plan.ListenerKey.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the listener key: %w", err)
+	}
+	pair, err := tls.X509KeyPair(certificate, key)
+	if err != nil {
+		return nil, err
+	}
+	return &pair, nil
+}
+
+// waitForEventRecord returns the state of the event received
+func waitForEventRecord(ctx context.Context, record *events.Record) (types.Object, diag.Diagnostics) {
+	args, diags := types.ListValueFrom(ctx, types.StringType, record.MessageArgs)
+	if diags.HasError() {
+		return types.ObjectNull(waitForEventRecordType.AttrTypes), diags
+	}
+	event, d := types.ObjectValueFrom(ctx, waitForEventRecordType.AttrTypes, models.WaitForEventRecord{
+		EventID:           types.StringValue(record.EventID),
+		MessageID:         types.StringValue(record.MessageID),
+		Message:           types.StringValue(record.Message),
+		MessageArgs:       args,
+		Severity:          types.StringValue(record.MessageSeverity),
+		OriginOfCondition: types.StringValue(record.OriginOfCondition),
+		Timestamp:         types.StringValue(record.EventTimestamp),
+	})
+	diags.Append(d...)
+	return event, diags
+}
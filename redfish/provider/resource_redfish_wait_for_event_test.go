@@ -0,0 +1,205 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stmcginnis/gofish"
+)
+
+const (
+	testAccWaitForEventResName = "redfish_wait_for_event.post"
+	testEventMessageID         = "IDRAC.2.8.SYS053"
+	testEventOrigin            = "/redfish/v1/Systems/System.Embedded.1"
+)
+
+// submitTestEventOnSubscription sends the test event once the resources have subscribed to the events of the server
+func submitTestEventOnSubscription(t *testing.T, resources int) {
+	service, err := getSweeperClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for i := 0; i < 60; i++ {
+			if subscribed(service) >= resources {
+				if err := submitTestEvent(service); err != nil {
+					t.Log("unable to submit the test event:", err)
+				}
+				return
+			}
+			time.Sleep(time.Second)
+		}
+	}()
+}
+
+// subscribed returns the number of subscriptions of the resources on the server
+func subscribed(service *gofish.Service) int {
+	eventService, err := service.EventService()
+	if err != nil {
+		return 0
+	}
+	subscriptions, err := eventService.GetEventSubscriptions()
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, subscription := range subscriptions {
+		if strings.HasPrefix(subscription.Context, waitForEventContextPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func submitTestEvent(service *gofish.Service) error {
+	eventService, err := service.EventService()
+	if err != nil {
+		return err
+	}
+	resp, err := service.GetClient().Post(eventService.SubmitTestEventTarget, map[string]interface{}{
+		"EventType":         "Alert",
+		"MessageId":         testEventMessageID,
+		"OriginOfCondition": testEventOrigin,
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Test to wait for an event sent by the server - Positive
+func TestAccRedfishWaitForEvent_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { submitTestEventOnSubscription(t, 1) },
+				Config:    testAccRedfishWaitForEventConfig(creds, "IDRAC.SYS053", 120),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccWaitForEventResName, "event.message_id", testEventMessageID),
+					resource.TestCheckResourceAttr(testAccWaitForEventResName, "event.origin_of_condition", testEventOrigin),
+					resource.TestCheckResourceAttrSet(testAccWaitForEventResName, "event.message"),
+				),
+			},
+			{
+				// the timeout does not wait for the event again
+				Config: testAccRedfishWaitForEventConfig(creds, "IDRAC.SYS053", 60),
+				Check:  resource.TestCheckResourceAttr(testAccWaitForEventResName, "event.message_id", testEventMessageID),
+			},
+		},
+	})
+}
+
+// Test to wait for an event with several resources listening on the same address - Positive
+func TestAccRedfishWaitForEvent_sharedListener(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { submitTestEventOnSubscription(t, 2) },
+				Config: testAccRedfishWaitForEventConfig(creds, "IDRAC.SYS053", 120) + fmt.Sprintf(`
+				resource "redfish_wait_for_event" "shared" {
+					redfish_server {
+						user = "%s"
+						password = "%s"
+						endpoint = "https://%s"
+						ssl_insecure = true
+					}
+
+					message_ids      = ["IDRAC.SYS053"]
+					listener_address = "%s"
+					timeout          = 120
+				}
+				`, creds.Username, creds.Password, creds.Endpoint, os.Getenv("TF_TESTING_EVENT_LISTENER")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccWaitForEventResName, "event.message_id", testEventMessageID),
+					resource.TestCheckResourceAttr("redfish_wait_for_event.shared", "event.message_id", testEventMessageID),
+				),
+			},
+		},
+	})
+}
+
+// Test to wait for an event which is not sent - Negative
+func TestAccRedfishWaitForEvent_timeout(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishWaitForEventConfig(creds, "IDRAC.2.8.SYS1003", 5),
+				ExpectError: regexp.MustCompile("Error waiting for the event"),
+			},
+		},
+	})
+}
+
+// Test to serve a listener certificate without its key - Negative
+func TestAccRedfishWaitForEvent_invalidCertificate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishWaitForEventConfig(creds, "IDRAC.SYS053", 5) + `
+				resource "redfish_wait_for_event" "certificate" {
+					message_ids          = ["IDRAC.SYS053"]
+					listener_address     = "127.0.0.1:0"
+					listener_certificate = "certificate.pem"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func testAccRedfishWaitForEventConfig(testingInfo TestingServerCredentials, messageID string, timeout int) string {
+	return fmt.Sprintf(`
+	resource "redfish_wait_for_event" "post" {
+		redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		}
+
+		message_ids         = ["%s"]
+		origin_of_condition = "%s"
+		listener_address    = "%s"
+		timeout             = %d
+	}
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		messageID,
+		testEventOrigin,
+		os.Getenv("TF_TESTING_EVENT_LISTENER"),
+		timeout,
+	)
+}
@@ -143,10 +143,14 @@ func (r *UserAccountPasswordResource) Create(ctx context.Context, req resource.C
 	}
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(redfishServer[0].Endpoint.ValueString())
-	defer redfishMutexKV.Unlock(redfishServer[0].Endpoint.ValueString())
+	unlock, err := lockServer(ctx, r.p, redfishServer[0].Endpoint.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(lockErrorMsg, err.Error())
+		return
+	}
+	defer unlock()
 
-	service, err := NewConfig(r.p, &redfishServer)
+	service, err := NewConfig(r.p, types.StringNull(), &redfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError(ServiceErrorMsg, err.Error())
 		return
@@ -178,7 +182,7 @@ func (r *UserAccountPasswordResource) Create(ctx context.Context, req resource.C
 	// update password to new password and check if login is successful
 	redfishServer[0].Password = types.StringValue(plan.NewPassword.ValueString())
 
-	service, err = NewConfig(r.p, &redfishServer)
+	service, err = NewConfig(r.p, types.StringNull(), &redfishServer)
 	if err != nil {
 		resp.Diagnostics.AddError("login failed using new password", err.Error())
 		return
@@ -0,0 +1,241 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"terraform-provider-redfish/redfish/models"
+	"time"
+)
+
+const (
+	// defaultRetryMaxAttempts is the number of attempts of a request, including the first one
+	defaultRetryMaxAttempts int64 = 3
+	// defaultRetryMinBackoff is the wait before the first retry, in seconds
+	defaultRetryMinBackoff int64 = 1
+	// defaultRetryMaxBackoff is the maximum wait between two attempts, in seconds
+	defaultRetryMaxBackoff int64 = 30
+)
+
+var (
+	// defaultRetryStatusCodes are the HTTP statuses of requests the BMC did not process
+	defaultRetryStatusCodes = []int64{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	// defaultRetryMessageIDs are the Redfish messages of requests the BMC did not process.
+	// SYS410 is the iDRAC "iDRAC is not ready. The configuration values cannot be accessed" message.
+	defaultRetryMessageIDs = []string{"SYS410", "ServiceTemporarilyUnavailable"}
+	// notProcessedStatusCodes are the HTTP statuses telling that the BMC did not process the request. They are the
+	// only retryable statuses for the requests with side effects, which the BMC may have run before a gateway
+	// error, and which must not be run twice.
+	notProcessedStatusCodes = map[int]bool{http.StatusTooManyRequests: true, http.StatusServiceUnavailable: true}
+)
+
+// retryPolicy tells which requests are sent again and how long to wait between attempts
+type retryPolicy struct {
+	maxAttempts int64
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	statusCodes map[int]bool
+	messageIDs  []string
+}
+
+// newRetryPolicy builds the retry policy from the provider retry block. Missing settings use the defaults.
+func newRetryPolicy(config []models.RetryConfig) retryPolicy {
+	var cfg models.RetryConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	policy := retryPolicy{
+		maxAttempts: defaultRetryMaxAttempts,
+		minBackoff:  time.Duration(defaultRetryMinBackoff) * time.Second,
+		maxBackoff:  time.Duration(defaultRetryMaxBackoff) * time.Second,
+		statusCodes: make(map[int]bool),
+		messageIDs:  defaultRetryMessageIDs,
+	}
+	if !cfg.MaxAttempts.IsNull() {
+		policy.maxAttempts = cfg.MaxAttempts.ValueInt64()
+	}
+	if !cfg.MinBackoff.IsNull() {
+		policy.minBackoff = time.Duration(cfg.MinBackoff.ValueInt64()) * time.Second
+	}
+	if !cfg.MaxBackoff.IsNull() {
+		policy.maxBackoff = time.Duration(cfg.MaxBackoff.ValueInt64()) * time.Second
+	} else if policy.minBackoff > policy.maxBackoff {
+		// the configured max_backoff is checked against min_backoff, the default one is raised
+		policy.maxBackoff = policy.minBackoff
+	}
+
+	statusCodes := defaultRetryStatusCodes
+	if cfg.StatusCodes != nil {
+		statusCodes = cfg.StatusCodes
+	}
+	for _, code := range statusCodes {
+		policy.statusCodes[int(code)] = true
+	}
+	if cfg.MessageIDs != nil {
+		policy.messageIDs = cfg.MessageIDs
+	}
+	return policy
+}
+
+// backoff returns the wait before the given retry, starting at 1. It grows exponentially
+// from the minimum backoff up to the maximum backoff, with a random jitter of up to half of it.
+func (p retryPolicy) backoff(retry int64) time.Duration {
+	wait := p.minBackoff
+	for i := int64(1); i < retry && wait < p.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.maxBackoff {
+		wait = p.maxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := int64(wait / 2)
+	return time.Duration(half + rand.Int63n(half+1)) // #nosec G404
+}
+
+// matchMessageID tells whether the MessageId is retryable. Configured IDs match either the full
+// MessageId or its last segment, so that "SYS410" matches "IDRAC.2.8.SYS410".
+func (p retryPolicy) matchMessageID(messageID string) bool {
+	for _, id := range p.messageIDs {
+		if messageID == id || strings.HasSuffix(messageID, "."+id) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryTransport sends requests again when the BMC answers that it is temporarily unable to process them
+type retryTransport struct {
+	policy retryPolicy
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body of the first attempt is gone, so the request can only be replayed if it can be rewound
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := int64(1); ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.maxAttempts || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.policy.backoff(attempt)
+		if resp != nil {
+			wait = t.retryAfter(resp, wait)
+			resp.Body.Close() // #nosec G104
+			log.Printf("[DEBUG] %s %s returned %d, attempt %d of %d in %s", req.Method, req.URL.Path, resp.StatusCode,
+				attempt+1, t.policy.maxAttempts, wait)
+		} else {
+			log.Printf("[DEBUG] %s %s failed: %s, attempt %d of %d in %s", req.Method, req.URL.Path, err.Error(),
+				attempt+1, t.policy.maxAttempts, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// shouldRetry tells whether the request failed for a transient reason. The requests with side effects, such as
+// resets, imports or the creation of jobs and accounts, are only sent again when the BMC tells it did not process
+// them.
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	safe := req.Method == http.MethodGet || req.Method == http.MethodHead
+	if err != nil {
+		// The BMC may have processed a request which failed on the way back
+		return req.Context().Err() == nil && safe
+	}
+	if t.policy.statusCodes[resp.StatusCode] && (safe || notProcessedStatusCodes[resp.StatusCode]) {
+		return true
+	}
+	if resp.StatusCode < http.StatusBadRequest || len(t.policy.messageIDs) == 0 {
+		return false
+	}
+	for _, id := range responseMessageIDs(resp) {
+		if t.policy.matchMessageID(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter returns the wait asked by the Retry-After header, if any, bounded by the maximum backoff
+func (t *retryTransport) retryAfter(resp *http.Response, wait time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return wait
+	}
+	if after := time.Duration(seconds) * time.Second; after < t.policy.maxBackoff {
+		return after
+	}
+	return t.policy.maxBackoff
+}
+
+// responseMessageIDs returns the MessageIds of a Redfish error response.
+// The body is read and put back, so that it is still available to the caller.
+func responseMessageIDs(resp *http.Response) []string {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close() // #nosec G104
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var redfishError struct {
+		Error struct {
+			Code         string `json:"code"`
+			ExtendedInfo []struct {
+				MessageID string `json:"MessageId"`
+			} `json:"@Message.ExtendedInfo"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &redfishError); err != nil {
+		return nil
+	}
+	ids := []string{redfishError.Error.Code}
+	for _, info := range redfishError.Error.ExtendedInfo {
+		ids = append(ids, info.MessageID)
+	}
+	return ids
+}
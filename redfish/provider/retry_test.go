@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-redfish/redfish/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// flakyBMC answers the given statuses in turn, and then 200 OK
+type flakyBMC struct {
+	lock     sync.Mutex
+	statuses []int
+	// retryAfter is the Retry-After header of the failed responses
+	retryAfter string
+	attempts   int
+	bodies     []string
+}
+
+func (b *flakyBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.attempts++
+	body, _ := io.ReadAll(r.Body)
+	b.bodies = append(b.bodies, string(body))
+	if len(b.statuses) == 0 {
+		return
+	}
+	status := b.statuses[0]
+	b.statuses = b.statuses[1:]
+	if len(b.retryAfter) > 0 {
+		w.Header().Set("Retry-After", b.retryAfter)
+	}
+	w.WriteHeader(status)
+	if status == http.StatusBadRequest {
+		w.Write([]byte(`{"error": {"code": "Base.1.12.GeneralError", "@Message.ExtendedInfo": [{"MessageId": "IDRAC.2.8.SYS410"}]}}`)) // #nosec G104
+	}
+}
+
+// testRetryPolicy retries 3 times with short waits
+func testRetryPolicy() retryPolicy {
+	policy := newRetryPolicy(nil)
+	policy.minBackoff, policy.maxBackoff = 10*time.Millisecond, 40*time.Millisecond
+	return policy
+}
+
+func sendRetried(t *testing.T, policy retryPolicy, bmc *flakyBMC, method string) (int, time.Duration) {
+	t.Helper()
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+	client := &http.Client{Transport: &retryTransport{policy: policy, base: http.DefaultTransport}}
+	req, err := http.NewRequest(method, server.URL+"/redfish/v1/Systems/System.Embedded.1", strings.NewReader(`{"ResetType":"On"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s failed: %s", method, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, time.Since(start)
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int
+		status   int
+		attempts int
+	}{
+		{name: "GET on 503", method: http.MethodGet, statuses: []int{503, 503}, status: 200, attempts: 3},
+		{name: "GET on 502 and 504", method: http.MethodGet, statuses: []int{502, 504}, status: 200, attempts: 3},
+		{name: "GET until the last attempt", method: http.MethodGet, statuses: []int{503, 503, 503, 503}, status: 503, attempts: 3},
+		{name: "GET on 500", method: http.MethodGet, statuses: []int{500}, status: 500, attempts: 1},
+		{name: "POST on 503", method: http.MethodPost, statuses: []int{503}, status: 200, attempts: 2},
+		{name: "POST on 429", method: http.MethodPost, statuses: []int{429}, status: 200, attempts: 2},
+		{name: "POST on 502", method: http.MethodPost, statuses: []int{502}, status: 502, attempts: 1},
+		{name: "POST on 504", method: http.MethodPost, statuses: []int{504}, status: 504, attempts: 1},
+		{name: "PATCH on 504", method: http.MethodPatch, statuses: []int{504}, status: 504, attempts: 1},
+		{name: "POST on a retryable message", method: http.MethodPost, statuses: []int{400}, status: 200, attempts: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bmc := &flakyBMC{statuses: test.statuses}
+			status, _ := sendRetried(t, testRetryPolicy(), bmc, test.method)
+			if status != test.status || bmc.attempts != test.attempts {
+				t.Errorf("expected %d after %d attempts, got %d after %d attempts", test.status, test.attempts, status, bmc.attempts)
+			}
+			for _, body := range bmc.bodies {
+				if test.method != http.MethodGet && body != `{"ResetType":"On"}` {
+					t.Errorf("expected the body to be sent again, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	// the waits are 100 and then 200 milliseconds, down to half of them with the jitter
+	policy := testRetryPolicy()
+	policy.minBackoff, policy.maxBackoff = 100*time.Millisecond, 200*time.Millisecond
+	status, elapsed := sendRetried(t, policy, &flakyBMC{statuses: []int{503, 503}}, http.MethodGet)
+	if status != http.StatusOK || elapsed < 150*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected a success after 150 to 300 milliseconds, got %d after %s", status, elapsed)
+	}
+
+	// the Retry-After header is bounded by the maximum backoff
+	bmc := &flakyBMC{statuses: []int{503}, retryAfter: "10"}
+	if status, elapsed := sendRetried(t, testRetryPolicy(), bmc, http.MethodGet); status != http.StatusOK || elapsed > 2*time.Second {
+		t.Errorf("expected the wait to be bounded, got %d after %s", status, elapsed)
+	}
+	bmc = &flakyBMC{statuses: []int{503}, retryAfter: "0"}
+	policy.minBackoff = 5 * time.Second
+	if status, elapsed := sendRetried(t, policy, bmc, http.MethodGet); status != http.StatusOK || elapsed > 2*time.Second {
+		t.Errorf("expected the Retry-After header to be honored, got %d after %s", status, elapsed)
+	}
+}
+
+func TestRetryTransportNetworkError(t *testing.T) {
+	for _, test := range []struct {
+		method   string
+		attempts int
+	}{{http.MethodGet, 3}, {http.MethodPost, 1}} {
+		attempts := 0
+		transport := &retryTransport{policy: testRetryPolicy(), base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset by peer")
+		})}
+		req, _ := http.NewRequest(test.method, "https://bmc.example.org/redfish/v1", http.NoBody)
+		if _, err := transport.RoundTrip(req); err == nil || attempts != test.attempts {
+			t.Errorf("%s: expected an error after %d attempts, got %v after %d", test.method, test.attempts, err, attempts)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := newRetryPolicy([]models.RetryConfig{{
+		MaxAttempts: types.Int64Value(5),
+		MinBackoff:  types.Int64Value(2),
+		MaxBackoff:  types.Int64Value(10),
+	}})
+	// the waits double from min_backoff up to max_backoff, with a jitter of up to half of them
+	for retry, wait := range map[int64]time.Duration{1: 2 * time.Second, 2: 4 * time.Second, 3: 8 * time.Second, 4: 10 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if backoff := policy.backoff(retry); backoff < wait/2 || backoff > wait {
+				t.Errorf("retry %d: expected a wait between %s and %s, got %s", retry, wait/2, wait, backoff)
+			}
+		}
+	}
+
+	// the default max_backoff does not go below min_backoff
+	policy = newRetryPolicy([]models.RetryConfig{{MaxAttempts: types.Int64Null(), MinBackoff: types.Int64Value(60), MaxBackoff: types.Int64Null()}})
+	if policy.maxBackoff != time.Minute {
+		t.Errorf("expected the maximum backoff to be raised to the minimum one, got %s", policy.maxBackoff)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	authMethodBasic   = "basic"
+	authMethodSession = "session"
+	// sessionsURI is the standard Redfish session collection used to log in
+	sessionsURI = "/redfish/v1/SessionService/Sessions"
+	// sessionCloseTimeout bounds the deletion of the sessions at the end of a run, so that an
+	// unreachable BMC does not keep the provider from stopping
+	sessionCloseTimeout = 30 * time.Second
+)
+
+// This is a global session store, so every resource and data source talking to the same
+// endpoint with the same user and TLS settings shares a single Redfish session during a run
+var redfishSessions = newSessionStore()
+
+// sessionStore keeps one Redfish session per endpoint, user and TLS settings
+type sessionStore struct {
+	lock     sync.Mutex
+	sessions map[string]*redfishSession
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*redfishSession),
+	}
+}
+
+// get returns the session for the given endpoint, user and TLS settings, creating it if needed.
+// The TLS settings are part of the key, so that provider blocks verifying the BMC differently
+// never share a session. The session is not opened until the first request is sent through it.
+func (s *sessionStore) get(endpoint, username, password, tlsKey string, transport http.RoundTripper) *redfishSession {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := strings.TrimRight(endpoint, "/") + "|" + username + "|" + tlsKey
+	session, ok := s.sessions[key]
+	if !ok {
+		session = &redfishSession{
+			endpoint:  strings.TrimRight(endpoint, "/"),
+			username:  username,
+			transport: transport,
+		}
+		s.sessions[key] = session
+	}
+	session.setPassword(password)
+	return session
+}
+
+// closeAll deletes every open session from its BMC, giving up on the ones left when the
+// context is done
+func (s *sessionStore) closeAll(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for key, session := range s.sessions {
+		if err := session.logout(ctx); err != nil {
+			log.Printf("[WARN] Unable to delete redfish session for %s: %s", session.endpoint, err.Error())
+		}
+		delete(s.sessions, key)
+	}
+}
+
+// CloseSessions deletes all Redfish sessions opened by the provider. It is meant to be called
+// once the provider server stops, and gives up after sessionCloseTimeout.
+func CloseSessions() {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCloseTimeout)
+	defer cancel()
+	redfishSessions.closeAll(ctx)
+}
+
+// redfishSession holds the X-Auth-Token of a Redfish session and is able to open it again
+// when the BMC invalidates it
+type redfishSession struct {
+	lock      sync.Mutex
+	endpoint  string
+	username  string
+	password  string
+	transport http.RoundTripper
+	token     string
+	location  string
+}
+
+func (s *redfishSession) setPassword(password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.password = password
+}
+
+// currentToken returns the session token, logging in first if no session is open
+func (s *redfishSession) currentToken(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token == "" {
+		if err := s.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+// refresh opens a new session if the given token is still the current one.
+// Concurrent callers that got a 401 with the same token end up with a single new session.
+func (s *redfishSession) refresh(ctx context.Context, staleToken string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token == staleToken {
+		log.Printf("[DEBUG] Redfish session for %s is no longer valid, authenticating again", s.endpoint)
+		s.token = ""
+		s.location = ""
+		if err := s.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+// login creates a session in the session collection. Caller must hold the lock.
+func (s *redfishSession) login(ctx context.Context) error {
+	payload, err := json.Marshal(map[string]string{
+		"UserName": s.username,
+		"Password": s.password,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+sessionsURI, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.transport.RoundTrip(req)
+	if err != nil {
+		return fmt.Errorf("error creating redfish session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body) // #nosec G104
+		return fmt.Errorf("error creating redfish session, status code was %d: %s", resp.StatusCode, string(body))
+	}
+
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return fmt.Errorf("error creating redfish session, no X-Auth-Token was returned")
+	}
+
+	s.token = token
+	s.location = resp.Header.Get("Location")
+	if strings.HasPrefix(s.location, "/") {
+		s.location = s.endpoint + s.location
+	}
+	log.Printf("[DEBUG] Redfish session created for %s", s.endpoint)
+	return nil
+}
+
+// logout deletes the session from the BMC, if one is open
+func (s *redfishSession) logout(ctx context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token == "" || s.location == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.location, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", s.token)
+
+	resp, err := s.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // #nosec G104
+
+	s.token = ""
+	s.location = ""
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("delete session status code was %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sessionTransport adds the session token to every request and authenticates again
+// when the BMC answers with 401 Unauthorized
+type sessionTransport struct {
+	session *redfishSession
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.session.currentToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withSessionToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The body of the first attempt is gone, so the request can only be replayed if it can be rewound
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close() // #nosec G104
+
+	token, err = t.session.refresh(req.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := withSessionToken(req, token)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retry)
+}
+
+func withSessionToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Del("Authorization")
+	r.Header.Set("X-Auth-Token", token)
+	return r
+}
@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// sessionBMC is a BMC accepting the tokens of the sessions it created
+type sessionBMC struct {
+	lock   sync.Mutex
+	logins int
+	tokens map[string]bool
+	// bodies are the bodies of the POSTs to /redfish/v1/Actions
+	bodies []string
+	// hang keeps the deletion of the sessions from answering
+	hang chan struct{}
+}
+
+func newSessionBMC(t *testing.T) (*sessionBMC, *httptest.Server) {
+	t.Helper()
+	bmc := &sessionBMC{tokens: make(map[string]bool), hang: make(chan struct{})}
+	server := httptest.NewServer(http.HandlerFunc(bmc.serveHTTP))
+	t.Cleanup(func() {
+		close(bmc.hang)
+		server.Close()
+	})
+	return bmc, server
+}
+
+func (b *sessionBMC) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		<-b.hang
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if r.Method == http.MethodPost && r.URL.Path == sessionsURI {
+		b.logins++
+		token := fmt.Sprintf("token-%d", b.logins)
+		b.tokens[token] = true
+		w.Header().Set("X-Auth-Token", token)
+		w.Header().Set("Location", sessionsURI+"/"+token)
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	if !b.tokens[r.Header.Get("X-Auth-Token")] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+		b.bodies = append(b.bodies, string(body))
+	}
+}
+
+// expire invalidates the sessions, as a BMC does once they time out
+func (b *sessionBMC) expire() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tokens = make(map[string]bool)
+}
+
+func (b *sessionBMC) loginCount() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.logins
+}
+
+func sessionClient(session *redfishSession) *http.Client {
+	return &http.Client{Transport: &sessionTransport{session: session, base: http.DefaultTransport}}
+}
+
+func sendRequest(t *testing.T, client *http.Client, method, url, body string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s %s answered %d", method, url, resp.StatusCode)
+	}
+}
+
+func TestSessionStoreReuse(t *testing.T) {
+	bmc, server := newSessionBMC(t)
+	store := newSessionStore()
+	first := store.get(server.URL, "root", "calvin", "tls", http.DefaultTransport)
+	second := store.get(server.URL+"/", "root", "calvin", "tls", http.DefaultTransport)
+	if first != second {
+		t.Fatalf("expected the session of the endpoint and user to be shared")
+	}
+	sendRequest(t, sessionClient(first), http.MethodGet, server.URL+"/redfish/v1", "")
+	sendRequest(t, sessionClient(second), http.MethodGet, server.URL+"/redfish/v1", "")
+	if logins := bmc.loginCount(); logins != 1 {
+		t.Errorf("expected a single login, got %d", logins)
+	}
+
+	// other users and TLS settings have their own session
+	if store.get(server.URL, "admin", "calvin", "tls", http.DefaultTransport) == first {
+		t.Errorf("expected another session for another user")
+	}
+	if store.get(server.URL, "root", "calvin", "other-tls", http.DefaultTransport) == first {
+		t.Errorf("expected another session for other TLS settings")
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	bmc, server := newSessionBMC(t)
+	client := sessionClient(newSessionStore().get(server.URL, "root", "calvin", "", http.DefaultTransport))
+	sendRequest(t, client, http.MethodGet, server.URL+"/redfish/v1", "")
+
+	// the 401 of the expired session opens a new one, and the body is sent again
+	bmc.expire()
+	sendRequest(t, client, http.MethodPost, server.URL+"/redfish/v1/Actions", `{"ResetType":"On"}`)
+	if logins := bmc.loginCount(); logins != 2 {
+		t.Errorf("expected a second login, got %d", logins)
+	}
+	if len(bmc.bodies) != 1 || bmc.bodies[0] != `{"ResetType":"On"}` {
+		t.Errorf("expected the body to be replayed, got %v", bmc.bodies)
+	}
+	sendRequest(t, client, http.MethodGet, server.URL+"/redfish/v1", "")
+	if logins := bmc.loginCount(); logins != 2 {
+		t.Errorf("expected the new session to be reused, got %d logins", logins)
+	}
+}
+
+func TestSessionStoreCloseTimeout(t *testing.T) {
+	_, server := newSessionBMC(t)
+	store := newSessionStore()
+	sendRequest(t, sessionClient(store.get(server.URL, "root", "calvin", "", http.DefaultTransport)),
+		http.MethodGet, server.URL+"/redfish/v1", "")
+
+	// the BMC never answers the deletion of the session
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	store.closeAll(ctx)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("closing the sessions took %s", elapsed)
+	}
+	if len(store.sessions) != 0 {
+		t.Errorf("expected the sessions to be forgotten, got %d", len(store.sessions))
+	}
+}
+
+func TestAccRedfishSession_auth(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishSessionConfig(creds),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios", "odata_id"),
+					resource.TestCheckResourceAttrSet("data.redfish_bios.bios_provider_session", "odata_id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedfishSessionConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  auth_method = "session"
+		}
+
+		data "redfish_bios" "bios" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+			auth_method = "session"
+		  }
+		}
+
+		data "redfish_bios" "bios_provider_session" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
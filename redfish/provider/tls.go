@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-redfish/redfish/models"
+)
+
+const (
+	caCertificateMD = "CA certificate bundle used to verify the server BMC certificate, " +
+		"given either as PEM content or as the path to a PEM file."
+	tlsServerNameMD = "Server name used to verify the server BMC certificate and sent as SNI, " +
+		"for BMCs reached by IP address or through an alias."
+	certificateFingerprintMD = "SHA-256 fingerprint of the server BMC certificate, in hexadecimal with or without colons. " +
+		"When set, the certificate is pinned: the connection is only accepted if the fingerprint matches. " +
+		"Without ca_certificate, the pin replaces the verification of the certificate, so that self-signed certificates " +
+		"can be pinned: neither its chain nor its host name are checked. " +
+		"With ca_certificate, the certificate is also verified against the CA and the host name, unless ssl_insecure is true."
+	clientCertificateMD = "Client certificate used for mutual TLS, given either as PEM content or as the path to a PEM file. " +
+		"Requires client_key."
+	clientKeyMD = "Private key of the client certificate used for mutual TLS, " +
+		"given either as PEM content or as the path to a PEM file."
+	// pemHeader marks a value as PEM content rather than a file path
+	pemHeader = "-----BEGIN"
+)
+
+// tlsOptions holds the TLS settings of a connection to a server BMC
+type tlsOptions struct {
+	insecure          bool
+	caCertificate     string
+	serverName        string
+	fingerprint       string
+	clientCertificate string
+	clientKey         string
+}
+
+// getTLSOptions returns the TLS settings of the server. Settings missing from the server
+// fall back to the provider level settings.
+func getTLSOptions(pconfig *redfishProvider, rserver models.RedfishServer) tlsOptions {
+	fallback := func(value, providerValue string) string {
+		if len(value) > 0 {
+			return value
+		}
+		return providerValue
+	}
+	return tlsOptions{
+		insecure:          rserver.SslInsecure.ValueBool(),
+		caCertificate:     fallback(rserver.CACertificate.ValueString(), pconfig.CACertificate),
+		serverName:        fallback(rserver.TLSServerName.ValueString(), pconfig.TLSServerName),
+		fingerprint:       fallback(rserver.CertificateFingerprint.ValueString(), pconfig.CertificateFingerprint),
+		clientCertificate: fallback(rserver.ClientCertificate.ValueString(), pconfig.ClientCertificate),
+		clientKey:         fallback(rserver.ClientKey.ValueString(), pconfig.ClientKey),
+	}
+}
+
+// key identifies the TLS settings, so that the connections using different settings are told apart
+// without keeping the PEM contents
+func (o tlsOptions) key() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		fmt.Sprint(o.insecure), o.caCertificate, o.serverName, o.fingerprint, o.clientCertificate, o.clientKey,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// tlsConfig builds the TLS configuration of the HTTP client used to reach the server BMC
+func (o tlsOptions) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.insecure, // #nosec G402
+		ServerName:         o.serverName,
+	}
+
+	if len(o.caCertificate) > 0 {
+		caPEM, err := readPEM(o.caCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ca_certificate: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("ca_certificate does not contain any valid PEM certificate")
+		}
+	}
+
+	if len(o.clientCertificate) > 0 || len(o.clientKey) > 0 {
+		if len(o.clientCertificate) == 0 || len(o.clientKey) == 0 {
+			return nil, errors.New("client_certificate and client_key must be set together")
+		}
+		certPEM, err := readPEM(o.clientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client_certificate: %w", err)
+		}
+		keyPEM, err := readPEM(o.clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client_key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client_certificate or client_key: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(o.fingerprint) > 0 {
+		fingerprint, err := parseFingerprint(o.fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		config.VerifyConnection = pinnedVerifier(fingerprint)
+		if config.RootCAs == nil {
+			// Without a CA, the pinning replaces the default verification, so that self-signed certificates
+			// can be pinned: neither the chain nor the host name of the certificate are checked
+			config.InsecureSkipVerify = true // #nosec G402
+		}
+	}
+	return config, nil
+}
+
+// pinnedVerifier checks that the leaf certificate of the server matches the fingerprint. It runs after the
+// default verification, when the latter is not skipped.
+func pinnedVerifier(fingerprint []byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("the server did not present any certificate")
+		}
+		sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), hex.EncodeToString(fingerprint)) {
+			return fmt.Errorf("server certificate fingerprint %s does not match certificate_fingerprint", hex.EncodeToString(sum[:]))
+		}
+		return nil
+	}
+}
+
+// parseFingerprint decodes a SHA-256 fingerprint written in hexadecimal, with or without colons
+func parseFingerprint(value string) ([]byte, error) {
+	fingerprint, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(value), ":", ""))
+	if err != nil || len(fingerprint) != sha256.Size {
+		return nil, fmt.Errorf("certificate_fingerprint %s is not a valid SHA-256 fingerprint", value)
+	}
+	return fingerprint, nil
+}
+
+// readPEM returns the value if it is PEM content, otherwise the content of the file it points to
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, pemHeader) {
+		return []byte(value), nil
+	}
+	return os.ReadFile(filepath.Clean(value))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	// the rejected handshakes are expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// the certificates of the test servers are self-signed, so they are their own CA
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	sum := sha256.Sum256(server.Certificate().Raw)
+	pin := hex.EncodeToString(sum[:])
+	wrongPin := strings.Repeat("ab", sha256.Size)
+
+	tests := []struct {
+		name    string
+		options tlsOptions
+		err     string
+	}{
+		{name: "matching pin", options: tlsOptions{fingerprint: pin}},
+		{name: "matching pin with colons", options: tlsOptions{fingerprint: colons(pin)}},
+		// the pin alone does not check the host name
+		{name: "matching pin of another host name", options: tlsOptions{fingerprint: pin, serverName: "bmc.example.org"}},
+		{name: "wrong pin", options: tlsOptions{fingerprint: wrongPin}, err: "does not match certificate_fingerprint"},
+		{name: "wrong pin with insecure", options: tlsOptions{fingerprint: wrongPin, insecure: true}, err: "does not match"},
+		{name: "CA only", options: tlsOptions{caCertificate: ca}},
+		{name: "no CA", options: tlsOptions{}, err: "certificate signed by unknown authority"},
+		{name: "CA of another host name", options: tlsOptions{caCertificate: ca, serverName: "bmc.example.org"},
+			err: "certificate is valid for"},
+		{name: "CA plus pin", options: tlsOptions{caCertificate: ca, fingerprint: pin}},
+		{name: "CA plus wrong pin", options: tlsOptions{caCertificate: ca, fingerprint: wrongPin}, err: "does not match"},
+		// the pin does not turn off the verification of the CA and host name
+		{name: "CA plus pin of another host name", options: tlsOptions{caCertificate: ca, fingerprint: pin, serverName: "bmc.example.org"},
+			err: "certificate is valid for"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := test.options.tlsConfig()
+			if err != nil {
+				t.Fatalf("unable to build the TLS configuration: %s", err)
+			}
+			client := &http.Client{Transport: newHTTPTransport(config)}
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected an error with %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+		})
+	}
+}
+
+func TestTLSConfigInvalid(t *testing.T) {
+	tests := []struct {
+		options tlsOptions
+		err     string
+	}{
+		{tlsOptions{fingerprint: "AB:CD"}, "is not a valid SHA-256 fingerprint"},
+		{tlsOptions{caCertificate: "-----BEGIN CERTIFICATE-----\nfoo\n-----END CERTIFICATE-----\n"}, "does not contain any valid PEM"},
+		{tlsOptions{clientCertificate: "-----BEGIN CERTIFICATE-----"}, "must be set together"},
+	}
+	for _, test := range tests {
+		if _, err := test.options.tlsConfig(); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected an error with %q, got %v", test.err, err)
+		}
+	}
+}
+
+func colons(fingerprint string) string {
+	parts := make([]string, 0, len(fingerprint)/2)
+	for i := 0; i < len(fingerprint); i += 2 {
+		parts = append(parts, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	return strings.Join(parts, ":")
+}
+
+func TestAccRedfishTLS_fingerprintMismatch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishTLSFingerprintConfig(creds),
+				ExpectError: regexp.MustCompile("does not match certificate_fingerprint"),
+			},
+		},
+	})
+}
+
+func testAccRedfishTLSFingerprintConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		data "redfish_bios" "bios" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			certificate_fingerprint = "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00"
+		  }
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
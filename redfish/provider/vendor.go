@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"terraform-provider-redfish/oem"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stmcginnis/gofish"
+)
+
+const unsupportedVendorMsg = "Unsupported vendor"
+
+// vendorSupport returns the vendor of the service, or an error when it does not implement the feature
+func vendorSupport(service *gofish.Service, feature oem.Feature) (oem.Vendor, error) {
+	vendor := oem.New(service)
+	if !vendor.Supports(feature) {
+		return nil, &oem.UnsupportedError{Vendor: vendor.Name(), Feature: feature}
+	}
+	return vendor, nil
+}
+
+// serverKnown tells whether the server attributes of a plan are known
+func serverKnown(ctx context.Context, plan tfsdk.Plan) bool {
+	var server types.String
+	var rserver types.List
+	if plan.GetAttribute(ctx, path.Root("server"), &server).HasError() ||
+		plan.GetAttribute(ctx, path.Root("redfish_server"), &rserver).HasError() {
+		return false
+	}
+	if server.IsUnknown() || rserver.IsUnknown() {
+		return false
+	}
+	value, err := rserver.ToTerraformValue(ctx)
+	return err == nil && value.IsFullyKnown()
+}
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry reads the Redfish registries of a service, such as the BIOS attribute registry of a
+// system, caches them, and checks attribute values against the attribute registries.
+package registry
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// AttributeRegistry is a Redfish attribute registry
+type AttributeRegistry struct {
+	*redfish.AttributeRegistry
+}
+
+// GetAttributeRegistry returns the attribute registry of the given ID, such as the AttributeRegistry of a
+// bios. The ID is either the ID of the registry file, the registry it holds, such as BiosAttributeRegistry.v1_0_3,
+// or its prefix.
+func GetAttributeRegistry(service *gofish.Service, id string) (*AttributeRegistry, error) {
+	file, err := findRegistryFile(service, id)
+	if err != nil {
+		return nil, err
+	}
+	uri := registryLocation(file)
+	if len(uri) == 0 {
+		return nil, fmt.Errorf("the registry %s has no location", id)
+	}
+	return GetAttributeRegistryFromURI(service.GetClient(), uri)
+}
+
+// registryLocation returns the URI of the English version of a registry, or of its first version
+func registryLocation(file *redfish.MessageRegistryFile) string {
+	for _, location := range file.Location {
+		if location.Language == "en" && len(location.URI) > 0 {
+			return location.URI
+		}
+	}
+	for _, location := range file.Location {
+		if len(location.URI) > 0 {
+			return location.URI
+		}
+	}
+	return ""
+}
+
+// GetAttributeRegistryFromURI returns the attribute registry at the URI
+func GetAttributeRegistryFromURI(c common.Client, uri string) (*AttributeRegistry, error) {
+	registry, err := redfish.GetAttributeRegistry(c, uri)
+	if err != nil {
+		return nil, err
+	}
+	return &AttributeRegistry{AttributeRegistry: registry}, nil
+}
+
+// Attribute returns the attribute of the given name
+func (r *AttributeRegistry) Attribute(name string) (*redfish.Attribute, error) {
+	for i := range r.RegistryEntries.Attributes {
+		if r.RegistryEntries.Attributes[i].AttributeName == name {
+			return &r.RegistryEntries.Attributes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("attribute %s was not found in the registry %s", name, r.ID)
+}
+
+// ParseValue converts the string value of an attribute to the type of the attribute: an int64 for the
+// Integer attributes, a bool for the Boolean ones and a string for the others
+func (r *AttributeRegistry) ParseValue(name, value string) (interface{}, error) {
+	attr, err := r.Attribute(name)
+	if err != nil {
+		return nil, err
+	}
+	switch attr.Type {
+	case redfish.IntegerAttributeType:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s is expected to be an integer, got %q", name, value)
+		}
+		return number, nil
+	case redfish.BooleanAttributeType:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s is expected to be a boolean, got %q", name, value)
+		}
+		return boolean, nil
+	default:
+		return value, nil
+	}
+}
+
+// CheckAttribute checks if the attribute can be written, and if the value is compliant with its type,
+// allowed values, bounds, length and regular expression
+func (r *AttributeRegistry) CheckAttribute(name string, value interface{}) error {
+	attr, err := r.Attribute(name)
+	if err != nil {
+		return err
+	}
+	if attr.ReadOnly || attr.Immutable {
+		return fmt.Errorf("attribute %s cannot be written as it is read only", name)
+	}
+
+	switch attr.Type {
+	case redfish.EnumerationAttributeType:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute %s is an enumeration, got %v", name, value)
+		}
+		return checkEnumeration(attr, str)
+	case redfish.IntegerAttributeType:
+		number, ok := integerValue(value)
+		if !ok {
+			return fmt.Errorf("attribute %s is an integer, got %v", name, value)
+		}
+		return checkInteger(attr, number)
+	case redfish.StringAttributeType, redfish.PasswordAttributeType:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute %s is a string, got %v", name, value)
+		}
+		return checkString(attr, str)
+	case redfish.BooleanAttributeType:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("attribute %s is a boolean, got %v", name, value)
+		}
+	}
+	return nil
+}
+
+func checkEnumeration(attr *redfish.Attribute, value string) error {
+	allowed := make([]string, 0, len(attr.Value))
+	for _, v := range attr.Value {
+		if v.ValueName == value {
+			return nil
+		}
+		allowed = append(allowed, v.ValueName)
+	}
+	return fmt.Errorf("value %s of attribute %s is not permitted. Allowed values: %s",
+		value, attr.AttributeName, strings.Join(allowed, ", "))
+}
+
+// integerValue returns the value of the integer types, and of the integral float64 decoded from JSON
+func integerValue(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), v == math.Trunc(v)
+	}
+	return 0, false
+}
+
+func checkInteger(attr *redfish.Attribute, value int64) error {
+	// registries without bounds leave both of them to zero
+	bounded := attr.LowerBound != 0 || attr.UpperBound != 0
+	if bounded && (value < attr.LowerBound || value > attr.UpperBound) {
+		return fmt.Errorf("value %d of attribute %s is out of bounds. Lower bound is %d, upper bound is %d",
+			value, attr.AttributeName, attr.LowerBound, attr.UpperBound)
+	}
+	if attr.ScalarIncrement > 0 && (value-attr.LowerBound)%attr.ScalarIncrement != 0 {
+		return fmt.Errorf("value %d of attribute %s is not a multiple of %d from %d",
+			value, attr.AttributeName, attr.ScalarIncrement, attr.LowerBound)
+	}
+	return nil
+}
+
+func checkString(attr *redfish.Attribute, value string) error {
+	length := int64(len(value))
+	// registries without maximum length leave it to zero
+	if length < attr.MinLength || (attr.MaxLength > 0 && length > attr.MaxLength) {
+		return fmt.Errorf("length %d of attribute %s is not compliant. Min length %d, max length %d",
+			length, attr.AttributeName, attr.MinLength, attr.MaxLength)
+	}
+	if len(attr.ValueExpression) > 0 {
+		re, err := regexp.Compile(attr.ValueExpression)
+		// registries may use expressions of other regular expression dialects, which are not checked
+		if err == nil && !re.MatchString(value) {
+			// the value is not reported, as it may be a password
+			return fmt.Errorf("value of attribute %s does not match the expression %s", attr.AttributeName, attr.ValueExpression)
+		}
+	}
+	return nil
+}
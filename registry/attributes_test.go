@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-redfish/emulator"
+)
+
+func TestGetAttributeRegistry(t *testing.T) {
+	_, service := emulator.Connect(t)
+	for _, id := range []string{"BiosAttributeRegistry.v1_0_3", "BiosAttributeRegistry"} {
+		registry, err := GetAttributeRegistry(service, id)
+		if err != nil {
+			t.Fatalf("unable to read the registry %s: %s", id, err)
+		}
+		if registry.RegistryVersion != "v1_0_3" || len(registry.RegistryEntries.Attributes) == 0 {
+			t.Errorf("unexpected registry %s %s", registry.ID, registry.RegistryVersion)
+		}
+	}
+	if _, err := GetAttributeRegistry(service, "UnknownRegistry.v1_0_0"); err == nil {
+		t.Errorf("expected an error for an unknown registry")
+	}
+}
+
+func TestCheckAttribute(t *testing.T) {
+	_, service := emulator.Connect(t)
+	registry, err := GetAttributeRegistry(service, "BiosAttributeRegistry.v1_0_3")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	tests := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{"MemTest", "Enabled", ""},
+		{"BootMode", "Uefi", ""},
+		{"BootMode", "UEFI", "not permitted. Allowed values: Uefi, Bios"},
+		{"MemTst", "Enabled", "attribute MemTst was not found"},
+		{"SystemServiceTag", "ABCDEFG", "read only"},
+		{"AcPwrRcvryUserDelay", "120", ""},
+		{"AcPwrRcvryUserDelay", "30", "out of bounds. Lower bound is 60, upper bound is 600"},
+		{"AcPwrRcvryUserDelay", "two", "expected to be an integer"},
+		{"AssetTag", "rack-12", ""},
+		{"AssetTag", strings.Repeat("a", 64), "length 64 of attribute AssetTag is not compliant"},
+		{"AssetTag", "café", "does not match the expression"},
+	}
+	for _, tt := range tests {
+		value, err := registry.ParseValue(tt.name, tt.value)
+		if err == nil {
+			err = registry.CheckAttribute(tt.name, value)
+		}
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s=%s: unexpected error %s", tt.name, tt.value, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s=%s: expected an error containing %q, got %v", tt.name, tt.value, tt.err, err)
+		}
+	}
+}
+
+func TestCheckAttributeTypes(t *testing.T) {
+	_, service := emulator.Connect(t)
+	registry, err := GetAttributeRegistry(service, "BiosAttributeRegistry.v1_0_3")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if err := registry.CheckAttribute("AcPwrRcvryUserDelay", 60.0); err != nil {
+		t.Errorf("expected a float64 decoded from JSON to be an integer, got %s", err)
+	}
+	if err := registry.CheckAttribute("AcPwrRcvryUserDelay", "60"); err == nil {
+		t.Errorf("expected an error for a string value of an integer attribute")
+	}
+	if err := registry.CheckAttribute("MemTest", true); err == nil {
+		t.Errorf("expected an error for a boolean value of an enumeration attribute")
+	}
+}
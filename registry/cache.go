@@ -0,0 +1,384 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Cache holds the registries read from the services, such as the attribute and message registries, so that
+// the servers running the same firmware do not each download them. The registries are kept in memory, and
+// also in a directory when one is set, so that the next runs find them.
+//
+// A registry is identified by its RegistryPrefix, RegistryVersion and SupportedSystems. A cached registry is
+// only used for a service whose registry file has the same prefix and version, and whose systems are among
+// its SupportedSystems.
+type Cache struct {
+	mu        sync.Mutex
+	directory string
+	entries   map[string]*cacheEntry
+	// loaded are the prefixes and versions whose files of the directory were read
+	loaded map[string]bool
+}
+
+// cacheEntry is a registry of the cache, with the JSON document of the service
+type cacheEntry struct {
+	header registryHeader
+	data   []byte
+}
+
+// registryHeader holds the properties identifying a registry
+type registryHeader struct {
+	ID               string `json:"Id"`
+	RegistryPrefix   string
+	RegistryVersion  string
+	SupportedSystems []SupportedSystem
+}
+
+// SupportedSystem is a system an attribute registry applies to
+type SupportedSystem struct {
+	ProductName     string
+	SystemID        string `json:"SystemId"`
+	FirmwareVersion string
+}
+
+// serviceSystems identifies the systems of a service, to select the registries supporting them
+type serviceSystems struct {
+	models   map[string]bool
+	versions map[string]bool
+}
+
+// NewCache returns an empty cache, which keeps the registries in memory only
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[string]*cacheEntry),
+		loaded:  make(map[string]bool),
+	}
+}
+
+// SetDirectory sets the directory where the registries are also kept, creating it if needed. An empty
+// directory keeps the registries in memory only.
+func (c *Cache) SetDirectory(directory string) error {
+	if len(directory) > 0 {
+		if err := os.MkdirAll(directory, 0o700); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.directory = directory
+	c.loaded = make(map[string]bool)
+	return nil
+}
+
+// Get returns the JSON document of the registry of the given ID, from the cache if it holds the registry
+// of the service, or else from the service. The ID is either the ID of the registry file, the registry it
+// holds, such as BiosAttributeRegistry.v1_0_3, or its prefix.
+func (c *Cache) Get(service *gofish.Service, id string) ([]byte, error) {
+	file, err := findRegistryFile(service, id)
+	if err != nil {
+		return nil, err
+	}
+	uri := registryLocation(file)
+	if len(uri) == 0 {
+		return nil, fmt.Errorf("the registry %s has no location", id)
+	}
+
+	prefix, version := splitRegistry(file.Registry)
+	// registry files without version cannot be checked against the cache
+	if len(version) > 0 {
+		if data := c.lookup(service, prefix, version); data != nil {
+			return data, nil
+		}
+	}
+
+	data, err := download(service.GetClient(), uri)
+	if err != nil {
+		return nil, err
+	}
+	var header registryHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("unable to read the registry %s: %w", id, err)
+	}
+	if len(header.RegistryPrefix) == 0 {
+		// attribute registries may only name their prefix in their ID, such as BiosAttributeRegistry.v1_0_3
+		header.RegistryPrefix, _ = splitRegistry(header.ID)
+	}
+	if len(header.RegistryPrefix) > 0 && len(header.RegistryVersion) > 0 {
+		c.store(&cacheEntry{header: header, data: data})
+	}
+	return data, nil
+}
+
+// Decode unmarshals the registry of the given ID into v, see Get
+func (c *Cache) Decode(service *gofish.Service, id string, v interface{}) error {
+	data, err := c.Get(service, id)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unable to read the registry %s: %w", id, err)
+	}
+	return nil
+}
+
+// AttributeRegistry returns the attribute registry of the given ID, see GetAttributeRegistry
+func (c *Cache) AttributeRegistry(service *gofish.Service, id string) (*AttributeRegistry, error) {
+	var registry redfish.AttributeRegistry
+	if err := c.Decode(service, id, &registry); err != nil {
+		return nil, err
+	}
+	registry.SetClient(service.GetClient())
+	return &AttributeRegistry{AttributeRegistry: &registry}, nil
+}
+
+// MessageRegistry returns the message registry of the given ID, such as Base.1.12
+func (c *Cache) MessageRegistry(service *gofish.Service, id string) (*redfish.MessageRegistry, error) {
+	var registry redfish.MessageRegistry
+	if err := c.Decode(service, id, &registry); err != nil {
+		return nil, err
+	}
+	registry.SetClient(service.GetClient())
+	return &registry, nil
+}
+
+// lookup returns the registry of the cache with the prefix and version supporting the systems of the
+// service, or nil
+func (c *Cache) lookup(service *gofish.Service, prefix, version string) []byte {
+	candidates := c.candidates(prefix, version)
+	var systems *serviceSystems
+	for _, entry := range candidates {
+		if len(entry.header.SupportedSystems) == 0 {
+			return entry.data
+		}
+		if systems == nil {
+			var err error
+			if systems, err = getServiceSystems(service); err != nil {
+				// without the systems of the service, the registry is read again
+				return nil
+			}
+		}
+		if systems.supported(entry.header.SupportedSystems) {
+			return entry.data
+		}
+	}
+	return nil
+}
+
+// candidates returns the registries of the cache with the prefix and version, reading the ones of the
+// directory the first time
+func (c *Cache) candidates(prefix, version string) []*cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	base := fileBase(prefix, version)
+	if len(c.directory) > 0 && !c.loaded[base] {
+		c.loaded[base] = true
+		c.loadDirectory(base)
+	}
+	var result []*cacheEntry
+	for _, key := range sortedKeys(c.entries) {
+		entry := c.entries[key]
+		if strings.EqualFold(entry.header.RegistryPrefix, prefix) && sameVersion(version, entry.header.RegistryVersion) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// loadDirectory reads the registries of the directory whose file names start with base. The files
+// which cannot be read are ignored, and replaced when the registry is read again from a service.
+func (c *Cache) loadDirectory(base string) {
+	files, err := os.ReadDir(c.directory)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), base) || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.directory, file.Name()))
+		if err != nil {
+			continue
+		}
+		var header registryHeader
+		if err := json.Unmarshal(data, &header); err != nil {
+			continue
+		}
+		if len(header.RegistryPrefix) == 0 {
+			header.RegistryPrefix, _ = splitRegistry(header.ID)
+		}
+		c.entries[entryKey(header)] = &cacheEntry{header: header, data: data}
+	}
+}
+
+// store adds a registry to the cache, and writes it to the directory. The registry stays in memory when it
+// cannot be written.
+func (c *Cache) store(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := entryKey(entry.header)
+	c.entries[key] = entry
+	if len(c.directory) == 0 {
+		return
+	}
+	// the file is renamed once written, so that the other runs never read a partial registry
+	temp, err := os.CreateTemp(c.directory, ".registry-*")
+	if err != nil {
+		return
+	}
+	_, err = temp.Write(entry.data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filepath.Join(c.directory, key+".json"))
+	}
+	if err != nil {
+		os.Remove(temp.Name()) // #nosec G104
+	}
+}
+
+// findRegistryFile returns the registry file of the given ID, registry or prefix
+func findRegistryFile(service *gofish.Service, id string) (*redfish.MessageRegistryFile, error) {
+	files, err := service.Registries()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.ID == id || file.Registry == id {
+			return file, nil
+		}
+	}
+	for _, file := range files {
+		if strings.HasPrefix(file.ID, id+".") || strings.HasPrefix(file.Registry, id+".") {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("the registry %s was not found", id)
+}
+
+func download(c common.Client, uri string) ([]byte, error) {
+	resp, err := c.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// getServiceSystems returns the models and the BIOS and manager firmware versions of the service
+func getServiceSystems(service *gofish.Service) (*serviceSystems, error) {
+	systems, err := service.Systems()
+	if err != nil {
+		return nil, err
+	}
+	managers, err := service.Managers()
+	if err != nil {
+		return nil, err
+	}
+	result := &serviceSystems{models: make(map[string]bool), versions: make(map[string]bool)}
+	for _, system := range systems {
+		result.models[system.Model] = true
+		result.versions[system.BIOSVersion] = true
+	}
+	for _, manager := range managers {
+		result.versions[manager.FirmwareVersion] = true
+	}
+	return result, nil
+}
+
+// supported tells whether a system of the service is among the supported systems of a registry
+func (s *serviceSystems) supported(supported []SupportedSystem) bool {
+	for _, system := range supported {
+		if s.models[system.ProductName] && (len(system.FirmwareVersion) == 0 || s.versions[system.FirmwareVersion]) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRegistry splits a registry, such as Base.1.12 or BiosAttributeRegistry.v1_0_3, into its prefix and
+// version
+func splitRegistry(registry string) (string, string) {
+	prefix, version, _ := strings.Cut(registry, ".")
+	return prefix, version
+}
+
+// sameVersion tells whether the version of a registry file, which may only have the major and minor
+// versions, is the one of a registry. Both Redfish versions, such as 1.12.0, and the ones of the attribute
+// registries, such as v1_0_3, are compared.
+func sameVersion(fileVersion, version string) bool {
+	fileParts := versionParts(fileVersion)
+	parts := versionParts(version)
+	if len(fileParts) > len(parts) {
+		return false
+	}
+	for i := range fileParts {
+		if fileParts[i] != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func versionParts(version string) []string {
+	return strings.Split(strings.ReplaceAll(strings.TrimPrefix(version, "v"), "_", "."), ".")
+}
+
+// entryKey returns the key of a registry, also the name of its file in the directory
+func entryKey(header registryHeader) string {
+	systems := "all"
+	if len(header.SupportedSystems) > 0 {
+		names := make([]string, 0, len(header.SupportedSystems))
+		for _, system := range header.SupportedSystems {
+			names = append(names, system.ProductName+"/"+system.SystemID+"/"+system.FirmwareVersion)
+		}
+		sort.Strings(names)
+		digest := sha256.Sum256([]byte(strings.Join(names, "\n")))
+		systems = hex.EncodeToString(digest[:8])
+	}
+	return fileBase(header.RegistryPrefix, header.RegistryVersion) + "-" + systems
+}
+
+// fileBase returns the start of the file names of the registries with a prefix and version. The registries of
+// a version with only its major and minor numbers start with the same base as the full version.
+func fileBase(prefix, version string) string {
+	return filepath.Base(prefix + "-" + strings.Join(versionParts(version), "."))
+}
+
+func sortedKeys(entries map[string]*cacheEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"os"
+	"testing"
+
+	"terraform-provider-redfish/emulator"
+)
+
+const (
+	biosRegistryFile = "/redfish/v1/Registries/BiosAttributeRegistry"
+	biosRegistryURI  = "/redfish/v1/Registries/BiosAttributeRegistry/BiosAttributeRegistry.json"
+	systemURI        = "/redfish/v1/Systems/System.Embedded.1"
+)
+
+// renameRegistry changes the name of the BIOS registry of the emulator, to tell whether the cache read it again
+func renameRegistry(t *testing.T, s *emulator.Server, name string) {
+	t.Helper()
+	registry, ok := s.Resource(biosRegistryURI)
+	if !ok {
+		t.Fatalf("the emulator has no BIOS registry")
+	}
+	registry["Name"] = name
+	s.SetResource(biosRegistryURI, registry)
+}
+
+func TestCacheAttributeRegistry(t *testing.T) {
+	s, service := emulator.Connect(t)
+	cache := NewCache()
+	registry, err := cache.AttributeRegistry(service, "BiosAttributeRegistry")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if len(registry.RegistryEntries.Attributes) == 0 {
+		t.Fatalf("the registry has no attribute")
+	}
+	if err := registry.CheckAttribute("MemTest", "Enabled"); err != nil {
+		t.Errorf("unexpected error of the cached registry: %s", err)
+	}
+
+	// the registry of the same version is not read again
+	renameRegistry(t, s, "Updated BIOS Attribute Registry")
+	registry, err = cache.AttributeRegistry(service, "BiosAttributeRegistry.v1_0_3")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if registry.Name != "BIOS Attribute Registry" {
+		t.Errorf("expected the cached registry, got %s", registry.Name)
+	}
+
+	// a new version of the registry is read again
+	file, _ := s.Resource(biosRegistryFile)
+	file["Registry"] = "BiosAttributeRegistry.v1_0_4"
+	s.SetResource(biosRegistryFile, file)
+	content, _ := s.Resource(biosRegistryURI)
+	content["RegistryVersion"] = "v1_0_4"
+	s.SetResource(biosRegistryURI, content)
+	registry, err = cache.AttributeRegistry(service, "BiosAttributeRegistry")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if registry.Name != "Updated BIOS Attribute Registry" || registry.RegistryVersion != "v1_0_4" {
+		t.Errorf("expected the new version of the registry, got %s %s", registry.Name, registry.RegistryVersion)
+	}
+
+	if _, err := cache.AttributeRegistry(service, "UnknownRegistry"); err == nil {
+		t.Errorf("expected an error for an unknown registry")
+	}
+}
+
+func TestCacheSupportedSystems(t *testing.T) {
+	s, service := emulator.Connect(t)
+	cache := NewCache()
+	if _, err := cache.Get(service, "BiosAttributeRegistry"); err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+
+	// the registry of another model is not used
+	renameRegistry(t, s, "PowerEdge R760 BIOS Attribute Registry")
+	system, _ := s.Resource(systemURI)
+	system["Model"] = "PowerEdge R760"
+	s.SetResource(systemURI, system)
+	registry, err := cache.AttributeRegistry(service, "BiosAttributeRegistry")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if registry.Name != "PowerEdge R760 BIOS Attribute Registry" {
+		t.Errorf("expected the registry of the model, got %s", registry.Name)
+	}
+}
+
+func TestCacheDirectory(t *testing.T) {
+	directory := t.TempDir()
+	cache := NewCache()
+	if err := cache.SetDirectory(directory); err != nil {
+		t.Fatalf("unable to set the directory: %s", err)
+	}
+	_, service := emulator.Connect(t)
+	if _, err := cache.Get(service, "BiosAttributeRegistry"); err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	files, err := os.ReadDir(directory)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected the registry in the directory, got %v %v", files, err)
+	}
+
+	// another run finds the registry in the directory
+	s, service := emulator.Connect(t)
+	renameRegistry(t, s, "Updated BIOS Attribute Registry")
+	next := NewCache()
+	if err := next.SetDirectory(directory); err != nil {
+		t.Fatalf("unable to set the directory: %s", err)
+	}
+	registry, err := next.AttributeRegistry(service, "BiosAttributeRegistry")
+	if err != nil {
+		t.Fatalf("unable to read the registry: %s", err)
+	}
+	if registry.Name != "BIOS Attribute Registry" {
+		t.Errorf("expected the registry of the directory, got %s", registry.Name)
+	}
+
+	// the registry of the manager is read by prefix
+	var manager struct {
+		RegistryPrefix string
+	}
+	if err := next.Decode(service, "ManagerAttributeRegistry", &manager); err != nil {
+		t.Fatalf("unable to read the manager registry: %s", err)
+	}
+	if manager.RegistryPrefix != "ManagerAttributeRegistry" {
+		t.Errorf("unexpected registry %s", manager.RegistryPrefix)
+	}
+}
+
+func TestSameVersion(t *testing.T) {
+	tests := []struct {
+		fileVersion string
+		version     string
+		same        bool
+	}{
+		{"v1_0_3", "v1_0_3", true},
+		{"1.12", "1.12.0", true},
+		{"1.12", "1.11.0", false},
+		{"1.1", "1.12.0", false},
+		{"v1_0_3", "1.0.3", true},
+		{"1.0.3", "1.0", false},
+	}
+	for _, test := range tests {
+		if same := sameVersion(test.fileVersion, test.version); same != test.same {
+			t.Errorf("sameVersion(%s, %s) = %t, expected %t", test.fileVersion, test.version, same, test.same)
+		}
+	}
+}
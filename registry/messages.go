@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Message is a message of a service expanded with its message registry
+type Message struct {
+	MessageID  string
+	Message    string
+	Severity   string
+	Resolution string
+}
+
+// MessageResolver expands the MessageIds of a service, such as Base.1.12.PropertyUnknown, with the message
+// registries of the service. The registries are read through the cache, once per resolver.
+type MessageResolver struct {
+	cache   *Cache
+	service *gofish.Service
+
+	mu sync.Mutex
+	// registries holds the registries read by prefix and version, nil for the ones the service does not have
+	registries map[string]*redfish.MessageRegistry
+}
+
+// MessageResolver returns a resolver of the MessageIds of the service
+func (c *Cache) MessageResolver(service *gofish.Service) *MessageResolver {
+	return &MessageResolver{
+		cache:      c,
+		service:    service,
+		registries: make(map[string]*redfish.MessageRegistry),
+	}
+}
+
+// Resolve returns the message of a MessageId, whose text has its arguments substituted. The MessageId is made of
+// the prefix, the major and minor versions of its registry and the key of the message, such as IDRAC.2.8.SYS011.
+// The registry of the service with the same prefix and major version is used when it has another minor version.
+func (r *MessageResolver) Resolve(messageID string, args []string) (*Message, error) {
+	return r.ResolveIn("", messageID, args)
+}
+
+// ResolveIn resolves a MessageId like Resolve, a MessageId without registry, such as the SYS051 of the Dell
+// jobs, being looked up in the registry given as Prefix.Major.Minor, such as the IDRAC.2.8 of the other
+// messages of the job. The registry is ignored for the MessageIds having one.
+func (r *MessageResolver) ResolveIn(registryName, messageID string, args []string) (*Message, error) {
+	parts := strings.Split(messageID, ".")
+	if len(parts) == 1 && len(registryName) > 0 {
+		parts = append(strings.Split(registryName, "."), messageID)
+	}
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("the message %s is not of the form Prefix.Major.Minor.Key", messageID)
+	}
+	registry, err := r.registry(parts[0], parts[1], parts[2])
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := registry.Messages[parts[3]]
+	if !ok {
+		return nil, fmt.Errorf("the message %s was not found in the registry %s", messageID, registry.ID)
+	}
+	severity := entry.MessageSeverity
+	if len(severity) == 0 {
+		severity = entry.Severity
+	}
+	return &Message{
+		MessageID:  messageID,
+		Message:    ExpandMessage(entry.Message, args),
+		Severity:   severity,
+		Resolution: entry.Resolution,
+	}, nil
+}
+
+// MessageRegistry returns the registry of a MessageId as Prefix.Major.Minor, or an empty string when the
+// MessageId has no registry
+func MessageRegistry(messageID string) string {
+	parts := strings.Split(messageID, ".")
+	if len(parts) != 4 {
+		return ""
+	}
+	return strings.Join(parts[:3], ".")
+}
+
+// registry returns the message registry of the prefix and version
+func (r *MessageResolver) registry(prefix, major, minor string) (*redfish.MessageRegistry, error) {
+	name := strings.Join([]string{prefix, major, minor}, ".")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if registry, done := r.registries[name]; done {
+		if registry == nil {
+			return nil, fmt.Errorf("the registry %s was not found", name)
+		}
+		return registry, nil
+	}
+
+	registry, err := r.cache.MessageRegistry(r.service, name)
+	if err != nil {
+		// another minor version of the registry holds the same messages
+		registry, err = r.cache.MessageRegistry(r.service, prefix)
+		if err == nil && !sameVersion(major, registry.RegistryVersion) {
+			err = fmt.Errorf("the registry %s was not found, the service has %s.%s", name, prefix, registry.RegistryVersion)
+		}
+	}
+	if err != nil {
+		r.registries[name] = nil
+		return nil, err
+	}
+	r.registries[name] = registry
+	return registry, nil
+}
+
+// ExpandMessage substitutes the arguments of a message, %1 being the first one
+func ExpandMessage(message string, args []string) string {
+	// the last arguments are substituted first, so that %1 does not replace the start of %10
+	for i := len(args); i > 0; i-- {
+		message = strings.ReplaceAll(message, fmt.Sprintf("%%%d", i), args[i-1])
+	}
+	return message
+}
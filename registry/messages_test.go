@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-redfish/emulator"
+)
+
+func TestMessageResolver(t *testing.T) {
+	_, service := emulator.Connect(t)
+	resolver := NewCache().MessageResolver(service)
+	tests := []struct {
+		id         string
+		args       []string
+		message    string
+		severity   string
+		resolution string
+		err        string
+	}{
+		{
+			id:         "IDRAC.2.8.SYS044",
+			args:       []string{"profile.xml"},
+			message:    "Unable to find the Server Configuration Profile file profile.xml on the network share.",
+			severity:   "Critical",
+			resolution: "Make sure the file is available on the network share and retry the operation.",
+		},
+		{
+			id:       "Base.1.12.PropertyValueNotInList",
+			args:     []string{"Maybe", "NumLock"},
+			message:  "The value Maybe for the property NumLock is not in the list of acceptable values.",
+			severity: "Warning",
+		},
+		{
+			// the registry of another minor version is used
+			id:       "Base.1.8.PropertyUnknown",
+			args:     []string{"Unknown"},
+			message:  "The property Unknown is not in the list of valid properties for the resource.",
+			severity: "Warning",
+		},
+		{id: "Base.2.0.PropertyUnknown", err: "the registry Base.2.0 was not found"},
+		{id: "IDRAC.2.8.SYS999", err: "the message IDRAC.2.8.SYS999 was not found"},
+		{id: "EEMI.1.0.SYS044", err: "the registry EEMI was not found"},
+		{id: "SYS044", err: "not of the form Prefix.Major.Minor.Key"},
+	}
+	for _, test := range tests {
+		message, err := resolver.Resolve(test.id, test.args)
+		if len(test.err) > 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Resolve(%s) expected an error with %q, got %v", test.id, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%s) failed: %s", test.id, err)
+			continue
+		}
+		if message.Message != test.message || message.Severity != test.severity {
+			t.Errorf("Resolve(%s) = %q %s", test.id, message.Message, message.Severity)
+		}
+		if len(test.resolution) > 0 && message.Resolution != test.resolution {
+			t.Errorf("Resolve(%s) has the resolution %q", test.id, message.Resolution)
+		}
+	}
+}
+
+func TestMessageResolverIn(t *testing.T) {
+	_, service := emulator.Connect(t)
+	resolver := NewCache().MessageResolver(service)
+
+	// the MessageIds of the Dell jobs have no registry
+	message, err := resolver.ResolveIn("IDRAC.2.8", "SYS044", []string{"profile.xml"})
+	if err != nil {
+		t.Fatalf("ResolveIn(SYS044) failed: %s", err)
+	}
+	if message.MessageID != "SYS044" || message.Severity != "Critical" ||
+		message.Message != "Unable to find the Server Configuration Profile file profile.xml on the network share." {
+		t.Errorf("ResolveIn(SYS044) = %s %q %s", message.MessageID, message.Message, message.Severity)
+	}
+
+	// the registry of a MessageId is kept
+	message, err = resolver.ResolveIn("IDRAC.2.8", "Base.1.12.PropertyUnknown", []string{"Unknown"})
+	if err != nil || message.Severity != "Warning" {
+		t.Errorf("ResolveIn(Base.1.12.PropertyUnknown) = %v, %v", message, err)
+	}
+
+	if _, err := resolver.ResolveIn("", "SYS044", nil); err == nil || !strings.Contains(err.Error(), "not of the form") {
+		t.Errorf("expected an error without registry, got %v", err)
+	}
+	if _, err := resolver.ResolveIn("IDRAC.2.8", "SYS999", nil); err == nil || !strings.Contains(err.Error(), "was not found") {
+		t.Errorf("expected an error for an unknown message, got %v", err)
+	}
+}
+
+func TestMessageRegistry(t *testing.T) {
+	for id, expected := range map[string]string{"IDRAC.2.8.SYS044": "IDRAC.2.8", "SYS044": "", "Base.1.PropertyUnknown": ""} {
+		if name := MessageRegistry(id); name != expected {
+			t.Errorf("MessageRegistry(%s) = %q, expected %q", id, name, expected)
+		}
+	}
+}
+
+func TestExpandMessage(t *testing.T) {
+	args := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	if expanded := ExpandMessage("%1 %2 %10", args); expanded != "a b j" {
+		t.Errorf("unexpected expansion %q", expanded)
+	}
+	if expanded := ExpandMessage("%1 %2", []string{"only"}); expanded != "only %2" {
+		t.Errorf("unexpected expansion %q", expanded)
+	}
+}